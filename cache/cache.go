@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache is a simple on-disk json cache.
+type Cache struct {
+	dir string
+	ttl time.Duration
+}
+
+// New creates a cache in dir, entries older than ttl are ignored,
+// a ttl of 0 means entries never expire.
+func New(dir string, ttl time.Duration) *Cache {
+	return &Cache{dir, ttl}
+}
+
+// UserCache creates a cache in the user's cache directory
+// (e.g. ~/.cache/name on linux).
+func UserCache(name string, ttl time.Duration) (*Cache, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return New(filepath.Join(dir, name), ttl), nil
+}
+
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+func (c *Cache) path(key string) string {
+	h := sha1.Sum([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(h[:])+".json")
+}
+
+// Get decodes the value stored under key into v
+// and reports whether a (non-expired) value was found.
+func (c *Cache) Get(key string, v interface{}) (bool, error) {
+	p := c.path(key)
+	stat, err := os.Stat(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if c.ttl != 0 && time.Since(stat.ModTime()) > c.ttl {
+		return false, nil
+	}
+
+	file, err := os.Open(p)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	if err := json.NewDecoder(file).Decode(v); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Set stores v under key.
+func (c *Cache) Set(key string, v interface{}) error {
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return err
+	}
+
+	p := c.path(key)
+	tmp := p + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	err = json.NewEncoder(file).Encode(v)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, p)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes the value stored under key.
+func (c *Cache) Delete(key string) error {
+	err := os.Remove(c.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Clear removes all cached values.
+func (c *Cache) Clear() error {
+	return os.RemoveAll(c.dir)
+}
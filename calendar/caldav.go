@@ -0,0 +1,88 @@
+package calendar
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const caldavQuery = `<?xml version="1.0" encoding="utf-8"?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop>
+    <C:calendar-data>
+      <C:expand start="%[1]s" end="%[2]s"/>
+    </C:calendar-data>
+  </D:prop>
+  <C:filter>
+    <C:comp-filter name="VCALENDAR">
+      <C:comp-filter name="VEVENT">
+        <C:time-range start="%[1]s" end="%[2]s"/>
+      </C:comp-filter>
+    </C:comp-filter>
+  </C:filter>
+</C:calendar-query>`
+
+// CalDAV returns the events of the calendar collection at uri between from
+// and to, the server expands recurring events. Username and password are
+// sent as basic auth when set.
+func CalDAV(ctx context.Context, uri, username, password string, from, to time.Time) ([]*Event, error) {
+	const layout = "20060102T150405Z"
+	body := fmt.Sprintf(caldavQuery, from.UTC().Format(layout), to.UTC().Format(layout))
+
+	req, err := http.NewRequestWithContext(ctx, "REPORT", uri, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "1")
+	if username != "" || password != "" {
+		req.SetBasicAuth(username, password)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusMultiStatus && res.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(res.Body, 1024))
+		return nil, fmt.Errorf("Unexpected caldav error (%d): %s", res.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	// Every response holds a VCALENDAR with the event (and its
+	// occurrences) in calendar-data.
+	var all []*Event
+	dec := xml.NewDecoder(res.Body)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "calendar-data" {
+			continue
+		}
+
+		var data string
+		if err := dec.DecodeElement(&data, &start); err != nil {
+			return nil, err
+		}
+		events, err := Parse(strings.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, events...)
+	}
+
+	return all, nil
+}
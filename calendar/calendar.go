@@ -0,0 +1,258 @@
+// Package calendar reads the events of an iCalendar (RFC 5545) file, as
+// exported or published by google calendar and outlook, or of a CalDAV
+// calendar, and expands their simple recurrence rules.
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is a single event or a single occurrence of a recurring event.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	// Cancelled events and transparent (free) events take no time.
+	Cancelled   bool
+	Transparent bool
+
+	rule         *rule
+	exdates      map[int64]struct{}
+	recurrenceID time.Time
+}
+
+// Busy reports whether the event takes up time.
+func (e *Event) Busy() bool {
+	return !e.AllDay && !e.Cancelled && !e.Transparent && e.End.After(e.Start)
+}
+
+// property is a content line: NAME;PARAM=VALUE:value.
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads every VEVENT of an iCalendar, recurring events are returned
+// once, see Between.
+func Parse(r io.Reader) ([]*Event, error) {
+	var events []*Event
+	var event *Event
+	var duration time.Duration
+	nested := 0
+
+	finish := func() error {
+		if event.Start.IsZero() {
+			return fmt.Errorf("Event '%s' has no DTSTART", event.Summary)
+		}
+		if event.End.IsZero() {
+			switch {
+			case duration != 0:
+				event.End = event.Start.Add(duration)
+			case event.AllDay:
+				event.End = event.Start.AddDate(0, 0, 1)
+			default:
+				event.End = event.Start
+			}
+		}
+		events = append(events, event)
+		event, duration = nil, 0
+		return nil
+	}
+
+	err := unfold(r, func(line string) error {
+		p, err := parseProperty(line)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case p.name == "BEGIN" && p.value == "VEVENT" && event == nil:
+			event = &Event{}
+			return nil
+		case event == nil:
+			return nil
+		case p.name == "BEGIN":
+			nested++
+			return nil
+		case p.name == "END" && nested > 0:
+			nested--
+			return nil
+		case nested > 0:
+			// Properties of alarms.
+			return nil
+		case p.name == "END" && p.value == "VEVENT":
+			return finish()
+		}
+
+		switch p.name {
+		case "UID":
+			event.UID = p.value
+		case "SUMMARY":
+			event.Summary = unescape(p.value)
+		case "DESCRIPTION":
+			event.Description = unescape(p.value)
+		case "LOCATION":
+			event.Location = unescape(p.value)
+		case "STATUS":
+			event.Cancelled = strings.EqualFold(p.value, "CANCELLED")
+		case "TRANSP":
+			event.Transparent = strings.EqualFold(p.value, "TRANSPARENT")
+		case "DTSTART":
+			event.Start, event.AllDay, err = parseTime(p)
+		case "DTEND":
+			event.End, _, err = parseTime(p)
+		case "DURATION":
+			duration, err = parseDuration(p.value)
+		case "RECURRENCE-ID":
+			event.recurrenceID, _, err = parseTime(p)
+		case "RRULE":
+			event.rule, err = parseRule(p.value)
+		case "EXDATE":
+			if event.exdates == nil {
+				event.exdates = make(map[int64]struct{})
+			}
+			for _, v := range strings.Split(p.value, ",") {
+				t, _, err := parseTime(&property{p.name, p.params, v})
+				if err != nil {
+					return err
+				}
+				event.exdates[t.Unix()] = struct{}{}
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("Invalid %s of event '%s': %w", p.name, event.Summary, err)
+		}
+
+		return nil
+	})
+
+	return events, err
+}
+
+// unfold calls fn with every content line, joining folded lines.
+func unfold(r io.Reader, fn func(line string) error) error {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var line string
+	for s.Scan() {
+		text := strings.TrimRight(s.Text(), "\r")
+		if strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t") {
+			line += text[1:]
+			continue
+		}
+		if line != "" {
+			if err := fn(line); err != nil {
+				return err
+			}
+		}
+		line = strings.TrimPrefix(text, "\ufeff")
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+
+	if line != "" {
+		return fn(line)
+	}
+
+	return nil
+}
+
+func parseProperty(line string) (*property, error) {
+	quoted := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		}
+		if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon == -1 {
+		return nil, fmt.Errorf("Invalid calendar line '%s'", line)
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	p := &property{
+		name:   strings.ToUpper(parts[0]),
+		params: make(map[string]string, len(parts)-1),
+		value:  line[colon+1:],
+	}
+	for _, param := range parts[1:] {
+		if k, v, ok := strings.Cut(param, "="); ok {
+			p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+
+	return p, nil
+}
+
+var unescaper = strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+
+func unescape(s string) string {
+	return unescaper.Replace(s)
+}
+
+// parseTime parses a DATE or DATE-TIME value. Times keep the location of
+// their TZID, so recurrences follow its daylight saving time, unknown
+// TZIDs (e.g. the windows names of outlook) and floating times are local.
+func parseTime(p *property) (time.Time, bool, error) {
+	v := strings.TrimSpace(p.value)
+	if p.params["VALUE"] == "DATE" || len(v) == 8 {
+		t, err := time.ParseInLocation("20060102", v, time.Local)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(v, "Z") {
+		t, err := time.Parse("20060102T150405Z", v)
+		return t, false, err
+	}
+
+	loc := time.Local
+	if tzid := p.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", v, loc)
+	return t, false, err
+}
+
+var durationRe = regexp.MustCompile(`^\+?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseDuration parses a DURATION value, e.g. PT1H30M or P1D.
+func parseDuration(s string) (time.Duration, error) {
+	m := durationRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("Invalid duration '%s'", s)
+	}
+
+	var d time.Duration
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return 0, fmt.Errorf("Invalid duration '%s'", s)
+		}
+		d += time.Duration(n) * unit
+	}
+
+	return d, nil
+}
@@ -0,0 +1,227 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPeriods bounds the expansion of rules without an end.
+const maxPeriods = 100000
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// rule is a RRULE with a DAILY, WEEKLY, MONTHLY or YEARLY frequency, an
+// INTERVAL, an end (COUNT or UNTIL) and the plain weekdays of BYDAY.
+// Rules using anything else can not be expanded.
+type rule struct {
+	freq        string
+	interval    int
+	count       int
+	until       time.Time
+	byDay       []time.Weekday
+	unsupported bool
+}
+
+func parseRule(s string) (*rule, error) {
+	r := &rule{interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		k, v, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			r.freq = strings.ToUpper(v)
+		case "INTERVAL":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("Invalid interval '%s'", v)
+			}
+			r.interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("Invalid count '%s'", v)
+			}
+			r.count = n
+		case "UNTIL":
+			t, allDay, err := parseTime(&property{value: v})
+			if err != nil {
+				return nil, fmt.Errorf("Invalid until '%s'", v)
+			}
+			if allDay {
+				t = t.AddDate(0, 0, 1).Add(-time.Second)
+			}
+			r.until = t
+		case "BYDAY":
+			for _, d := range strings.Split(v, ",") {
+				wd, ok := weekdays[strings.ToUpper(d)]
+				if !ok {
+					// e.g. 2TU, the second tuesday.
+					r.unsupported = true
+					continue
+				}
+				r.byDay = append(r.byDay, wd)
+			}
+		case "WKST":
+		default:
+			r.unsupported = true
+		}
+	}
+
+	switch r.freq {
+	case "DAILY", "WEEKLY":
+	case "MONTHLY", "YEARLY":
+		r.unsupported = r.unsupported || len(r.byDay) != 0
+	default:
+		r.unsupported = true
+	}
+
+	return r, nil
+}
+
+// monday returns the offset of a weekday from monday.
+func monday(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
+
+// each calls fn with the start of every occurrence from start until to, or
+// until fn returns false.
+func (r *rule) each(start, to time.Time, fn func(time.Time) bool) {
+	past := func(t time.Time) bool {
+		return t.After(to) || (!r.until.IsZero() && t.After(r.until))
+	}
+
+	byDay := make(map[time.Weekday]struct{}, len(r.byDay))
+	for _, wd := range r.byDay {
+		byDay[wd] = struct{}{}
+	}
+
+	days := append([]time.Weekday(nil), r.byDay...)
+	if len(days) == 0 {
+		days = []time.Weekday{start.Weekday()}
+	}
+	sort.Slice(days, func(i, j int) bool { return monday(days[i]) < monday(days[j]) })
+
+	n := 0
+	emit := func(t time.Time) bool {
+		if t.Before(start) {
+			return true
+		}
+		if past(t) {
+			return false
+		}
+		if n++; r.count != 0 && n > r.count {
+			return false
+		}
+		return fn(t)
+	}
+
+	for i := 0; i < maxPeriods; i++ {
+		switch r.freq {
+		case "DAILY":
+			t := start.AddDate(0, 0, i*r.interval)
+			if _, ok := byDay[t.Weekday()]; len(byDay) != 0 && !ok {
+				if past(t) {
+					return
+				}
+				continue
+			}
+			if !emit(t) {
+				return
+			}
+		case "WEEKLY":
+			week := start.AddDate(0, 0, 7*i*r.interval-monday(start.Weekday()))
+			for _, wd := range days {
+				if !emit(week.AddDate(0, 0, monday(wd))) {
+					return
+				}
+			}
+		case "MONTHLY", "YEARLY":
+			t := start.AddDate(0, i*r.interval, 0)
+			if r.freq == "YEARLY" {
+				t = start.AddDate(i*r.interval, 0, 0)
+			}
+			if t.Day() != start.Day() {
+				// Months without this day, e.g. the 31st.
+				if past(t) {
+					return
+				}
+				continue
+			}
+			if !emit(t) {
+				return
+			}
+		}
+	}
+}
+
+// Between returns the events and occurrences of recurring events that
+// overlap from to, in local time and sorted by start. Occurrences that were
+// moved or edited (RECURRENCE-ID) are replaced by their own event.
+func Between(events []*Event, from, to time.Time) []*Event {
+	key := func(uid string, t time.Time) string {
+		return fmt.Sprintf("%s|%d", uid, t.Unix())
+	}
+
+	overrides := make(map[string]struct{})
+	for _, e := range events {
+		if !e.recurrenceID.IsZero() {
+			overrides[key(e.UID, e.recurrenceID)] = struct{}{}
+		}
+	}
+
+	var res []*Event
+	add := func(e Event) {
+		if e.End.After(from) && e.Start.Before(to) {
+			e.Start, e.End = e.Start.Local(), e.End.Local()
+			res = append(res, &e)
+		}
+	}
+
+	for _, e := range events {
+		if e.rule == nil || e.rule.unsupported || !e.recurrenceID.IsZero() {
+			add(*e)
+			continue
+		}
+
+		d := e.End.Sub(e.Start)
+		e.rule.each(e.Start, to, func(start time.Time) bool {
+			if _, ok := e.exdates[start.Unix()]; ok {
+				return true
+			}
+			if _, ok := overrides[key(e.UID, start)]; ok {
+				return true
+			}
+			o := *e
+			o.Start, o.End = start, start.Add(d)
+			add(o)
+			return true
+		})
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Start.Before(res[j].Start) })
+
+	return res
+}
+
+// Unsupported returns the recurring events with a rule that can not be
+// expanded, Between only returns their first occurrence.
+func Unsupported(events []*Event) []*Event {
+	var res []*Event
+	for _, e := range events {
+		if e.rule != nil && e.rule.unsupported {
+			res = append(res, e)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,151 @@
+package calendar
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRuleEach(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		start string
+		to    string
+		want  []string
+	}{
+		{"daily", "FREQ=DAILY;COUNT=3", "2024-03-04 09:00", "2024-12-31 00:00", []string{"2024-03-04", "2024-03-05", "2024-03-06"}},
+		{"daily until", "FREQ=DAILY;INTERVAL=2;UNTIL=20240310T000000Z", "2024-03-04 09:00", "2024-12-31 00:00", []string{"2024-03-04", "2024-03-06", "2024-03-08"}},
+		{"daily until a date", "FREQ=DAILY;UNTIL=20240305", "2024-03-04 09:00", "2024-12-31 00:00", []string{"2024-03-04", "2024-03-05"}},
+		{"daily by day", "FREQ=DAILY;BYDAY=MO,FR;COUNT=3", "2024-03-04 09:00", "2024-12-31 00:00", []string{"2024-03-04", "2024-03-08", "2024-03-11"}},
+		{"daily up to to", "FREQ=DAILY", "2024-03-04 09:00", "2024-03-06 09:00", []string{"2024-03-04", "2024-03-05", "2024-03-06"}},
+		{"weekly", "FREQ=WEEKLY;COUNT=3", "2024-03-06 09:00", "2024-12-31 00:00", []string{"2024-03-06", "2024-03-13", "2024-03-20"}},
+		{"weekly by day", "FREQ=WEEKLY;BYDAY=WE,MO;COUNT=4", "2024-03-04 09:00", "2024-12-31 00:00", []string{"2024-03-04", "2024-03-06", "2024-03-11", "2024-03-13"}},
+		{"weekly by day before start", "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=3", "2024-03-06 09:00", "2024-12-31 00:00", []string{"2024-03-06", "2024-03-11", "2024-03-13"}},
+		{"every other week", "FREQ=WEEKLY;INTERVAL=2;COUNT=3", "2024-03-04 09:00", "2024-12-31 00:00", []string{"2024-03-04", "2024-03-18", "2024-04-01"}},
+		{"monthly skips short months", "FREQ=MONTHLY;COUNT=3", "2024-01-31 09:00", "2024-12-31 00:00", []string{"2024-01-31", "2024-03-31", "2024-05-31"}},
+		{"yearly on a leap day", "FREQ=YEARLY;COUNT=2", "2024-02-29 09:00", "2032-12-31 00:00", []string{"2024-02-29", "2028-02-29"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := parseRule(test.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.unsupported {
+				t.Fatalf("%s is unsupported", test.rule)
+			}
+
+			got := []string{}
+			r.each(date(test.start), date(test.to), func(d time.Time) bool {
+				got = append(got, d.Format("2006-01-02"))
+				return true
+			})
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("%s from %s = %v, want %v", test.rule, test.start, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		rule        string
+		unsupported bool
+		err         bool
+	}{
+		{rule: "FREQ=WEEKLY;BYDAY=MO;WKST=SU"},
+		{rule: "freq=daily;interval=3"},
+		{rule: "FREQ=MONTHLY;BYDAY=2TU", unsupported: true},
+		{rule: "FREQ=WEEKLY;BYMONTH=3", unsupported: true},
+		{rule: "FREQ=YEARLY;BYDAY=MO", unsupported: true},
+		{rule: "FREQ=HOURLY", unsupported: true},
+		{rule: "FREQ=DAILY;INTERVAL=0", err: true},
+		{rule: "FREQ=DAILY;COUNT=x", err: true},
+		{rule: "FREQ=DAILY;UNTIL=tomorrow", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.rule, func(t *testing.T) {
+			r, err := parseRule(test.rule)
+			if test.err {
+				if err == nil {
+					t.Error("parseRule did not fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.unsupported != test.unsupported {
+				t.Errorf("unsupported = %t, want %t", r.unsupported, test.unsupported)
+			}
+		})
+	}
+}
+
+func TestBetween(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:standup",
+		"SUMMARY:Standup",
+		"DTSTART:20240304T090000Z",
+		"DTEND:20240304T091500Z",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR",
+		"EXDATE:20240306T090000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:standup",
+		"SUMMARY:Standup (moved)",
+		"RECURRENCE-ID:20240308T090000Z",
+		"DTSTART:20240308T140000Z",
+		"DTEND:20240308T141500Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:retro",
+		"SUMMARY:Retro",
+		"DTSTART:20240301T150000Z",
+		"DTEND:20240301T160000Z",
+		"RRULE:FREQ=MONTHLY;BYDAY=1FR",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	events, err := Parse(strings.NewReader(ics))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.March, 12, 0, 0, 0, 0, time.UTC)
+	var got []string
+	for _, e := range Between(events, from, to) {
+		got = append(got, e.Start.UTC().Format("01-02 15:04")+" "+e.Summary)
+	}
+
+	want := []string{
+		"03-01 15:00 Retro",
+		"03-04 09:00 Standup",
+		"03-08 14:00 Standup (moved)",
+		"03-11 09:00 Standup",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Between = %v, want %v", got, want)
+	}
+
+	unsupported := Unsupported(events)
+	if len(unsupported) != 1 || unsupported[0].UID != "retro" {
+		t.Errorf("Unsupported = %v, want the retro", unsupported)
+	}
+}
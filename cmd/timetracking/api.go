@@ -1,258 +1,240 @@
 package main
 
 import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/frizinak/harvest-timetracking/forecast"
-	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/parse"
+	"github.com/frizinak/harvest-timetracking/timetracking"
 )
 
-const dateFormat = "2006-01-02"
+// api serves the reports as json to local tools that have the api token
+// but no harvest credentials:
+//
+//	GET /api/recent?days=20&group=day&worked=true&from=yesterday
+//	GET /api/summary?period=this-week&group=project
+//	GET /api/summary?from=2024-03-01&to=2024-03-31&group=client
+//	GET /api/balance?from=2024-03-01&to=today
+type api struct {
+	ctx  context.Context
+	l    *slog.Logger
+	t    *timetracking.Timetracking
+	conf *timetracking.Config
+
+	// Reports share the harvest client and config with the metrics,
+	// one at a time.
+	reports *sync.Mutex
+}
+
+// maxAPIDays limits the days of /api/recent, every day is a request to
+// harvest when it has no entries.
+const maxAPIDays = 366
 
-type Timetracking struct {
-	l            *log.Logger
-	conf         *Config
-	harvest      *harvest.Harvest
-	forecast     *forecast.Forecast
-	user         *harvest.User
-	forecastUser *forecast.User
+type apiError struct {
+	Error string `json:"error"`
 }
 
-func New(l *log.Logger, c *Config) (*Timetracking, error) {
-	aid, err := strconv.Atoi(c.AccountID)
-	if err != nil {
-		return nil, errors.New("account_id should be a numeric value")
-	}
+// badRequest is an error in the query of an api request.
+type badRequest struct {
+	err error
+}
 
-	fid := 0
-	if c.ForecastAccountID != "" {
-		fid, err = strconv.Atoi(c.ForecastAccountID)
-		if err != nil {
-			return nil, errors.New("forecast_account_id should be a numeric value or empty")
-		}
-	}
+func (b badRequest) Error() string {
+	return b.err.Error()
+}
 
-	return &Timetracking{
-		l:        l,
-		conf:     c,
-		harvest:  harvest.New(aid, c.Token),
-		forecast: forecast.New(fid, c.Token),
-	}, nil
+func (a *api) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(a.conf.API.Token)) == 1
 }
 
-func (t *Timetracking) SetUID(uid int) (err error) {
-	t.user = nil
-	if uid == 0 {
-		t.user, err = t.harvest.GetMe()
+func (a *api) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		a.write(w, http.StatusMethodNotAllowed, &apiError{"Method not allowed"})
 		return
 	}
 
-	t.user, err = t.harvest.GetUser(uid)
-	return
-}
+	if !a.authorized(r) {
+		a.write(w, http.StatusUnauthorized, &apiError{"Invalid token"})
+		return
+	}
+
+	var report func(q queryValues) (interface{}, error)
+	switch r.URL.Path {
+	case "/api/recent":
+		report = a.recent
+	case "/api/summary":
+		report = a.summary
+	case "/api/balance":
+		report = a.balance
+	default:
+		a.write(w, http.StatusNotFound, &apiError{"Not found"})
+		return
+	}
 
-func (t *Timetracking) SetForecastUID(uid int) (err error) {
-	t.forecastUser = nil
-	var me *forecast.Me
-	if uid == 0 {
-		me, err = t.forecast.GetMe()
-		if err != nil {
-			return
+	a.reports.Lock()
+	v, err := report(queryValues(r.URL.Query()))
+	a.reports.Unlock()
+	if err != nil {
+		status := http.StatusBadGateway
+		if _, ok := err.(badRequest); ok {
+			status = http.StatusBadRequest
+		} else {
+			a.l.Error("Api request failed", "path", r.URL.Path, "err", err)
 		}
-		uid = me.ID
+		a.write(w, status, &apiError{err.Error()})
+		return
 	}
 
-	t.forecastUser, err = t.forecast.GetUser(uid)
-	return
+	a.write(w, http.StatusOK, v)
 }
 
-func (t *Timetracking) User() *harvest.User {
-	return t.user
-}
+func (a *api) write(w http.ResponseWriter, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		status = http.StatusInternalServerError
+		body, _ = json.Marshal(&apiError{err.Error()})
+	}
 
-func (t *Timetracking) ForecastUser() *forecast.User {
-	return t.forecastUser
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(body, '\n'))
 }
 
-func (t *Timetracking) GetRecentDaysGrouped(
-	amount int,
-	from time.Time,
-	actualDays bool,
-	groupBy string,
-) (int, harvest.Grouped, error) {
-	groupFormat := "2006-01-02"
-	switch groupBy {
-	case groupByDay:
-	case groupByWeek:
-	case groupByMonth:
-		groupFormat = "2006-01"
-	case groupByYear:
-		groupFormat = "2006"
-	default:
-		return 0, nil, fmt.Errorf("Invalid group '%s'", groupBy)
+func (a *api) recent(q queryValues) (interface{}, error) {
+	now := a.t.Now()
+	from, err := q.date("from", now, now)
+	if err != nil {
+		return nil, err
 	}
-
-	days, entries, err := t.GetRecentDays(amount, from, actualDays)
+	days, err := q.int("days", 20, maxAPIDays)
 	if err != nil {
-		return 0, nil, err
+		return nil, err
+	}
+	worked, err := q.bool("worked")
+	if err != nil {
+		return nil, err
 	}
 
-	group := entries.Group(
-		func(e *harvest.TimeEntry) (string, bool) {
-			if e.SpentDate == nil {
-				return "", false
-			}
-
-			d := e.SpentDate.Time
-			for t.conf.Excluded(d) || t.conf.Off(d) {
-				d = d.AddDate(0, 0, -1)
-			}
-			e.SpentDate = &harvest.Date{d}
-
-			if groupBy == groupByWeek {
-				y, w := e.SpentDate.ISOWeek()
-				return fmt.Sprintf("%d|%d", y, w), true
-			}
-
-			return e.SpentDate.Format(groupFormat), true
-		},
-	)
-
-	return days, group, err
+	return a.report(reportQuery{from: from, days: days, worked: worked}, q)
 }
 
-func (t *Timetracking) GetRecentDays(
-	amount int,
-	from time.Time,
-	actualDays bool,
-) (int, harvest.TimeEntries, error) {
-	params := &harvest.TimeEntriesParams{UserID: &t.User().ID, To: &from}
-
-	entries := make(harvest.TimeEntries, 0, amount)
-	counter := make(map[string]struct{})
-
-	if actualDays {
-		d := from
-		for {
-			for t.conf.Excluded(d) || t.conf.Off(d) {
-				d = d.AddDate(0, 0, -1)
-			}
-
-			counter[d.Format(dateFormat)] = struct{}{}
-			entries = append(
-				entries,
-				&harvest.TimeEntry{
-					Hours:     harvest.DurationHours{0},
-					SpentDate: &harvest.Date{d},
-				},
-			)
-			d = d.AddDate(0, 0, -1)
-			if len(counter) == amount {
-				break
-			}
+func (a *api) summary(q queryValues) (interface{}, error) {
+	var from, to time.Time
+	if period := q.Get("period"); period != "" {
+		var err error
+		if from, to, err = a.t.Period(period, a.t.Now()); err != nil {
+			return nil, badRequest{err}
 		}
-	}
-
-outer:
-	for {
-		res, err := t.harvest.GetTimeEntries(params)
-		if err != nil {
-			return 0, nil, err
+	} else {
+		if q.Get("from") == "" {
+			return nil, badRequest{errors.New("Period or from is required")}
 		}
 
-		for _, e := range res.TimeEntries {
-			if e.SpentDate == nil {
-				continue
-			}
-
-			d := e.SpentDate.Time
-			for t.conf.Excluded(d) || t.conf.Off(d) {
-				d = d.AddDate(0, 0, -1)
-			}
-
-			df := d.Format(dateFormat)
-			if _, ok := counter[df]; !ok && len(counter) == amount {
-				break outer
-			}
-			counter[df] = struct{}{}
-			entries = append(entries, e)
+		now := a.t.Now()
+		var err error
+		if from, err = q.date("from", now, time.Time{}); err != nil {
+			return nil, err
 		}
-
-		if res.NextPage == nil {
-			break
+		if to, err = q.date("to", now, timetracking.Day(now)); err != nil {
+			return nil, err
+		}
+		if to.Before(from) {
+			return nil, badRequest{errors.New("To should not be before from")}
 		}
-
-		params.Page = res.NextPage
 	}
 
-	return len(counter), entries, nil
+	return a.report(reportQuery{from: from, to: &to}, q)
 }
 
-func (t *Timetracking) GetAssignmentsByName(projectName string) ([]*forecast.Assignment, error) {
-	if t.forecastUser == nil || t.forecastUser.ID == 0 {
-		return nil, errors.New("No forecast user set")
+func (a *api) report(rq reportQuery, q queryValues) (interface{}, error) {
+	rq.group = q.Get("group")
+	if rq.group == "" {
+		rq.group = timetracking.GroupByDay
+	}
+	if _, err := a.t.Grouper(rq.group); err != nil {
+		return nil, badRequest{err}
 	}
+	rq.capacity = a.t.Capacity()
+	rq.rounding = a.conf.Rounding
 
-	ps, err := t.forecast.GetProjects()
+	report, _, _, err := newReport(a.ctx, a.t, a.conf, rq)
+	return report, err
+}
+
+func (a *api) balance(q queryValues) (interface{}, error) {
+	now := a.t.Now()
+	today := timetracking.Day(now)
+	from, err := q.date("from", now, today.AddDate(0, 0, 1-today.Day()))
+	if err != nil {
+		return nil, err
+	}
+	to, err := q.date("to", now, today)
 	if err != nil {
 		return nil, err
 	}
+	if to.Before(from) {
+		return nil, badRequest{errors.New("To should not be before from")}
+	}
 
-	id := 0
-	for _, p := range ps.Projects {
-		if p.Name == projectName {
-			id = p.ID
-		}
+	return newBalance(a.ctx, a.t, a.conf, a.t.Capacity(), from, to)
+}
+
+// queryValues parses the query of an api request, errors are bad requests.
+type queryValues map[string][]string
+
+func (q queryValues) Get(key string) string {
+	if v := q[key]; len(v) != 0 {
+		return v[0]
 	}
+	return ""
+}
 
-	if id == 0 {
-		return nil, fmt.Errorf("Could not find project id for a project named '%s'", projectName)
+// date parses a date relative to now, in the timezone of now.
+func (q queryValues) date(key string, now, def time.Time) (time.Time, error) {
+	v := q.Get(key)
+	if v == "" {
+		return def, nil
 	}
 
-	as, err := t.forecast.GetAssignments(
-		&forecast.AssignmentsParams{
-			ProjectID: &id,
-			PersonID:  &t.forecastUser.ID,
-		},
-	)
+	d, err := parse.Date(v, now)
 	if err != nil {
-		return nil, err
+		return d, badRequest{err}
 	}
-
-	return as.Assignments, nil
+	return d, nil
 }
 
-func (t *Timetracking) GetUserProjectAssignments() ([]*harvest.UserAssignment, error) {
-	params := &harvest.UserAssignmentParams{}
-	items := make([]*harvest.UserAssignment, 0)
-	for {
-		res, err := t.harvest.GetUserAssignments(t.User().ID, params)
-		if err != nil {
-			return nil, err
-		}
-
-		items = append(items, res.Assignments...)
-		if res.NextPage == nil {
-			break
-		}
-
-		params.Page = res.NextPage
+func (q queryValues) int(key string, def, max int) (int, error) {
+	v := q.Get(key)
+	if v == "" {
+		return def, nil
 	}
 
-	return items, nil
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || n > max {
+		return 0, badRequest{fmt.Errorf("Invalid %s '%s' expected a number from 1 to %d", key, v, max)}
+	}
+	return n, nil
 }
 
-func (t *Timetracking) StartTracker(projectID, taskID int) (*harvest.TimeEntry, error) {
-	return t.harvest.CreateTimeEntry(
-		&harvest.CreateTimeEntryBody{
-			UserID:    &t.User().ID,
-			ProjectID: projectID,
-			TaskID:    taskID,
-			SpentDate: harvest.Date{time.Now()},
-		},
-	)
+func (q queryValues) bool(key string) (bool, error) {
+	v := q.Get(key)
+	if v == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, badRequest{fmt.Errorf("Invalid %s '%s' expected true or false", key, v)}
+	}
+	return b, nil
 }
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/harvesttest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func TestAPISummaryTimezone(t *testing.T) {
+	s := harvesttest.NewServer()
+	defer s.Close()
+	s.Me = &harvest.User{ID: 1, WeeklyCapacity: 40 * 3600}
+
+	conf := &timetracking.Config{
+		AccountID:   "1",
+		Timezone:    "Asia/Tokyo",
+		WeekdaysOff: []string{"saturday", "sunday"},
+		API:         &timetracking.APIConfig{Token: "token"},
+	}
+	if err := conf.Validate(); err != nil {
+		t.Skip(err)
+	}
+
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tt, err := timetracking.New(l, conf, s.Options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := tt.SetUID(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+	tokyo := tt.Location()
+	a := &api{ctx: ctx, l: l, t: tt, conf: conf, reports: &sync.Mutex{}}
+
+	tests := []struct {
+		name     string
+		from, to string
+		days     int
+	}{
+		{"week", "2024-03-04", "2024-03-08", 5},
+		{"single day", "2024-03-08", "2024-03-08", 1},
+		{"weekend", "2024-03-09", "2024-03-10", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v, err := a.summary(queryValues{"from": {test.from}, "to": {test.to}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r := v.(*Report)
+			if r.From.Location() != tokyo || r.To.Location() != tokyo {
+				t.Errorf("summary from %s to %s, want both in %s", r.From, r.To, tokyo)
+			}
+			if r.Days != test.days {
+				t.Errorf("summary(%s, %s) has %d working days, want %d", test.from, test.to, r.Days, test.days)
+			}
+			if want := timetracking.Duration(test.days) * timetracking.Duration(8*time.Hour); r.Capacity != want {
+				t.Errorf("summary(%s, %s) capacity = %s, want %s", test.from, test.to, time.Duration(r.Capacity), time.Duration(want))
+			}
+		})
+	}
+
+	if _, err := a.summary(queryValues{"to": {"2024-03-08"}}); err == nil {
+		t.Error("summary without period or from did not fail")
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"math"
+	"strings"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+// chartWidth is the width in characters of the longest bar.
+const chartWidth = 40
+
+var chartBlocks = []rune(" ▏▎▍▌▋▊▉█")
+
+// bar draws value relative to max as a bar of unicode blocks,
+// a block is divided in eighths.
+func bar(value, max float64, width int) string {
+	if max <= 0 || value <= 0 {
+		return ""
+	}
+
+	eighths := int(math.Round(value / max * float64(width*8)))
+	full, rest := eighths/8, eighths%8
+	s := strings.Repeat(string(chartBlocks[8]), full)
+	if rest != 0 {
+		s += string(chartBlocks[rest])
+	}
+	return s
+}
+
+// chart renders the hours of every group as a bar, colored like the
+// groups in the table.
+func (r *Report) chart(f *Formatting) *Table {
+	var max timetracking.Duration
+	for _, g := range r.Groups {
+		if g.Hours > max {
+			max = g.Hours
+		}
+		if timetracking.DateGroup(r.Group) && g.Target > max {
+			max = g.Target
+		}
+	}
+
+	t := NewTable().Right(2)
+	for _, g := range r.Groups {
+		label := g.Name
+		style := timetracking.StyleNone
+		if timetracking.DateGroup(r.Group) {
+			label = g.Date.Format("Mon Jan 02 2006")
+			style = groupStyle(r.Group, g)
+		}
+
+		t.Row(
+			style,
+			label,
+			bar(float64(g.Hours), float64(max), chartWidth),
+			f.Hours(g.Hours),
+		)
+	}
+
+	return t
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/parse"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandAbsence(c *Command) (int, error) {
+	var hours string
+	flag.StringVar(&hours, "hours", "", "Hours you are still expected to work that day (e.g. 4, 2:30) (default: none when sick, half a day for half-day)")
+	flag.Parse()
+
+	// absence [sick|half-day|clear [date]]
+	args := flag.Args()
+	if len(args) > 2 {
+		return 1, errors.New("Usage: absence [sick|half-day|clear [date]]")
+	}
+
+	confLoader, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	if len(args) == 0 {
+		list := make(Absences, 0, len(config.Absences))
+		for date, a := range config.Absences {
+			list = append(list, &AbsenceItem{date, a})
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].Date < list[j].Date })
+		if err := c.Render(list); err != nil {
+			return 1, err
+		}
+		return 0, nil
+	}
+
+	loc, err := c.location(config)
+	if err != nil {
+		return 1, err
+	}
+
+	date := time.Now().In(loc)
+	if len(args) > 1 {
+		if date, err = parse.Date(args[1], date); err != nil {
+			return 1, err
+		}
+	}
+	key := date.Format(timetracking.DateFormat)
+
+	raw := &timetracking.Config{}
+	if err := confLoader.Read(raw); err != nil {
+		return 1, err
+	}
+	w := raw.Writable(c.profile)
+	if w.Absences == nil {
+		w.Absences = make(map[string]*timetracking.Absence)
+	}
+
+	switch args[0] {
+	case "clear":
+		if _, ok := w.Absences[key]; !ok {
+			return 1, fmt.Errorf("No absence on %s", key)
+		}
+		delete(w.Absences, key)
+	default:
+		a := &timetracking.Absence{Type: args[0]}
+		if hours != "" {
+			d, err := parse.Hours(hours)
+			if err != nil {
+				return 1, err
+			}
+			h := d.Hours()
+			a.Hours = &h
+		}
+		if err := a.Validate(key); err != nil {
+			return 1, err
+		}
+		w.Absences[key] = a
+	}
+
+	if err := confLoader.Create(raw); err != nil {
+		return 1, err
+	}
+	c.l.Println("Saved")
+
+	return 0, nil
+}
+
+type AbsenceItem struct {
+	Date string `json:"date"`
+	*timetracking.Absence
+}
+
+type Absences []*AbsenceItem
+
+func (a Absences) Text(l *Printer) {
+	for _, item := range a {
+		if item.Hours != nil {
+			l.Printf("%s %s (%gh expected)", item.Date, item.Type, *item.Hours)
+			continue
+		}
+		l.Printf("%s %s", item.Date, item.Type)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandActuals(c *Command) (int, error) {
+	var userID int
+	var fromStr string
+	var toStr string
+	flag.IntVar(&userID, "uid", 0, "The forecast user id of the user to compare")
+	flag.StringVar(&fromStr, "from", "", "First day of the period [YYYY-MM-DD] (default: monday of this week)")
+	flag.StringVar(&toStr, "to", "", "Last day of the period [YYYY-MM-DD] (default: sunday of this week)")
+	flag.Parse()
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	from, to, err := t.Period(timetracking.PeriodThisWeek, t.Now())
+	if err != nil {
+		return 1, err
+	}
+	if fromStr != "" {
+		if from, err = t.ParseDate(fromStr); err != nil {
+			return 1, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", fromStr)
+		}
+	}
+	if toStr != "" {
+		if to, err = t.ParseDate(toStr); err != nil {
+			return 1, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", toStr)
+		}
+	}
+
+	if err := t.SetForecastUID(c.ctx, userID); err != nil {
+		return 1, err
+	}
+
+	if err := t.SetUID(c.ctx, t.ForecastUser().HarvestID); err != nil {
+		return 1, err
+	}
+
+	list, err := t.GetActuals(c.ctx, from, to)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := c.RenderReport(&Actuals{from, to, list}); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+type Actuals struct {
+	From     time.Time                      `json:"from"`
+	To       time.Time                      `json:"to"`
+	Projects []*timetracking.ProjectActuals `json:"projects"`
+}
+
+func (a *Actuals) MarshalJSON() ([]byte, error) {
+	type project struct {
+		*timetracking.ProjectActuals
+		Delta timetracking.Duration `json:"delta"`
+	}
+
+	projects := make([]project, len(a.Projects))
+	for i, p := range a.Projects {
+		projects[i] = project{p, p.Delta()}
+	}
+
+	return json.Marshal(
+		struct {
+			From     string    `json:"from"`
+			To       string    `json:"to"`
+			Projects []project `json:"projects"`
+		}{a.From.Format(timetracking.DateFormat), a.To.Format(timetracking.DateFormat), projects},
+	)
+}
+
+func (a *Actuals) Text(l *Printer) {
+	l.Printf(
+		"From: %s\nTo: %s\n\n",
+		a.From.Format("Mon Jan 02 2006"),
+		a.To.Format("Mon Jan 02 2006"),
+	)
+
+	t := NewTable("Project", "Planned", "Logged", "Delta").Right(1, 2, 3)
+	var allocated, logged timetracking.Duration
+	for _, p := range a.Projects {
+		t.Row(
+			timetracking.StyleNone,
+			p.Name,
+			l.Hours(p.Allocated),
+			l.Hours(p.Logged),
+			l.Signed(p.Delta()),
+		)
+		allocated += p.Allocated
+		logged += p.Logged
+	}
+
+	t.Row(
+		timetracking.StyleHeader,
+		"Total",
+		l.Hours(allocated),
+		l.Hours(logged),
+		l.Signed(logged-allocated),
+	)
+	t.Text(l)
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/keyring"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+const (
+	authLogin   = "login"
+	authLogout  = "logout"
+	authKeyring = "keyring"
+)
+
+func commandAuth(c *Command) (int, error) {
+	switch sub := shiftArg(); sub {
+	case authLogin:
+		return commandAuthLogin(c)
+	case authLogout:
+		return commandAuthLogout(c)
+	case authKeyring:
+		return commandAuthKeyring(c)
+	default:
+		return 1, fmt.Errorf("Usage: auth %s|%s|%s", authLogin, authLogout, authKeyring)
+	}
+}
+
+func commandAuthLogin(c *Command) (int, error) {
+	var clientID string
+	var clientSecret string
+	var port int
+	flag.StringVar(&clientID, "client-id", "", "OAuth2 client id (https://id.getharvest.com/developers)")
+	flag.StringVar(&clientSecret, "client-secret", "", "OAuth2 client secret")
+	flag.IntVar(&port, "port", 8765, "Local port for the redirect url (http://localhost:<port>/callback)")
+	flag.Parse()
+
+	confLoader, conf, err := loadConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if conf == nil {
+		return 1, nil
+	}
+
+	if conf.OAuth != nil {
+		if clientID == "" {
+			clientID = conf.OAuth.ClientID
+		}
+		if clientSecret == "" {
+			clientSecret = conf.OAuth.ClientSecret
+		}
+	}
+
+	if clientID == "" || clientSecret == "" {
+		return 1, errors.New("-client-id and -client-secret are required the first time you login")
+	}
+
+	oauth := &harvest.OAuth{ClientID: clientID, ClientSecret: clientSecret}
+
+	stateRaw := make([]byte, 16)
+	if _, err := rand.Read(stateRaw); err != nil {
+		return 1, err
+	}
+	state := hex.EncodeToString(stateRaw)
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return 1, err
+	}
+
+	type callback struct {
+		code  string
+		scope string
+		err   error
+	}
+	done := make(chan callback, 1)
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			var cb callback
+			switch {
+			case q.Get("error") != "":
+				cb.err = fmt.Errorf("Authorization failed: %s", q.Get("error"))
+			case q.Get("state") != state:
+				cb.err = errors.New("Authorization failed: invalid state")
+			case q.Get("code") == "":
+				http.NotFound(w, r)
+				return
+			default:
+				cb.code, cb.scope = q.Get("code"), q.Get("scope")
+			}
+
+			if cb.err != nil {
+				http.Error(w, cb.err.Error(), http.StatusBadRequest)
+			} else {
+				fmt.Fprintln(w, "Logged in, you can close this window.")
+			}
+
+			select {
+			case done <- cb:
+			default:
+			}
+		}),
+	}
+	go server.Serve(listener)
+	defer server.Close()
+
+	u := oauth.AuthorizeURL(state)
+	c.l.Printf("Opening your browser, if it does not open visit:\n%s", u)
+	if err := openBrowser(u); err != nil {
+		c.l.Printf("Could not open browser: %s", err)
+	}
+
+	var cb callback
+	select {
+	case cb = <-done:
+	case <-time.After(5 * time.Minute):
+		return 1, errors.New("Timed out waiting for authorization")
+	case <-c.ctx.Done():
+		return 1, c.ctx.Err()
+	}
+
+	if cb.err != nil {
+		return 1, cb.err
+	}
+
+	token, err := oauth.Exchange(c.ctx, cb.code)
+	if err != nil {
+		return 1, err
+	}
+
+	raw := &timetracking.Config{}
+	if err := confLoader.Read(raw); err != nil {
+		return 1, err
+	}
+
+	w := raw.Writable(c.profile)
+	w.OAuth = &timetracking.OAuthConfig{ClientID: clientID, ClientSecret: clientSecret}
+	w.OAuth.SetToken(token)
+	for _, s := range strings.Fields(cb.scope) {
+		switch {
+		case strings.HasPrefix(s, "harvest:"):
+			w.AccountID = strings.TrimPrefix(s, "harvest:")
+		case strings.HasPrefix(s, "forecast:"):
+			w.ForecastAccountID = strings.TrimPrefix(s, "forecast:")
+		}
+	}
+
+	if err := confLoader.Create(raw); err != nil {
+		return 1, err
+	}
+
+	c.l.Println("Logged in")
+	return 0, nil
+}
+
+func commandAuthLogout(c *Command) (int, error) {
+	flag.Parse()
+
+	confLoader, conf, err := loadConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if conf == nil {
+		return 1, nil
+	}
+
+	raw := &timetracking.Config{}
+	if err := confLoader.Read(raw); err != nil {
+		return 1, err
+	}
+
+	w := raw.Writable(c.profile)
+	if w.OAuth == nil {
+		w = raw
+	}
+	if w.OAuth != nil {
+		w.OAuth.SetToken(&harvest.Token{})
+	}
+
+	if err := confLoader.Create(raw); err != nil {
+		return 1, err
+	}
+
+	c.l.Println("Logged out")
+	return 0, nil
+}
+
+func commandAuthKeyring(c *Command) (int, error) {
+	var token string
+	flag.StringVar(&token, "token", "", "Personal access token to store (default: read from stdin)")
+	flag.Parse()
+
+	confLoader, conf, err := loadConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if conf == nil {
+		return 1, nil
+	}
+
+	if token == "" {
+		c.l.Print("Token: ")
+		restore, echoErr := noEcho()
+		token, err = bufio.NewReader(os.Stdin).ReadString('\n')
+		if echoErr == nil {
+			restore()
+			c.l.Println()
+		}
+		if err != nil && err != io.EOF {
+			return 1, err
+		}
+		token = strings.TrimSpace(token)
+	}
+
+	if token == "" {
+		return 1, errors.New("No token given")
+	}
+
+	if err := keyring.Set(keyringService, conf.AccountID, token); err != nil {
+		return 1, err
+	}
+
+	raw := &timetracking.Config{}
+	if err := confLoader.Read(raw); err != nil {
+		return 1, err
+	}
+
+	w := raw.Writable(c.profile)
+	w.Token = ""
+	w.TokenSource = timetracking.TokenSourceKeyring
+	if err := confLoader.Create(raw); err != nil {
+		return 1, err
+	}
+
+	c.l.Printf("Stored token for account %s in keyring", conf.AccountID)
+	return 0, nil
+}
+
+func openBrowser(u string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", u).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", u).Start()
+	default:
+		return exec.Command("xdg-open", u).Start()
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandBalance(c *Command) (int, error) {
+	var userID int
+	var customCapacity int
+	var fromStr string
+	var toStr string
+	flag.IntVar(&userID, "uid", 0, "The user id of the user to calculate the balance for")
+	flag.IntVar(&customCapacity, "hours", 0, "Amount of hours in a single workweek (default: from harvest api)")
+	flag.StringVar(&fromStr, "from", "", "First day of the period [YYYY-MM-DD] (default: first day of this month)")
+	flag.StringVar(&toStr, "to", "", "Last day of the period [YYYY-MM-DD] (default: today)")
+	flag.Parse()
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	now := t.Now()
+	y, m, d := now.Date()
+	to := t.Date(y, m, d)
+	from := t.Date(y, m, 1)
+	if fromStr != "" {
+		if from, err = t.ParseDate(fromStr); err != nil {
+			return 1, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", fromStr)
+		}
+	}
+	if toStr != "" {
+		if to, err = t.ParseDate(toStr); err != nil {
+			return 1, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", toStr)
+		}
+	}
+
+	if to.Before(from) {
+		return 1, fmt.Errorf("-to should not be before -from")
+	}
+
+	if err := t.SetUID(c.ctx, userID); err != nil {
+		return 1, err
+	}
+
+	if err := t.LoadCompany(c.ctx); err != nil {
+		return 1, err
+	}
+
+	capacity := t.Capacity()
+	if customCapacity != 0 {
+		capacity = timetracking.Duration(customCapacity) * timetracking.Duration(time.Hour)
+	}
+
+	balance, err := newBalance(c.ctx, t, config, capacity, from, to)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := c.RenderReport(balance); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// newBalance compares the hours tracked between from and to (inclusive)
+// with the target of those days.
+func newBalance(
+	ctx context.Context,
+	t *timetracking.Timetracking,
+	conf *timetracking.Config,
+	capacity timetracking.Duration,
+	from time.Time,
+	to time.Time,
+) (*Balance, error) {
+	t = t.WithTimeOff(ctx, from, to)
+	conf = t.Config()
+
+	entries, err := t.GetTimeEntriesBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := &Balance{
+		User:        NewReportUser(t.User(), conf.WeekTarget(capacity)),
+		From:        from,
+		To:          to,
+		WorkingDays: conf.WorkingDays(from, to),
+	}
+	balance.Required = conf.Target(capacity, from, to)
+	balance.Absences = conf.AbsenceTotals(capacity, from, to)
+	for _, e := range entries {
+		balance.Tracked += timetracking.Duration(e.Hours.Duration)
+	}
+
+	return balance, nil
+}
+
+type Balance struct {
+	User        ReportUser            `json:"user"`
+	From        time.Time             `json:"from"`
+	To          time.Time             `json:"to"`
+	WorkingDays int                   `json:"working_days"`
+	Required    timetracking.Duration `json:"required"`
+	Tracked     timetracking.Duration `json:"tracked"`
+
+	Absences []*timetracking.AbsenceTotal `json:"absences"`
+}
+
+func (b *Balance) Balance() timetracking.Duration {
+	return b.Tracked - b.Required
+}
+
+// Absence returns the hours of planned (excluded days, holidays, half days)
+// and unplanned (sick) absence.
+func (b *Balance) Absence() (planned, unplanned timetracking.Duration) {
+	for _, a := range b.Absences {
+		if a.Planned {
+			planned += a.Hours
+			continue
+		}
+		unplanned += a.Hours
+	}
+
+	return
+}
+
+func (b *Balance) MarshalJSON() ([]byte, error) {
+	type balance Balance
+	planned, unplanned := b.Absence()
+	return json.Marshal(
+		struct {
+			*balance
+			Balance   timetracking.Duration `json:"balance"`
+			Planned   timetracking.Duration `json:"planned_absence"`
+			Unplanned timetracking.Duration `json:"unplanned_absence"`
+		}{(*balance)(b), b.Balance(), planned, unplanned},
+	)
+}
+
+func (b *Balance) Text(l *Printer) {
+	l.Printf(
+		"Running for %s %s\nID: %d\nWeek: %s\nFrom: %s\nTo: %s\n",
+		b.User.FirstName,
+		b.User.LastName,
+		b.User.ID,
+		l.Hours(b.User.Capacity),
+		b.From.Format("Mon Jan 02 2006"),
+		b.To.Format("Mon Jan 02 2006"),
+	)
+
+	l.Printf("Working days: %d", b.WorkingDays)
+	l.Printf("Required: %s", l.Hours(b.Required))
+	l.Printf("Tracked: %s", l.Hours(b.Tracked))
+
+	if len(b.Absences) != 0 {
+		planned, unplanned := b.Absence()
+		l.Printf("Planned absence: %s", l.Hours(planned))
+		l.Printf("Unplanned absence: %s", l.Hours(unplanned))
+		for _, a := range b.Absences {
+			l.Printf("  %s: %.1f days, %s", a.Type, a.Days, l.Hours(a.Hours))
+		}
+	}
+
+	diff := b.Balance()
+	if diff < 0 {
+		l.Printf("Deficit: %s", l.Hours(-diff))
+		return
+	}
+	l.Printf("Surplus: %s", l.Hours(diff))
+}
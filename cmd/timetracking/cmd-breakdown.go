@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/parse"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandBreakdown(c *Command) (int, error) {
+	var userID int
+	var fromStr string
+	var toStr string
+	var noRounding bool
+	period := make(map[string]*bool, len(timetracking.Periods))
+	flag.IntVar(&userID, "uid", 0, "The user id of the user to analyze")
+	flag.StringVar(&fromStr, "from", "", "First day of the period [YYYY-MM-DD, last monday, ...] (default: first day of this month)")
+	flag.StringVar(&toStr, "to", "", "Last day of the period [YYYY-MM-DD, yesterday, ...] (default: today)")
+	flag.BoolVar(&noRounding, "no-rounding", false, "Use the tracked hours without the rounding of the config")
+	for _, p := range timetracking.Periods {
+		period[p] = flag.Bool(p, false, fmt.Sprintf("Analyze the %s date range", p))
+	}
+	flag.Parse()
+
+	var namedPeriod string
+	for p, set := range period {
+		if !*set {
+			continue
+		}
+		if namedPeriod != "" || fromStr != "" || toStr != "" {
+			return 1, fmt.Errorf("Use either -from and -to or one of -%s", strings.Join(timetracking.Periods, ", -"))
+		}
+		namedPeriod = p
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.LoadCompany(c.ctx); err != nil {
+		return 1, err
+	}
+
+	now := t.Now()
+	today := timetracking.Day(now)
+	from, to := today.AddDate(0, 0, 1-today.Day()), today
+	if namedPeriod != "" {
+		if from, to, err = t.Period(namedPeriod, now); err != nil {
+			return 1, err
+		}
+	}
+	if fromStr != "" {
+		if from, err = parse.Date(fromStr, now); err != nil {
+			return 1, err
+		}
+	}
+	if toStr != "" {
+		if to, err = parse.Date(toStr, now); err != nil {
+			return 1, err
+		}
+	}
+	from, to = timetracking.Day(from), timetracking.Day(to)
+	if to.Before(from) {
+		return 1, fmt.Errorf("-to should not be before -from")
+	}
+	prevFrom, prevTo := timetracking.PreviousPeriod(from, to)
+
+	if err := t.SetUID(c.ctx, userID); err != nil {
+		return 1, err
+	}
+
+	entries, err := t.GetTimeEntriesBetween(c.ctx, from, to)
+	if err != nil {
+		return 1, err
+	}
+	previous, err := t.GetTimeEntriesBetween(c.ctx, prevFrom, prevTo)
+	if err != nil {
+		return 1, err
+	}
+	if !noRounding {
+		entries, previous = config.Rounding.Apply(entries), config.Rounding.Apply(previous)
+	}
+
+	b := &Breakdown{
+		User:         NewReportUser(t.User(), config.WeekTarget(t.Capacity())),
+		From:         from,
+		To:           to,
+		PreviousFrom: prevFrom,
+		PreviousTo:   prevTo,
+		Tasks:        timetracking.TaskShares(entries, previous),
+	}
+	for _, s := range b.Tasks {
+		b.Total += s.Hours
+		b.Previous += s.Previous
+	}
+
+	if err := c.RenderReport(b); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// Breakdown is the share of every task in the hours of a period and how it
+// changed since the period before it.
+type Breakdown struct {
+	User         ReportUser                `json:"user"`
+	From         time.Time                 `json:"from"`
+	To           time.Time                 `json:"to"`
+	PreviousFrom time.Time                 `json:"previous_from"`
+	PreviousTo   time.Time                 `json:"previous_to"`
+	Total        timetracking.Duration     `json:"total"`
+	Previous     timetracking.Duration     `json:"previous_total"`
+	Tasks        []*timetracking.TaskShare `json:"tasks"`
+}
+
+func (b *Breakdown) Text(l *Printer) {
+	l.Printf(
+		"Running for %s %s\nFrom: %s\nTo: %s\nCompared to: %s - %s\n\n",
+		b.User.FirstName,
+		b.User.LastName,
+		b.From.Format("Mon Jan 02 2006"),
+		b.To.Format("Mon Jan 02 2006"),
+		b.PreviousFrom.Format("Mon Jan 02 2006"),
+		b.PreviousTo.Format("Mon Jan 02 2006"),
+	)
+
+	t := NewTable("Task", "Hours", "Share", "Previous", "Share", "Trend", "Projects").Right(1, 2, 3, 4, 5)
+	for _, s := range b.Tasks {
+		style := timetracking.StyleNone
+		if s.Hours == 0 {
+			style = timetracking.StyleWeekend
+		}
+		t.Row(
+			style,
+			s.Name,
+			l.Hours(s.Hours),
+			fmt.Sprintf("%.1f%%", s.Share),
+			l.Hours(s.Previous),
+			fmt.Sprintf("%.1f%%", s.PreviousShare),
+			fmt.Sprintf("%s (%+.1f)", l.Signed(s.Trend()), s.ShareTrend()),
+			strings.Join(s.Projects, ", "),
+		)
+	}
+	t.Text(l)
+
+	l.Printf("\nTotal: %s (previous: %s, %s)", l.Hours(b.Total), l.Hours(b.Previous), l.Signed(b.Total-b.Previous))
+}
+
+func (b *Breakdown) CSV(w *csv.Writer) error {
+	err := w.Write([]string{"task", "hours", "share", "previous_hours", "previous_share", "trend", "share_trend", "projects"})
+	if err != nil {
+		return err
+	}
+
+	for _, s := range b.Tasks {
+		err := w.Write(
+			[]string{
+				s.Name,
+				strconv.FormatFloat(time.Duration(s.Hours).Hours(), 'f', 2, 64),
+				strconv.FormatFloat(s.Share, 'f', 2, 64),
+				strconv.FormatFloat(time.Duration(s.Previous).Hours(), 'f', 2, 64),
+				strconv.FormatFloat(s.PreviousShare, 'f', 2, 64),
+				strconv.FormatFloat(time.Duration(s.Trend()).Hours(), 'f', 2, 64),
+				strconv.FormatFloat(s.ShareTrend(), 'f', 2, 64),
+				strings.Join(s.Projects, ";"),
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandBudget(c *Command) (int, error) {
+	var project string
+	var days int
+	flag.StringVar(&project, "project", "", "Only show this project (name or id) (default: all active projects with a budget)")
+	flag.IntVar(&days, "days", 28, "Amount of days the burn rate is calculated over")
+	flag.Parse()
+
+	if days < 1 {
+		return 1, errors.New("-days should be at least 1")
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	active := true
+	all, err := t.GetProjects(c.ctx, &active)
+	if err != nil {
+		return 1, err
+	}
+
+	projects := make([]*harvest.Project, 0, len(all))
+	for _, p := range all {
+		if project != "" && !strings.EqualFold(p.Name, project) && strconv.Itoa(p.ID) != project {
+			continue
+		}
+		if p.Budget == 0 && p.CostBudget == 0 {
+			continue
+		}
+		projects = append(projects, p)
+	}
+
+	if len(projects) == 0 {
+		if project != "" {
+			return 1, fmt.Errorf("Could not find an active project with a budget named '%s'", project)
+		}
+		return 1, errors.New("No active projects with a budget")
+	}
+
+	today := t.Today()
+	rows := make(Budgets, len(projects))
+	err = harvest.Parallel(c.ctx, len(projects), timetracking.TeamWorkers, func(ctx context.Context, i int) error {
+		p := projects[i]
+		var from *time.Time
+		if p.BudgetIsMonthly {
+			f := t.Date(today.Year(), today.Month(), 1)
+			from = &f
+		}
+
+		entries, err := t.GetProjectEntries(ctx, p.ID, from)
+		if err != nil {
+			return err
+		}
+
+		rows[i] = burnDown(p, entries, today, days)
+		return nil
+	})
+	if err != nil {
+		return 1, err
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Percent > rows[j].Percent })
+
+	if err := c.RenderReport(rows); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// burnDown calculates how much of the budget of p is used and when it runs out
+// at the rate it was used at over the last days.
+func burnDown(p *harvest.Project, entries harvest.TimeEntries, today time.Time, days int) *BudgetRow {
+	r := &BudgetRow{
+		ID:       p.ID,
+		Project:  p.Name,
+		Client:   p.Client.Name,
+		BudgetBy: p.BudgetBy,
+		Monthly:  p.BudgetIsMonthly,
+		Budget:   float64(p.Budget),
+		Money:    p.MoneyBudget(),
+		Currency: p.Client.Currency,
+	}
+	if (r.Money || p.Budget == 0) && p.CostBudget != 0 {
+		r.Budget, r.Money = float64(p.CostBudget), true
+	}
+
+	since := today.AddDate(0, 0, -days).Format(timetracking.DateFormat)
+	var recent float64
+	for _, e := range entries {
+		used := e.Hours.Hours()
+		if r.Money {
+			if !e.Billable {
+				continue
+			}
+			used *= e.BillableRate
+		}
+
+		r.Used += used
+		if e.SpentDate != nil && e.SpentDate.Format(timetracking.DateFormat) > since {
+			recent += used
+		}
+	}
+
+	if r.Budget != 0 {
+		r.Percent = 100 * r.Used / r.Budget
+	}
+	r.PerDay = recent / float64(days)
+
+	if left := r.Budget - r.Used; left > 0 && r.PerDay > 0 {
+		d := today.AddDate(0, 0, int(math.Ceil(left/r.PerDay)))
+		r.Exhausted = &d
+	}
+
+	return r
+}
+
+type BudgetRow struct {
+	ID        int        `json:"id"`
+	Project   string     `json:"project"`
+	Client    string     `json:"client"`
+	BudgetBy  string     `json:"budget_by"`
+	Monthly   bool       `json:"monthly"`
+	Money     bool       `json:"money"`
+	Currency  string     `json:"currency,omitempty"`
+	Budget    float64    `json:"budget"`
+	Used      float64    `json:"used"`
+	Percent   float64    `json:"percent"`
+	PerDay    float64    `json:"per_day"`
+	Exhausted *time.Time `json:"exhausted"`
+}
+
+func (r *BudgetRow) format(f *Formatting, v float64) string {
+	if r.Money {
+		return f.Money(v, r.Currency)
+	}
+	return f.Hours(timetracking.Duration(v * float64(time.Hour)))
+}
+
+func (r *BudgetRow) exhausted() string {
+	switch {
+	case r.Used >= r.Budget:
+		return "exhausted"
+	case r.Exhausted == nil:
+		return "-"
+	}
+	return r.Exhausted.Format(timetracking.DateFormat)
+}
+
+type Budgets []*BudgetRow
+
+func (b Budgets) Text(l *Printer) {
+	t := NewTable("ID", "Project", "Budget", "Used", "%", "Per day", "Exhausted").Right(2, 3, 4, 5)
+	for _, r := range b {
+		name := r.Project
+		if r.Monthly {
+			name += " (monthly)"
+		}
+		t.Row(
+			timetracking.StyleNone,
+			strconv.Itoa(r.ID),
+			name,
+			r.format(l.Formatting, r.Budget),
+			r.format(l.Formatting, r.Used),
+			fmt.Sprintf("%.1f%%", r.Percent),
+			r.format(l.Formatting, r.PerDay),
+			r.exhausted(),
+		)
+	}
+	t.Text(l)
+}
+
+func (b Budgets) CSV(w *csv.Writer) error {
+	err := w.Write([]string{"id", "project", "client", "budget_by", "monthly", "budget", "used", "percent", "per_day", "exhausted"})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range b {
+		err := w.Write(
+			[]string{
+				strconv.Itoa(r.ID),
+				r.Project,
+				r.Client,
+				r.BudgetBy,
+				strconv.FormatBool(r.Monthly),
+				strconv.FormatFloat(r.Budget, 'f', 2, 64),
+				strconv.FormatFloat(r.Used, 'f', 2, 64),
+				strconv.FormatFloat(r.Percent, 'f', 2, 64),
+				strconv.FormatFloat(r.PerDay, 'f', 2, 64),
+				r.exhausted(),
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
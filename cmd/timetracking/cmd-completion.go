@@ -0,0 +1,228 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const (
+	completionBash     = "bash"
+	completionZsh      = "zsh"
+	completionFish     = "fish"
+	completionProjects = "projects"
+	completionTasks    = "tasks"
+)
+
+func commandCompletion(c *Command) (int, error) {
+	switch sub := shiftArg(); sub {
+	case completionBash, completionZsh, completionFish:
+		flag.Parse()
+		return 0, completionScripts.ExecuteTemplate(c.l.Writer(), sub, c.completionCommands())
+	case completionProjects, completionTasks:
+		return commandCompletionNames(c, sub)
+	default:
+		return 1, fmt.Errorf(
+			"Usage: completion %s|%s|%s",
+			completionBash,
+			completionZsh,
+			completionFish,
+		)
+	}
+}
+
+// commandCompletionNames prints the project or task names the shell
+// completion scripts complete -project, -task and start with.
+func commandCompletionNames(c *Command, sub string) (int, error) {
+	var projectName string
+	flag.StringVar(&projectName, "project", "", "Only list the tasks of this project")
+	flag.Parse()
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.newClient(config)
+	if err != nil {
+		return 1, err
+	}
+
+	names, err := t.TaskNames(c.ctx)
+	if err != nil {
+		return 1, err
+	}
+
+	var list []string
+	switch sub {
+	case completionProjects:
+		for project := range names {
+			list = append(list, project)
+		}
+	case completionTasks:
+		unique := make(map[string]struct{})
+		for project, tasks := range names {
+			if projectName != "" && !strings.EqualFold(project, projectName) {
+				continue
+			}
+			for _, task := range tasks {
+				unique[task] = struct{}{}
+			}
+		}
+		for task := range unique {
+			list = append(list, task)
+		}
+	}
+
+	sort.Strings(list)
+	for _, name := range list {
+		c.l.Println(name)
+	}
+
+	return 0, nil
+}
+
+type completionCommand struct {
+	Name        string
+	Description string
+}
+
+func (c *Command) completionCommands() []completionCommand {
+	cmds := make([]completionCommand, 0, len(c.commands))
+	for name, cmd := range c.commands {
+		cmds = append(cmds, completionCommand{name, cmd.Description})
+	}
+
+	sort.Slice(cmds, func(i, j int) bool {
+		return cmds[i].Name < cmds[j].Name
+	})
+
+	return cmds
+}
+
+var completionScripts = template.Must(template.New("completion").Funcs(
+	template.FuncMap{
+		"shquote": func(s string) string {
+			return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+		},
+		"fishquote": func(s string) string {
+			return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+		},
+	},
+).Parse(`
+{{- define "bash" -}}
+# bash completion for timetracking
+# source <(timetracking completion bash)
+
+_timetracking_project() {
+	local i
+	for ((i = 2; i < ${#COMP_WORDS[@]} - 1; i++)); do
+		if [ "${COMP_WORDS[i]}" = "-project" ]; then
+			printf '%s' "${COMP_WORDS[i+1]}"
+			return
+		fi
+	done
+}
+
+_timetracking() {
+	local cur="${COMP_WORDS[COMP_CWORD]}"
+	local prev="${COMP_WORDS[COMP_CWORD-1]}"
+	local names
+	COMPREPLY=()
+
+	if [ "$COMP_CWORD" -eq 1 ]; then
+		COMPREPLY=($(compgen -W "{{range .}}{{.Name}} {{end}}" -- "$cur"))
+		return
+	fi
+
+	local IFS=$'\n'
+
+	case "${COMP_WORDS[1]} $prev" in
+	"log -project")
+		names="$(timetracking completion projects 2>/dev/null)"
+		;;
+	"log -task")
+		names="$(timetracking completion tasks -project "$(_timetracking_project)" 2>/dev/null)"
+		;;
+	start\ *)
+		names="$(timetracking completion tasks 2>/dev/null)"
+		;;
+	*)
+		return
+		;;
+	esac
+
+	COMPREPLY=($(compgen -W "$names" -- "$cur"))
+	if [ "${#COMPREPLY[@]}" -gt 0 ]; then
+		COMPREPLY=($(printf '%q\n' "${COMPREPLY[@]}"))
+	fi
+}
+
+complete -F _timetracking timetracking
+{{end}}
+
+{{- define "zsh" -}}
+#compdef timetracking
+# zsh completion for timetracking
+# source <(timetracking completion zsh)
+
+_timetracking() {
+	local -a names
+	if (( CURRENT == 2 )); then
+		names=({{range .}}
+			{{shquote (printf "%s:%s" .Name .Description)}}{{end}}
+		)
+		_describe 'command' names
+		return
+	fi
+
+	case "${words[2]} ${words[CURRENT-1]}" in
+	"log -project")
+		names=("${(@f)$(timetracking completion projects 2>/dev/null)}")
+		;;
+	"log -task")
+		local project="${words[${words[(i)-project]}+1]}"
+		names=("${(@f)$(timetracking completion tasks -project "$project" 2>/dev/null)}")
+		;;
+	start\ *)
+		names=("${(@f)$(timetracking completion tasks 2>/dev/null)}")
+		;;
+	*)
+		return
+		;;
+	esac
+
+	compadd -a names
+}
+
+compdef _timetracking timetracking
+{{end}}
+
+{{- define "fish" -}}
+# fish completion for timetracking
+# timetracking completion fish | source
+
+function __timetracking_tasks
+	set -l args (commandline -opc)
+	if set -l i (contains -i -- -project $args)
+		timetracking completion tasks -project $args[(math $i + 1)] 2>/dev/null
+	else
+		timetracking completion tasks 2>/dev/null
+	end
+end
+
+complete -c timetracking -f
+{{- range .}}
+complete -c timetracking -n __fish_use_subcommand -a {{.Name}} -d {{fishquote .Description}}
+{{- end}}
+complete -c timetracking -n '__fish_seen_subcommand_from log' -o project -x -a '(timetracking completion projects 2>/dev/null)'
+complete -c timetracking -n '__fish_seen_subcommand_from log' -o task -x -a '(__timetracking_tasks)'
+complete -c timetracking -n '__fish_seen_subcommand_from start' -a '(timetracking completion tasks 2>/dev/null)'
+{{end}}
+`))
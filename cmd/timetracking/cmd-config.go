@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+const configPathSub = "path"
+
+func commandConfig(c *Command) (int, error) {
+	switch sub := shiftArg(); sub {
+	case configPathSub:
+		return commandConfigPath(c)
+	default:
+		return 1, fmt.Errorf("Usage: config %s", configPathSub)
+	}
+}
+
+func commandConfigPath(c *Command) (int, error) {
+	flag.Parse()
+
+	path, source, err := findConfig()
+	if err != nil {
+		return 1, err
+	}
+
+	_, err = os.Stat(path)
+	return 0, c.Render(&ConfigPath{path, source, err == nil})
+}
+
+type ConfigPath struct {
+	Path   string `json:"path"`
+	Source string `json:"source"`
+	Exists bool   `json:"exists"`
+}
+
+func (c *ConfigPath) Text(l *Printer) {
+	if !c.Exists {
+		l.Printf("%s (%s, does not exist yet)", c.Path, c.Source)
+		return
+	}
+	l.Printf("%s (%s)", c.Path, c.Source)
+}
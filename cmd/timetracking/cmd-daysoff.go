@@ -1,9 +1,12 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"sort"
 	"time"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
 )
 
 func commandDaysOff(c *Command) (int, error) {
@@ -14,12 +17,12 @@ func commandDaysOff(c *Command) (int, error) {
 	flag.IntVar(&userID, "uid", 0, "The forecast user id of the user to fetch time-off entries for")
 	flag.StringVar(&projectName, "project", "Time Off", "Name of the 'Time Off' project")
 	flag.IntVar(&hoursInt, "hours", 7, "Amount of hours 'Time Off' should last for it to be an entire day off.")
-	flag.BoolVar(&save, "save", false, "Save in ~/.timetracking")
+	flag.BoolVar(&save, "save", false, "Save in the config file")
 	flag.Parse()
 
 	hours := time.Hour * time.Duration(hoursInt)
 
-	confLoader, config, err := getConfig(c.l)
+	confLoader, config, err := getConfig(c.l, c.profile)
 	if err != nil {
 		return 1, err
 	}
@@ -28,16 +31,16 @@ func commandDaysOff(c *Command) (int, error) {
 		return 1, nil
 	}
 
-	t, err := New(c.l, config)
+	t, err := c.New(config)
 	if err != nil {
 		return 1, err
 	}
 
-	if err := t.SetForecastUID(userID); err != nil {
+	if err := t.SetForecastUID(c.ctx, userID); err != nil {
 		return 1, err
 	}
 
-	r, err := t.GetAssignmentsByName(projectName)
+	r, err := t.GetAssignmentsByName(c.ctx, projectName)
 	if err != nil {
 		return 1, err
 	}
@@ -70,13 +73,13 @@ func commandDaysOff(c *Command) (int, error) {
 	)
 
 	if save {
-		config = &Config{}
-		if err := confLoader.Read(config); err != nil {
+		raw := &timetracking.Config{}
+		if err := confLoader.Read(raw); err != nil {
 			return 1, err
 		}
 		unique := make(map[string]struct{}, len(off))
 		for _, o := range off {
-			unique[o.Format(dateFormat)] = struct{}{}
+			unique[o.Format(timetracking.DateFormat)] = struct{}{}
 		}
 		for _, o := range config.ExcludedDates {
 			unique[o] = struct{}{}
@@ -91,17 +94,34 @@ func commandDaysOff(c *Command) (int, error) {
 			func(i, j int) bool { return uniqueSorted[i] < uniqueSorted[j] },
 		)
 
-		config.ExcludedDates = uniqueSorted
-		if err = confLoader.Create(config); err != nil {
+		raw.Writable(c.profile).ExcludedDates = uniqueSorted
+		if err = confLoader.Create(raw); err != nil {
 			return 1, err
 		}
 		c.l.Println("Saved")
 		return 0, nil
 	}
 
-	for _, d := range off {
-		c.l.Println(d.Format(dateFormat))
+	if err := c.Render(Dates(off)); err != nil {
+		return 1, err
 	}
 
 	return 0, nil
 }
+
+type Dates []time.Time
+
+func (d Dates) Text(l *Printer) {
+	for _, t := range d {
+		l.Println(t.Format(timetracking.DateFormat))
+	}
+}
+
+func (d Dates) MarshalJSON() ([]byte, error) {
+	s := make([]string, len(d))
+	for i, t := range d {
+		s[i] = t.Format(timetracking.DateFormat)
+	}
+
+	return json.Marshal(s)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandEdit(c *Command) (int, error) {
+	arg := shiftArg()
+	var notes string
+	var hours float64
+	var date string
+	flag.StringVar(&notes, "notes", "", "New notes")
+	flag.Float64Var(&hours, "hours", 0, "New amount of hours")
+	flag.StringVar(&date, "date", "", "New date [YYYY-MM-DD]")
+	flag.Parse()
+	if arg == "" {
+		arg = flag.Arg(0)
+	}
+
+	body := &harvest.UpdateTimeEntryBody{}
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "notes":
+			body.Notes, set = &notes, true
+		case "hours":
+			body.Hours, set = &hours, true
+		}
+	})
+
+	if date != "" {
+		d, err := time.Parse(timetracking.DateFormat, date)
+		if err != nil {
+			return 1, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", date)
+		}
+		body.SpentDate, set = &harvest.Date{d}, true
+	}
+
+	if !set {
+		return 1, errors.New("Nothing to change, use -notes, -hours or -date")
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	id, err := entryID(c, t, arg)
+	if err != nil {
+		return 1, err
+	}
+
+	entry, err := t.UpdateEntry(c.ctx, id, body)
+	if err != nil {
+		return 1, err
+	}
+
+	c.l.Printf(
+		"Updated %d: %s %s - %s",
+		entry.ID,
+		entry.Project.Name,
+		entry.Task.Name,
+		c.formatting().Hours(timetracking.Duration(entry.Hours.Duration)),
+	)
+
+	return 0, nil
+}
+
+func commandDelete(c *Command) (int, error) {
+	arg := shiftArg()
+	var yes bool
+	flag.BoolVar(&yes, "yes", false, "Delete without asking")
+	flag.Parse()
+	if arg == "" {
+		arg = flag.Arg(0)
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	id, err := entryID(c, t, arg)
+	if err != nil {
+		return 1, err
+	}
+
+	if !yes {
+		e, err := t.GetEntry(c.ctx, id)
+		if err != nil {
+			return 1, err
+		}
+		ok, err := confirm(c, fmt.Sprintf(
+			"Delete %d: %s %s / %s - %s %s?",
+			e.ID,
+			e.SpentDate.Format("Mon Jan 02 2006"),
+			e.Project.Name,
+			e.Task.Name,
+			c.formatting().Hours(timetracking.Duration(e.Hours.Duration)),
+			e.Notes,
+		))
+		if err != nil {
+			return 1, err
+		}
+		if !ok {
+			c.l.Println("Not deleted")
+			return 1, nil
+		}
+	}
+
+	if err := t.DeleteEntry(c.ctx, id); err != nil {
+		return 1, err
+	}
+
+	c.l.Printf("Deleted %d", id)
+	return 0, nil
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+const (
+	expensesList   = "list"
+	expensesCreate = "create"
+)
+
+func commandExpenses(c *Command) (int, error) {
+	switch sub := shiftArg(); sub {
+	case expensesList:
+		return commandExpensesList(c)
+	case expensesCreate:
+		return commandExpensesCreate(c)
+	default:
+		return 1, fmt.Errorf("Usage: expenses %s|%s", expensesList, expensesCreate)
+	}
+}
+
+func commandExpensesList(c *Command) (int, error) {
+	var customFrom string
+	var customTo string
+	flag.StringVar(&customFrom, "from", "", "First day [YYYY-MM-DD] (default: first day of this month)")
+	flag.StringVar(&customTo, "to", "", "Last day [YYYY-MM-DD] (default: last day of this month)")
+	flag.Parse()
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	from, to, err := t.Period(timetracking.PeriodThisMonth, t.Now())
+	if err != nil {
+		return 1, err
+	}
+	if customFrom != "" {
+		if from, err = t.ParseDate(customFrom); err != nil {
+			return 1, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", customFrom)
+		}
+	}
+	if customTo != "" {
+		if to, err = t.ParseDate(customTo); err != nil {
+			return 1, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", customTo)
+		}
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	expenses, err := t.GetExpensesBetween(c.ctx, from, to)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := c.Render(Expenses(expenses)); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+func commandExpensesCreate(c *Command) (int, error) {
+	var project string
+	var category string
+	var date string
+	var cost float64
+	var units float64
+	var notes string
+	var receipt string
+	var billable string
+	flag.StringVar(&project, "project", "", "The project (name or id)")
+	flag.StringVar(&category, "category", "", "The expense category (name or id)")
+	flag.StringVar(&date, "date", "", "Date of the expense [YYYY-MM-DD] (default: today)")
+	flag.Float64Var(&cost, "cost", 0, "Total cost of the expense")
+	flag.Float64Var(&units, "units", 0, "Amount of units, for unit based categories (e.g. mileage)")
+	flag.StringVar(&notes, "notes", "", "Notes")
+	flag.StringVar(&receipt, "receipt", "", "Path to a receipt to upload")
+	flag.StringVar(&billable, "billable", "", "Whether the expense is billable (true|false, default: harvest's default)")
+	flag.Parse()
+
+	if project == "" || category == "" {
+		return 1, errors.New("-project and -category are required")
+	}
+
+	if (cost == 0) == (units == 0) {
+		return 1, errors.New("Specify either -cost or -units")
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	spent := t.Today()
+	if date != "" {
+		if spent, err = t.ParseDate(date); err != nil {
+			return 1, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", date)
+		}
+	}
+
+	body := &harvest.CreateExpenseBody{SpentDate: spent}
+	if cost != 0 {
+		body.TotalCost = &cost
+	}
+	if units != 0 {
+		body.Units = &units
+	}
+	if notes != "" {
+		body.Notes = &notes
+	}
+	if billable != "" {
+		b, err := strconv.ParseBool(billable)
+		if err != nil {
+			return 1, fmt.Errorf("Invalid -billable '%s' expected true or false", billable)
+		}
+		body.Billable = &b
+	}
+
+	if receipt != "" {
+		f, err := os.Open(receipt)
+		if err != nil {
+			return 1, err
+		}
+		defer f.Close()
+		body.Receipt = f
+		body.ReceiptName = filepath.Base(receipt)
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	p, err := t.FindProject(c.ctx, project)
+	if err != nil {
+		return 1, err
+	}
+	body.ProjectID = p.ID
+
+	cat, err := t.FindExpenseCategory(c.ctx, category)
+	if err != nil {
+		return 1, err
+	}
+	body.ExpenseCategoryID = cat.ID
+
+	expense, err := t.CreateExpense(c.ctx, body)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := c.Render(Expenses{expense}); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+type Expenses []*harvest.Expense
+
+func (e Expenses) Text(l *Printer) {
+	t := NewTable("ID", "Date", "Project", "Category", "Cost", "Notes").Right(4)
+	for _, expense := range e {
+		t.Row(
+			timetracking.StyleNone,
+			strconv.Itoa(expense.ID),
+			formatDate(expense.SpentDate),
+			expense.Project.Name,
+			expense.ExpenseCategory.Name,
+			fmt.Sprintf("%.2f", expense.TotalCost),
+			expense.Notes,
+		)
+	}
+	t.Text(l)
+
+	l.Println()
+	summarizeExpenses(e).Text(l)
+}
+
+func (e Expenses) CSV(w *csv.Writer) error {
+	err := w.Write([]string{"id", "date", "client", "project", "category", "units", "cost", "billable", "notes"})
+	if err != nil {
+		return err
+	}
+
+	for _, expense := range e {
+		err := w.Write(
+			[]string{
+				strconv.Itoa(expense.ID),
+				formatDate(expense.SpentDate),
+				expense.Client.Name,
+				expense.Project.Name,
+				expense.ExpenseCategory.Name,
+				strconv.FormatFloat(expense.Units, 'f', -1, 64),
+				strconv.FormatFloat(expense.TotalCost, 'f', 2, 64),
+				strconv.FormatBool(expense.Billable),
+				expense.Notes,
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type ExpenseCategoryTotal struct {
+	Name  string  `json:"name"`
+	Count int     `json:"count"`
+	Total float64 `json:"total"`
+}
+
+type ExpenseSummary struct {
+	Categories []*ExpenseCategoryTotal `json:"categories"`
+	Total      float64                 `json:"total"`
+	Billable   float64                 `json:"billable"`
+}
+
+func summarizeExpenses(expenses []*harvest.Expense) *ExpenseSummary {
+	s := &ExpenseSummary{Categories: make([]*ExpenseCategoryTotal, 0)}
+	lookup := make(map[int]*ExpenseCategoryTotal)
+	for _, e := range expenses {
+		cat, ok := lookup[e.ExpenseCategory.ID]
+		if !ok {
+			cat = &ExpenseCategoryTotal{Name: e.ExpenseCategory.Name}
+			lookup[e.ExpenseCategory.ID] = cat
+			s.Categories = append(s.Categories, cat)
+		}
+		cat.Count++
+		cat.Total += e.TotalCost
+		s.Total += e.TotalCost
+		if e.Billable {
+			s.Billable += e.TotalCost
+		}
+	}
+
+	sort.SliceStable(s.Categories, func(i, j int) bool {
+		return s.Categories[i].Total > s.Categories[j].Total
+	})
+
+	return s
+}
+
+func (s *ExpenseSummary) Text(l *Printer) {
+	t := NewTable("Category", "Count", "Total").Right(1, 2)
+	for _, c := range s.Categories {
+		t.Row(timetracking.StyleNone, c.Name, fmt.Sprintf("%dx", c.Count), fmt.Sprintf("%.2f", c.Total))
+	}
+	t.Text(l)
+	l.Printf("Expenses: %.2f (billable %.2f)", s.Total, s.Billable)
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandExport(c *Command) (int, error) {
+	var userID int
+	var days int
+	var customDate string
+	var alias string
+	var noRounding bool
+	var stream bool
+	var filters entryFilter
+	period := make(map[string]*bool, len(timetracking.Periods))
+	flag.IntVar(&userID, "uid", 0, "The user id of the user to export time entries for")
+	flag.IntVar(&days, "days", 20, "Amount of days to export time entries for")
+	flag.StringVar(&customDate, "from", "", "Custom date to start at [YYYY-MM-DD]")
+	flag.BoolVar(&noRounding, "no-rounding", false, "Export the tracked hours without the rounding of the config")
+	flag.StringVar(&alias, "alias", "", "Only export entries of the project (and task) of this alias")
+	filters.Flags()
+	flag.BoolVar(&stream, "stream", false, "Write entries as they are fetched (text, json lines or csv), for large periods")
+	for _, p := range timetracking.Periods {
+		period[p] = flag.Bool(p, false, fmt.Sprintf("Export the %s date range", p))
+	}
+	flag.Parse()
+
+	var namedPeriod string
+	for p, set := range period {
+		if !*set {
+			continue
+		}
+		if namedPeriod != "" {
+			return 1, fmt.Errorf("Only one of -%s can be used", strings.Join(timetracking.Periods, ", -"))
+		}
+		namedPeriod = p
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := registerExportFormats(c, config); err != nil {
+		return 1, err
+	}
+
+	filter, err := filters.Filter(nil)
+	if err != nil {
+		return 1, err
+	}
+	if alias != "" {
+		a, err := config.Alias(alias)
+		if err != nil {
+			return 1, err
+		}
+		prev := filter
+		filter = func(e *harvest.TimeEntry) bool {
+			return a.Matches(e) && (prev == nil || prev(e))
+		}
+	}
+
+	from := t.Now()
+	if customDate != "" {
+		f, err := t.ParseDate(customDate)
+		if err != nil {
+			return 1, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", customDate)
+		}
+		from = f
+	}
+
+	if err := t.SetUID(c.ctx, userID); err != nil {
+		return 1, err
+	}
+
+	if err := t.LoadCompany(c.ctx); err != nil {
+		return 1, err
+	}
+
+	var entries harvest.TimeEntries
+	var rangeFrom, rangeTo time.Time
+	if namedPeriod != "" {
+		if rangeFrom, rangeTo, err = t.Period(namedPeriod, t.Now()); err != nil {
+			return 1, err
+		}
+	}
+
+	if stream {
+		if len(c.outputs) != 0 {
+			return 1, errors.New("-stream writes a single -format, it can not be combined with -output")
+		}
+		rounding := config.Rounding
+		if noRounding {
+			rounding = nil
+		}
+		if namedPeriod == "" {
+			rangeFrom, rangeTo = config.FirstWorkingDay(from, days), from
+		}
+		if err := streamExport(c, t, rounding, filter, rangeFrom, rangeTo); err != nil {
+			return 1, err
+		}
+		return 0, nil
+	}
+
+	if namedPeriod != "" {
+		_, entries, err = t.GetRange(c.ctx, rangeFrom, rangeTo, true)
+	} else {
+		_, entries, err = t.GetRecentDays(c.ctx, days, from, true)
+	}
+	if err != nil {
+		return 1, err
+	}
+
+	export := make(ExportEntries, 0, len(entries))
+	for _, e := range entries {
+		if e.ID == 0 || (filter != nil && !filter(e)) {
+			continue
+		}
+		export = append(export, e)
+	}
+
+	if !noRounding {
+		export = ExportEntries(config.Rounding.Apply(harvest.TimeEntries(export)))
+	}
+
+	as := make(map[string]interface{}, 1)
+	if c.wants(formatPDF) {
+		tmpl, err := loadTimesheetTemplate(c.l)
+		if err != nil {
+			return 1, err
+		}
+
+		if namedPeriod == "" {
+			rangeFrom, rangeTo = from, from
+			for _, e := range export {
+				if e.SpentDate == nil {
+					continue
+				}
+				if e.SpentDate.Before(rangeFrom) {
+					rangeFrom = e.SpentDate.Time
+				}
+				if e.SpentDate.After(rangeTo) {
+					rangeTo = e.SpentDate.Time
+				}
+			}
+		}
+		as[formatPDF] = NewTimesheet(export, rangeFrom, rangeTo, tmpl)
+	}
+
+	if err := c.RenderAs(export, as); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// streamExport writes every entry between from and to as soon as its page
+// is fetched instead of rendering them all at once. json is written as
+// one entry per line.
+func streamExport(
+	c *Command,
+	t *timetracking.Timetracking,
+	rounding *timetracking.Rounding,
+	filter harvest.Filter,
+	from time.Time,
+	to time.Time,
+) error {
+	if rounding != nil && rounding.Per != "" && rounding.Per != timetracking.RoundPerEntry {
+		return fmt.Errorf("Rounding per %s can not be streamed, use -no-rounding", rounding.Per)
+	}
+
+	var write func(*harvest.TimeEntry) error
+	flush := func() error { return nil }
+	switch c.format {
+	case formatText:
+		l := &Printer{c.l, c.formatting()}
+		write = func(e *harvest.TimeEntry) error {
+			exportText(l, e)
+			return nil
+		}
+	case formatJSON:
+		enc := json.NewEncoder(c.l.Writer())
+		write = func(e *harvest.TimeEntry) error { return enc.Encode(e) }
+	default:
+		newFormatter, ok := c.exportFormats()[c.format]
+		if !ok {
+			return fmt.Errorf("Can not stream %s, use %s, %s or a csv format", c.format, formatText, formatJSON)
+		}
+		f := newFormatter()
+		w := csv.NewWriter(c.l.Writer())
+		if err := w.Write(f.Header()); err != nil {
+			return err
+		}
+		write = func(e *harvest.TimeEntry) error { return w.Write(f.Record(e)) }
+		flush = func() error {
+			w.Flush()
+			return w.Error()
+		}
+	}
+
+	err := t.ForEachTimeEntryBetween(c.ctx, from, to, func(e *harvest.TimeEntry) error {
+		if e.SpentDate == nil || (filter != nil && !filter(e)) {
+			return nil
+		}
+		if rounding != nil {
+			e.Hours.Duration = rounding.Round(e.Hours.Duration)
+		}
+		return write(e)
+	})
+	if ferr := flush(); err == nil {
+		err = ferr
+	}
+
+	return err
+}
+
+type ExportEntries harvest.TimeEntries
+
+func exportText(l *Printer, entry *harvest.TimeEntry) {
+	l.Printf(
+		"%s - %5s - [%s] %s %s: %s",
+		entry.SpentDate.Format("Mon Jan 02 2006"),
+		l.Hours(timetracking.Duration(entry.Hours.Duration)),
+		entry.Client.Name,
+		entry.Project.Name,
+		entry.Task.Name,
+		entry.Notes,
+	)
+}
+
+func (e ExportEntries) Text(l *Printer) {
+	for _, entry := range e {
+		exportText(l, entry)
+	}
+}
+
+func (e ExportEntries) Entries() harvest.TimeEntries {
+	return harvest.TimeEntries(e)
+}
+
+func (e ExportEntries) CSV(w *csv.Writer) error {
+	return writeEntries(w, harvestFormatter{}, e.Entries())
+}
+
+func (e ExportEntries) ICS(w *ICSWriter, f *Formatting) error {
+	now := time.Now()
+	for _, entry := range e {
+		if entry.SpentDate == nil {
+			continue
+		}
+
+		w.Begin("VEVENT")
+		w.Line("UID", fmt.Sprintf("time-entry-%d@harvestapp.com", entry.ID))
+		stamp := now
+		if entry.UpdatedAt != nil {
+			stamp = entry.UpdatedAt.Time
+		}
+		w.UTC("DTSTAMP", stamp)
+
+		start, err := parseClock(entry.SpentDate.Time, entry.StartedTime)
+		if err == nil {
+			end, err := parseClock(entry.SpentDate.Time, entry.EndedTime)
+			if err != nil || !end.After(start) {
+				end = start.Add(entry.Hours.Duration)
+			}
+			w.Time("DTSTART", start)
+			w.Time("DTEND", end)
+		} else {
+			// Duration based entries only have a date.
+			w.Date("DTSTART", entry.SpentDate.Time)
+			w.Date("DTEND", entry.SpentDate.AddDate(0, 0, 1))
+		}
+
+		w.Text(
+			"SUMMARY",
+			fmt.Sprintf(
+				"%s - %s (%s)",
+				entry.Project.Name,
+				entry.Task.Name,
+				f.Hours(timetracking.Duration(entry.Hours.Duration)),
+			),
+		)
+		if entry.Notes != "" {
+			w.Text("DESCRIPTION", entry.Notes)
+		}
+		w.Text("CATEGORIES", entry.Client.Name)
+		w.End("VEVENT")
+	}
+
+	return w.Err()
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+const (
+	forecastMilestones = "milestones"
+	forecastTeam       = "team"
+)
+
+func commandForecast(c *Command) (int, error) {
+	switch sub := shiftArg(); sub {
+	case forecastMilestones:
+		return commandForecastMilestones(c)
+	case forecastTeam:
+		return commandForecastTeam(c)
+	default:
+		return 1, fmt.Errorf("Usage: forecast %s|%s", forecastMilestones, forecastTeam)
+	}
+}
+
+func commandForecastMilestones(c *Command) (int, error) {
+	var days int
+	flag.IntVar(&days, "days", 30, "Show milestones of the next n days next to the hours you logged in the last n days")
+	flag.Parse()
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	if err := t.SetForecastUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	today := t.Today()
+	since := today.AddDate(0, 0, -days)
+	milestones, projects, err := t.GetMilestones(c.ctx, today, today.AddDate(0, 0, days))
+	if err != nil {
+		return 1, err
+	}
+
+	entries, err := t.GetTimeEntriesBetween(c.ctx, since, today)
+	if err != nil {
+		return 1, err
+	}
+
+	logged := make(map[int]time.Duration)
+	for _, e := range entries {
+		logged[e.Project.ID] += e.Hours.Duration
+	}
+
+	list := &Milestones{Since: since, List: make([]*Milestone, 0, len(milestones))}
+	for _, m := range milestones {
+		date, err := t.ParseDate(m.Date.Format(timetracking.DateFormat))
+		if err != nil {
+			return 1, err
+		}
+		row := &Milestone{Name: m.Name, Date: date, Days: int(date.Sub(today).Round(24*time.Hour) / (24 * time.Hour))}
+		if p, ok := projects[m.ProjectID]; ok {
+			row.Project = p.Name
+			row.Logged = timetracking.Duration(logged[p.HarvestID])
+		}
+		list.List = append(list.List, row)
+	}
+
+	if err := c.Render(list); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+type Milestone struct {
+	Name    string                `json:"name"`
+	Project string                `json:"project"`
+	Date    time.Time             `json:"date"`
+	Days    int                   `json:"days"`
+	Logged  timetracking.Duration `json:"logged"`
+}
+
+type Milestones struct {
+	Since time.Time    `json:"logged_since"`
+	List  []*Milestone `json:"milestones"`
+}
+
+func (m *Milestones) Text(l *Printer) {
+	t := NewTable(
+		"Date",
+		"Days",
+		"Project",
+		"Milestone",
+		"Logged since "+m.Since.Format(timetracking.DateFormat),
+	).Right(1, 4)
+	for _, r := range m.List {
+		t.Row(
+			timetracking.StyleNone,
+			r.Date.Format("Mon Jan 02"),
+			strconv.Itoa(r.Days),
+			r.Project,
+			r.Name,
+			l.Hours(r.Logged),
+		)
+	}
+	t.Text(l)
+}
+
+func commandForecastTeam(c *Command) (int, error) {
+	var weeks int
+	var fromStr string
+	var timeOff string
+	var summary bool
+	flag.IntVar(&weeks, "weeks", 4, "Amount of weeks to show")
+	flag.StringVar(&fromStr, "from", "", "A day in the first week to show [YYYY-MM-DD] (default: this week)")
+	flag.StringVar(&timeOff, "time-off", "Time Off", "Name of the forecast 'Time Off' project, its assignments lower the capacity")
+	flag.BoolVar(&summary, "summary", false, "Only show the totals of every person, not their projects")
+	flag.Parse()
+
+	if weeks < 1 {
+		return 1, errors.New("-weeks should be at least 1")
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	from := t.Now()
+	if fromStr != "" {
+		if from, err = t.ParseDate(fromStr); err != nil {
+			return 1, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", fromStr)
+		}
+	}
+	from = t.StartOfWeek(from)
+
+	plans, err := t.GetTeamPlan(c.ctx, from, weeks, timeOff)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := c.RenderReport(&TeamPlanning{From: from, Summary: summary, People: plans}); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+type TeamPlanning struct {
+	From    time.Time                `json:"from"`
+	Summary bool                     `json:"-"`
+	People  []*timetracking.TeamPlan `json:"people"`
+}
+
+// projects returns the names of the projects a person is allocated to in
+// any of the weeks, sorted.
+func (p *TeamPlanning) projects(plan *timetracking.TeamPlan) []string {
+	seen := make(map[string]struct{})
+	var names []string
+	for _, w := range plan.Weeks {
+		for name := range w.Projects {
+			if _, ok := seen[name]; !ok {
+				seen[name] = struct{}{}
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (p *TeamPlanning) Text(l *Printer) {
+	if len(p.People) == 0 {
+		l.Println("No people")
+		return
+	}
+
+	header := []string{"Person"}
+	right := make([]int, 0, len(p.People[0].Weeks))
+	for i, w := range p.People[0].Weeks {
+		header = append(header, w.Start.Format("Jan 02"))
+		right = append(right, i+1)
+	}
+	table := NewTable(header...).Right(right...)
+
+	for _, plan := range p.People {
+		row := []string{plan.Person.Name()}
+		style := timetracking.StyleOnTarget
+		for _, w := range plan.Weeks {
+			mark := " "
+			switch {
+			case w.Over():
+				mark = "+"
+				if style == timetracking.StyleOnTarget {
+					style = timetracking.StyleNone
+				}
+			case w.Under():
+				mark, style = "-", timetracking.StyleUnder
+			}
+			row = append(row, fmt.Sprintf("%s / %s%s", l.Hours(w.Allocated), l.Hours(w.Available()), mark))
+		}
+		table.Row(style, row...)
+
+		if p.Summary {
+			continue
+		}
+
+		for _, name := range p.projects(plan) {
+			row := []string{"  " + name}
+			for _, w := range plan.Weeks {
+				cell := ""
+				if d, ok := w.Projects[name]; ok {
+					cell = l.Hours(d) + " "
+				}
+				row = append(row, cell)
+			}
+			table.Row(timetracking.StyleNone, row...)
+		}
+	}
+
+	table.Text(l)
+	l.Println("\nAllocated / capacity minus time off, + over and - under allocated")
+}
+
+func (p *TeamPlanning) CSV(w *csv.Writer) error {
+	header := []string{"person_id", "name", "project"}
+	if len(p.People) != 0 {
+		for _, week := range p.People[0].Weeks {
+			header = append(header, week.Start.Format(timetracking.DateFormat))
+		}
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	hours := func(d timetracking.Duration) string {
+		return strconv.FormatFloat(time.Duration(d).Hours(), 'f', 2, 64)
+	}
+	write := func(plan *timetracking.TeamPlan, project string, value func(*timetracking.PlannedWeek) timetracking.Duration) error {
+		rec := []string{strconv.Itoa(plan.Person.ID), plan.Person.Name(), project}
+		for _, week := range plan.Weeks {
+			rec = append(rec, hours(value(week)))
+		}
+		return w.Write(rec)
+	}
+
+	for _, plan := range p.People {
+		if err := write(plan, "total", func(w *timetracking.PlannedWeek) timetracking.Duration { return w.Allocated }); err != nil {
+			return err
+		}
+		if err := write(plan, "available", (*timetracking.PlannedWeek).Available); err != nil {
+			return err
+		}
+		if p.Summary {
+			continue
+		}
+		for _, name := range p.projects(plan) {
+			name := name
+			if err := write(plan, name, func(w *timetracking.PlannedWeek) timetracking.Duration { return w.Projects[name] }); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/calendar"
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/parse"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+// calendarGroup is the external_reference group_id of imported events.
+const calendarGroup = "calendar"
+
+func commandImportCalendar(c *Command) (int, error) {
+	var ics string
+	var caldav string
+	var fromStr string
+	var toStr string
+	var dryRun bool
+	var yes bool
+	flag.StringVar(&ics, "ics", "", "Calendar file, url or - for stdin (default: \"ics\" of \"calendar\" in the config)")
+	flag.StringVar(&caldav, "caldav", "", "Url of a caldav calendar (default: \"caldav\" of \"calendar\" in the config)")
+	flag.StringVar(&fromStr, "from", "today", "First day to import events of [YYYY-MM-DD, yesterday, monday, ...]")
+	flag.StringVar(&toStr, "to", "", "Last day to import events of (default: -from)")
+	flag.BoolVar(&dryRun, "dry-run", false, "Only show the entries that would be created")
+	flag.BoolVar(&yes, "yes", false, "Create the suggested entries without asking")
+	flag.Parse()
+
+	if ics != "" && caldav != "" {
+		return 1, errors.New("Use either -ics or -caldav")
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	// Events and working hours are in the configured timezone.
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	cal := config.Calendar
+	if cal == nil {
+		cal = &timetracking.CalendarConfig{}
+		if err := cal.Validate(); err != nil {
+			return 1, err
+		}
+	}
+	if ics == "" && caldav == "" {
+		ics, caldav = cal.ICS, cal.CalDAV
+	}
+	if ics == "" && caldav == "" {
+		return 1, errors.New("Usage: import calendar -ics <file.ics | url | -> or -caldav <url>, or set them in \"calendar\" in the config")
+	}
+
+	from, err := parse.Date(fromStr, t.Now())
+	if err != nil {
+		return 1, err
+	}
+	to := from
+	if toStr != "" {
+		if to, err = parse.Date(toStr, t.Now()); err != nil {
+			return 1, err
+		}
+	}
+	from, to = timetracking.Day(from), timetracking.Day(to).AddDate(0, 0, 1)
+	if !to.After(from) {
+		return 1, errors.New("-to should not be before -from")
+	}
+
+	var events []*calendar.Event
+	if caldav != "" {
+		password := cal.Password
+		if password == "" {
+			password = os.Getenv("CALDAV_PASSWORD")
+		}
+		events, err = calendar.CalDAV(c.ctx, caldav, cal.Username, password, from, to)
+	} else {
+		events, err = readCalendar(c.ctx, ics)
+	}
+	if err != nil {
+		return 1, err
+	}
+
+	for _, e := range calendar.Unsupported(events) {
+		if !e.Start.Before(to) {
+			continue
+		}
+		c.l.Printf("Only the first occurrence of '%s' is imported, its recurrence is not supported", e.Summary)
+	}
+
+	rows, unmatched := calendarRows(config, cal, calendar.Between(events, from, to))
+	for _, e := range unmatched {
+		c.l.Printf(
+			"No rule for '%s' on %s, add one to \"calendar\" \"rules\" in the config",
+			e.Summary,
+			e.Start.Format("Mon Jan 02 15:04"),
+		)
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	if err := prepareImport(c, t, rows); err != nil {
+		return 1, err
+	}
+
+	if !dryRun && !yes {
+		if rows, err = confirmRows(c, rows); err != nil {
+			return 1, err
+		}
+		if len(rows) == 0 {
+			return 0, nil
+		}
+	}
+
+	if err := logImport(c, t, rows, dryRun); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// readCalendar parses the ics file or url, - reads stdin.
+func readCalendar(ctx context.Context, ics string) ([]*calendar.Event, error) {
+	if ics == "-" {
+		return calendar.Parse(os.Stdin)
+	}
+
+	uri := ics
+	if strings.HasPrefix(uri, "webcal://") {
+		uri = "https://" + strings.TrimPrefix(uri, "webcal://")
+	}
+	if !strings.HasPrefix(uri, "http://") && !strings.HasPrefix(uri, "https://") {
+		f, err := os.Open(ics)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return calendar.Parse(f)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(res.Body, 1024))
+		return nil, fmt.Errorf("Could not fetch calendar (%d): %s", res.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return calendar.Parse(res.Body)
+}
+
+// calendarRows returns a row for the part of every busy event that falls in
+// the working hours of a working day, and the events no rule matched.
+func calendarRows(
+	config *timetracking.Config,
+	cal *timetracking.CalendarConfig,
+	events []*calendar.Event,
+) (ImportRows, []*calendar.Event) {
+	workStart, workEnd := cal.WorkingHours()
+
+	var rows ImportRows
+	var unmatched []*calendar.Event
+	for _, e := range events {
+		if !e.Busy() {
+			continue
+		}
+
+		rule := cal.Rule(e.Summary)
+		if rule != nil && rule.Project == "" {
+			continue
+		}
+
+		matched := false
+		for day := timetracking.Day(e.Start); day.Before(e.End); day = day.AddDate(0, 0, 1) {
+			if config.Excluded(day) || config.Off(day) {
+				continue
+			}
+
+			start, end := atClock(day, workStart), atClock(day, workEnd)
+			if e.Start.After(start) {
+				start = e.Start
+			}
+			if e.End.Before(end) {
+				end = e.End
+			}
+			if !end.After(start) {
+				continue
+			}
+
+			if rule == nil {
+				matched = true
+				continue
+			}
+
+			rows = append(rows, &ImportRow{
+				Source:   fmt.Sprintf("%s %s", e.Start.Format("Mon Jan 02 15:04"), e.Summary),
+				Date:     day,
+				Project:  rule.Project,
+				TaskName: rule.Task,
+				Hours:    timetracking.Duration(end.Sub(start)),
+				Notes:    e.Summary,
+				Ref: &harvest.ExternalReference{
+					ID:      fmt.Sprintf("%s@%s", e.UID, start.Format("20060102T1504")),
+					GroupID: calendarGroup,
+				},
+			})
+		}
+
+		if rule == nil && matched {
+			unmatched = append(unmatched, e)
+		}
+	}
+
+	return rows, unmatched
+}
+
+// atClock returns the time on day the given time after midnight, on the
+// clock, so daylight saving time changes do not shift it.
+func atClock(day time.Time, d time.Duration) time.Time {
+	y, m, dd := day.Date()
+	return time.Date(y, m, dd, int(d/time.Hour), int(d%time.Hour/time.Minute), 0, 0, day.Location())
+}
+
+// confirmRows asks which of the rows that were not logged before should be
+// created.
+func confirmRows(c *Command, rows ImportRows) (ImportRows, error) {
+	stat, err := os.Stdin.Stat()
+	if err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+		return nil, errors.New("Run import calendar in a terminal to confirm the entries, or pass -yes or -dry-run")
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	all := false
+	confirmed := make(ImportRows, 0, len(rows))
+	for _, row := range rows {
+		if row.Exists || all {
+			confirmed = append(confirmed, row)
+			continue
+		}
+
+		c.l.Printf(
+			"Log %s on %s for '%s' on %s? [y/N/a(ll)] ",
+			c.formatting().Hours(row.Hours),
+			row.Task,
+			row.Notes,
+			row.Date.Format("Mon Jan 02"),
+		)
+		line, err := in.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "a", "all":
+			all = true
+		case "y", "yes":
+		default:
+			continue
+		}
+		confirmed = append(confirmed, row)
+	}
+
+	return confirmed, nil
+}
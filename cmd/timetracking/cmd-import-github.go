@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/parse"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandImportGitHub(c *Command) (int, error) {
+	var dryRun bool
+	var sinceStr string
+	var worklog string
+	var author string
+	flag.BoolVar(&dryRun, "dry-run", false, "Only show the entries that would be created")
+	flag.StringVar(&sinceStr, "since", "7 days ago", "Import time spent since this day [YYYY-MM-DD, monday, 3 days ago, ...]")
+	flag.StringVar(&worklog, "worklog", "", "Read time spent from this worklog file (or - for stdin) instead of issue comments")
+	flag.StringVar(&author, "author", "", "Only import comments of this github user (default: the owner of the token)")
+	flag.Parse()
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	gh := config.GitHub
+	if gh == nil || len(gh.Repositories) == 0 {
+		return 1, errors.New("No repositories, add them to \"github\" in the config")
+	}
+
+	// Dates are in the configured timezone.
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	since, err := parse.Date(sinceStr, t.Now())
+	if err != nil {
+		return 1, err
+	}
+
+	var rows ImportRows
+	if worklog != "" {
+		var r io.Reader = os.Stdin
+		if worklog != "-" {
+			f, err := os.Open(worklog)
+			if err != nil {
+				return 1, err
+			}
+			defer f.Close()
+			r = f
+		}
+		if rows, err = readWorklog(r, gh, t.Now()); err != nil {
+			return 1, err
+		}
+	} else {
+		repos := flag.Args()
+		if len(repos) == 0 {
+			for repo := range gh.Repositories {
+				repos = append(repos, repo)
+			}
+			sort.Strings(repos)
+		}
+		if rows, err = githubRows(c, gh, repos, author, since, t.Location()); err != nil {
+			return 1, err
+		}
+	}
+
+	recent := rows[:0]
+	for _, row := range rows {
+		if !row.Date.Before(since) {
+			recent = append(recent, row)
+		}
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	if err := importRows(c, t, recent, dryRun); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// githubRows returns a row for every /spend line in the issue comments of
+// author in repos, on the day they were written in loc.
+func githubRows(
+	c *Command,
+	gh *timetracking.GitHubConfig,
+	repos []string,
+	author string,
+	since time.Time,
+	loc *time.Location,
+) (ImportRows, error) {
+	client := &githubClient{url: strings.TrimRight(gh.URL, "/"), token: gh.Token}
+	if client.url == "" {
+		client.url = githubAPI
+	}
+	if client.token == "" {
+		client.token = os.Getenv("GITHUB_TOKEN")
+	}
+
+	if author == "" {
+		if client.token == "" {
+			return nil, errors.New("Set a github token (or $GITHUB_TOKEN) or pass -author")
+		}
+		var err error
+		if author, err = client.user(c.ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var rows ImportRows
+	for _, repo := range repos {
+		m := gh.Repository(repo)
+		if m == nil {
+			return nil, fmt.Errorf("Repository '%s' is not in \"github\" \"repositories\" in the config", repo)
+		}
+
+		comments, err := client.comments(c.ctx, repo, since)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, comment := range comments {
+			if !strings.EqualFold(comment.User.Login, author) {
+				continue
+			}
+
+			issue := fmt.Sprintf("%s#%s", repo, comment.Issue())
+			for i, spend := range spendRe.FindAllStringSubmatch(comment.Body, -1) {
+				hours, err := parse.Hours(spend[1])
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", comment.HTMLURL, err)
+				}
+
+				date := timetracking.Day(comment.CreatedAt.In(loc))
+				if spend[2] != "" {
+					if date, err = time.ParseInLocation(timetracking.DateFormat, spend[2], loc); err != nil {
+						return nil, fmt.Errorf("%s: %w", comment.HTMLURL, err)
+					}
+				}
+
+				id := comment.HTMLURL
+				if i > 0 {
+					id = fmt.Sprintf("%s-%d", id, i)
+				}
+
+				rows = append(rows, &ImportRow{
+					Source:   comment.HTMLURL,
+					Date:     date,
+					Project:  m.Project,
+					TaskName: m.Task,
+					Hours:    timetracking.Duration(hours),
+					Notes:    issue,
+					Ref:      &harvest.ExternalReference{ID: id, GroupID: repo, Permalink: comment.HTMLURL},
+				})
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+// readWorklog reads lines of date, hours, issue and notes:
+//
+//	2024-03-12 1h30m owner/repo#123 fixed the login redirect
+//	2024-03-12 :45 https://gitlab.com/group/repo/-/issues/7 review
+//
+// Empty lines and lines starting with # are skipped. Every issue is
+// logged once per day, relative dates are relative to now.
+func readWorklog(r io.Reader, gh *timetracking.GitHubConfig, now time.Time) (ImportRows, error) {
+	var rows ImportRows
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		f := strings.Fields(text)
+		if len(f) < 3 {
+			return nil, fmt.Errorf("Line %d: expected date, hours, issue and notes", line)
+		}
+
+		date, err := parse.Date(f[0], now)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %w", line, err)
+		}
+		hours, err := parse.Hours(f[1])
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %w", line, err)
+		}
+		repo, issue, permalink, err := parseIssue(f[2])
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %w", line, err)
+		}
+
+		m := gh.Repository(repo)
+		if m == nil {
+			return nil, fmt.Errorf("Line %d: repository '%s' is not in \"github\" \"repositories\" in the config", line, repo)
+		}
+
+		notes := strings.TrimSpace(fmt.Sprintf("%s#%s %s", repo, issue, strings.Join(f[3:], " ")))
+		rows = append(rows, &ImportRow{
+			Line:     line,
+			Date:     date,
+			Project:  m.Project,
+			TaskName: m.Task,
+			Hours:    timetracking.Duration(hours),
+			Notes:    notes,
+			Ref: &harvest.ExternalReference{
+				ID:        fmt.Sprintf("%s#%s", repo, issue),
+				GroupID:   repo,
+				Permalink: permalink,
+			},
+		})
+	}
+
+	return rows, s.Err()
+}
+
+// parseIssue parses owner/repo#123 or the url of a github or gitlab issue
+// or pull/merge request.
+func parseIssue(s string) (repo, issue, permalink string, err error) {
+	if u, uerr := url.Parse(s); uerr == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		path := strings.Trim(u.Path, "/")
+		for _, sep := range []string{"/-/issues/", "/-/merge_requests/", "/issues/", "/pull/"} {
+			if before, after, ok := strings.Cut(path, sep); ok && before != "" && after != "" {
+				return before, after, s, nil
+			}
+		}
+		return "", "", "", fmt.Errorf("Invalid issue url '%s'", s)
+	}
+
+	repo, issue, ok := strings.Cut(s, "#")
+	if !ok || !strings.Contains(repo, "/") || issue == "" {
+		return "", "", "", fmt.Errorf("Invalid issue '%s' expected owner/repo#123 or a url", s)
+	}
+
+	return repo, issue, fmt.Sprintf("https://github.com/%s/issues/%s", repo, issue), nil
+}
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+// togglGroup is the external_reference group_id of imported toggl entries.
+const togglGroup = "toggl"
+
+// togglEntry is a line of a toggl detailed report export.
+type togglEntry struct {
+	line        int
+	id          string
+	user        string
+	email       string
+	project     string
+	description string
+	tags        []string
+	start       time.Time
+	duration    time.Duration
+}
+
+func commandImportToggl(c *Command) (int, error) {
+	var file string
+	var user string
+	var dryRun bool
+	flag.StringVar(&file, "csv", "", "Detailed report exported from toggl as csv")
+	flag.StringVar(&user, "user", "", "Only import the entries of this toggl user (name or email), required when the export has several")
+	flag.BoolVar(&dryRun, "dry-run", false, "Only show the entries that would be created")
+	flag.Parse()
+
+	if file == "" {
+		return 1, errors.New("Usage: import toggl -csv <file.csv> [-user <name>] [-dry-run]")
+	}
+
+	confLoader, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	// Start times are in the configured timezone.
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return 1, err
+	}
+	defer f.Close()
+
+	entries, err := readToggl(f, t.Location())
+	if err != nil {
+		return 1, err
+	}
+
+	if entries, err = togglUser(entries, user); err != nil {
+		return 1, err
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	toggl := config.Toggl
+	if toggl == nil {
+		toggl = &timetracking.TogglConfig{}
+	}
+
+	unmapped := make(map[string]int)
+	for _, e := range entries {
+		if toggl.Mapping(e.project, e.tags) == nil {
+			unmapped[e.project]++
+		}
+	}
+
+	if len(unmapped) != 0 {
+		mapped, err := mapToggl(c, t, unmapped)
+		if err != nil {
+			return 1, err
+		}
+
+		if len(mapped) != 0 {
+			raw := &timetracking.Config{}
+			if err := confLoader.Read(raw); err != nil {
+				return 1, err
+			}
+			w := raw.Writable(c.profile)
+			if w.Toggl == nil {
+				w.Toggl = &timetracking.TogglConfig{}
+			}
+			if w.Toggl.Projects == nil {
+				w.Toggl.Projects = make(map[string]*timetracking.RepositoryMapping)
+			}
+			if toggl.Projects == nil {
+				toggl.Projects = make(map[string]*timetracking.RepositoryMapping)
+			}
+			for project, m := range mapped {
+				w.Toggl.Projects[project] = m
+				toggl.Projects[project] = m
+			}
+			if err := confLoader.Create(raw); err != nil {
+				return 1, err
+			}
+			c.l.Printf("Saved %d toggl project mappings", len(mapped))
+		}
+	}
+
+	rows := make(ImportRows, 0, len(entries))
+	skipped := 0
+	for _, e := range entries {
+		m := toggl.Mapping(e.project, e.tags)
+		if m == nil {
+			skipped++
+			continue
+		}
+
+		rows = append(rows, &ImportRow{
+			Line:     e.line,
+			Date:     timetracking.Day(e.start),
+			Project:  m.Project,
+			TaskName: m.Task,
+			Hours:    timetracking.Duration(e.duration),
+			Notes:    e.description,
+			Ref: &harvest.ExternalReference{
+				ID:      e.ref(),
+				GroupID: togglGroup,
+			},
+		})
+	}
+	if skipped != 0 {
+		c.l.Printf("Skipping %d entries of unmapped toggl projects", skipped)
+	}
+
+	if err := importRows(c, t, rows, dryRun); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// ref identifies e in the external_reference of its harvest entry: its toggl
+// id when the export has one, else a hash of when, by whom and on what it
+// was tracked.
+func (e *togglEntry) ref() string {
+	if e.id != "" {
+		return e.id
+	}
+
+	h := sha256.Sum256([]byte(strings.Join(
+		[]string{e.start.Format(time.RFC3339), e.user, e.email, e.project, e.description},
+		"\x00",
+	)))
+	return hex.EncodeToString(h[:8])
+}
+
+// togglUser returns the entries of user (name or email), all entries when
+// user is empty and the export has a single user.
+func togglUser(entries []*togglEntry, user string) ([]*togglEntry, error) {
+	if user == "" {
+		users := make(map[string]struct{})
+		for _, e := range entries {
+			users[e.user+"\x00"+e.email] = struct{}{}
+		}
+		if len(users) > 1 {
+			return nil, errors.New("The export has the entries of several toggl users, pick one with -user")
+		}
+		return entries, nil
+	}
+
+	var filtered []*togglEntry
+	for _, e := range entries {
+		if strings.EqualFold(e.user, user) || strings.EqualFold(e.email, user) {
+			filtered = append(filtered, e)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("No entries of toggl user '%s'", user)
+	}
+
+	return filtered, nil
+}
+
+// mapToggl asks which harvest task each unmapped toggl project (with its
+// amount of entries) should be logged on, projects left empty are skipped.
+func mapToggl(
+	c *Command,
+	t *timetracking.Timetracking,
+	unmapped map[string]int,
+) (map[string]*timetracking.RepositoryMapping, error) {
+	stat, err := os.Stdin.Stat()
+	if err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+		projects := make([]string, 0, len(unmapped))
+		for p := range unmapped {
+			projects = append(projects, fmt.Sprintf("'%s'", p))
+		}
+		sort.Strings(projects)
+		return nil, fmt.Errorf(
+			"Toggl projects %s are not mapped, run import toggl in a terminal or add them to \"toggl\" in the config",
+			strings.Join(projects, ", "),
+		)
+	}
+
+	res, err := t.GetUserProjectAssignments(c.ctx)
+	if err != nil {
+		return nil, err
+	}
+	tasks := timetracking.NewTasks(res)
+
+	projects := make([]string, 0, len(unmapped))
+	for p := range unmapped {
+		projects = append(projects, p)
+	}
+	sort.Strings(projects)
+
+	in := bufio.NewReader(os.Stdin)
+	mapped := make(map[string]*timetracking.RepositoryMapping)
+	for _, p := range projects {
+		for {
+			c.l.Printf("Harvest task for toggl project '%s' (%d entries), search or empty to skip: ", p, unmapped[p])
+			line, err := in.ReadString('\n')
+			if err != nil {
+				return nil, err
+			}
+			search := strings.TrimSpace(line)
+			if search == "" {
+				break
+			}
+
+			found := tasks.FuzzyFind(search, 5, true)
+			if len(found) == 0 {
+				c.l.Println("Nothing found")
+				continue
+			}
+			for i, task := range found {
+				c.l.Printf("%3d) %s", i+1, task)
+			}
+			c.l.Print("Task (empty to search again): ")
+			if line, err = in.ReadString('\n'); err != nil {
+				return nil, err
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(line))
+			if err != nil || n < 1 || n > len(found) {
+				continue
+			}
+
+			mapped[p] = &timetracking.RepositoryMapping{
+				Project: found[n-1].ProjectName,
+				Task:    found[n-1].TaskName,
+			}
+			break
+		}
+	}
+
+	return mapped, nil
+}
+
+// readToggl reads a detailed report exported from toggl, using the
+// Project, Description, Tags, Start date, Start time and Duration columns
+// and Id, User and Email when they are there.
+func readToggl(r io.Reader, loc *time.Location) ([]*togglEntry, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	cols := make(map[string]int, len(header))
+	for i, h := range header {
+		cols[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+	for _, col := range []string{"project", "description", "start date", "start time", "duration"} {
+		if _, ok := cols[col]; !ok {
+			return nil, fmt.Errorf("Missing column '%s', export a detailed report from toggl", col)
+		}
+	}
+	get := func(rec []string, col string) string {
+		i, ok := cols[col]
+		if !ok || i >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[i])
+	}
+
+	var entries []*togglEntry
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, err := time.ParseInLocation(
+			"2006-01-02 15:04:05",
+			get(rec, "start date")+" "+get(rec, "start time"),
+			loc,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: invalid start date or time", line)
+		}
+
+		d, err := togglDuration(get(rec, "duration"))
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %w", line, err)
+		}
+
+		var tags []string
+		for _, tag := range strings.Split(get(rec, "tags"), ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+
+		entries = append(entries, &togglEntry{
+			line:        line,
+			id:          get(rec, "id"),
+			user:        get(rec, "user"),
+			email:       get(rec, "email"),
+			project:     get(rec, "project"),
+			description: get(rec, "description"),
+			tags:        tags,
+			start:       start,
+			duration:    d,
+		})
+	}
+
+	if len(entries) == 0 {
+		return nil, errors.New("Nothing to import")
+	}
+
+	return entries, nil
+}
+
+// togglDuration parses the hh:mm:ss durations of toggl.
+func togglDuration(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("Invalid duration '%s' expected hh:mm:ss", s)
+	}
+
+	var d time.Duration
+	for i, unit := range []time.Duration{time.Hour, time.Minute, time.Second} {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("Invalid duration '%s' expected hh:mm:ss", s)
+		}
+		d += time.Duration(n) * unit
+	}
+
+	return d, nil
+}
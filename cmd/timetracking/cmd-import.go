@@ -0,0 +1,331 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/parse"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+const (
+	importGitHub   = "github"
+	importToggl    = "toggl"
+	importCalendar = "calendar"
+)
+
+func commandImport(c *Command) (int, error) {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case importGitHub:
+			shiftArg()
+			return commandImportGitHub(c)
+		case importToggl:
+			shiftArg()
+			return commandImportToggl(c)
+		case importCalendar:
+			shiftArg()
+			return commandImportCalendar(c)
+		}
+	}
+
+	return commandImportCSV(c)
+}
+
+func commandImportCSV(c *Command) (int, error) {
+	var dryRun bool
+	flag.BoolVar(&dryRun, "dry-run", false, "Only show the entries that would be created")
+	flag.Parse()
+
+	file := flag.Arg(0)
+	if file == "" {
+		return 1, errors.New("Usage: import [-dry-run] <file.csv | ->, import github, import toggl or import calendar")
+	}
+
+	var r io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return 1, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	rows, err := readImport(r)
+	if err != nil {
+		return 1, err
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	if err := importRows(c, t, rows, dryRun); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// importRows finds the task of every row and creates their entries, except
+// for rows with an external reference that was logged on their day before.
+func importRows(c *Command, t *timetracking.Timetracking, rows ImportRows, dryRun bool) error {
+	if err := prepareImport(c, t, rows); err != nil {
+		return err
+	}
+
+	return logImport(c, t, rows, dryRun)
+}
+
+// prepareImport finds the task of every row and marks the rows with an
+// external reference that was logged on their day before as existing.
+// Nothing is imported unless every row has a task and no reference is in
+// the rows twice on the same day.
+func prepareImport(c *Command, t *timetracking.Timetracking, rows ImportRows) error {
+	if len(rows) == 0 {
+		return errors.New("Nothing to import")
+	}
+
+	assignments, err := t.GetUserProjectAssignments(c.ctx)
+	if err != nil {
+		return err
+	}
+
+	from, to := rows[0].Date, rows[0].Date
+	refs := make(map[string]*ImportRow)
+	var invalid []string
+	for _, row := range rows {
+		if row.Ref != nil {
+			key := importKey(row.Date, row.Ref)
+			if prev, ok := refs[key]; ok {
+				invalid = append(invalid, fmt.Sprintf(
+					"%s: %s is already imported on %s by %s",
+					row.where(),
+					row.Ref.ID,
+					row.Date.Format("Mon Jan 02 2006"),
+					prev.where(),
+				))
+				continue
+			}
+			refs[key] = row
+		}
+		if row.Task, err = timetracking.FindTaskIn(assignments, row.Project, row.TaskName); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: %s", row.where(), err))
+			continue
+		}
+		if row.Date.Before(from) {
+			from = row.Date
+		}
+		if row.Date.After(to) {
+			to = row.Date
+		}
+	}
+	if len(invalid) != 0 {
+		return errors.New(strings.Join(invalid, "\n"))
+	}
+
+	if len(refs) != 0 {
+		entries, err := t.GetTimeEntriesBetween(c.ctx, timetracking.Day(from), timetracking.Day(to))
+		if err != nil {
+			return err
+		}
+		logged := make(map[string]int, len(entries))
+		for _, e := range entries {
+			if e.ExternalReference.ID != "" && e.SpentDate != nil {
+				logged[importKey(e.SpentDate.Time, &e.ExternalReference)] = e.ID
+			}
+		}
+		for _, row := range rows {
+			if row.Ref == nil {
+				continue
+			}
+			if id, ok := logged[importKey(row.Date, row.Ref)]; ok {
+				row.ID, row.Exists = id, true
+			}
+		}
+	}
+
+	return nil
+}
+
+// logImport creates the entries of the rows that do not exist yet and
+// renders them. When creating one fails the entries created so far are
+// rendered before the error, so their rows can be left out of the next try.
+func logImport(c *Command, t *timetracking.Timetracking, rows ImportRows, dryRun bool) error {
+	if !dryRun {
+		for i, row := range rows {
+			if row.Exists {
+				continue
+			}
+			entry, err := t.LogTime(
+				c.ctx,
+				row.Task.ProjectID,
+				row.Task.TaskID,
+				row.Date,
+				time.Duration(row.Hours),
+				row.Notes,
+				row.Ref,
+			)
+			if err != nil {
+				var created ImportRows
+				for _, r := range rows[:i] {
+					if !r.Exists {
+						created = append(created, r)
+					}
+				}
+				if len(created) == 0 {
+					return fmt.Errorf("%s: %w", row.where(), err)
+				}
+				if rerr := c.Render(created); rerr != nil {
+					return rerr
+				}
+				return fmt.Errorf(
+					"%s: %w, the %d entries above were created, leave them out when importing again",
+					row.where(),
+					err,
+					len(created),
+				)
+			}
+			row.ID = entry.ID
+		}
+	}
+
+	return c.Render(rows)
+}
+
+func importKey(d time.Time, ref *harvest.ExternalReference) string {
+	return strings.Join([]string{d.Format(timetracking.DateFormat), ref.GroupID, ref.ID}, "|")
+}
+
+// readImport reads csv records of date, project, task, hours and notes,
+// the first line is skipped if it is a header. Every invalid line is
+// reported.
+func readImport(r io.Reader) (ImportRows, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	rows := make(ImportRows, 0)
+	var invalid []string
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if line == 1 && strings.EqualFold(rec[0], "date") {
+			continue
+		}
+
+		if len(rec) < 4 {
+			invalid = append(invalid, fmt.Sprintf("Line %d: expected date, project, task, hours and notes", line))
+			continue
+		}
+
+		d, err := time.Parse(timetracking.DateFormat, rec[0])
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("Line %d: invalid date '%s' expected YYYY-mm-dd", line, rec[0]))
+			continue
+		}
+
+		hours, err := parse.Hours(rec[3])
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("Line %d: %s", line, err))
+			continue
+		}
+
+		row := &ImportRow{
+			Line:     line,
+			Date:     d,
+			Project:  rec[1],
+			TaskName: rec[2],
+			Hours:    timetracking.Duration(hours),
+		}
+		if len(rec) > 4 {
+			row.Notes = rec[4]
+		}
+		rows = append(rows, row)
+	}
+
+	if len(invalid) != 0 {
+		return nil, errors.New(strings.Join(invalid, "\n"))
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("Nothing to import")
+	}
+
+	return rows, nil
+}
+
+type ImportRow struct {
+	Line     int                        `json:"line,omitempty"`
+	Source   string                     `json:"source,omitempty"`
+	ID       int                        `json:"id,omitempty"`
+	Exists   bool                       `json:"exists,omitempty"`
+	Date     time.Time                  `json:"date"`
+	Project  string                     `json:"-"`
+	TaskName string                     `json:"-"`
+	Task     *timetracking.Task         `json:"task"`
+	Hours    timetracking.Duration      `json:"hours"`
+	Notes    string                     `json:"notes"`
+	Ref      *harvest.ExternalReference `json:"external_reference,omitempty"`
+}
+
+// where describes the origin of the row in errors.
+func (r *ImportRow) where() string {
+	if r.Source != "" {
+		return r.Source
+	}
+
+	return fmt.Sprintf("Line %d", r.Line)
+}
+
+type ImportRows []*ImportRow
+
+func (r ImportRows) Text(l *Printer) {
+	var total timetracking.Duration
+	for _, row := range r {
+		status := "would create"
+		if row.Exists {
+			status = fmt.Sprintf("already logged %d", row.ID)
+		} else if row.ID != 0 {
+			status = fmt.Sprintf("created %d", row.ID)
+		}
+		l.Printf(
+			"%s - %5s - %s: %s [%s]",
+			row.Date.Format("Mon Jan 02 2006"),
+			l.Hours(row.Hours),
+			row.Task,
+			row.Notes,
+			status,
+		)
+		total += row.Hours
+	}
+
+	l.Printf("\n%d entries, %s", len(r), l.Hours(total))
+}
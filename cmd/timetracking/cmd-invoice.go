@@ -0,0 +1,631 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+const (
+	invoiceList  = "list"
+	invoiceDraft = "draft"
+
+	invoiceUninvoiced  = "uninvoiced"
+	invoiceOutstanding = "outstanding"
+)
+
+var invoiceSummaries = []string{
+	harvest.InvoiceSummaryProject,
+	harvest.InvoiceSummaryTask,
+	harvest.InvoiceSummaryPeople,
+	harvest.InvoiceSummaryDetailed,
+}
+
+func commandInvoice(c *Command) (int, error) {
+	switch sub := shiftArg(); sub {
+	case invoiceList:
+		return commandInvoiceList(c)
+	case invoiceDraft:
+		return commandInvoiceDraft(c)
+	case invoiceUninvoiced:
+		return commandInvoiceUninvoiced(c)
+	case invoiceOutstanding:
+		return commandInvoiceOutstanding(c)
+	default:
+		return 1, fmt.Errorf("Usage: invoice %s|%s|%s|%s", invoiceList, invoiceDraft, invoiceUninvoiced, invoiceOutstanding)
+	}
+}
+
+func commandInvoiceList(c *Command) (int, error) {
+	var client string
+	var state string
+	flag.StringVar(&client, "client", "", "Only list invoices of this client (name or id)")
+	flag.StringVar(&state, "state", "", "Only list invoices in this state (draft|open|paid|closed)")
+	flag.Parse()
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	params := &harvest.InvoicesParams{}
+	if client != "" {
+		cl, err := t.FindClient(c.ctx, client)
+		if err != nil {
+			return 1, err
+		}
+		params.ClientID = &cl.ID
+	}
+	if state != "" {
+		params.State = &state
+	}
+
+	invoices, err := t.GetInvoices(c.ctx, params)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := c.Render(Invoices(invoices)); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+func commandInvoiceDraft(c *Command) (int, error) {
+	var client string
+	var customFrom string
+	var customTo string
+	var summary string
+	var subject string
+	var dryRun bool
+	flag.StringVar(&client, "client", "", "The client to invoice (name or id)")
+	flag.StringVar(&customFrom, "from", "", "First day of uninvoiced time to include [YYYY-MM-DD] (default: first day of last month)")
+	flag.StringVar(&customTo, "to", "", "Last day of uninvoiced time to include [YYYY-MM-DD] (default: last day of last month)")
+	flag.StringVar(
+		&summary,
+		"summary",
+		harvest.InvoiceSummaryProject,
+		fmt.Sprintf("Line item per %s", strings.Join(invoiceSummaries, "|")),
+	)
+	flag.StringVar(&subject, "subject", "", "Invoice subject")
+	flag.BoolVar(&dryRun, "dry-run", false, "Only show the uninvoiced hours, do not create the invoice")
+	flag.Parse()
+
+	if client == "" {
+		return 1, errors.New("-client is required")
+	}
+
+	validSummary := false
+	for _, s := range invoiceSummaries {
+		if s == summary {
+			validSummary = true
+			break
+		}
+	}
+	if !validSummary {
+		return 1, fmt.Errorf("Invalid -summary '%s'", summary)
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	from, to, err := t.Period(timetracking.PeriodLastMonth, t.Now())
+	if err != nil {
+		return 1, err
+	}
+	if customFrom != "" {
+		if from, err = t.ParseDate(customFrom); err != nil {
+			return 1, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", customFrom)
+		}
+	}
+	if customTo != "" {
+		if to, err = t.ParseDate(customTo); err != nil {
+			return 1, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", customTo)
+		}
+	}
+	if to.Before(from) {
+		return 1, errors.New("-to should not be before -from")
+	}
+
+	cl, err := t.FindClient(c.ctx, client)
+	if err != nil {
+		return 1, err
+	}
+
+	entries, err := t.GetUninvoiced(c.ctx, cl.ID, &from, &to)
+	if err != nil {
+		return 1, err
+	}
+
+	if len(entries) == 0 {
+		return 1, fmt.Errorf(
+			"No uninvoiced billable hours for %s between %s and %s",
+			cl.Name,
+			from.Format(timetracking.DateFormat),
+			to.Format(timetracking.DateFormat),
+		)
+	}
+
+	grouped := entries.Group(
+		func(e *harvest.TimeEntry) (harvest.GroupKey, bool) {
+			return harvest.Key(e.Project.Name, strconv.Itoa(e.Project.ID)), true
+		},
+	)
+	sort.SliceStable(grouped, func(i, j int) bool {
+		return grouped[i].Key.Name < grouped[j].Key.Name
+	})
+
+	draft := &InvoiceDraft{Client: cl, From: from, To: to}
+	projectIDs := make([]int, 0, len(grouped))
+	for _, g := range grouped {
+		id, _ := strconv.Atoi(g.Key.Parts[0])
+		projectIDs = append(projectIDs, id)
+		draft.Projects = append(
+			draft.Projects,
+			InvoiceDraftProject{
+				Name:   g.Key.Name,
+				Hours:  timetracking.Duration(g.BillableHours),
+				Amount: g.Revenue,
+			},
+		)
+		draft.Hours += timetracking.Duration(g.BillableHours)
+		draft.Amount += g.Revenue
+	}
+
+	if !dryRun {
+		draft.Invoice, err = t.DraftInvoice(c.ctx, cl.ID, projectIDs, from, to, summary, subject)
+		if err != nil {
+			return 1, err
+		}
+	}
+
+	if err := c.Render(draft); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+func commandInvoiceUninvoiced(c *Command) (int, error) {
+	var client string
+	var customFrom string
+	var customTo string
+	flag.StringVar(&client, "client", "", "Only include this client (name or id)")
+	flag.StringVar(&customFrom, "from", "", "Only include time spent from this day on [YYYY-MM-DD]")
+	flag.StringVar(&customTo, "to", "", "Only include time spent up to this day [YYYY-MM-DD]")
+	flag.Parse()
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	var from, to *time.Time
+	if customFrom != "" {
+		f, err := t.ParseDate(customFrom)
+		if err != nil {
+			return 1, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", customFrom)
+		}
+		from = &f
+	}
+	if customTo != "" {
+		f, err := t.ParseDate(customTo)
+		if err != nil {
+			return 1, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", customTo)
+		}
+		to = &f
+	}
+
+	clientID := 0
+	if client != "" {
+		cl, err := t.FindClient(c.ctx, client)
+		if err != nil {
+			return 1, err
+		}
+		clientID = cl.ID
+	}
+
+	entries, err := t.GetUninvoiced(c.ctx, clientID, from, to)
+	if err != nil {
+		return 1, err
+	}
+
+	code, err := t.ConvertRates(c.ctx, entries)
+	if err != nil {
+		return 1, err
+	}
+
+	clients := make(map[string]*harvest.ClientRef)
+	grouped := entries.Group(
+		func(e *harvest.TimeEntry) (harvest.GroupKey, bool) {
+			id := strconv.Itoa(e.Client.ID)
+			clients[id] = &e.Client
+			return harvest.Key(e.Project.Name, id, strconv.Itoa(e.Project.ID)), true
+		},
+	)
+
+	report := &Uninvoiced{Projects: make([]*UninvoicedProject, 0, len(grouped)), Currency: code}
+	for _, g := range grouped {
+		oldest := g.FirstSpentDate
+		for _, d := range g.SpentDates {
+			if d.Before(oldest) {
+				oldest = d
+			}
+		}
+		report.Projects = append(
+			report.Projects,
+			&UninvoicedProject{
+				Client:   clients[g.Key.Parts[0]].Name,
+				Project:  g.Key.Name,
+				Oldest:   oldest,
+				Hours:    timetracking.Duration(g.BillableHours),
+				Revenue:  g.Revenue,
+				Currency: clients[g.Key.Parts[0]].Currency,
+			},
+		)
+		report.Hours += timetracking.Duration(g.BillableHours)
+		report.Revenue += g.Revenue
+	}
+	sort.SliceStable(report.Projects, func(i, j int) bool {
+		a, b := report.Projects[i], report.Projects[j]
+		if a.Client != b.Client {
+			return a.Client < b.Client
+		}
+		return a.Project < b.Project
+	})
+
+	if err := c.RenderReport(report); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+type UninvoicedProject struct {
+	Client   string                `json:"client"`
+	Project  string                `json:"project"`
+	Oldest   time.Time             `json:"oldest"`
+	Hours    timetracking.Duration `json:"hours"`
+	Revenue  float64               `json:"revenue"`
+	Currency string                `json:"currency"`
+}
+
+// Uninvoiced is the billable work that was not invoiced yet, the total
+// revenue has no Currency when it sums several.
+type Uninvoiced struct {
+	Projects []*UninvoicedProject  `json:"projects"`
+	Hours    timetracking.Duration `json:"hours"`
+	Revenue  float64               `json:"revenue"`
+	Currency string                `json:"currency,omitempty"`
+}
+
+func (u *Uninvoiced) Text(l *Printer) {
+	t := NewTable("Project", "Oldest", "Hours", "Revenue").Right(2, 3)
+	client, code := "", ""
+	var hours timetracking.Duration
+	var revenue float64
+	subtotal := func() {
+		if client != "" {
+			t.Row(timetracking.StyleNone, "  Total", "", l.Hours(hours), l.Money(revenue, code))
+			t.Row(timetracking.StyleNone)
+		}
+	}
+	for _, p := range u.Projects {
+		if p.Client != client {
+			subtotal()
+			client, code, hours, revenue = p.Client, p.Currency, 0, 0
+			t.Row(timetracking.StyleHeader, client)
+		}
+		t.Row(
+			timetracking.StyleNone,
+			"  "+p.Project,
+			p.Oldest.Format(timetracking.DateFormat),
+			l.Hours(p.Hours),
+			l.Money(p.Revenue, p.Currency),
+		)
+		hours += p.Hours
+		revenue += p.Revenue
+	}
+	subtotal()
+
+	t.Row(timetracking.StyleHeader, "Total", "", l.Hours(u.Hours), l.Money(u.Revenue, u.Currency))
+	t.Text(l)
+}
+
+func (u *Uninvoiced) CSV(w *csv.Writer) error {
+	if err := w.Write([]string{"client", "project", "oldest", "hours", "revenue", "currency"}); err != nil {
+		return err
+	}
+
+	for _, p := range u.Projects {
+		err := w.Write(
+			[]string{
+				p.Client,
+				p.Project,
+				p.Oldest.Format(timetracking.DateFormat),
+				strconv.FormatFloat(time.Duration(p.Hours).Hours(), 'f', 2, 64),
+				strconv.FormatFloat(p.Revenue, 'f', 2, 64),
+				p.Currency,
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func commandInvoiceOutstanding(c *Command) (int, error) {
+	var client string
+	var overdue bool
+	flag.StringVar(&client, "client", "", "Only list invoices of this client (name or id)")
+	flag.BoolVar(&overdue, "overdue", false, "Only list invoices past their due date")
+	flag.Parse()
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.LoadCompany(c.ctx); err != nil {
+		return 1, err
+	}
+
+	clientID := 0
+	if client != "" {
+		cl, err := t.FindClient(c.ctx, client)
+		if err != nil {
+			return 1, err
+		}
+		clientID = cl.ID
+	}
+
+	invoices, err := t.GetOutstandingInvoices(c.ctx, clientID, t.Now())
+	if err != nil {
+		return 1, err
+	}
+
+	if overdue {
+		list := invoices[:0]
+		for _, i := range invoices {
+			if i.Overdue != 0 {
+				list = append(list, i)
+			}
+		}
+		invoices = list
+	}
+
+	if err := c.RenderReport(Outstanding(invoices)); err != nil {
+		return 1, err
+	}
+
+	if err := c.overdueInvoices(config.AccountID, invoices); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// Outstanding are the invoices that are not fully paid, with their age and
+// the days they are overdue.
+type Outstanding []*timetracking.OutstandingInvoice
+
+func (o Outstanding) Text(l *Printer) {
+	t := NewTable("ID", "Number", "Client", "Issued", "Due", "Age", "Overdue", "Amount", "Due amount", "Last payment").Right(5, 6, 7, 8)
+	totals := make(map[string]float64)
+	var codes []string
+	for _, i := range o {
+		style := timetracking.StyleNone
+		if i.Overdue != 0 {
+			style = timetracking.StyleUnder
+		}
+
+		overdue, paid := "-", "-"
+		if i.Overdue != 0 {
+			overdue = fmt.Sprintf("%dd", i.Overdue)
+		}
+		if p := i.LastPayment(); p != nil {
+			paid = fmt.Sprintf("%s %s", formatDate(p.PaidDate), l.Money(p.Amount, i.Currency))
+		}
+
+		t.Row(
+			style,
+			strconv.Itoa(i.ID),
+			i.Number,
+			i.Client.Name,
+			formatDate(i.IssueDate),
+			formatDate(i.DueDate),
+			fmt.Sprintf("%dd", i.Age),
+			overdue,
+			l.Money(i.Amount, i.Currency),
+			l.Money(i.DueAmount, i.Currency),
+			paid,
+		)
+
+		if _, ok := totals[i.Currency]; !ok {
+			codes = append(codes, i.Currency)
+		}
+		totals[i.Currency] += i.DueAmount
+	}
+	t.Text(l)
+
+	if len(codes) != 0 {
+		l.Println()
+	}
+	for _, code := range codes {
+		l.Printf("Outstanding: %s", l.Money(totals[code], code))
+	}
+}
+
+func (o Outstanding) CSV(w *csv.Writer) error {
+	err := w.Write([]string{"id", "number", "client", "issue_date", "due_date", "age_days", "overdue_days", "amount", "due_amount", "currency", "last_payment"})
+	if err != nil {
+		return err
+	}
+
+	for _, i := range o {
+		paid := ""
+		if p := i.LastPayment(); p != nil {
+			paid = formatDate(p.PaidDate)
+		}
+		err := w.Write(
+			[]string{
+				strconv.Itoa(i.ID),
+				i.Number,
+				i.Client.Name,
+				formatDate(i.IssueDate),
+				formatDate(i.DueDate),
+				strconv.Itoa(i.Age),
+				strconv.Itoa(i.Overdue),
+				strconv.FormatFloat(i.Amount, 'f', 2, 64),
+				strconv.FormatFloat(i.DueAmount, 'f', 2, 64),
+				i.Currency,
+				paid,
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type InvoiceDraftProject struct {
+	Name   string                `json:"name"`
+	Hours  timetracking.Duration `json:"hours"`
+	Amount float64               `json:"amount"`
+}
+
+type InvoiceDraft struct {
+	Client   *harvest.Client       `json:"client"`
+	From     time.Time             `json:"from"`
+	To       time.Time             `json:"to"`
+	Projects []InvoiceDraftProject `json:"projects"`
+	Hours    timetracking.Duration `json:"hours"`
+	Amount   float64               `json:"amount"`
+	Invoice  *harvest.Invoice      `json:"invoice,omitempty"`
+}
+
+func (i *InvoiceDraft) Text(l *Printer) {
+	l.Printf(
+		"Uninvoiced billable hours for %s from %s to %s",
+		i.Client.Name,
+		i.From.Format(timetracking.DateFormat),
+		i.To.Format(timetracking.DateFormat),
+	)
+	l.Println()
+	t := NewTable("Project", "Hours", "Amount").Right(1, 2)
+	for _, p := range i.Projects {
+		t.Row(timetracking.StyleNone, p.Name, l.Hours(p.Hours), l.Money(p.Amount, i.Client.Currency))
+	}
+	t.Row(timetracking.StyleHeader, "Total", l.Hours(i.Hours), l.Money(i.Amount, i.Client.Currency))
+	t.Text(l)
+
+	if i.Invoice == nil {
+		return
+	}
+
+	l.Println()
+	l.Printf(
+		"Created %s invoice %d for %s",
+		i.Invoice.State,
+		i.Invoice.ID,
+		l.Money(i.Invoice.Amount, i.Invoice.Currency),
+	)
+}
+
+type Invoices []*harvest.Invoice
+
+func (inv Invoices) Text(l *Printer) {
+	t := NewTable("ID", "Number", "Client", "Issued", "State", "Amount", "Due").Right(5, 6)
+	for _, i := range inv {
+		t.Row(
+			timetracking.StyleNone,
+			strconv.Itoa(i.ID),
+			i.Number,
+			i.Client.Name,
+			formatDate(i.IssueDate),
+			i.State,
+			l.Money(i.Amount, i.Currency),
+			l.Money(i.DueAmount, i.Currency),
+		)
+	}
+	t.Text(l)
+}
+
+func (inv Invoices) CSV(w *csv.Writer) error {
+	err := w.Write([]string{"id", "number", "client", "issue_date", "due_date", "state", "amount", "due_amount", "currency"})
+	if err != nil {
+		return err
+	}
+
+	for _, i := range inv {
+		err := w.Write(
+			[]string{
+				strconv.Itoa(i.ID),
+				i.Number,
+				i.Client.Name,
+				formatDate(i.IssueDate),
+				formatDate(i.DueDate),
+				i.State,
+				strconv.FormatFloat(i.Amount, 'f', 2, 64),
+				strconv.FormatFloat(i.DueAmount, 'f', 2, 64),
+				i.Currency,
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+const defaultLeaveProject = "Time Off"
+
+func commandLeave(c *Command) (int, error) {
+	var year int
+	flag.IntVar(&year, "year", 0, "Year to report on (default: this year)")
+	flag.Parse()
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	if config.Leave == nil || (len(config.Leave.Projects) == 0 && len(config.Leave.Tasks) == 0) {
+		return 1, errors.New("Configure \"leave\" with an allowance and the projects or tasks that count as leave")
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	today := t.Today()
+	if year == 0 {
+		year = today.Year()
+	}
+	from := t.Date(year, time.January, 1)
+	to := t.Date(year, time.December, 31)
+
+	capacity := t.Capacity()
+	daily := time.Duration(float64(config.WeekTarget(capacity)) / float64(config.WorkWeek()))
+	if daily == 0 {
+		return 1, errors.New("Your harvest weekly capacity is not set")
+	}
+
+	// A day of leave is the usual target of the day it is on.
+	fullDay := func(d time.Time) time.Duration {
+		if target := time.Duration(config.UsualTarget(capacity, d)); target != 0 {
+			return target
+		}
+		return daily
+	}
+
+	entries, err := t.GetTimeEntriesBetween(c.ctx, from, to)
+	if err != nil {
+		return 1, err
+	}
+
+	taken := make(map[string]time.Duration)
+	planned := make(map[string]time.Duration)
+	for _, e := range entries {
+		if e.SpentDate == nil || !config.Leave.IsLeave(e) {
+			continue
+		}
+		d := e.SpentDate.Format(timetracking.DateFormat)
+		if d > today.Format(timetracking.DateFormat) {
+			planned[d] += e.Hours.Duration
+			continue
+		}
+		taken[d] += e.Hours.Duration
+	}
+
+	if config.ForecastAccountID != "" && to.After(today) {
+		project := config.Leave.ForecastProject
+		if project == "" {
+			project = defaultLeaveProject
+		}
+
+		if err := t.SetForecastUID(c.ctx, 0); err != nil {
+			return 1, err
+		}
+
+		// Only the time off after today is planned, in a future year that
+		// is all of it.
+		offFrom := today.AddDate(0, 0, 1)
+		if from.After(offFrom) {
+			offFrom = from
+		}
+		off, err := t.GetTimeOff(c.ctx, project, offFrom, to, fullDay)
+		if err != nil {
+			return 1, err
+		}
+
+		// Time off logged ahead in harvest is usually planned in forecast
+		// as well, only count the largest of both.
+		for d, o := range off {
+			date, err := t.ParseDate(d)
+			if err != nil {
+				return 1, err
+			}
+			if config.Excluded(date) || config.Off(date) {
+				continue
+			}
+			planned[d] = max(planned[d], min(o, fullDay(date)))
+		}
+	}
+
+	days := func(hours map[string]time.Duration) (float64, error) {
+		var n float64
+		for d, h := range hours {
+			date, err := t.ParseDate(d)
+			if err != nil {
+				return 0, err
+			}
+			n += float64(h) / float64(fullDay(date))
+		}
+		return n, nil
+	}
+
+	leave := &Leave{
+		Year:      year,
+		PerDay:    timetracking.Duration(daily),
+		Allowance: config.Leave.Allowance,
+	}
+	if leave.Taken, err = days(taken); err != nil {
+		return 1, err
+	}
+	if leave.Planned, err = days(planned); err != nil {
+		return 1, err
+	}
+	leave.Remaining = leave.Allowance - leave.Taken - leave.Planned
+
+	if err := c.RenderReport(leave); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// Leave is a yearly leave report, all amounts are in days of the usual
+// target of the day they are on. PerDay is that of an average working day.
+type Leave struct {
+	Year      int                   `json:"year"`
+	PerDay    timetracking.Duration `json:"hours_per_day"`
+	Allowance float64               `json:"allowance"`
+	Taken     float64               `json:"taken"`
+	Planned   float64               `json:"planned"`
+	Remaining float64               `json:"remaining"`
+}
+
+func (lv *Leave) Text(l *Printer) {
+	l.Printf("Leave %d (days of %s on average)", lv.Year, l.Hours(lv.PerDay))
+	l.Printf("Allowance: %6.2f", lv.Allowance)
+	l.Printf("Taken:     %6.2f", lv.Taken)
+	l.Printf("Planned:   %6.2f", lv.Planned)
+	l.Printf("Remaining: %6.2f", lv.Remaining)
+	if lv.Remaining < 0 {
+		l.Printf("%.2f days over your allowance!", -lv.Remaining)
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/frizinak/harvest-timetracking/parse"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandLint(c *Command) (int, error) {
+	var userID int
+	var fromStr string
+	var toStr string
+	period := make(map[string]*bool, len(timetracking.Periods))
+	flag.IntVar(&userID, "uid", 0, "The user id of the user to check")
+	flag.StringVar(&fromStr, "from", "", "First day to check [YYYY-MM-DD, last monday, ...] (default: first day of this week)")
+	flag.StringVar(&toStr, "to", "", "Last day to check [YYYY-MM-DD, yesterday, ...] (default: today)")
+	for _, p := range timetracking.Periods {
+		period[p] = flag.Bool(p, false, fmt.Sprintf("Check the %s date range", p))
+	}
+	flag.Parse()
+
+	var namedPeriod string
+	for p, set := range period {
+		if !*set {
+			continue
+		}
+		if namedPeriod != "" || fromStr != "" || toStr != "" {
+			return 1, fmt.Errorf("Use either -from and -to or one of -%s", strings.Join(timetracking.Periods, ", -"))
+		}
+		namedPeriod = p
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	if config.Lint == nil || len(config.Lint.Rules) == 0 {
+		return 1, errors.New("No lint rules configured")
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.LoadCompany(c.ctx); err != nil {
+		return 1, err
+	}
+
+	now := t.Now()
+	today := timetracking.Day(now)
+	from, to := t.StartOfWeek(today), today
+	if namedPeriod != "" {
+		if from, to, err = t.Period(namedPeriod, now); err != nil {
+			return 1, err
+		}
+	}
+	if fromStr != "" {
+		if from, err = parse.Date(fromStr, now); err != nil {
+			return 1, err
+		}
+	}
+	if toStr != "" {
+		if to, err = parse.Date(toStr, now); err != nil {
+			return 1, err
+		}
+	}
+	from, to = timetracking.Day(from), timetracking.Day(to)
+	if to.Before(from) {
+		return 1, fmt.Errorf("-to should not be before -from")
+	}
+
+	if err := t.SetUID(c.ctx, userID); err != nil {
+		return 1, err
+	}
+
+	results, err := t.WithTimeOff(c.ctx, from, to).Lint(c.ctx, config.Lint.Rules, from, to)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := c.Render(Lint(results)); err != nil {
+		return 1, err
+	}
+
+	if results.Errors() != 0 {
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+// Lint are the results of the lint rules, errors make lint exit with 1.
+type Lint timetracking.LintResults
+
+// lintEntry describes the entry of a result, empty for daily totals.
+func lintEntry(f *Formatting, res *timetracking.LintResult) string {
+	if res.Entry == nil {
+		return ""
+	}
+	e := res.Entry
+	return fmt.Sprintf("%s / %s (%s)", e.Project.Name, e.Task.Name, f.Hours(timetracking.Duration(e.Hours.Duration)))
+}
+
+func (r Lint) Text(l *Printer) {
+	if len(r) == 0 {
+		l.Println("No problems")
+		return
+	}
+
+	t := NewTable("Date", "Level", "Rule", "Entry", "Problem")
+	for _, res := range r {
+		style := timetracking.StyleNone
+		if res.Level == timetracking.LintError {
+			style = timetracking.StyleUnder
+		}
+		t.Row(
+			style,
+			res.Date.Format("Mon Jan 02 2006"),
+			res.Level,
+			res.Rule,
+			lintEntry(l.Formatting, res),
+			res.Message,
+		)
+	}
+	t.Text(l)
+
+	errs := timetracking.LintResults(r).Errors()
+	l.Printf("\n%d errors, %d warnings", errs, len(r)-errs)
+}
+
+func (r Lint) CSV(w *csv.Writer) error {
+	err := w.Write([]string{"date", "level", "rule", "entry_id", "project", "task", "hours", "message"})
+	if err != nil {
+		return err
+	}
+
+	for _, res := range r {
+		var id, project, task, hours string
+		if e := res.Entry; e != nil {
+			id = strconv.Itoa(e.ID)
+			project, task = e.Project.Name, e.Task.Name
+			hours = strconv.FormatFloat(e.Hours.Hours(), 'f', 2, 64)
+		}
+		err := w.Write([]string{
+			res.Date.Format(timetracking.DateFormat),
+			res.Level,
+			res.Rule,
+			id,
+			project,
+			task,
+			hours,
+			res.Message,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
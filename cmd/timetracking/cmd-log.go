@@ -0,0 +1,262 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/parse"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandLog(c *Command) (int, error) {
+	var projectName string
+	var taskName string
+	var hours string
+	var date string
+	var notes string
+	var copyFrom string
+	var template string
+	var refStr string
+	flag.StringVar(&projectName, "project", "", "Name of the project to log time on")
+	flag.StringVar(&taskName, "task", "", "Name of the task to log time on")
+	flag.StringVar(&hours, "hours", "", "Amount of hours to log (e.g. 2.5, 1:30, :45, 1h30m or 90m)")
+	flag.StringVar(&date, "date", "", "Date to log the hours on [YYYY-MM-DD, yesterday, fri, last friday, ...] (default: today)")
+	flag.StringVar(&notes, "notes", "", "Notes for the time entry")
+	flag.StringVar(&copyFrom, "copy", "", "Copy all entries of this date to -date [YYYY-MM-DD, yesterday, fri, ...]")
+	flag.StringVar(&template, "template", "", "Log the entry of this template from the config, hours and notes override it")
+	flag.StringVar(&refStr, "ref", "", "Link the entry to an issue or other item [id, group:id or a url], refuses to log it twice on a day")
+	flag.Parse()
+
+	// log [alias [hours [notes...]]]
+	// log -template name [hours [notes...]]
+	// log -copy date
+	args := flag.Args()
+	var alias string
+	switch {
+	case copyFrom != "":
+		if len(args) != 0 || projectName != "" || taskName != "" || template != "" || hours != "" || notes != "" || refStr != "" {
+			return 1, errors.New("-copy logs the entries as they were, it takes no alias, -project, -task, -template, -ref, hours or notes")
+		}
+	case template != "":
+		if projectName != "" || taskName != "" {
+			return 1, errors.New("Use either a template, an alias or -project and -task")
+		}
+		args = append([]string{""}, args...)
+	case len(args) > 0:
+		alias = args[0]
+		if projectName != "" || taskName != "" {
+			return 1, errors.New("Use either an alias or -project and -task")
+		}
+	}
+	if len(args) > 1 {
+		hours = args[1]
+	}
+	if len(args) > 2 {
+		notes = strings.Join(args[2:], " ")
+	}
+
+	if copyFrom == "" && template == "" {
+		if alias == "" && (projectName == "" || taskName == "") {
+			return 1, errors.New("Both -project and -task are required, or an alias")
+		}
+
+		if hours == "" {
+			return 1, errors.New("Specify the -hours to log")
+		}
+	}
+
+	var duration time.Duration
+	if hours != "" {
+		var err error
+		if duration, err = parse.Hours(hours); err != nil {
+			return 1, err
+		}
+	}
+
+	var ref *harvest.ExternalReference
+	if refStr != "" {
+		ref = parseReference(refStr)
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	spent := t.Now()
+	if date != "" {
+		if spent, err = parse.Date(date, t.Now()); err != nil {
+			return 1, err
+		}
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	if copyFrom != "" {
+		from, err := parse.Date(copyFrom, t.Now())
+		if err != nil {
+			return 1, err
+		}
+		if err := logCopy(c, t, from, spent); err != nil {
+			return 1, err
+		}
+		return 0, nil
+	}
+
+	var task *timetracking.Task
+	switch {
+	case template != "":
+		var tmpl *timetracking.Template
+		if tmpl, err = config.Template(template); err != nil {
+			return 1, err
+		}
+		if hours == "" {
+			duration = time.Duration(tmpl.Hours * float64(time.Hour))
+		}
+		if notes == "" {
+			notes = tmpl.Notes
+		}
+		task, err = t.FindTaskByID(c.ctx, tmpl.ProjectID, tmpl.TaskID)
+	case alias != "":
+		var a *timetracking.Alias
+		if a, err = config.Alias(alias); err != nil {
+			return 1, err
+		}
+		if a.TaskID == 0 {
+			return 1, fmt.Errorf("Alias '%s' has no task_id to log time on", alias)
+		}
+		task, err = t.FindTaskByID(c.ctx, a.ProjectID, a.TaskID)
+	default:
+		task, err = t.FindTask(c.ctx, projectName, taskName)
+	}
+	if err != nil {
+		return 1, err
+	}
+
+	if ref != nil {
+		existing, err := t.FindByReference(c.ctx, spent, ref)
+		if err != nil {
+			return 1, err
+		}
+		if existing != nil {
+			return 1, fmt.Errorf(
+				"%s is already logged on %s (%d)",
+				refStr,
+				spent.Format("Mon Jan 02 2006"),
+				existing.ID,
+			)
+		}
+	}
+
+	entry, err := t.LogTime(
+		c.ctx,
+		task.ProjectID,
+		task.TaskID,
+		spent,
+		duration,
+		notes,
+		ref,
+	)
+	if err != nil {
+		return 1, err
+	}
+
+	c.l.Printf(
+		"Logged %s on %s for %s (%d)",
+		c.formatting().Hours(timetracking.Duration(entry.Hours.Duration)),
+		spent.Format("Mon Jan 02 2006"),
+		task,
+		entry.ID,
+	)
+
+	return 0, nil
+}
+
+// parseReference parses -ref: a url is its own id and permalink, grouped by
+// its host, anything else is an id optionally prefixed with a group.
+func parseReference(s string) *harvest.ExternalReference {
+	if u, err := url.Parse(s); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+		return &harvest.ExternalReference{ID: s, GroupID: u.Host, Permalink: s}
+	}
+
+	if group, id, ok := strings.Cut(s, ":"); ok && group != "" && id != "" {
+		return &harvest.ExternalReference{ID: id, GroupID: group}
+	}
+
+	return &harvest.ExternalReference{ID: s}
+}
+
+// copyGroup is the external_reference group_id of entries logged with
+// -copy, the id is that of the entry they are a copy of.
+const copyGroup = "copy"
+
+// logCopy logs the entries of from again on to, with the same project, task,
+// notes and hours. Entries that were already copied to to are skipped.
+func logCopy(c *Command, t *timetracking.Timetracking, from, to time.Time) error {
+	from, to = timetracking.Day(from), timetracking.Day(to)
+	if from.Equal(to) {
+		return errors.New("Can not copy entries to the day they were tracked on, pass another -date")
+	}
+
+	entries, err := t.GetTimeEntriesBetween(c.ctx, from, from)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("Nothing tracked on %s", from.Format("Mon Jan 02 2006"))
+	}
+
+	existing, err := t.GetTimeEntriesBetween(c.ctx, to, to)
+	if err != nil {
+		return err
+	}
+	copied := make(map[string]int, len(existing))
+	for _, e := range existing {
+		if e.ExternalReference.GroupID == copyGroup {
+			copied[e.ExternalReference.ID] = e.ID
+		}
+	}
+
+	// Oldest first, so the copies are listed in the same order.
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		ref := &harvest.ExternalReference{ID: strconv.Itoa(e.ID), GroupID: copyGroup}
+		if id, ok := copied[ref.ID]; ok {
+			c.l.Printf("Already copied %s / %s (%d)", e.Project.Name, e.Task.Name, id)
+			continue
+		}
+
+		entry, err := t.LogTime(c.ctx, e.Project.ID, e.Task.ID, to, e.Hours.Duration, e.Notes, ref)
+		if err != nil {
+			return err
+		}
+
+		c.l.Printf(
+			"Logged %s on %s for %s / %s (%d)",
+			c.formatting().Hours(timetracking.Duration(entry.Hours.Duration)),
+			to.Format("Mon Jan 02 2006"),
+			e.Project.Name,
+			e.Task.Name,
+			entry.ID,
+		)
+	}
+
+	return nil
+}
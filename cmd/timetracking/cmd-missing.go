@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandMissing(c *Command) (int, error) {
+	var weeks int
+	var short bool
+	var customCapacity int
+	flag.IntVar(&weeks, "weeks", 4, "Amount of weeks to check, up to and including yesterday")
+	flag.BoolVar(&short, "short", false, "Also list days on which less than their target was tracked")
+	flag.IntVar(&customCapacity, "hours", 0, "Amount of hours in a single workweek (default: from harvest api)")
+	flag.Parse()
+
+	if weeks < 1 {
+		return 1, errors.New("-weeks should be at least 1")
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	if err := t.LoadCompany(c.ctx); err != nil {
+		return 1, err
+	}
+
+	capacity := t.Capacity()
+	if customCapacity != 0 {
+		capacity = timetracking.Duration(customCapacity) * timetracking.Duration(time.Hour)
+	}
+
+	from, to := missingPeriod(t.Now(), weeks)
+	days, err := t.GetMissingDays(c.ctx, capacity, from, to, short)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := c.RenderReport(Missing(days)); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// missingPeriod returns the last amount of weeks before the day of now.
+func missingPeriod(now time.Time, weeks int) (from, to time.Time) {
+	to = timetracking.Day(now).AddDate(0, 0, -1)
+	return to.AddDate(0, 0, 1-7*weeks), to
+}
+
+type Missing []*timetracking.MissingDay
+
+func (m Missing) Text(l *Printer) {
+	if len(m) == 0 {
+		l.Println("Nothing missing")
+		return
+	}
+
+	var total timetracking.Duration
+	for _, d := range m {
+		total += d.Missing()
+		l.Printf(
+			"%s %s %6s / %-6s %6s missing",
+			d.Date.Format(timetracking.DateFormat),
+			d.Date.Weekday().String()[:3],
+			l.Hours(d.Tracked),
+			l.Hours(d.Target),
+			l.Hours(d.Missing()),
+		)
+	}
+	l.Printf("%d days, %s missing", len(m), l.Hours(total))
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+const (
+	notifySlack = "slack"
+)
+
+func commandNotify(c *Command) (int, error) {
+	switch sub := shiftArg(); sub {
+	case notifySlack:
+		return commandNotifySlack(c)
+	default:
+		return 1, fmt.Errorf("Usage: notify %s", notifySlack)
+	}
+}
+
+func commandNotifySlack(c *Command) (int, error) {
+	var week bool
+	var dryRun bool
+	flag.BoolVar(&week, "week", false, "Summarize this week instead of today")
+	flag.BoolVar(&dryRun, "dry-run", false, "Print the message instead of posting it")
+	flag.Parse()
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	if config.SlackWebhook == "" && !dryRun {
+		return 1, errors.New("Add your slack incoming webhook url as slack_webhook to the config")
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	if err := t.LoadCompany(c.ctx); err != nil {
+		return 1, err
+	}
+
+	to := t.Today()
+	from := to
+	if week {
+		from = t.StartOfWeek(to)
+	}
+
+	entries, err := t.GetTimeEntriesBetween(c.ctx, from, to)
+	if err != nil {
+		return 1, err
+	}
+
+	grouper, err := t.Grouper(timetracking.GroupByProject)
+	if err != nil {
+		return 1, err
+	}
+
+	summary := &Summary{
+		User:   NewReportUser(t.User(), config.WeekTarget(t.Capacity())),
+		From:   from,
+		To:     to,
+		Target: config.Target(t.Capacity(), from, to),
+	}
+	for _, g := range entries.Group(grouper).SortHours() {
+		summary.Projects = append(
+			summary.Projects,
+			&ReportGroup{Name: g.Key.Name, Hours: timetracking.Duration(g.Hours)},
+		)
+		summary.Total += timetracking.Duration(g.Hours)
+	}
+
+	if dryRun {
+		if err := c.Render(summary); err != nil {
+			return 1, err
+		}
+		return 0, nil
+	}
+
+	if err := postSlack(c.ctx, config.SlackWebhook, summary.Message(c.formatting())); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+type Summary struct {
+	User     ReportUser            `json:"user"`
+	From     time.Time             `json:"from"`
+	To       time.Time             `json:"to"`
+	Projects []*ReportGroup        `json:"projects"`
+	Total    timetracking.Duration `json:"total"`
+	Target   timetracking.Duration `json:"target"`
+}
+
+// Message formats the summary as slack mrkdwn.
+func (s *Summary) Message(f *Formatting) string {
+	return s.message(f, slackEscape)
+}
+
+// message formats the summary as mrkdwn, names are passed through escape.
+func (s *Summary) message(f *Formatting, escape func(string) string) string {
+	var b strings.Builder
+	period := s.From.Format("Mon Jan 02")
+	if !s.From.Equal(s.To) {
+		period = fmt.Sprintf("%s - %s", period, s.To.Format("Mon Jan 02"))
+	}
+
+	fmt.Fprintf(&b, "*%s %s* %s\n", escape(s.User.FirstName), escape(s.User.LastName), period)
+	for _, p := range s.Projects {
+		fmt.Fprintf(&b, "• %s: %s\n", escape(p.Name), f.Hours(p.Hours))
+	}
+
+	fmt.Fprintf(&b, "Total: *%s* / %s (%s)", f.Hours(s.Total), f.Hours(s.Target), f.Signed(s.Total-s.Target))
+
+	return b.String()
+}
+
+func (s *Summary) Text(l *Printer) {
+	l.Println(s.message(l.Formatting, func(s string) string { return s }))
+}
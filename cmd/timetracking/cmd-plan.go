@@ -0,0 +1,210 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/forecast"
+	"github.com/frizinak/harvest-timetracking/parse"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+const (
+	planList   = "list"
+	planAdd    = "add"
+	planDelete = "delete"
+)
+
+func commandPlan(c *Command) (int, error) {
+	switch sub := shiftArg(); sub {
+	case planList:
+		return commandPlanList(c)
+	case planAdd:
+		return commandPlanAdd(c)
+	case planDelete:
+		return commandPlanDelete(c)
+	default:
+		return 1, fmt.Errorf("Usage: plan %s|%s|%s", planList, planAdd, planDelete)
+	}
+}
+
+// planRange parses the -from and -to flags of plan, both default to the
+// current week.
+func planRange(t *timetracking.Timetracking, from, to string) (time.Time, time.Time, error) {
+	first := t.StartOfWeek(t.Now())
+	last := first.AddDate(0, 0, 6)
+	var err error
+	if from != "" {
+		if first, err = t.ParseDate(from); err != nil {
+			return first, last, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", from)
+		}
+		if to == "" {
+			last = first
+		}
+	}
+	if to != "" {
+		if last, err = t.ParseDate(to); err != nil {
+			return first, last, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", to)
+		}
+	}
+	if last.Before(first) {
+		return first, last, errors.New("-to should not be before -from")
+	}
+
+	return first, last, nil
+}
+
+func planTimetracking(c *Command) (*timetracking.Timetracking, error) {
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil || config == nil {
+		return nil, err
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.LoadCompany(c.ctx); err != nil {
+		return nil, err
+	}
+
+	return t, t.SetForecastUID(c.ctx, 0)
+}
+
+func commandPlanList(c *Command) (int, error) {
+	var from, to string
+	flag.StringVar(&from, "from", "", "First day [YYYY-MM-DD] (default: start of this week)")
+	flag.StringVar(&to, "to", "", "Last day [YYYY-MM-DD] (default: end of this week)")
+	flag.Parse()
+
+	t, err := planTimetracking(c)
+	if err != nil || t == nil {
+		return 1, err
+	}
+
+	first, last, err := planRange(t, from, to)
+	if err != nil {
+		return 1, err
+	}
+
+	assignments, err := t.GetForecastAssignments(c.ctx, first, last)
+	if err != nil {
+		return 1, err
+	}
+
+	plan := make(Plan, 0, len(assignments))
+	for project, as := range assignments {
+		for _, a := range as {
+			plan = append(plan, &PlanRow{Project: project, Assignment: a})
+		}
+	}
+	sort.SliceStable(plan, func(i, j int) bool {
+		return formatDate(plan[i].StartDate) < formatDate(plan[j].StartDate)
+	})
+
+	if err := c.Render(plan); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+func commandPlanAdd(c *Command) (int, error) {
+	var project, from, to, hours, notes string
+	flag.StringVar(&project, "project", "", "Name of the forecast project")
+	flag.StringVar(&from, "from", "", "First day [YYYY-MM-DD] (default: start of this week)")
+	flag.StringVar(&to, "to", "", "Last day [YYYY-MM-DD] (default: -from or end of this week)")
+	flag.StringVar(&hours, "hours", "", "Hours per day, e.g. 4 or 2:30 (default: the entire day)")
+	flag.StringVar(&notes, "notes", "", "Notes")
+	flag.Parse()
+
+	if project == "" {
+		return 1, errors.New("Specify a -project")
+	}
+
+	var perDay time.Duration
+	if hours != "" {
+		var err error
+		if perDay, err = parse.Hours(hours); err != nil {
+			return 1, err
+		}
+	}
+
+	t, err := planTimetracking(c)
+	if err != nil || t == nil {
+		return 1, err
+	}
+
+	first, last, err := planRange(t, from, to)
+	if err != nil {
+		return 1, err
+	}
+
+	a, err := t.Plan(c.ctx, project, first, last, perDay, notes)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := c.Render(Plan{{Project: project, Assignment: a}}); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+func commandPlanDelete(c *Command) (int, error) {
+	arg := shiftArg()
+	flag.Parse()
+	if arg == "" {
+		arg = flag.Arg(0)
+	}
+
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return 1, fmt.Errorf("Invalid assignment id '%s'", arg)
+	}
+
+	t, err := planTimetracking(c)
+	if err != nil || t == nil {
+		return 1, err
+	}
+
+	if err := t.DeleteAssignment(c.ctx, id); err != nil {
+		return 1, err
+	}
+
+	c.l.Printf("Deleted %d", id)
+	return 0, nil
+}
+
+type PlanRow struct {
+	Project string `json:"project"`
+	*forecast.Assignment
+}
+
+type Plan []*PlanRow
+
+func (p Plan) Text(l *Printer) {
+	t := NewTable("ID", "Project", "From", "To", "Per day", "Notes").Right(4)
+	for _, r := range p {
+		perDay := "all day"
+		if r.Allocation.Duration != 0 {
+			perDay = l.Hours(timetracking.Duration(r.Allocation.Duration))
+		}
+		t.Row(
+			timetracking.StyleNone,
+			strconv.Itoa(r.ID),
+			r.Project,
+			formatDate(r.StartDate),
+			formatDate(r.EndDate),
+			perDay,
+			r.Notes,
+		)
+	}
+	t.Text(l)
+}
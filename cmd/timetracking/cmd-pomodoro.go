@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/notify"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+// pomodoroRe matches the pomodoro count at the end of the notes of an entry.
+var pomodoroRe = regexp.MustCompile(`\s*\[(\d+) pomodoros?\]$`)
+
+func commandPomodoro(c *Command) (int, error) {
+	var work time.Duration
+	var short time.Duration
+	var long time.Duration
+	var every int
+	var cycles int
+	var projectID int
+	var taskID int
+	flag.DurationVar(&work, "work", 25*time.Minute, "Length of a pomodoro")
+	flag.DurationVar(&short, "break", 5*time.Minute, "Length of a short break")
+	flag.DurationVar(&long, "long-break", 15*time.Minute, "Length of the break after every -every pomodoros")
+	flag.IntVar(&every, "every", 4, "Take a long break after this many pomodoros (0: never)")
+	flag.IntVar(&cycles, "cycles", 0, "Stop after this many pomodoros (0: until interrupted)")
+	flag.IntVar(&projectID, "project-id", 0, "Track the task (-task-id) of this project instead of searching")
+	flag.IntVar(&taskID, "task-id", 0, "Track this task of -project-id")
+	flag.Parse()
+	input := strings.Join(flag.Args(), " ")
+
+	if work < time.Minute || short < 0 || long < 0 {
+		return 1, errors.New("-work should be at least a minute and breaks can not be negative")
+	}
+	if every < 0 || cycles < 0 {
+		return 1, errors.New("-every and -cycles can not be negative")
+	}
+	if (projectID == 0) != (taskID == 0) {
+		return 1, errors.New("-project-id and -task-id go together")
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	p := &pomodoro{t: t, projectID: projectID, taskID: taskID}
+	if projectID == 0 && input != "" {
+		r := config.Tasks.FuzzyFind(input, 1, true)
+		if len(r) == 0 {
+			return 1, fmt.Errorf("Nothing found")
+		}
+		p.projectID, p.taskID = r[0].ProjectID, r[0].TaskID
+	}
+
+	running, err := t.GetRunning(c.ctx)
+	if err != nil {
+		return 1, err
+	}
+	if p.projectID == 0 {
+		if running == nil {
+			return 1, errors.New("No timer running, pass a task to search for or -project-id and -task-id")
+		}
+		p.projectID, p.taskID = running.Project.ID, running.Task.ID
+	}
+	if running != nil && running.Project.ID == p.projectID && running.Task.ID == p.taskID {
+		p.entry = running
+	}
+
+	for n := 1; cycles == 0 || n <= cycles; n++ {
+		if err := p.start(c.ctx); err != nil {
+			return 1, err
+		}
+		c.l.Printf(
+			"Pomodoro %d: %s %s until %s",
+			n,
+			p.entry.Project.Name,
+			p.entry.Task.Name,
+			t.Now().Add(work).Format("15:04"),
+		)
+
+		if !sleep(c.ctx, work) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if _, err := t.StopTracker(ctx); err != nil {
+				return 1, err
+			}
+			c.l.Println("Interrupted, stopped the timer")
+			return 0, nil
+		}
+
+		count, err := p.stop(c.ctx)
+		if err != nil {
+			return 1, err
+		}
+
+		if cycles != 0 && n == cycles {
+			p.notify(c, "Pomodoros done", fmt.Sprintf("%d pomodoros on this entry", count))
+			break
+		}
+
+		rest := short
+		if every != 0 && n%every == 0 {
+			rest = long
+		}
+		p.notify(c, "Take a break", fmt.Sprintf("%d pomodoros on this entry, back at %s", count, t.Now().Add(rest).Format("15:04")))
+		c.l.Printf("Break until %s", t.Now().Add(rest).Format("15:04"))
+		if !sleep(c.ctx, rest) {
+			return 0, nil
+		}
+		p.notify(c, "Back to work", fmt.Sprintf("%s %s", p.entry.Project.Name, p.entry.Task.Name))
+	}
+
+	return 0, nil
+}
+
+// pomodoro tracks one task, restarting the same entry for each pomodoro
+// on the same day.
+type pomodoro struct {
+	t         *timetracking.Timetracking
+	projectID int
+	taskID    int
+	entry     *harvest.TimeEntry
+}
+
+func (p *pomodoro) start(ctx context.Context) (err error) {
+	today := p.t.Today()
+	switch {
+	case p.entry != nil && p.entry.Running:
+		return nil
+	case p.entry != nil && p.entry.SpentDate != nil && timetracking.Day(p.entry.SpentDate.Time).Equal(today):
+		p.entry, err = p.t.RestartTracker(ctx, p.entry.ID)
+	default:
+		p.entry, err = p.t.StartTracker(ctx, p.projectID, p.taskID)
+	}
+
+	return err
+}
+
+// stop stops the timer and bumps the pomodoro count in the notes of its
+// entry, returning the new count.
+func (p *pomodoro) stop(ctx context.Context) (int, error) {
+	entry, err := p.t.StopTracker(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if entry == nil || entry.ID != p.entry.ID {
+		return 0, errors.New("The pomodoro timer was stopped or replaced by another timer")
+	}
+
+	notes, count := pomodoros(entry.Notes)
+	count++
+	label := "pomodoros"
+	if count == 1 {
+		label = "pomodoro"
+	}
+	notes = strings.TrimSpace(fmt.Sprintf("%s [%d %s]", notes, count, label))
+
+	if p.entry, err = p.t.UpdateNotes(ctx, entry.ID, notes); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (p *pomodoro) notify(c *Command, title, msg string) {
+	if err := notify.Send(title, msg); err != nil {
+		c.logger().Error("Failed to notify", "err", err)
+	}
+}
+
+// pomodoros splits the pomodoro count off the end of notes.
+func pomodoros(notes string) (string, int) {
+	m := pomodoroRe.FindStringSubmatchIndex(notes)
+	if m == nil {
+		return notes, 0
+	}
+
+	n, _ := strconv.Atoi(notes[m[2]:m[3]])
+	return notes[:m[0]], n
+}
+
+// sleep waits for d, it returns false if the context is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
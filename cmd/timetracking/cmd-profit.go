@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/parse"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandProfit(c *Command) (int, error) {
+	var fromStr string
+	var toStr string
+	var group string
+	var filters entryFilter
+	period := make(map[string]*bool, len(timetracking.Periods))
+	flag.StringVar(&fromStr, "from", "", "First day of the period [YYYY-MM-DD, last monday, ...] (default: first day of this month)")
+	flag.StringVar(&toStr, "to", "", "Last day of the period [YYYY-MM-DD, yesterday, ...] (default: today)")
+	flag.StringVar(&group, "group", timetracking.GroupByProject, fmt.Sprintf("Group by %s|%s", timetracking.GroupByProject, timetracking.GroupByClient))
+	filters.Flags()
+	for _, p := range timetracking.Periods {
+		period[p] = flag.Bool(p, false, fmt.Sprintf("Report on the %s date range", p))
+	}
+	flag.Parse()
+
+	if group != timetracking.GroupByProject && group != timetracking.GroupByClient {
+		return 1, fmt.Errorf("Invalid -group '%s' expected %s or %s", group, timetracking.GroupByProject, timetracking.GroupByClient)
+	}
+
+	var namedPeriod string
+	for p, set := range period {
+		if !*set {
+			continue
+		}
+		if namedPeriod != "" || fromStr != "" || toStr != "" {
+			return 1, fmt.Errorf("Use either -from and -to or one of -%s", strings.Join(timetracking.Periods, ", -"))
+		}
+		namedPeriod = p
+	}
+
+	filter, err := filters.Filter(nil)
+	if err != nil {
+		return 1, err
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.LoadCompany(c.ctx); err != nil {
+		return 1, err
+	}
+
+	now := t.Now()
+	today := timetracking.Day(now)
+	from, to := today.AddDate(0, 0, 1-today.Day()), today
+	if namedPeriod != "" {
+		if from, to, err = t.Period(namedPeriod, now); err != nil {
+			return 1, err
+		}
+	}
+	if fromStr != "" {
+		if from, err = parse.Date(fromStr, now); err != nil {
+			return 1, err
+		}
+	}
+	if toStr != "" {
+		if to, err = parse.Date(toStr, now); err != nil {
+			return 1, err
+		}
+	}
+	from, to = timetracking.Day(from), timetracking.Day(to)
+	if to.Before(from) {
+		return 1, fmt.Errorf("-to should not be before -from")
+	}
+
+	rows, err := t.GetProfitability(c.ctx, from, to, group, filter)
+	if err != nil {
+		return 1, err
+	}
+
+	report := &ProfitReport{From: from, To: to, Group: group, Rows: rows}
+	totals := make(map[string]*timetracking.Profitability)
+	for _, r := range rows {
+		total, ok := totals[r.Currency]
+		if !ok {
+			total = &timetracking.Profitability{Name: "Total", Currency: r.Currency}
+			totals[r.Currency] = total
+			report.Totals = append(report.Totals, total)
+		}
+		total.Hours += r.Hours
+		total.Billable += r.Billable
+		total.Revenue += r.Revenue
+		total.Cost += r.Cost
+	}
+
+	if err := c.RenderReport(report); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// ProfitReport is the revenue, labor cost and margin of every project or
+// client of a period. Amounts in different currencies are never summed, there
+// is a total per currency.
+type ProfitReport struct {
+	From   time.Time                     `json:"from"`
+	To     time.Time                     `json:"to"`
+	Group  string                        `json:"group"`
+	Rows   []*timetracking.Profitability `json:"rows"`
+	Totals []*timetracking.Profitability `json:"totals"`
+}
+
+func (r *ProfitReport) Text(l *Printer) {
+	l.Printf(
+		"From: %s\nTo: %s\n\n",
+		r.From.Format("Mon Jan 02 2006"),
+		r.To.Format("Mon Jan 02 2006"),
+	)
+
+	project := r.Group == timetracking.GroupByProject
+	header := []string{"Name"}
+	if project {
+		header = append(header, "Client")
+	}
+	n := len(header)
+	header = append(header, "Hours", "Billable", "Revenue", "Cost", "Profit", "Margin")
+	t := NewTable(header...).Right(n, n+1, n+2, n+3, n+4, n+5)
+
+	row := func(p *timetracking.Profitability) {
+		style := timetracking.StyleOnTarget
+		if p.Profit() < 0 {
+			style = timetracking.StyleUnder
+		}
+		margin := "-"
+		if p.Revenue != 0 {
+			margin = fmt.Sprintf("%.1f%%", p.Margin())
+		}
+
+		cells := []string{p.Name}
+		if project {
+			cells = append(cells, p.Client)
+		}
+		cells = append(
+			cells,
+			l.Hours(p.Hours),
+			l.Hours(p.Billable),
+			l.Money(p.Revenue, p.Currency),
+			l.Money(p.Cost, p.Currency),
+			l.Money(p.Profit(), p.Currency),
+			margin,
+		)
+		t.Row(style, cells...)
+	}
+
+	for _, p := range r.Rows {
+		row(p)
+	}
+	for _, p := range r.Totals {
+		row(p)
+	}
+	t.Text(l)
+}
+
+func (r *ProfitReport) CSV(w *csv.Writer) error {
+	err := w.Write([]string{"id", "name", "client", "currency", "hours", "billable_hours", "revenue", "cost", "profit", "margin"})
+	if err != nil {
+		return err
+	}
+
+	amount := func(f float64) string { return strconv.FormatFloat(f, 'f', 2, 64) }
+	for _, p := range r.Rows {
+		err := w.Write(
+			[]string{
+				strconv.Itoa(p.ID),
+				p.Name,
+				p.Client,
+				p.Currency,
+				amount(time.Duration(p.Hours).Hours()),
+				amount(time.Duration(p.Billable).Hours()),
+				amount(p.Revenue),
+				amount(p.Cost),
+				amount(p.Profit()),
+				amount(p.Margin()),
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
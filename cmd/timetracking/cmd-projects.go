@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandProjects(c *Command) (int, error) {
+	var all bool
+	var company bool
+	flag.BoolVar(&all, "all", false, "Include inactive projects of the account (with -company)")
+	flag.BoolVar(&company, "company", false, "List every project of the account instead of the ones assigned to you (requires administrator or manager permissions)")
+	flag.Parse()
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if !company {
+		if err := t.SetUID(c.ctx, 0); err != nil {
+			return 1, err
+		}
+
+		res, err := t.GetUserProjectAssignments(c.ctx)
+		if err != nil {
+			return 1, err
+		}
+
+		// Only the active projects are assigned.
+		assigned := make(AssignedProjects, 0, len(res))
+		for _, a := range res {
+			if a.Project != nil {
+				assigned = append(assigned, a)
+			}
+		}
+
+		if err := c.Render(assigned); err != nil {
+			return 1, err
+		}
+
+		return 0, nil
+	}
+
+	var active *bool
+	if !all {
+		b := true
+		active = &b
+	}
+
+	projects, err := t.GetProjects(c.ctx, active)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := c.Render(Projects(projects)); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+type Projects []*harvest.Project
+
+func (p Projects) Text(l *Printer) {
+	t := NewTable("ID", "Name", "Client", "Budget", "Active").Right(3)
+	for _, project := range p {
+		t.Row(
+			timetracking.StyleNone,
+			strconv.Itoa(project.ID),
+			project.Name,
+			project.Client.Name,
+			budget(project),
+			strconv.FormatBool(project.Active),
+		)
+	}
+	t.Text(l)
+}
+
+func (p Projects) CSV(w *csv.Writer) error {
+	err := w.Write([]string{"id", "name", "client", "budget", "budget_by", "active"})
+	if err != nil {
+		return err
+	}
+
+	for _, project := range p {
+		err := w.Write(
+			[]string{
+				strconv.Itoa(project.ID),
+				project.Name,
+				project.Client.Name,
+				strconv.FormatFloat(float64(project.Budget), 'f', 2, 64),
+				project.BudgetBy,
+				strconv.FormatBool(project.Active),
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AssignedProjects are the active projects you can log time on.
+type AssignedProjects []*harvest.UserAssignment
+
+func (p AssignedProjects) Text(l *Printer) {
+	t := NewTable("ID", "Name", "Client", "Tasks").Right(3)
+	for _, a := range p {
+		t.Row(
+			timetracking.StyleNone,
+			strconv.Itoa(a.Project.ID),
+			a.Project.Name,
+			assignedClient(a),
+			strconv.Itoa(len(a.TaskAssignments)),
+		)
+	}
+	t.Text(l)
+}
+
+func (p AssignedProjects) CSV(w *csv.Writer) error {
+	err := w.Write([]string{"id", "name", "code", "client", "tasks"})
+	if err != nil {
+		return err
+	}
+
+	for _, a := range p {
+		err := w.Write(
+			[]string{
+				strconv.Itoa(a.Project.ID),
+				a.Project.Name,
+				a.Project.Code,
+				assignedClient(a),
+				strconv.Itoa(len(a.TaskAssignments)),
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func assignedClient(a *harvest.UserAssignment) string {
+	if a.Client == nil {
+		return ""
+	}
+	return a.Client.Name
+}
+
+func budget(p *harvest.Project) string {
+	switch {
+	case p.Budget == 0:
+		return "-"
+	case p.MoneyBudget() || p.BudgetBy == harvest.BudgetByNone:
+		return fmt.Sprintf("%.2f", float64(p.Budget))
+	default:
+		return fmt.Sprintf("%.1fh", float64(p.Budget))
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandRemaining(c *Command) (int, error) {
+	var customCapacity int
+	var timeOff string
+	flag.IntVar(&customCapacity, "hours", 0, "Amount of hours in a single workweek (default: from harvest api)")
+	flag.StringVar(&timeOff, "time-off", "Time Off", "Name of the forecast 'Time Off' project, empty to ignore planned time off")
+	flag.Parse()
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	if err := t.LoadCompany(c.ctx); err != nil {
+		return 1, err
+	}
+
+	capacity := t.Capacity()
+	if customCapacity != 0 {
+		capacity = timetracking.Duration(customCapacity) * timetracking.Duration(time.Hour)
+	}
+
+	today := t.Today()
+	from := t.StartOfWeek(today)
+	to := from.AddDate(0, 0, 6)
+
+	off := make(map[string]time.Duration)
+	if timeOff != "" && config.ForecastAccountID != "" {
+		if err := t.SetForecastUID(c.ctx, 0); err != nil {
+			return 1, err
+		}
+		fullDay := func(d time.Time) time.Duration {
+			return time.Duration(config.UsualTarget(capacity, d))
+		}
+		if off, err = t.GetTimeOff(c.ctx, timeOff, from, to, fullDay); err != nil {
+			return 1, err
+		}
+	}
+
+	entries, err := t.GetTimeEntriesBetween(c.ctx, from, to)
+	if err != nil {
+		return 1, err
+	}
+
+	r := &Remaining{User: NewReportUser(t.User(), config.WeekTarget(capacity)), From: from, To: to}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if config.Excluded(d) || config.Off(d) {
+			continue
+		}
+
+		target := time.Duration(config.DayTarget(capacity, d))
+		if o := off[d.Format(timetracking.DateFormat)]; o > 0 {
+			r.TimeOff += timetracking.Duration(min(o, target))
+			target = max(0, target-o)
+		}
+		r.Week.Target += timetracking.Duration(target)
+		if d.Equal(today) {
+			r.Today.Target = timetracking.Duration(target)
+		}
+	}
+
+	for _, e := range entries {
+		r.Week.Tracked += timetracking.Duration(e.Hours.Duration)
+		if e.SpentDate != nil && e.SpentDate.Format(timetracking.DateFormat) == today.Format(timetracking.DateFormat) {
+			r.Today.Tracked += timetracking.Duration(e.Hours.Duration)
+		}
+	}
+
+	r.Today.Left = max(0, r.Today.Target-r.Today.Tracked)
+	r.Week.Left = max(0, r.Week.Target-r.Week.Tracked)
+
+	if err := c.RenderReport(r); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+type RemainingPeriod struct {
+	Target  timetracking.Duration `json:"target"`
+	Tracked timetracking.Duration `json:"tracked"`
+	Left    timetracking.Duration `json:"left"`
+}
+
+type Remaining struct {
+	User    ReportUser            `json:"user"`
+	From    time.Time             `json:"from"`
+	To      time.Time             `json:"to"`
+	TimeOff timetracking.Duration `json:"time_off"`
+	Today   RemainingPeriod       `json:"today"`
+	Week    RemainingPeriod       `json:"week"`
+}
+
+func (r *Remaining) Text(l *Printer) {
+	l.Printf(
+		"Today: %s left (%s / %s)",
+		l.Hours(r.Today.Left),
+		l.Hours(r.Today.Tracked),
+		l.Hours(r.Today.Target),
+	)
+	l.Printf(
+		"Week:  %s left (%s / %s, %s time off)",
+		l.Hours(r.Week.Left),
+		l.Hours(r.Week.Tracked),
+		l.Hours(r.Week.Target),
+		l.Hours(r.TimeOff),
+	)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandRetainer(c *Command) (int, error) {
+	var project string
+	var months int
+	flag.StringVar(&project, "project", "", "Only show the retainer of this project (default: all retainers in the config)")
+	flag.IntVar(&months, "months", 6, "Amount of months to show, 0 for every month since the start of the retainer")
+	flag.Parse()
+
+	if months < 0 {
+		return 1, errors.New("-months can not be negative")
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	names := make([]string, 0, len(config.Retainers))
+	for name := range config.Retainers {
+		if project != "" && !strings.EqualFold(name, project) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		if project != "" {
+			return 1, fmt.Errorf("No retainer configured for '%s'", project)
+		}
+		return 1, errors.New("No retainers configured")
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	today := t.Today()
+	reports := make(Retainers, len(names))
+	err = harvest.Parallel(c.ctx, len(names), timetracking.TeamWorkers, func(ctx context.Context, i int) error {
+		r, err := t.GetRetainer(ctx, names[i], config.Retainers[names[i]], today, months)
+		if err != nil {
+			return err
+		}
+		reports[i] = r
+		return nil
+	})
+	if err != nil {
+		return 1, err
+	}
+
+	if err := c.RenderReport(reports); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+type Retainers []*timetracking.RetainerReport
+
+func (rs Retainers) Text(l *Printer) {
+	for i, r := range rs {
+		if i != 0 {
+			l.Println()
+		}
+		name := r.Project
+		if r.Client != "" {
+			name = fmt.Sprintf("%s (%s)", r.Project, r.Client)
+		}
+		l.Printf("%s: %s per month, %s\n\n", name, l.Hours(r.Hours), r.Rollover)
+
+		t := NewTable("Month", "Allowance", "Carried", "Delivered", "Remaining", "Over").Right(1, 2, 3, 4, 5)
+		for _, m := range r.Months {
+			style := timetracking.StyleNone
+			switch {
+			case m.Over() > 0:
+				style = timetracking.StyleUnder
+			case m.Remaining() == 0:
+				style = timetracking.StyleOnTarget
+			}
+			t.Row(
+				style,
+				m.Month.Format("Jan 2006"),
+				l.Hours(m.Allowance),
+				l.Hours(m.Carried),
+				l.Hours(m.Delivered),
+				l.Hours(m.Remaining()),
+				l.Hours(m.Over()),
+			)
+		}
+		t.Text(l)
+	}
+}
+
+func (rs Retainers) CSV(w *csv.Writer) error {
+	err := w.Write([]string{"project_id", "project", "client", "month", "allowance", "carried", "delivered", "remaining", "over"})
+	if err != nil {
+		return err
+	}
+
+	hours := func(d timetracking.Duration) string {
+		return strconv.FormatFloat(time.Duration(d).Hours(), 'f', 2, 64)
+	}
+	for _, r := range rs {
+		for _, m := range r.Months {
+			err := w.Write(
+				[]string{
+					strconv.Itoa(r.ProjectID),
+					r.Project,
+					r.Client,
+					m.Month.Format("2006-01"),
+					hours(m.Allowance),
+					hours(m.Carried),
+					hours(m.Delivered),
+					hours(m.Remaining()),
+					hours(m.Over()),
+				},
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
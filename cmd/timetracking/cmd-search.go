@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/parse"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandSearch(c *Command) (int, error) {
+	var fromStr string
+	var toStr string
+	var isRegex bool
+	query := shiftArg()
+	flag.StringVar(&fromStr, "from", "", "First day to search [YYYY-MM-DD, last monday, ...] (default: a year ago)")
+	flag.StringVar(&toStr, "to", "", "Last day to search [YYYY-MM-DD, yesterday, ...] (default: today)")
+	flag.BoolVar(&isRegex, "regex", false, "The query is a regular expression")
+	flag.Parse()
+	if query == "" {
+		query = strings.Join(flag.Args(), " ")
+	}
+
+	if query == "" {
+		return 1, errors.New("Usage: search <query> [-from <date>] [-to <date>] [-regex]")
+	}
+
+	pattern := regexp.QuoteMeta(query)
+	if isRegex {
+		pattern = query
+	}
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return 1, fmt.Errorf("Invalid regex '%s': %w", query, err)
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	now := t.Now()
+	to := timetracking.Day(now)
+	from := to.AddDate(-1, 0, 0)
+	if fromStr != "" {
+		if from, err = parse.Date(fromStr, now); err != nil {
+			return 1, err
+		}
+	}
+	if toStr != "" {
+		if to, err = parse.Date(toStr, now); err != nil {
+			return 1, err
+		}
+	}
+	if to.Before(from) {
+		return 1, errors.New("-to should not be before -from")
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	if err := t.LoadCompany(c.ctx); err != nil {
+		return 1, err
+	}
+
+	// Uses the cache when enabled, searching the same range again only
+	// fetches the entries updated since.
+	entries, err := t.GetTimeEntriesBetween(c.ctx, from, to)
+	if err != nil {
+		return 1, err
+	}
+
+	results := make(SearchResults, 0)
+	for _, e := range entries {
+		if re.MatchString(e.Notes) {
+			results = append(results, e)
+		}
+	}
+
+	if err := c.Render(results); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// SearchResults are the entries whose notes matched a search.
+type SearchResults ExportEntries
+
+func (s SearchResults) Text(l *Printer) {
+	if len(s) == 0 {
+		l.Println("Nothing found")
+		return
+	}
+
+	var total timetracking.Duration
+	for _, e := range s {
+		exportText(l, e)
+		total += timetracking.Duration(e.Hours.Duration)
+	}
+
+	l.Printf("\nFound %d, %s in total", len(s), l.Hours(total))
+}
+
+func (s SearchResults) Entries() harvest.TimeEntries {
+	return harvest.TimeEntries(s)
+}
+
+func (s SearchResults) CSV(w *csv.Writer) error {
+	return ExportEntries(s).CSV(w)
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandServe(c *Command) (int, error) {
+	var addr string
+	var hooksAddr string
+	var apiAddr string
+	var interval time.Duration
+	flag.StringVar(&addr, "metrics", "", "Address to expose prometheus metrics on (e.g. :9090, 127.0.0.1 when there is no host)")
+	flag.StringVar(&hooksAddr, "webhooks", "", "Address to receive harvest webhooks on at /webhooks (e.g. :8080, 127.0.0.1 when there is no host)")
+	flag.StringVar(&apiAddr, "api", "", "Address to serve the reports as json on at /api/ (e.g. :8080, 127.0.0.1 when there is no host)")
+	flag.DurationVar(&interval, "interval", 5*time.Minute, "How often to refresh the metrics")
+	flag.Parse()
+
+	addr, hooksAddr, apiAddr = localAddr(addr), localAddr(hooksAddr), localAddr(apiAddr)
+
+	if addr == "" && hooksAddr == "" && apiAddr == "" {
+		return 1, errors.New("-metrics, -webhooks or -api is required")
+	}
+
+	if interval < time.Minute {
+		return 1, errors.New("-interval should be at least a minute")
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	if hooksAddr != "" && config.Webhooks == nil {
+		return 1, errors.New("-webhooks requires a webhooks section in the config")
+	}
+
+	if apiAddr != "" && config.API == nil {
+		return 1, errors.New("-api requires an api section with a token in the config")
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	// A mux per address, metrics, webhooks and the api can share one.
+	muxes := make(map[string]*http.ServeMux)
+	mux := func(addr string) *http.ServeMux {
+		if muxes[addr] == nil {
+			muxes[addr] = http.NewServeMux()
+		}
+		return muxes[addr]
+	}
+
+	// Metrics and api reports use t and config one at a time.
+	var reports sync.Mutex
+	if addr != "" || apiAddr != "" {
+		if err := t.SetUID(c.ctx, 0); err != nil {
+			return 1, err
+		}
+
+		if err := t.LoadCompany(c.ctx); err != nil {
+			return 1, err
+		}
+	}
+
+	if addr != "" {
+		m := &metrics{t: t, conf: config}
+		if err := m.refresh(c); err != nil {
+			return 1, err
+		}
+
+		go func() {
+			tick := time.NewTicker(interval)
+			defer tick.Stop()
+			for {
+				select {
+				case <-c.ctx.Done():
+					return
+				case <-tick.C:
+					reports.Lock()
+					err := m.refresh(c)
+					reports.Unlock()
+					if err != nil {
+						c.logger().Error("Failed to refresh metrics", "err", err)
+					}
+				}
+			}
+		}()
+
+		mux(addr).Handle("/metrics", m)
+		c.l.Printf("Serving metrics on %s/metrics", addr)
+	}
+
+	if hooksAddr != "" {
+		mux(hooksAddr).Handle("/webhooks", newWebhooks(c.ctx, c.logger(), config.Webhooks))
+		c.l.Printf("Receiving webhooks on %s/webhooks", hooksAddr)
+	}
+
+	if apiAddr != "" {
+		mux(apiAddr).Handle("/api/", &api{ctx: c.ctx, l: c.logger(), t: t, conf: config, reports: &reports})
+		c.l.Printf("Serving the api on %s/api/", apiAddr)
+	}
+
+	errs := make(chan error, len(muxes))
+	for addr, mux := range muxes {
+		srv := &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+			ReadTimeout:       30 * time.Second,
+			// Reports fetch from harvest before they are written.
+			WriteTimeout: 2 * time.Minute,
+			IdleTimeout:  2 * time.Minute,
+		}
+		go func() {
+			<-c.ctx.Done()
+			srv.Close()
+		}()
+		go func() {
+			errs <- srv.ListenAndServe()
+		}()
+	}
+
+	for range muxes {
+		if err := <-errs; err != nil && err != http.ErrServerClosed {
+			return 1, err
+		}
+	}
+
+	return 0, nil
+}
+
+// localAddr makes an address without a host listen on 127.0.0.1 only,
+// 0.0.0.0:port listens on every interface.
+func localAddr(addr string) string {
+	if strings.HasPrefix(addr, ":") {
+		return "127.0.0.1" + addr
+	}
+	return addr
+}
+
+// metrics exposes the tracked hours in the prometheus text format.
+type metrics struct {
+	t    *timetracking.Timetracking
+	conf *timetracking.Config
+
+	sem       sync.Mutex
+	today     timetracking.Duration
+	week      timetracking.Duration
+	balance   timetracking.Duration
+	running   *time.Time
+	refreshed time.Time
+	errors    int
+}
+
+func (m *metrics) refresh(c *Command) error {
+	now := m.t.Now()
+	today := timetracking.Day(now)
+	monthStart := today.AddDate(0, 0, 1-today.Day())
+	weekStart := m.t.StartOfWeek(today)
+	from := monthStart
+	if weekStart.Before(from) {
+		from = weekStart
+	}
+
+	entries, err := m.t.GetTimeEntriesBetween(c.ctx, from, today)
+	if err != nil {
+		m.sem.Lock()
+		m.errors++
+		m.sem.Unlock()
+		return err
+	}
+
+	var running *time.Time
+	var todayHours, week, month timetracking.Duration
+	for _, e := range entries {
+		if e.SpentDate == nil {
+			continue
+		}
+		d := timetracking.Duration(e.Hours.Duration)
+		if e.Running && e.TimerStartedAt != nil {
+			started := e.TimerStartedAt.Time
+			running = &started
+		}
+
+		spent := e.SpentDate.Format(timetracking.DateFormat)
+		if spent == today.Format(timetracking.DateFormat) {
+			todayHours += d
+		}
+		if spent >= weekStart.Format(timetracking.DateFormat) {
+			week += d
+		}
+		if spent >= monthStart.Format(timetracking.DateFormat) {
+			month += d
+		}
+	}
+
+	capacity := m.t.Capacity()
+	m.sem.Lock()
+	defer m.sem.Unlock()
+	m.today, m.week, m.running = todayHours, week, running
+	m.balance = month - m.conf.Target(capacity, monthStart, today)
+	m.refreshed = now
+
+	return nil
+}
+
+func (m *metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.sem.Lock()
+	defer m.sem.Unlock()
+
+	hours := func(d timetracking.Duration) float64 { return time.Duration(d).Hours() }
+	running := 0.0
+	if m.running != nil {
+		running = time.Since(*m.running).Seconds()
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	gauge := func(name, help string, v float64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %g\n", name, help, name, name, v)
+	}
+
+	gauge("timetracking_hours_today", "Hours tracked today.", hours(m.today))
+	gauge("timetracking_hours_week", "Hours tracked this week.", hours(m.week))
+	gauge("timetracking_balance_hours", "Surplus (or deficit) of hours tracked this month.", hours(m.balance))
+	gauge("timetracking_running_timer_seconds", "Seconds the running timer has been running, 0 if none.", running)
+	gauge("timetracking_last_refresh_timestamp_seconds", "Unix time of the last successful refresh.", float64(m.refreshed.Unix()))
+	fmt.Fprintf(
+		w,
+		"# HELP %[1]s Failed refreshes.\n# TYPE %[1]s counter\n%[1]s %d\n",
+		"timetracking_refresh_errors_total",
+		m.errors,
+	)
+}
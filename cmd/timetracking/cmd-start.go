@@ -1,16 +1,23 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"strings"
 )
 
 func commandStart(c *Command) (int, error) {
+	var entryID int
+	var projectID int
+	var taskID int
+	flag.IntVar(&entryID, "id", 0, "Restart the time entry with this id instead of creating a new one")
+	flag.IntVar(&projectID, "project-id", 0, "Start a timer on the task (-task-id) of this project instead of searching")
+	flag.IntVar(&taskID, "task-id", 0, "Start a timer on this task of -project-id")
 	flag.Parse()
 	input := strings.Join(flag.Args(), " ")
 
-	_, config, err := getConfig(c.l)
+	_, config, err := getConfig(c.l, c.profile)
 	if err != nil {
 		return 1, err
 	}
@@ -19,22 +26,37 @@ func commandStart(c *Command) (int, error) {
 		return 1, nil
 	}
 
-	t, err := New(c.l, config)
+	t, err := c.New(config)
 	if err != nil {
 		return 1, err
 	}
 
-	if err := t.SetUID(0); err != nil {
+	if err := t.SetUID(c.ctx, 0); err != nil {
 		return 1, err
 	}
 
-	r := config.Tasks.FuzzyFind(input, 1, true)
-	if len(r) == 0 {
-		return 1, fmt.Errorf("Nothing found")
+	if entryID != 0 {
+		entry, err := t.RestartTracker(c.ctx, entryID)
+		if err != nil {
+			return 1, err
+		}
+		c.l.Printf("Restarted %d", entry.ID)
+		return 0, nil
 	}
 
-	task := r[0]
-	entry, err := t.StartTracker(task.ProjectID, task.TaskID)
+	if (projectID == 0) != (taskID == 0) {
+		return 1, errors.New("-project-id and -task-id go together")
+	}
+
+	if projectID == 0 {
+		r := config.Tasks.FuzzyFind(input, 1, true)
+		if len(r) == 0 {
+			return 1, fmt.Errorf("Nothing found")
+		}
+		projectID, taskID = r[0].ProjectID, r[0].TaskID
+	}
+
+	entry, err := t.StartTracker(c.ctx, projectID, taskID)
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandStats(c *Command) (int, error) {
+	var weeks int
+	var customCapacity int
+	flag.IntVar(&weeks, "weeks", 12, "Amount of complete weeks before this one to compute statistics of")
+	flag.IntVar(&customCapacity, "hours", 0, "Amount of hours in a single workweek (default: from harvest api)")
+	flag.Parse()
+
+	if weeks < 1 {
+		return 1, errors.New("-weeks should be at least 1")
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	if err := t.LoadCompany(c.ctx); err != nil {
+		return 1, err
+	}
+
+	capacity := t.Capacity()
+	if customCapacity != 0 {
+		capacity = timetracking.Duration(customCapacity) * timetracking.Duration(time.Hour)
+	}
+
+	to := t.StartOfWeek(t.Now()).AddDate(0, 0, -1)
+	from := to.AddDate(0, 0, 1-7*weeks)
+	t = t.WithTimeOff(c.ctx, from.AddDate(0, 0, -7*(timetracking.RollingWeeks-1)), to)
+	stats, err := t.GetStats(c.ctx, capacity, from, to)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := c.RenderReport(&Stats{stats}); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+type Stats struct {
+	*timetracking.Stats
+}
+
+func (s *Stats) Text(l *Printer) {
+	l.Printf(
+		"From: %s\nTo: %s\n\n",
+		s.From.Format("Mon Jan 02 2006"),
+		s.To.Format("Mon Jan 02 2006"),
+	)
+
+	t := NewTable("Week", "Hours", fmt.Sprintf("%d week average", timetracking.RollingWeeks)).Right(1, 2)
+	for _, w := range s.Weeks {
+		t.Row(timetracking.StyleNone, "week of "+w.Start.Format("Mon Jan 02 2006"), l.Hours(w.Hours), l.Hours(w.Rolling))
+	}
+	t.Text(l)
+	l.Println()
+
+	t = NewTable("Weekday", "Days", "Average").Right(1, 2)
+	busiest := s.Busiest()
+	for _, wd := range s.Weekdays {
+		style := timetracking.StyleNone
+		if wd == busiest {
+			style = timetracking.StyleHeader
+		}
+		t.Row(style, wd.Weekday.String(), fmt.Sprint(wd.Days), l.Hours(wd.Average))
+	}
+	t.Text(l)
+	l.Println()
+
+	l.Printf("Working days: %d, on target: %d", s.Days, s.OnTarget)
+	l.Printf("Daily average: %s (standard deviation %s)", l.Hours(s.Average), l.Hours(s.StdDev))
+	if busiest != nil {
+		l.Printf("Busiest weekday: %s (%s)", busiest.Weekday, l.Hours(busiest.Average))
+	}
+	if s.LongestEnd != nil {
+		l.Printf(
+			"Longest streak on target: %d days, until %s",
+			s.Longest,
+			s.LongestEnd.Format("Mon Jan 02 2006"),
+		)
+	}
+	l.Printf("Current streak on target: %d days", s.Current)
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/cache"
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandStatus(c *Command) (int, error) {
+	var maxAge time.Duration
+	flag.DurationVar(&maxAge, "max-age", 30*time.Second, "How long the status of -format waybar or line is reused before asking harvest again")
+	flag.Parse()
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	if c.format == formatWaybar || c.format == formatLine {
+		s, err := c.statusBar(config, maxAge)
+		if err != nil {
+			return 1, err
+		}
+		if err := c.Render(s); err != nil {
+			return 1, err
+		}
+		return 0, nil
+	}
+
+	// The running timer does not depend on the day, skip the timezone.
+	t, err := c.newClient(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	entry, err := t.GetRunning(c.ctx)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := c.Render(&TimerStatus{entry != nil, entry}); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+type TimerStatus struct {
+	Running bool               `json:"running"`
+	Entry   *harvest.TimeEntry `json:"entry,omitempty"`
+}
+
+func (s *TimerStatus) Text(l *Printer) {
+	entry := s.Entry
+	if entry == nil {
+		l.Println("No timer running")
+		return
+	}
+
+	l.Printf(
+		"Running %d: [%s] %s %s",
+		entry.ID,
+		entry.Client.Name,
+		entry.Project.Name,
+		entry.Task.Name,
+	)
+	if entry.Notes != "" {
+		l.Printf("Notes: %s", entry.Notes)
+	}
+	if entry.TimerStartedAt != nil {
+		l.Printf(
+			"Started: %s",
+			entry.TimerStartedAt.In(l.Location).Format("Mon Jan 02 2006 15:04"),
+		)
+	}
+	l.Printf("Elapsed: %s", l.Hours(timetracking.Duration(entry.Hours.Duration.Round(time.Minute))))
+}
+
+// statusBar returns the running timer and the hours of today and this week,
+// reusing the last status of today for maxAge so status bars can ask every
+// second.
+func (c *Command) statusBar(conf *timetracking.Config, maxAge time.Duration) (*StatusBar, error) {
+	// The client sets up the hooks and formatting a cached status renders with,
+	// the timezone is only looked up when the status is fetched.
+	t, err := c.newClient(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := c.location(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	var store *cache.Cache
+	key := fmt.Sprintf(
+		"statusbar|%s|%s|%s",
+		conf.AccountID,
+		c.profile,
+		time.Now().In(loc).Format(timetracking.DateFormat),
+	)
+	if !c.noCache && maxAge > 0 {
+		if store, err = cache.UserCache("timetracking", maxAge); err != nil {
+			return nil, err
+		}
+
+		s := &StatusBar{}
+		if ok, err := store.Get(key, s); err == nil && ok {
+			return s, nil
+		}
+	}
+
+	if err := c.setTimezone(t, conf); err != nil {
+		return nil, err
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return nil, err
+	}
+
+	s, err := newStatusBar(c.ctx, t, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	if store != nil {
+		if err := store.Set(key, s); err != nil {
+			c.logger().Warn("Failed to write cache", "err", err)
+		}
+	}
+
+	return s, nil
+}
+
+func newStatusBar(ctx context.Context, t *timetracking.Timetracking, conf *timetracking.Config) (*StatusBar, error) {
+	today := t.Today()
+	weekStart := t.StartOfWeek(today)
+	entries, err := t.GetTimeEntriesBetween(ctx, weekStart, today)
+	if err != nil {
+		return nil, err
+	}
+
+	capacity := t.Capacity()
+	s := &StatusBar{
+		Fetched:     t.Now(),
+		TodayTarget: conf.DayTarget(capacity, today),
+		WeekTarget:  conf.Target(capacity, weekStart, today),
+	}
+	for _, e := range entries {
+		if e.SpentDate == nil {
+			continue
+		}
+		d := timetracking.Duration(e.Hours.Duration)
+		s.Week += d
+		if e.SpentDate.Format(timetracking.DateFormat) == today.Format(timetracking.DateFormat) {
+			s.Today += d
+		}
+		if e.Running {
+			s.Running = e
+		}
+	}
+
+	return s, nil
+}
+
+// StatusBar is what status bars show: the running timer, the hours tracked
+// today and the balance of this week so far.
+type StatusBar struct {
+	Fetched     time.Time             `json:"fetched"`
+	Running     *harvest.TimeEntry    `json:"running"`
+	Today       timetracking.Duration `json:"today"`
+	TodayTarget timetracking.Duration `json:"today_target"`
+	Week        timetracking.Duration `json:"week"`
+	WeekTarget  timetracking.Duration `json:"week_target"`
+}
+
+// elapsed is the time the running timer ran since the status was fetched.
+func (s *StatusBar) elapsed() timetracking.Duration {
+	if s.Running == nil {
+		return 0
+	}
+	return timetracking.Duration(time.Since(s.Fetched))
+}
+
+func (s *StatusBar) Line() string {
+	parts := make([]string, 0, 3)
+	if s.Running != nil {
+		parts = append(
+			parts,
+			fmt.Sprintf(
+				"%s %s %s",
+				s.Running.Project.Name,
+				s.Running.Task.Name,
+				clock(timetracking.Duration(s.Running.Hours.Duration)+s.elapsed()),
+			),
+		)
+	}
+	parts = append(parts, "today "+clock(s.Today+s.elapsed()))
+
+	balance := s.Week + s.elapsed() - s.WeekTarget
+	sign := "+"
+	if balance < 0 {
+		sign, balance = "-", -balance
+	}
+	parts = append(parts, "week "+sign+clock(balance))
+
+	return strings.Join(parts, " | ")
+}
+
+func (s *StatusBar) Waybar() *Waybar {
+	w := &Waybar{
+		Text:  s.Line(),
+		Alt:   "idle",
+		Class: "idle",
+		Tooltip: fmt.Sprintf(
+			"Today: %s / %s\nWeek: %s / %s",
+			clock(s.Today+s.elapsed()),
+			clock(s.TodayTarget),
+			clock(s.Week+s.elapsed()),
+			clock(s.WeekTarget),
+		),
+	}
+	if s.Running != nil {
+		w.Alt, w.Class = "running", "running"
+		if s.Running.Notes != "" {
+			w.Tooltip += "\n" + s.Running.Notes
+		}
+	}
+	if s.TodayTarget > 0 {
+		w.Percentage = int(100 * float64(s.Today+s.elapsed()) / float64(s.TodayTarget))
+	}
+
+	return w
+}
+
+// clock formats d as hours and minutes (1:05), whatever the time_format,
+// status bars have little room.
+func clock(d timetracking.Duration) string {
+	m := time.Duration(d) / time.Minute
+	return fmt.Sprintf("%d:%02d", m/60, m%60)
+}
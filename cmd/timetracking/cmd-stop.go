@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandStop(c *Command) (int, error) {
+	flag.Parse()
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	entry, err := t.StopTracker(c.ctx)
+	if err != nil {
+		return 1, err
+	}
+
+	if entry == nil {
+		c.l.Println("No timer running")
+		return 0, nil
+	}
+
+	c.l.Printf(
+		"Stopped %d: %s %s - %s",
+		entry.ID,
+		entry.Project.Name,
+		entry.Task.Name,
+		c.formatting().Hours(timetracking.Duration(entry.Hours.Duration)),
+	)
+
+	return 0, nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+// minimumSuggestion is the time added to the span between the first and
+// last commit, for the work that went into the first one.
+const minimumSuggestion = 30 * time.Minute
+
+func commandSuggest(c *Command) (int, error) {
+	var date string
+	var all bool
+	var yes bool
+	flag.StringVar(&date, "date", "", "Day to suggest entries for [YYYY-MM-DD] (default: today)")
+	flag.BoolVar(&all, "all", false, "Scan every repository in the config instead of the current one")
+	flag.BoolVar(&yes, "yes", false, "Create the suggested entries without asking")
+	flag.Parse()
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	from := t.Today()
+	if date != "" {
+		if from, err = t.ParseDate(date); err != nil {
+			return 1, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", date)
+		}
+	}
+	to := from.AddDate(0, 0, 1)
+
+	repos := make([]string, 0, len(config.Repositories))
+	if all {
+		for repo := range config.Repositories {
+			repos = append(repos, filepath.Clean(timetracking.ExpandHome(repo)))
+		}
+		sort.Strings(repos)
+	} else {
+		root, err := gitRoot(".")
+		if err != nil {
+			return 1, err
+		}
+		repos = append(repos, root)
+	}
+
+	suggestions := make(Suggestions, 0, len(repos))
+	for _, repo := range repos {
+		s, err := suggest(config, repo, from, to)
+		if err != nil {
+			return 1, err
+		}
+		if s != nil {
+			suggestions = append(suggestions, s)
+		}
+	}
+
+	if len(suggestions) == 0 {
+		return 1, errors.New("No commits found")
+	}
+
+	if err := c.Render(suggestions); err != nil {
+		return 1, err
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	for _, s := range suggestions {
+		if s.Project == "" {
+			c.l.Printf("Skipping %s, add it to \"repositories\" in the config", s.Repository)
+			continue
+		}
+
+		if !yes {
+			c.l.Printf("Log %s on %s / %s for %s? [y/N] ", c.formatting().Hours(s.Hours), s.Project, s.Task, s.Repository)
+			line, err := in.ReadString('\n')
+			if err != nil {
+				return 1, err
+			}
+			if a := strings.ToLower(strings.TrimSpace(line)); a != "y" && a != "yes" {
+				continue
+			}
+		}
+
+		task, err := t.FindTask(c.ctx, s.Project, s.Task)
+		if err != nil {
+			return 1, err
+		}
+
+		entry, err := t.LogTime(c.ctx, task.ProjectID, task.TaskID, from, time.Duration(s.Hours), s.Notes(), nil)
+		if err != nil {
+			return 1, err
+		}
+		c.l.Printf("Logged %s on %s (%d)", c.formatting().Hours(timetracking.Duration(entry.Hours.Duration)), task, entry.ID)
+	}
+
+	return 0, nil
+}
+
+// suggest builds a suggestion from the commits of the configured git author
+// in repo, nil if there are none.
+func suggest(config *timetracking.Config, repo string, from, to time.Time) (*Suggestion, error) {
+	author, err := gitAuthor(repo)
+	if err != nil {
+		return nil, fmt.Errorf("Could not determine your git email in %s: %w", repo, err)
+	}
+
+	commits, err := gitCommits(repo, author, from, to)
+	if err != nil || len(commits) == 0 {
+		return nil, err
+	}
+
+	sort.Slice(commits, func(i, j int) bool { return commits[i].Time.Before(commits[j].Time) })
+	span := commits[len(commits)-1].Time.Sub(commits[0].Time) + minimumSuggestion
+	s := &Suggestion{
+		Repository: repo,
+		Commits:    commits,
+		Hours:      timetracking.Duration(span.Round(15 * time.Minute)),
+	}
+	if m := config.Repository(repo); m != nil {
+		s.Project, s.Task = m.Project, m.Task
+	}
+
+	return s, nil
+}
+
+type Suggestion struct {
+	Repository string                `json:"repository"`
+	Project    string                `json:"project"`
+	Task       string                `json:"task"`
+	Hours      timetracking.Duration `json:"hours"`
+	Commits    []*Commit             `json:"commits"`
+}
+
+// Notes joins the commit subjects.
+func (s *Suggestion) Notes() string {
+	subjects := make([]string, len(s.Commits))
+	for i, c := range s.Commits {
+		subjects[i] = c.Subject
+	}
+
+	return strings.Join(subjects, "\n")
+}
+
+type Suggestions []*Suggestion
+
+func (s Suggestions) Text(l *Printer) {
+	for _, sug := range s {
+		project := "unmapped"
+		if sug.Project != "" {
+			project = sug.Project + " / " + sug.Task
+		}
+		l.Printf("%s (%s) ~%s", sug.Repository, project, l.Hours(sug.Hours))
+		for _, c := range sug.Commits {
+			l.Printf("    %s %s %s", c.Time.In(l.Location).Format("15:04"), c.Hash[:7], c.Subject)
+		}
+		l.Println()
+	}
+}
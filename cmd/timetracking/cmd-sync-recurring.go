@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/parse"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandSyncRecurring(c *Command) (int, error) {
+	var fromStr string
+	var toStr string
+	var dryRun bool
+	flag.StringVar(&fromStr, "from", "", "First day to log recurring entries on [YYYY-MM-DD, monday, ...] (default: -to)")
+	flag.StringVar(&toStr, "to", "", "Last day to log recurring entries on [YYYY-MM-DD, yesterday, ...] (default: today)")
+	flag.BoolVar(&dryRun, "dry-run", false, "Only list the entries that would be logged")
+	flag.Parse()
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	if len(config.Recurring) == 0 {
+		return 1, errors.New("No recurring entries, add them to \"recurring\" in the config")
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	now := t.Now()
+	to := timetracking.Day(now)
+	if toStr != "" {
+		if to, err = parse.Date(toStr, now); err != nil {
+			return 1, err
+		}
+	}
+	from := to
+	if fromStr != "" {
+		if from, err = parse.Date(fromStr, now); err != nil {
+			return 1, err
+		}
+	}
+	if to.Before(from) {
+		return 1, errors.New("-to should not be before -from")
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	missing, err := t.GetMissingRecurring(c.ctx, from, to)
+	if err != nil {
+		return 1, err
+	}
+
+	for _, r := range missing {
+		hours := timetracking.Duration(r.Hours * float64(time.Hour))
+		if dryRun {
+			c.l.Printf("Would log %s on %s for %s", c.formatting().Hours(hours), r.Date.Format("Mon Jan 02 2006"), r.Name)
+			continue
+		}
+
+		entry, err := t.LogRecurring(c.ctx, r)
+		if err != nil {
+			return 1, err
+		}
+		c.l.Printf(
+			"Logged %s on %s for %s (%d)",
+			c.formatting().Hours(timetracking.Duration(entry.Hours.Duration)),
+			r.Date.Format("Mon Jan 02 2006"),
+			r.Name,
+			entry.ID,
+		)
+	}
+
+	if len(missing) == 0 {
+		c.l.Println("Nothing to log")
+	}
+
+	return 0, nil
+}
@@ -2,14 +2,21 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
 )
 
 func commandTasks(c *Command) (int, error) {
 	var save bool
-	flag.BoolVar(&save, "save", false, "Save in ~/.timetracking")
+	var project string
+	flag.BoolVar(&save, "save", false, "Save in the config file")
+	flag.StringVar(&project, "project", "", "List the tasks of this project (name or id)")
 	flag.Parse()
 
-	confLoader, config, err := getConfig(c.l)
+	confLoader, config, err := getConfig(c.l, c.profile)
 	if err != nil {
 		return 1, err
 	}
@@ -18,53 +25,83 @@ func commandTasks(c *Command) (int, error) {
 		return 1, nil
 	}
 
-	t, err := New(c.l, config)
+	t, err := c.New(config)
 	if err != nil {
 		return 1, err
 	}
 
-	if err := t.SetUID(0); err != nil {
+	if project != "" {
+		p, err := t.FindProject(c.ctx, project)
+		if err != nil {
+			return 1, err
+		}
+
+		tasks, err := t.GetProjectTasks(c.ctx, p.ID)
+		if err != nil {
+			return 1, err
+		}
+
+		if err := c.Render(ProjectTasks(tasks)); err != nil {
+			return 1, err
+		}
+
+		return 0, nil
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
 		return 1, err
 	}
 
-	res, err := t.GetUserProjectAssignments()
-	d := make(Tasks, 0, len(res))
-	if save {
-		for _, a := range res {
-			for _, t := range a.TaskAssignments {
-				d = append(
-					d,
-					&Task{
-						ClientID:    a.Client.ID,
-						ProjectID:   a.Project.ID,
-						TaskID:      t.Task.ID,
-						ClientName:  a.Client.Name,
-						ProjectName: a.Project.Name,
-						TaskName:    t.Task.Name,
-					},
-				)
-			}
-		}
+	res, err := t.GetUserProjectAssignments(c.ctx)
+	if err != nil {
+		return 1, err
+	}
 
-		config = &Config{}
-		if err := confLoader.Read(config); err != nil {
+	if save {
+		d := timetracking.NewTasks(res)
+		raw := &timetracking.Config{}
+		if err := confLoader.Read(raw); err != nil {
 			return 1, err
 		}
 
-		config.Tasks = d
-		if err = confLoader.Create(config); err != nil {
+		raw.Writable(c.profile).Tasks = d
+		if err = confLoader.Create(raw); err != nil {
 			return 1, err
 		}
 		c.l.Println("Saved")
 		return 0, nil
 	}
 
-	for _, a := range res {
-		c.l.Printf("%s [%s]\n", a.Project.Name, a.Client.Name)
+	if err := c.Render(ProjectAssignments(res)); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+type ProjectAssignments []*harvest.UserAssignment
+
+func (p ProjectAssignments) Text(l *Printer) {
+	for _, a := range p {
+		l.Printf("%s [%s]\n", a.Project.Name, a.Client.Name)
 		for _, t := range a.TaskAssignments {
-			c.l.Printf("    %s", t.Task.Name)
+			l.Printf("    %s", t.Task.Name)
 		}
 	}
+}
 
-	return 0, nil
+type ProjectTasks []*harvest.TaskAssignment
+
+func (p ProjectTasks) Text(l *Printer) {
+	t := NewTable("ID", "Task", "Rate", "Billable").Right(2)
+	for _, a := range p {
+		t.Row(
+			timetracking.StyleNone,
+			strconv.Itoa(a.Task.ID),
+			a.Task.Name,
+			fmt.Sprintf("%.2f", a.HourlyRate),
+			strconv.FormatBool(a.Billable),
+		)
+	}
+	t.Text(l)
 }
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandTeam(c *Command) (int, error) {
+	var userIDs string
+	var allActive bool
+	var role string
+	var group string
+	var fromStr string
+	var toStr string
+	var workers int
+	flag.StringVar(&userIDs, "users", "", "Comma separated list of user ids")
+	flag.BoolVar(&allActive, "all-active", false, "Report on all active users")
+	flag.StringVar(&role, "role", "", "Report on the active users with this role (name or id)")
+	flag.StringVar(&group, "group", timetracking.GroupByDay, fmt.Sprintf("Group columns by %s|%s", timetracking.GroupByDay, timetracking.GroupByWeek))
+	flag.StringVar(&fromStr, "from", "", "First day of the period [YYYY-MM-DD] (default: first day of this week)")
+	flag.StringVar(&toStr, "to", "", "Last day of the period [YYYY-MM-DD] (default: last day of this week)")
+	flag.IntVar(&workers, "workers", timetracking.TeamWorkers, "Amount of requests to run concurrently")
+	flag.Parse()
+
+	selected := 0
+	for _, set := range []bool{userIDs != "", allActive, role != ""} {
+		if set {
+			selected++
+		}
+	}
+	if selected != 1 {
+		return 1, errors.New("Specify either -users, -role or -all-active")
+	}
+
+	if group != timetracking.GroupByDay && group != timetracking.GroupByWeek {
+		return 1, fmt.Errorf("Invalid -group '%s' expected %s or %s", group, timetracking.GroupByDay, timetracking.GroupByWeek)
+	}
+
+	var ids []int
+	if userIDs != "" {
+		for _, s := range strings.Split(userIDs, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				return 1, fmt.Errorf("Invalid user id '%s'", s)
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.LoadCompany(c.ctx); err != nil {
+		return 1, err
+	}
+
+	from, to, err := t.Period(timetracking.PeriodThisWeek, t.Now())
+	if err != nil {
+		return 1, err
+	}
+	if fromStr != "" {
+		if from, err = t.ParseDate(fromStr); err != nil {
+			return 1, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", fromStr)
+		}
+	}
+	if toStr != "" {
+		if to, err = t.ParseDate(toStr); err != nil {
+			return 1, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", toStr)
+		}
+	}
+	if to.Before(from) {
+		return 1, errors.New("-to should not be before -from")
+	}
+
+	users, err := t.GetTeam(c.ctx, ids, workers)
+	if err != nil {
+		return 1, err
+	}
+
+	if role != "" {
+		r, err := t.FindRole(c.ctx, role)
+		if err != nil {
+			return 1, err
+		}
+		members := make(map[int]bool, len(r.UserIDs))
+		for _, id := range r.UserIDs {
+			members[id] = true
+		}
+		team := users[:0]
+		for _, u := range users {
+			if members[u.ID] {
+				team = append(team, u)
+			}
+		}
+		users = team
+	}
+
+	entries, err := t.GetTeamEntries(c.ctx, users, from, to, workers)
+	if err != nil {
+		return 1, err
+	}
+
+	column := func(d time.Time) time.Time { return timetracking.Day(d) }
+	if group == timetracking.GroupByWeek {
+		column = t.StartOfWeek
+	}
+
+	report := &TeamReport{From: from, To: to, Group: group}
+	index := make(map[string]int)
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		col := column(d)
+		key := col.Format(timetracking.DateFormat)
+		if _, ok := index[key]; !ok {
+			index[key] = len(report.Columns)
+			report.Columns = append(report.Columns, col)
+		}
+	}
+
+	workingDays := config.WorkingDays(from, to)
+	workWeek := float64(config.WorkWeek())
+	for i, u := range users {
+		capacity := timetracking.Duration(u.Capacity())
+		row := &TeamRow{
+			User:   NewReportUser(u, capacity),
+			Hours:  make([]timetracking.Duration, len(report.Columns)),
+			Target: timetracking.Duration(float64(capacity) * float64(workingDays) / workWeek),
+		}
+		for _, e := range entries[i] {
+			if e.SpentDate == nil {
+				continue
+			}
+			n, ok := index[column(e.SpentDate.Time).Format(timetracking.DateFormat)]
+			if !ok {
+				continue
+			}
+			row.Hours[n] += timetracking.Duration(e.Hours.Duration)
+			row.Total += timetracking.Duration(e.Hours.Duration)
+		}
+		row.Under = row.Total < row.Target
+		report.Rows = append(report.Rows, row)
+		report.Total += row.Total
+	}
+
+	if err := c.RenderReport(report); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+type TeamRow struct {
+	User   ReportUser              `json:"user"`
+	Hours  []timetracking.Duration `json:"hours"`
+	Total  timetracking.Duration   `json:"total"`
+	Target timetracking.Duration   `json:"target"`
+	Under  bool                    `json:"under_capacity"`
+}
+
+type TeamReport struct {
+	From    time.Time             `json:"from"`
+	To      time.Time             `json:"to"`
+	Group   string                `json:"group"`
+	Columns []time.Time           `json:"columns"`
+	Rows    []*TeamRow            `json:"rows"`
+	Total   timetracking.Duration `json:"total"`
+}
+
+func (r *TeamReport) columnName(d time.Time) string {
+	if r.Group == timetracking.GroupByWeek {
+		return d.Format("Jan 02")
+	}
+	return d.Format("Mon 02")
+}
+
+func (r *TeamReport) Text(l *Printer) {
+	header := []string{"User"}
+	for _, col := range r.Columns {
+		header = append(header, r.columnName(col))
+	}
+	header = append(header, "Total", "Target")
+
+	t := NewTable(header...)
+	for i := 1; i < len(header); i++ {
+		t.Right(i)
+	}
+	for _, row := range r.Rows {
+		cells := []string{row.User.FirstName + " " + row.User.LastName}
+		for _, h := range row.Hours {
+			cells = append(cells, l.Hours(h))
+		}
+		cells = append(cells, l.Hours(row.Total), l.Hours(row.Target))
+
+		style := timetracking.StyleNone
+		if row.Under {
+			style = timetracking.StyleUnder
+			cells = append(cells, "under capacity")
+		}
+		t.Row(style, cells...)
+	}
+	t.Text(l)
+
+	l.Printf("\nTotal: %s", l.Hours(r.Total))
+}
+
+func (r *TeamReport) CSV(w *csv.Writer) error {
+	header := []string{"id", "first_name", "last_name"}
+	for _, col := range r.Columns {
+		header = append(header, col.Format(timetracking.DateFormat))
+	}
+	header = append(header, "total", "target", "under_capacity")
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	hours := func(d timetracking.Duration) string {
+		return strconv.FormatFloat(time.Duration(d).Hours(), 'f', 2, 64)
+	}
+
+	for _, row := range r.Rows {
+		rec := []string{strconv.Itoa(row.User.ID), row.User.FirstName, row.User.LastName}
+		for _, h := range row.Hours {
+			rec = append(rec, hours(h))
+		}
+		rec = append(rec, hours(row.Total), hours(row.Target), strconv.FormatBool(row.Under))
+		if err := w.Write(rec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
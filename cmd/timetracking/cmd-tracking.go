@@ -3,7 +3,13 @@ package main
 import (
 	"flag"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/parse"
+	"github.com/frizinak/harvest-timetracking/timetracking"
 )
 
 func commandTracking(c *Command) (int, error) {
@@ -13,36 +19,80 @@ func commandTracking(c *Command) (int, error) {
 	var customDate string
 	var onlyWorkedDays bool
 	var group string
+	var filters entryFilter
+	var split bool
+	var profits bool
+	var chart bool
+	var expenses bool
+	var customTo string
+	var issueRegex string
+	var issue string
+	var alias string
+	var noRounding bool
+	var compare string
+	period := make(map[string]*bool, len(timetracking.Periods))
 	flag.IntVar(&userID, "uid", 0, "The user id of the user to fetch time entries for")
 	flag.IntVar(&days, "days", 20, "Amount of days to retrieve time entries for")
 	flag.IntVar(&customCapacity, "hours", 0, "Amount of hours in a single workweek (default: from harvest api)")
 	flag.BoolVar(&onlyWorkedDays, "worked", false, "Only track days that have tracking entries")
+	filters.Flags()
+	flag.BoolVar(&split, "split", false, "Show billable and non-billable hours and revenue per group")
+	flag.BoolVar(&profits, "profit", false, "Show revenue, cost and profit per group, filling in missing rates (cost rates require an administrator)")
+	flag.BoolVar(&chart, "chart", false, "Show the hours per group as a bar chart")
+	flag.BoolVar(&expenses, "expenses", false, "Summarize expenses of the same period")
+	flag.StringVar(&issueRegex, "issue-regex", "", "Regex that extracts the issue key from notes (default: issue_regex from config or "+timetracking.DefaultIssueRegex+")")
+	flag.StringVar(&issue, "issue", "", "Only include entries with this issue key")
+	flag.BoolVar(&noRounding, "no-rounding", false, "Report the tracked hours without the rounding of the config")
+	flag.StringVar(&compare, "compare", "", "Show the report next to the "+comparePrevious+" equivalent period (requires a date range)")
+	flag.StringVar(&alias, "alias", "", "Only include entries of the project (and task) of this alias")
 	flag.StringVar(
 		&group,
 		"group",
-		groupByDay,
-		fmt.Sprintf(
-			"Group results by %s|%s|%s|%s",
-			groupByDay,
-			groupByWeek,
-			groupByMonth,
-			groupByYear,
-		),
+		timetracking.GroupByDay,
+		fmt.Sprintf("Group results by %s or tag:prefix", strings.Join(timetracking.Groups, "|")),
 	)
 	flag.StringVar(
 		&customDate,
 		"from",
 		"",
 		fmt.Sprintf(
-			"Custom date to start at [YYYY-MM-DD or %s or %s]",
+			"Custom date to start at [YYYY-MM-DD, yesterday, last friday, ... or %s or %s]",
 			endOfWeek,
 			nextWeek,
 		),
 	)
+	flag.StringVar(&customTo, "to", "", "Last day of a date range [YYYY-MM-DD, yesterday, ...], -from is then the first day")
+	for _, p := range timetracking.Periods {
+		period[p] = flag.Bool(p, false, fmt.Sprintf("Report on the %s date range", p))
+	}
 	//userName := flag.String("user", "", "The user name to fetch time entries for")
 	flag.Parse()
 
-	_, config, err := getConfig(c.l)
+	var rangeFrom, rangeTo time.Time
+	var rangeMode bool
+	var namedPeriod string
+	for p, set := range period {
+		if !*set {
+			continue
+		}
+		if namedPeriod != "" {
+			return 1, fmt.Errorf("Only one of -%s can be used", strings.Join(timetracking.Periods, ", -"))
+		}
+		namedPeriod = p
+	}
+
+	if customTo != "" && (namedPeriod != "" || customDate == "") {
+		return 1, fmt.Errorf("-to requires -from and can not be combined with a named period")
+	}
+
+	if compare != "" && compare != comparePrevious {
+		return 1, fmt.Errorf("Invalid -compare '%s' expected %s", compare, comparePrevious)
+	}
+	if compare != "" && customTo == "" && namedPeriod == "" {
+		return 1, fmt.Errorf("-compare requires -from and -to or a named period")
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
 	if err != nil {
 		return 1, err
 	}
@@ -51,104 +101,168 @@ func commandTracking(c *Command) (int, error) {
 		return 1, nil
 	}
 
-	t, err := New(c.l, config)
+	t, err := c.New(config)
 	if err != nil {
 		return 1, err
 	}
 
-	switch group {
-	case groupByDay:
-	case groupByWeek:
-	case groupByMonth:
-	case groupByYear:
-	default:
-		return 1, fmt.Errorf("Invalid group '%s'", group)
+	if customTo != "" {
+		if rangeFrom, err = parse.Date(customDate, t.Now()); err != nil {
+			return 1, err
+		}
+		if rangeTo, err = parse.Date(customTo, t.Now()); err != nil {
+			return 1, err
+		}
+		if rangeTo.Before(rangeFrom) {
+			return 1, fmt.Errorf("-to should not be before -from")
+		}
+		rangeMode = true
+	}
+
+	if issueRegex != "" {
+		re, err := regexp.Compile(issueRegex)
+		if err != nil {
+			return 1, fmt.Errorf("Invalid -issue-regex '%s': %w", issueRegex, err)
+		}
+		t.SetIssueRegex(re)
+	}
+
+	if _, err := t.Grouper(group); err != nil {
+		return 1, err
+	}
+
+	if err := t.LoadCompany(c.ctx); err != nil {
+		return 1, err
+	}
+
+	if namedPeriod != "" {
+		if rangeFrom, rangeTo, err = t.Period(namedPeriod, t.Now()); err != nil {
+			return 1, err
+		}
+		rangeMode = true
+	}
+
+	filter, err := filters.Filter(nil)
+	if err != nil {
+		return 1, err
+	}
+
+	if issue != "" {
+		re, prev := t.IssueRegex(), filter
+		filter = func(e *harvest.TimeEntry) bool {
+			return strings.EqualFold(e.IssueKey(re), issue) && (prev == nil || prev(e))
+		}
+	}
+
+	if alias != "" {
+		a, err := config.Alias(alias)
+		if err != nil {
+			return 1, err
+		}
+		prev := filter
+		filter = func(e *harvest.TimeEntry) bool {
+			return a.Matches(e) && (prev == nil || prev(e))
+		}
 	}
 
-	from := time.Now()
+	from := t.Now()
 	switch {
+	case rangeMode:
+		from = rangeFrom
 	case customDate == endOfWeek || customDate == nextWeek:
-		wd := from.Weekday() - 1
-		if wd < 0 {
-			wd = 6
-		}
-		from = from.AddDate(0, 0, 6-int(wd))
+		from = t.StartOfWeek(from).AddDate(0, 0, 6)
 		if customDate == nextWeek {
 			from = from.AddDate(0, 0, 7)
 		}
 
 	case customDate != "":
-		f, err := time.Parse(dateFormat, customDate)
+		f, err := parse.Date(customDate, t.Now())
 		if err != nil {
-			return 1, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", customDate)
+			return 1, err
 		}
 		from = f
 	}
 
-	if err := t.SetUID(userID); err != nil {
+	if err := t.SetUID(c.ctx, userID); err != nil {
 		return 1, err
 	}
 
-	workWeek := float64(config.WorkWeek())
-	capacity := Duration(t.User().Capacity())
+	capacity := t.Capacity()
 	if customCapacity != 0 {
-		capacity = Duration(customCapacity) * Duration(time.Hour)
+		capacity = timetracking.Duration(customCapacity) * timetracking.Duration(time.Hour)
 	}
-	daysCapacity := Duration(
-		float64(capacity) * float64(days) / workWeek,
-	)
-	onlyWorkedDaysCopy := ""
-	if onlyWorkedDays {
-		onlyWorkedDaysCopy = " (estimate)"
-	}
-
-	c.l.Printf(
-		"Running for %s %s\nID: %d\nWeek: %s\nOver %d days%s: %s\nFrom: %s\n\n",
-		t.User().FirstName,
-		t.User().LastName,
-		t.User().ID,
-		capacity,
-		days,
-		onlyWorkedDaysCopy,
-		daysCapacity,
-		from.Format("Mon Jan 02 2006"),
-	)
 
-	daysWorked, grouped, err := t.GetRecentDaysGrouped(days, from, !onlyWorkedDays, group)
-	daysCapacity = Duration(
-		float64(capacity) * float64(daysWorked) / workWeek,
-	)
+	q := reportQuery{
+		from:     from,
+		days:     days,
+		worked:   onlyWorkedDays,
+		group:    group,
+		capacity: capacity,
+		filter:   filter,
+		rates:    profits,
+	}
+	if rangeMode {
+		q.to = &rangeTo
+	}
+	if !noRounding {
+		q.rounding = config.Rounding
+	}
 
-	var sum time.Duration
-	for _, e := range grouped.SortSpent() {
-		days := make(map[string]struct{}, 1)
-		for _, d := range e.SpentDates {
-			days[d.Format(dateFormat)] = struct{}{}
-		}
-		should := Duration(float64(capacity) * float64(len(days)) / workWeek)
-		c.l.Printf(
-			"%s - %5s / %s (%.2f%%)",
-			e.FirstSpentDate.Format("Mon Jan 02 2006"),
-			Duration(e.Hours),
-			should,
-			100*float64(e.Hours)/float64(should),
-		)
-		sum += e.Hours
-	}
-
-	diff := daysCapacity - Duration(sum)
-	diffStr := fmt.Sprintf("%s remaining...", diff)
-	if diff < 0 {
-		diffStr = "target reached!"
-	}
-
-	c.l.Printf(
-		"\nTotal: %s / %s (%.2f%%)\n%s",
-		Duration(sum),
-		daysCapacity,
-		100*float64(sum)/float64(daysCapacity),
-		diffStr,
-	)
+	report, entries, grouped, err := newReport(c.ctx, t, config, q)
+	if err != nil {
+		return 1, err
+	}
+	report.Split, report.Profits, report.Chart = split, profits, chart
+
+	if compare != "" {
+		prevFrom, prevTo := timetracking.PreviousPeriod(rangeFrom, rangeTo)
+		q.from, q.to = prevFrom, &prevTo
+		previous, _, _, err := newReport(c.ctx, t, config, q)
+		if err != nil {
+			return 1, err
+		}
+
+		if err := c.RenderReport(newComparison(report, previous, t.WeekStart())); err != nil {
+			return 1, err
+		}
+
+		return 0, nil
+	}
+
+	if c.wants(formatHTML) {
+		projects, err := t.Group(entries, timetracking.GroupByProject, filter)
+		if err != nil {
+			return 1, err
+		}
+		report.Projects = make([]*ReportGroup, 0, len(projects))
+		for _, e := range projects.SortHours() {
+			report.Projects = append(report.Projects, newReportGroup(e, config, capacity))
+		}
+	}
+
+	if expenses {
+		first, last := from, from
+		if rangeMode {
+			first, last = rangeFrom, rangeTo
+		}
+		for _, e := range grouped {
+			for _, d := range e.SpentDates {
+				if d.Before(first) {
+					first = d
+				}
+			}
+		}
+
+		list, err := t.GetExpensesBetween(c.ctx, first, last)
+		if err != nil {
+			return 1, err
+		}
+		report.Expenses = summarizeExpenses(list)
+	}
+
+	if err := c.RenderReport(report); err != nil {
+		return 1, err
+	}
 
 	return 0, nil
 }
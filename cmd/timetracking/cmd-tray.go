@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+// trayRecentDays is how far back the tasks offered in the tray menu go.
+const trayRecentDays = 14
+
+func commandTray(c *Command) (int, error) {
+	var xbar bool
+	var interval time.Duration
+	var recent int
+	flag.BoolVar(&xbar, "xbar", false, "Print the menu as an xbar or SwiftBar plugin (macOS menu bar) and exit")
+	flag.DurationVar(&interval, "interval", 10*time.Second, "How often to refresh the tray icon and menu")
+	flag.IntVar(&recent, "recent", 5, "Amount of recently tracked tasks that can be started from the menu")
+	flag.Parse()
+
+	if interval < time.Second {
+		return 1, errors.New("-interval should be at least a second")
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	if !xbar && runtime.GOOS == "darwin" {
+		return 1, errors.New("Use tray -xbar as an xbar or SwiftBar plugin on macOS")
+	}
+
+	yad, err := exec.LookPath("yad")
+	if !xbar && err != nil {
+		return 1, errors.New("Tray needs yad (https://github.com/v1cont/yad), or use tray -xbar")
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return 1, err
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	tray := &tray{t: t, conf: config, self: self, profile: c.profile, recent: recent}
+	if xbar {
+		m, err := tray.menu(c.ctx)
+		if err != nil {
+			return 1, err
+		}
+		m.xbar(c.l.Writer())
+		return 0, nil
+	}
+
+	if err := tray.run(c, yad, interval); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// tray keeps a yad notification icon up to date, its menu items run this
+// binary to start and stop timers.
+type tray struct {
+	t       *timetracking.Timetracking
+	conf    *timetracking.Config
+	self    string
+	profile string
+	recent  int
+}
+
+type trayItem struct {
+	Label string
+	// Args of the command the item runs, nil for a line of text.
+	Args []string
+}
+
+type trayMenu struct {
+	Running bool
+	Title   string
+	Items   []trayItem
+}
+
+// command returns the arguments to run a timetracking command with the
+// same profile and config.
+func (tr *tray) command(name string, args ...string) []string {
+	cmd := []string{tr.self, name}
+	if tr.profile != "" {
+		cmd = append(cmd, "-profile", tr.profile)
+	}
+	if overrides.Path != "" {
+		cmd = append(cmd, "-config", overrides.Path)
+	}
+
+	return append(cmd, args...)
+}
+
+func (tr *tray) menu(ctx context.Context) (*trayMenu, error) {
+	s, err := newStatusBar(ctx, tr.t, tr.conf)
+	if err != nil {
+		return nil, err
+	}
+
+	today := tr.t.Today()
+	entries, err := tr.t.GetTimeEntriesBetween(ctx, today.AddDate(0, 0, -trayRecentDays), today)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &trayMenu{Running: s.Running != nil, Title: s.Line()}
+	m.Items = append(
+		m.Items,
+		trayItem{Label: fmt.Sprintf("Today: %s / %s", clock(s.Today), clock(s.TodayTarget))},
+		trayItem{Label: fmt.Sprintf("Week: %s / %s", clock(s.Week), clock(s.WeekTarget))},
+	)
+	if s.Running != nil {
+		m.Items = append(m.Items, trayItem{Label: "Stop timer", Args: tr.command("stop")})
+	}
+
+	for _, e := range recentTasks(entries, tr.recent) {
+		if s.Running != nil && s.Running.Project.ID == e.Project.ID && s.Running.Task.ID == e.Task.ID {
+			continue
+		}
+		m.Items = append(m.Items, trayItem{
+			Label: fmt.Sprintf("Start %s - %s", e.Project.Name, e.Task.Name),
+			Args: tr.command(
+				"start",
+				"-project-id", strconv.Itoa(e.Project.ID),
+				"-task-id", strconv.Itoa(e.Task.ID),
+			),
+		})
+	}
+
+	return m, nil
+}
+
+// recentTasks returns an entry of each of the last amount distinct
+// project tasks, most recently tracked first.
+func recentTasks(entries harvest.TimeEntries, amount int) harvest.TimeEntries {
+	entries = entries.SortSpent()
+	seen := make(map[string]struct{}, amount)
+	recent := make(harvest.TimeEntries, 0, amount)
+	for _, e := range entries {
+		if len(recent) == amount {
+			break
+		}
+		key := fmt.Sprintf("%d|%d", e.Project.ID, e.Task.ID)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		recent = append(recent, e)
+	}
+
+	return recent
+}
+
+// run starts yad in listen mode and sends it the icon, tooltip
+// and menu every interval until the context is cancelled.
+func (tr *tray) run(c *Command, yad string, interval time.Duration) error {
+	cmd := exec.CommandContext(c.ctx, yad, "--notification", "--listen", "--command=menu", "--text=timetracking")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	w := bufio.NewWriter(stdin)
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		m, err := tr.menu(c.ctx)
+		if err != nil {
+			c.logger().Error("Failed to refresh the tray", "err", err)
+			m = &trayMenu{Title: err.Error()}
+		}
+		m.yad(w)
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		select {
+		case <-c.ctx.Done():
+			return nil
+		case err := <-done:
+			return err
+		case <-tick.C:
+		}
+	}
+}
+
+// yad writes the commands of yad --notification --listen.
+func (m *trayMenu) yad(w io.Writer) {
+	icon := "appointment-soon"
+	if m.Running {
+		icon = "media-record"
+	}
+
+	items := make([]string, 0, len(m.Items))
+	for _, item := range m.Items {
+		label := strings.NewReplacer("!", " ", "|", " ", "\n", " ").Replace(item.Label)
+		cmd := make([]string, len(item.Args))
+		for i, arg := range item.Args {
+			cmd[i] = shellQuote(arg)
+		}
+		items = append(items, label+"!"+strings.Join(cmd, " "))
+	}
+
+	fmt.Fprintf(w, "icon:%s\n", icon)
+	fmt.Fprintf(w, "tooltip:%s\n", strings.ReplaceAll(m.Title, "\n", " "))
+	fmt.Fprintf(w, "menu:%s\n", strings.Join(items, "|"))
+}
+
+// xbar writes the menu in the plugin format of xbar and SwiftBar.
+func (m *trayMenu) xbar(w io.Writer) {
+	fmt.Fprintln(w, strings.ReplaceAll(m.Title, "|", "/"))
+	fmt.Fprintln(w, "---")
+	for _, item := range m.Items {
+		label := strings.ReplaceAll(item.Label, "|", "/")
+		if item.Args == nil {
+			fmt.Fprintln(w, label)
+			continue
+		}
+
+		params := make([]string, 0, len(item.Args))
+		params = append(params, "bash="+strconv.Quote(item.Args[0]))
+		for i, arg := range item.Args[1:] {
+			params = append(params, fmt.Sprintf("param%d=%s", i+1, strconv.Quote(arg)))
+		}
+		fmt.Fprintf(w, "%s | %s terminal=false refresh=true\n", label, strings.Join(params, " "))
+	}
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func commandTUI(c *Command) (int, error) {
+	flag.Parse()
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	if err := t.LoadCompany(c.ctx); err != nil {
+		return 1, err
+	}
+
+	if config.ForecastAccountID != "" {
+		if err := t.SetForecastUID(c.ctx, 0); err != nil {
+			return 1, err
+		}
+	}
+
+	term, err := rawTerminal()
+	if err != nil {
+		return 1, err
+	}
+
+	err = newDashboard(c.ctx, t).Run(os.Stdout, os.Stdin)
+	fmt.Print(ansiClear)
+	if rerr := term.Restore(); err == nil {
+		err = rerr
+	}
+	if err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
@@ -1,6 +1,27 @@
 package main
 
+import (
+	"encoding/json"
+	"flag"
+)
+
+type Version string
+
+func (v Version) Text(l *Printer) {
+	l.Println(string(v))
+}
+
+func (v Version) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Version string `json:"version"`
+	}{string(v)})
+}
+
 func commandVersion(c *Command) (int, error) {
-	c.l.Println(v)
+	flag.Parse()
+	if err := c.Render(Version(v)); err != nil {
+		return 1, err
+	}
+
 	return 0, nil
 }
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/idle"
+	"github.com/frizinak/harvest-timetracking/notify"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandWatch(c *Command) (int, error) {
+	var interval time.Duration
+	var noTimer time.Duration
+	var limit time.Duration
+	var away time.Duration
+	var awayStop bool
+	var missing int
+	var startStr string
+	var endStr string
+	flag.DurationVar(&interval, "interval", time.Minute, "How often to check the running timer")
+	flag.DurationVar(&noTimer, "idle", 15*time.Minute, "Notify after this long without a running timer during working hours")
+	flag.DurationVar(&limit, "limit", 8*time.Hour, "Notify once today's tracked hours pass this")
+	flag.StringVar(&startStr, "start", "09:00", "Start of working hours [HH:MM]")
+	flag.StringVar(&endStr, "end", "18:00", "End of working hours [HH:MM]")
+	flag.DurationVar(&away, "away", 0, "Ask to stop a running timer once the machine was idle this long (0: disabled)")
+	flag.IntVar(&missing, "missing", 0, "Notify once a day of working days without tracked hours in this many past weeks (0: disabled)")
+	flag.BoolVar(&awayStop, "away-stop", false, "Stop the timer after -away without asking and remove the idle time from the entry")
+	flag.Parse()
+
+	if interval < 10*time.Second {
+		return 1, errors.New("-interval should be at least 10s")
+	}
+
+	start, err := time.Parse("15:04", startStr)
+	if err != nil {
+		return 1, fmt.Errorf("Invalid -start '%s' expected HH:MM", startStr)
+	}
+	end, err := time.Parse("15:04", endStr)
+	if err != nil {
+		return 1, fmt.Errorf("Invalid -end '%s' expected HH:MM", endStr)
+	}
+
+	if away > 0 {
+		if _, err := idle.Duration(); err != nil {
+			return 1, fmt.Errorf("-away needs the idle time of this machine: %w", err)
+		}
+	}
+
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil {
+		return 1, err
+	}
+
+	if config == nil {
+		return 1, nil
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := t.SetUID(c.ctx, 0); err != nil {
+		return 1, err
+	}
+
+	w := &watcher{
+		t:        t,
+		conf:     config,
+		idle:     noTimer,
+		limit:    timetracking.Duration(limit),
+		start:    start,
+		end:      end,
+		away:     away,
+		awayStop: awayStop,
+		missing:  missing,
+		active:   t.Now(),
+		asking:   make(chan struct{}, 1),
+	}
+
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		if err := w.check(c, t.Now()); err != nil {
+			c.logger().Error("Failed to check timer", "err", err)
+		}
+
+		select {
+		case <-c.ctx.Done():
+			return 0, nil
+		case <-tick.C:
+		}
+	}
+}
+
+type watcher struct {
+	t     *timetracking.Timetracking
+	conf  *timetracking.Config
+	idle  time.Duration
+	limit timetracking.Duration
+	start time.Time
+	end   time.Time
+
+	// away is how long the machine may be idle while a timer runs, 0 when
+	// not checked.
+	away     time.Duration
+	awayStop bool
+	// missing is the amount of weeks checked for missing days.
+	missing int
+
+	day           string
+	active        time.Time
+	idleNotified  bool
+	limitNotified bool
+	awayHandled   bool
+	// asking holds a token while the away question waits for an answer.
+	asking chan struct{}
+}
+
+// workingHours reports whether now is during working hours of a working day.
+func (w *watcher) workingHours(now time.Time) bool {
+	if w.conf.Excluded(now) || w.conf.Off(now) {
+		return false
+	}
+
+	clock := now.Hour()*60 + now.Minute()
+	return clock >= w.start.Hour()*60+w.start.Minute() &&
+		clock < w.end.Hour()*60+w.end.Minute()
+}
+
+func (w *watcher) check(c *Command, now time.Time) error {
+	today := timetracking.Day(now)
+	if d := today.Format(timetracking.DateFormat); d != w.day {
+		w.day = d
+		w.limitNotified = false
+		if w.missing > 0 {
+			if err := w.notifyMissing(c, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	entries, err := w.t.GetTimeEntriesBetween(c.ctx, today, today)
+	if err != nil {
+		return err
+	}
+
+	var total timetracking.Duration
+	var running *harvest.TimeEntry
+	for _, e := range entries {
+		total += timetracking.Duration(e.Hours.Duration)
+		if e.Running {
+			running = e
+		}
+	}
+
+	if running != nil && w.away > 0 {
+		if err := w.checkAway(c, running); err != nil {
+			return err
+		}
+	}
+
+	if running != nil || !w.workingHours(now) {
+		w.active = now
+		w.idleNotified = false
+	}
+
+	if !w.idleNotified && now.Sub(w.active) >= w.idle {
+		w.idleNotified = true
+		msg := fmt.Sprintf(
+			"No timer running for %d minutes, %s tracked today",
+			int(now.Sub(w.active).Minutes()),
+			c.formatting().Hours(total),
+		)
+		if err := notify.Send("Not tracking time", msg); err != nil {
+			return err
+		}
+	}
+
+	if !w.limitNotified && total >= w.limit {
+		w.limitNotified = true
+		msg := fmt.Sprintf("You tracked %s today", c.formatting().Hours(total))
+		if err := notify.Send("Time to call it a day", msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkAway asks to stop the running timer, or stops it with -away-stop,
+// once the machine has been idle for longer than -away. The question is
+// asked in the background so the other checks keep running while nobody
+// answers. The entry keeps the time up to when the machine went idle.
+func (w *watcher) checkAway(c *Command, running *harvest.TimeEntry) error {
+	away, err := idle.Duration()
+	if err != nil {
+		return err
+	}
+
+	if away < w.away {
+		w.awayHandled = false
+		return nil
+	}
+	if w.awayHandled {
+		return nil
+	}
+	w.awayHandled = true
+	idleStart := time.Now().Add(-away)
+
+	msg := fmt.Sprintf(
+		"%s %s has been running while idle since %s",
+		running.Project.Name,
+		running.Task.Name,
+		idleStart.In(w.t.Location()).Format("15:04"),
+	)
+	if w.awayStop {
+		if err := w.stopAway(c, idleStart, msg); err != nil {
+			return err
+		}
+		return notify.Send("Stopped the timer", msg)
+	}
+
+	if err := notify.Send("Still working?", msg); err != nil {
+		return err
+	}
+
+	stat, err := os.Stdin.Stat()
+	if err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+		return nil
+	}
+
+	select {
+	case w.asking <- struct{}{}:
+	default:
+		// Still waiting for the answer to an earlier question.
+		return nil
+	}
+
+	go func() {
+		defer func() { <-w.asking }()
+		c.l.Printf("%s. Stop the timer and remove the idle time? [y/N] ", msg)
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil {
+			c.logger().Error("Failed to read answer", "err", err)
+			return
+		}
+		if a := strings.ToLower(strings.TrimSpace(line)); a != "y" && a != "yes" {
+			return
+		}
+		if err := w.stopAway(c, idleStart, msg); err != nil {
+			c.logger().Error("Failed to stop timer", "err", err)
+		}
+	}()
+
+	return nil
+}
+
+// stopAway stops the running timer and removes the time since idleStart
+// from its entry.
+func (w *watcher) stopAway(c *Command, idleStart time.Time, msg string) error {
+	entry, err := w.t.StopTracker(c.ctx)
+	if err != nil || entry == nil {
+		return err
+	}
+
+	removed := time.Since(idleStart)
+	hours := entry.Hours.Duration - removed
+	if hours < 0 {
+		hours = 0
+	}
+	h := hours.Hours()
+	notes := strings.TrimSpace(fmt.Sprintf("%s (stopped after %s idle)", entry.Notes, c.formatting().Hours(timetracking.Duration(removed))))
+	entry, err = w.t.UpdateEntry(c.ctx, entry.ID, &harvest.UpdateTimeEntryBody{Hours: &h, Notes: &notes})
+	if err != nil {
+		return err
+	}
+
+	c.l.Printf("Stopped %d at %s: %s", entry.ID, c.formatting().Hours(timetracking.Duration(entry.Hours.Duration)), msg)
+	return nil
+}
+
+// notifyMissing notifies of the days without tracked hours in the last
+// -missing weeks.
+func (w *watcher) notifyMissing(c *Command, now time.Time) error {
+	from, to := missingPeriod(now, w.missing)
+	days, err := w.t.GetMissingDays(c.ctx, w.t.Capacity(), from, to, false)
+	if err != nil || len(days) == 0 {
+		return err
+	}
+
+	dates := make([]string, 0, len(days))
+	for _, d := range days {
+		dates = append(dates, d.Date.Format("Mon 01-02"))
+	}
+	msg := fmt.Sprintf("Nothing tracked on %s", strings.Join(dates, ", "))
+
+	return notify.Send(fmt.Sprintf("%d days missing", len(days)), msg)
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func commandWeekStatus(c *Command) (int, error) {
+	var userID int
+	var date string
+	flag.IntVar(&userID, "uid", 0, "The user id of the user to show the timesheet of")
+	flag.StringVar(&date, "date", "", "A day in the week [YYYY-MM-DD] (default: today)")
+	flag.Parse()
+
+	t, _, status, err := weekStatus(c, userID, date)
+	if err != nil || t == nil {
+		return 1, err
+	}
+
+	if err := c.RenderReport(status); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+func commandSubmitWeek(c *Command) (int, error) {
+	var date string
+	var force bool
+	flag.StringVar(&date, "date", "", "A day in the week [YYYY-MM-DD] (default: today)")
+	flag.BoolVar(&force, "force", false, "Submit even when lint rules fail")
+	flag.Parse()
+
+	t, config, status, err := weekStatus(c, 0, date)
+	if err != nil || t == nil {
+		return 1, err
+	}
+
+	if status.Status != harvest.ApprovalUnsubmitted {
+		return 1, fmt.Errorf(
+			"The week of %s is already %s",
+			status.From.Format("Mon Jan 02 2006"),
+			status.Status,
+		)
+	}
+
+	if lint := config.Lint; lint != nil && lint.SubmitWeek && len(lint.Rules) != 0 {
+		results, err := t.WithTimeOff(c.ctx, status.From, status.To).Lint(c.ctx, lint.Rules, status.From, status.To)
+		if err != nil {
+			return 1, err
+		}
+		if len(results) != 0 {
+			if err := c.Render(Lint(results)); err != nil {
+				return 1, err
+			}
+		}
+		if results.Errors() != 0 && !force {
+			return 1, errors.New("Fix the lint errors or submit with -force")
+		}
+	}
+
+	company, err := t.GetCompany(c.ctx)
+	if err != nil {
+		return 1, err
+	}
+
+	// The harvest api has no endpoint to submit timesheets,
+	// so submit it in the web interface.
+	u := fmt.Sprintf(
+		"https://%s/time/week/%s",
+		company.FullDomain,
+		status.From.Format("2006/01/02"),
+	)
+	c.l.Printf("Submit the week of %s (%s tracked) at %s", status.From.Format("Mon Jan 02 2006"), c.formatting().Hours(status.Hours), u)
+	if err := openBrowser(u); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+func weekStatus(c *Command, userID int, date string) (*timetracking.Timetracking, *timetracking.Config, *WeekStatus, error) {
+	_, config, err := getConfig(c.l, c.profile)
+	if err != nil || config == nil {
+		return nil, nil, nil, err
+	}
+
+	t, err := c.New(config)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	when := t.Now()
+	if date != "" {
+		if when, err = t.ParseDate(date); err != nil {
+			return nil, nil, nil, fmt.Errorf("Invalid date '%s' expected YYYY-mm-dd", date)
+		}
+	}
+
+	if err := t.SetUID(c.ctx, userID); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := t.LoadCompany(c.ctx); err != nil {
+		return nil, nil, nil, err
+	}
+
+	from := t.StartOfWeek(when)
+	to := from.AddDate(0, 0, 6)
+	entries, err := t.GetTimeEntriesBetween(c.ctx, from, to)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	status := &WeekStatus{
+		User:   NewReportUser(t.User(), config.WeekTarget(t.Capacity())),
+		From:   from,
+		To:     to,
+		Status: entries.ApprovalStatus(),
+	}
+
+	reasons := make(map[string]struct{})
+	for _, e := range entries {
+		status.Hours += timetracking.Duration(e.Hours.Duration)
+		if e.LockedReason != "" {
+			reasons[e.LockedReason] = struct{}{}
+		}
+	}
+	for r := range reasons {
+		status.Reasons = append(status.Reasons, r)
+	}
+	sort.Strings(status.Reasons)
+
+	return t, config, status, nil
+}
+
+type WeekStatus struct {
+	User    ReportUser            `json:"user"`
+	From    time.Time             `json:"from"`
+	To      time.Time             `json:"to"`
+	Status  string                `json:"status"`
+	Hours   timetracking.Duration `json:"hours"`
+	Reasons []string              `json:"locked_reasons"`
+}
+
+func (w *WeekStatus) Text(l *Printer) {
+	l.Printf(
+		"Week of %s - %s for %s %s",
+		w.From.Format("Mon Jan 02 2006"),
+		w.To.Format("Mon Jan 02 2006"),
+		w.User.FirstName,
+		w.User.LastName,
+	)
+	l.Printf("Tracked: %s / %s", l.Hours(w.Hours), l.Hours(w.User.Capacity))
+	l.Printf("Status: %s", w.Status)
+	for _, r := range w.Reasons {
+		l.Printf("  %s", r)
+	}
+}
@@ -1,8 +1,19 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"log/slog"
+	"net/http"
+	"os"
 	"sort"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/cache"
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
 )
 
 type Cmd struct {
@@ -11,8 +22,261 @@ type Cmd struct {
 }
 
 type Command struct {
+	ctx      context.Context
 	l        *log.Logger
+	format   string
+	outputs  outputs
+	noCache  bool
+	profile  string
+	tz       string
+	verbose  bool
+	debug    bool
+	logJSON  bool
+	record   string
+	replay   string
+	maxReqs  int
 	commands map[string]*Cmd
+
+	name    string
+	hooks   *timetracking.HooksConfig
+	log     *slog.Logger
+	formats map[string]func() EntryFormatter
+	// client is the Timetracking newClient created last, output is
+	// formatted with its settings.
+	client *timetracking.Timetracking
+}
+
+// logger returns the logger for diagnostics on stderr, warnings and errors
+// are always logged, -v adds progress and -vv every request.
+// It is created on first use as the flags have to be parsed by then.
+func (c *Command) logger() *slog.Logger {
+	if c.log != nil {
+		return c.log
+	}
+
+	level := slog.LevelWarn
+	switch {
+	case c.debug:
+		level = slog.LevelDebug
+	case c.verbose:
+		level = slog.LevelInfo
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	var h slog.Handler = slog.NewTextHandler(os.Stderr, opts)
+	if c.logJSON {
+		h = slog.NewJSONHandler(os.Stderr, opts)
+	}
+
+	c.log = slog.New(h)
+	return c.log
+}
+
+// New creates a Timetracking instance configured with the global flags,
+// days start and end in the timezone of setTimezone.
+func (c *Command) New(conf *timetracking.Config) (*timetracking.Timetracking, error) {
+	t, err := c.newClient(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.setTimezone(t, conf); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// newClient is New without looking up the timezone, for commands that do
+// not care which day it is.
+func (c *Command) newClient(conf *timetracking.Config) (*timetracking.Timetracking, error) {
+	opts := []harvest.Option{harvest.WithUserAgent("timetracking/" + v)}
+	if client := c.replayClient(conf); client != nil {
+		opts = append(opts, harvest.WithHTTPClient(client))
+	}
+	if c.maxReqs > 0 {
+		opts = append(opts, harvest.WithRequestBudget(&harvest.RequestBudget{Max: c.maxReqs}))
+	}
+	if p := newProgressBar(); p != nil && !c.logJSON {
+		opts = append(opts, harvest.WithProgress(p.Progress))
+	}
+
+	t, err := timetracking.New(c.logger(), conf, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.tz != "" {
+		loc, err := timetracking.LoadLocation(c.tz)
+		if err != nil {
+			return nil, err
+		}
+		t.SetLocation(loc)
+	}
+
+	if conf.OAuth.LoggedIn() {
+		var ts harvest.TokenSource = harvest.NewRefreshingTokenSource(
+			conf.OAuth.OAuth(),
+			conf.OAuth.Token(),
+			c.saveToken,
+		)
+		// Replays are offline, an expired token is not refreshed or saved.
+		if c.replay != "" {
+			ts = harvest.StaticTokenSource(conf.OAuth.AccessToken)
+		}
+		t.SetTokenSource(ts)
+	}
+
+	// Cached entries would not be recorded or would hide replayed ones.
+	if !c.noCache && c.record == "" && c.replay == "" {
+		cache, err := cache.UserCache("timetracking", 0)
+		if err != nil {
+			return nil, err
+		}
+		t.SetCache(cache)
+	}
+
+	c.hooks = conf.Hooks
+	c.client = t
+
+	return t, nil
+}
+
+// replayClient returns the http client for -record or -replay, nil if
+// neither is set. Recording happens below the retries so every attempt is
+// written, replays are never retried.
+func (c *Command) replayClient(conf *timetracking.Config) *http.Client {
+	switch {
+	case c.replay != "":
+		return &http.Client{
+			Transport: &harvest.RetryTransport{
+				Transport:   &harvest.ReplayTransport{Dir: c.replay},
+				MaxAttempts: 1,
+			},
+		}
+	case c.record != "":
+		attempts := conf.MaxAttempts
+		if attempts == 0 {
+			attempts = harvest.DefaultMaxAttempts
+		}
+		return &http.Client{
+			Transport: &harvest.RetryTransport{
+				Transport:   &harvest.RecordTransport{Dir: c.record},
+				MaxAttempts: attempts,
+			},
+		}
+	}
+
+	return nil
+}
+
+// setTimezone makes days of t start and end in the timezone of your harvest
+// profile when neither -tz nor the config set one, regardless of where this
+// machine is.
+func (c *Command) setTimezone(t *timetracking.Timetracking, conf *timetracking.Config) error {
+	if c.tz != "" || conf.Timezone != "" {
+		return nil
+	}
+
+	name, err := t.HarvestTimezone(c.ctx)
+	if err != nil || name == "" {
+		return err
+	}
+
+	loc, err := timetracking.LoadLocation(name)
+	if err != nil {
+		return err
+	}
+
+	t.SetLocation(loc)
+	return nil
+}
+
+// location returns the timezone of -tz or the config, the local one if
+// neither is set, for commands that do not create a Timetracking.
+func (c *Command) location(conf *timetracking.Config) (*time.Location, error) {
+	name := c.tz
+	if name == "" {
+		name = conf.Timezone
+	}
+	if name == "" {
+		return time.Local, nil
+	}
+
+	return timetracking.LoadLocation(name)
+}
+
+func (c *Command) saveToken(token *harvest.Token) error {
+	confLoader, err := configLoader(c.l)
+	if err != nil {
+		return err
+	}
+
+	raw := &timetracking.Config{}
+	if err := confLoader.Read(raw); err != nil {
+		return err
+	}
+
+	w := raw.Writable(c.profile)
+	if w.OAuth == nil {
+		w = raw
+	}
+	if w.OAuth == nil {
+		return errors.New("No oauth configuration to store the refreshed token in")
+	}
+
+	w.OAuth.SetToken(token)
+	return confLoader.Create(raw)
+}
+
+// Render writes v in -format to stdout, or in every -output when given.
+func (c *Command) Render(v interface{}) error {
+	return c.RenderAs(v, nil)
+}
+
+// RenderAs renders v, outputs of a format in as render its value instead
+// (e.g. a timesheet for pdf).
+func (c *Command) RenderAs(v interface{}, as map[string]interface{}) error {
+	if len(c.outputs) != 0 {
+		return c.renderOutputs(v, as)
+	}
+
+	r, err := NewRenderer(c.format, c.l, c.exportFormats(), c.formatting())
+	if err != nil {
+		return err
+	}
+
+	if alt, ok := as[c.format]; ok {
+		v = alt
+	}
+	return r.Render(v)
+}
+
+// formatting returns how the output of c prints hours, money and times,
+// with the settings of its client once it has one.
+func (c *Command) formatting() *Formatting {
+	return NewFormatting(c.client)
+}
+
+// RenderReport renders the report v and passes it to the post_report hook.
+func (c *Command) RenderReport(v interface{}) error {
+	if err := c.Render(v); err != nil {
+		return err
+	}
+
+	return c.postReport(v)
+}
+
+// shiftArg removes and returns the first positional argument (the
+// (sub)command) so the remaining arguments can be parsed as flags.
+func shiftArg() string {
+	if len(os.Args) > 1 && os.Args[1] != "" && os.Args[1][0] != '-' {
+		arg := os.Args[1]
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+		return arg
+	}
+
+	return ""
 }
 
 func (c *Command) Run(arg string) (int, error) {
@@ -21,9 +285,29 @@ func (c *Command) Run(arg string) (int, error) {
 		return commandHelp(c)
 	}
 
+	c.name = arg
 	return cmd.Command(c)
 }
 
+// explain adds a hint on how to resolve common api errors.
+func explain(err error) string {
+	var rateLimit *harvest.RateLimitError
+	switch {
+	case errors.Is(err, harvest.ErrTokenExpired):
+		return fmt.Sprintf("%s\nYour login expired, run `timetracking auth login`", err)
+	case errors.Is(err, harvest.ErrUnauthorized):
+		return fmt.Sprintf("%s\nYour token is invalid or expired, run `timetracking auth login` or update the token in your config", err)
+	case errors.Is(err, harvest.ErrForbidden):
+		return fmt.Sprintf("%s\nYour harvest role does not allow this, ask an administrator", err)
+	case errors.As(err, &rateLimit):
+		return fmt.Sprintf("%s\nHarvest is limiting the amount of requests, try again later", err)
+	case errors.Is(err, harvest.ErrBudgetExceeded):
+		return fmt.Sprintf("%s\nUse a shorter period or raise -max-requests", err)
+	}
+
+	return err.Error()
+}
+
 func commandHelp(c *Command) (int, error) {
 	cmds := make([]string, 0, len(c.commands))
 	for i := range c.commands {
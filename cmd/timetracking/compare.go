@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+// comparePrevious compares a report with the period before it, the only
+// mode of tracking -compare.
+const comparePrevious = "previous"
+
+// Comparison is a report next to the report of the period before it, with
+// the groups of both aligned.
+type Comparison struct {
+	Current  *Report          `json:"current"`
+	Previous *Report          `json:"previous"`
+	Groups   []*ComparedGroup `json:"groups"`
+}
+
+// ComparedGroup is a group of the current report and the matching group of
+// the previous one, either can be missing.
+type ComparedGroup struct {
+	Name         string                `json:"name"`
+	Date         *time.Time            `json:"date,omitempty"`
+	PreviousName string                `json:"previous_name"`
+	PreviousDate *time.Time            `json:"previous_date,omitempty"`
+	Hours        timetracking.Duration `json:"hours"`
+	Previous     timetracking.Duration `json:"previous_hours"`
+
+	offset int
+}
+
+func (g *ComparedGroup) Delta() timetracking.Duration {
+	return g.Hours - g.Previous
+}
+
+// Change returns the relative change in percent, 0 when nothing was tracked
+// in the previous period.
+func (g *ComparedGroup) Change() float64 {
+	if g.Previous == 0 {
+		return 0
+	}
+	return 100 * float64(g.Delta()) / float64(g.Previous)
+}
+
+// newComparison aligns the groups of current and previous. Date groups
+// match when they are as far from the start of their period, e.g. the
+// mondays of two weeks or the 3rd of two months, other groups by key.
+// Weeks start on weekStart.
+func newComparison(current, previous *Report, weekStart time.Weekday) *Comparison {
+	c := &Comparison{Current: current, Previous: previous}
+	date := timetracking.DateGroup(current.Group)
+	key := func(r *Report, g *ReportGroup) (string, int) {
+		if !date {
+			return g.Name, 0
+		}
+		offset := periodOffset(r.Group, r.From, g.Date, weekStart)
+		return strconv.Itoa(offset), offset
+	}
+
+	byKey := make(map[string]*ComparedGroup, len(current.Groups))
+	for _, g := range current.Groups {
+		k, offset := key(current, g)
+		cg, ok := byKey[k]
+		if !ok {
+			cg = &ComparedGroup{Name: g.Name, offset: offset}
+			if date {
+				d := g.Date
+				cg.Date = &d
+			}
+			byKey[k] = cg
+			c.Groups = append(c.Groups, cg)
+		}
+		cg.Hours += g.Hours
+	}
+
+	for _, g := range previous.Groups {
+		k, offset := key(previous, g)
+		cg, ok := byKey[k]
+		if !ok {
+			cg = &ComparedGroup{offset: offset}
+			byKey[k] = cg
+			c.Groups = append(c.Groups, cg)
+		}
+		cg.PreviousName = g.Name
+		if date {
+			d := g.Date
+			cg.PreviousDate = &d
+		}
+		cg.Previous += g.Hours
+	}
+
+	if date {
+		// Like the report, the most recent first.
+		sort.SliceStable(c.Groups, func(i, j int) bool { return c.Groups[i].offset > c.Groups[j].offset })
+	}
+
+	return c
+}
+
+// periodOffset returns how many groups d lies after the start of a period.
+func periodOffset(group string, from, d time.Time, weekStart time.Weekday) int {
+	switch group {
+	case timetracking.GroupByWeek:
+		return int(timetracking.StartOfWeek(d, weekStart).Sub(timetracking.StartOfWeek(from, weekStart)).Hours()/24+0.5) / 7
+	case timetracking.GroupByMonth:
+		return (d.Year()-from.Year())*12 + int(d.Month()-from.Month())
+	case timetracking.GroupByYear:
+		return d.Year() - from.Year()
+	}
+
+	return int(timetracking.Day(d).Sub(timetracking.Day(from)).Hours()/24 + 0.5)
+}
+
+func (c *Comparison) Text(l *Printer) {
+	l.Printf(
+		"Running for %s %s\nFrom: %s\nTo: %s\nCompared to: %s - %s\n\n",
+		c.Current.User.FirstName,
+		c.Current.User.LastName,
+		c.Current.From.Format("Mon Jan 02 2006"),
+		c.Current.To.Format("Mon Jan 02 2006"),
+		c.Previous.From.Format("Mon Jan 02 2006"),
+		c.Previous.To.Format("Mon Jan 02 2006"),
+	)
+
+	var t *Table
+	date := timetracking.DateGroup(c.Current.Group)
+	if date {
+		t = NewTable("Date", "Hours", "Previous", "Hours", "Delta", "Change").Right(1, 3, 4, 5)
+	} else {
+		t = NewTable("Name", "Hours", "Previous", "Delta", "Change").Right(1, 2, 3, 4)
+	}
+
+	for _, g := range c.Groups {
+		change := "-"
+		if g.Previous != 0 {
+			change = fmt.Sprintf("%+.1f%%", g.Change())
+		}
+
+		style := timetracking.StyleNone
+		switch {
+		case g.Delta() < 0:
+			style = timetracking.StyleUnder
+		case g.Delta() > 0:
+			style = timetracking.StyleOnTarget
+		}
+
+		if !date {
+			t.Row(style, g.Name, l.Hours(g.Hours), l.Hours(g.Previous), l.Signed(g.Delta()), change)
+			continue
+		}
+
+		name, prev := "", ""
+		if g.Date != nil {
+			name = g.Date.Format("Mon Jan 02 2006")
+		}
+		if g.PreviousDate != nil {
+			prev = g.PreviousDate.Format("Mon Jan 02 2006")
+		}
+		t.Row(style, name, l.Hours(g.Hours), prev, l.Hours(g.Previous), l.Signed(g.Delta()), change)
+	}
+	t.Text(l)
+
+	l.Printf(
+		"\nTotal: %s (previous: %s, %s)",
+		l.Hours(c.Current.Total),
+		l.Hours(c.Previous.Total),
+		l.Signed(c.Current.Total-c.Previous.Total),
+	)
+}
+
+func (c *Comparison) CSV(w *csv.Writer) error {
+	err := w.Write([]string{"name", "previous_name", "hours", "previous_hours", "delta"})
+	if err != nil {
+		return err
+	}
+
+	hours := func(d timetracking.Duration) string {
+		return strconv.FormatFloat(time.Duration(d).Hours(), 'f', 2, 64)
+	}
+	for _, g := range c.Groups {
+		name, prev := g.Name, g.PreviousName
+		if g.Date != nil {
+			name = g.Date.Format(timetracking.DateFormat)
+		}
+		if g.PreviousDate != nil {
+			prev = g.PreviousDate.Format(timetracking.DateFormat)
+		}
+		err := w.Write([]string{name, prev, hours(g.Hours), hours(g.Previous), hours(g.Delta())})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,36 +1,190 @@
 package main
 
 import (
-	"errors"
 	"fmt"
 	"log"
 	"os"
-	"strings"
-	"time"
+	"path/filepath"
 
 	"github.com/frizinak/harvest-timetracking/config"
+	"github.com/frizinak/harvest-timetracking/keyring"
+	"github.com/frizinak/harvest-timetracking/timetracking"
 )
 
-func getConfig(l *log.Logger) (*config.ConfigLoader, *Config, error) {
-	confLoader, err := config.DotFile(
-		".timetracking",
-		&Config{
-			"-- your account id --",
-			"-- your forecast account id (optional)--",
-			defaultToken,
-			[]string{"saturday", "sunday"},
-			[]string{},
-			Tasks{},
-			nil,
-			nil,
-		},
-	)
+const keyringService = "timetracking"
+
+// configOverrides are set with flags or environment variables and take
+// precedence over the config file, which then becomes optional.
+type configOverrides struct {
+	Path              string
+	AccountID         string
+	ForecastAccountID string
+	Token             string
+}
+
+var overrides configOverrides
+
+// env fills the overrides that were not passed as a flag
+// from the environment.
+func (o configOverrides) env() configOverrides {
+	set := func(v *string, key string) {
+		if *v == "" {
+			*v = os.Getenv(key)
+		}
+	}
+	set(&o.AccountID, "HARVEST_ACCOUNT_ID")
+	set(&o.ForecastAccountID, "FORECAST_ACCOUNT_ID")
+	set(&o.Token, "HARVEST_TOKEN")
+
+	return o
+}
+
+// Complete reports whether the overrides suffice to run without a config file.
+func (o configOverrides) Complete() bool {
+	return o.AccountID != "" && o.Token != ""
+}
+
+func (o configOverrides) Apply(c *timetracking.Config) {
+	if o.AccountID != "" {
+		c.AccountID = o.AccountID
+	}
+	if o.ForecastAccountID != "" {
+		c.ForecastAccountID = o.ForecastAccountID
+	}
+	if o.Token != "" {
+		c.Token = o.Token
+		c.TokenSource = ""
+		c.OAuth = nil
+	}
+}
+
+func defaultConfig() *timetracking.Config {
+	return &timetracking.Config{
+		Version:           configVersion(),
+		AccountID:         "-- your account id --",
+		ForecastAccountID: "-- your forecast account id (optional)--",
+		Token:             defaultToken,
+		WeekdaysOff:       []string{"saturday", "sunday"},
+		ExcludedDates:     []string{},
+		Tasks:             timetracking.Tasks{},
+	}
+}
+
+const (
+	configSourceFlag   = "flag"
+	configSourceEnv    = "env"
+	configSourceXDG    = "xdg"
+	configSourceLegacy = "legacy"
+)
+
+// configPath returns the config file to use and where that choice came from,
+// see findConfig. A config in the legacy location (~/.timetracking) is moved
+// to the user config directory.
+func configPath(l *log.Logger) (string, string, error) {
+	path, source, err := findConfig()
+	if err != nil || source != configSourceLegacy {
+		return path, source, err
+	}
+
+	xdg, err := xdgConfig()
+	if err != nil {
+		return "", "", err
+	}
+
+	err = os.MkdirAll(filepath.Dir(xdg), 0700)
+	if err == nil {
+		err = os.Rename(path, xdg)
+	}
+	if err != nil {
+		l.Printf("Failed to move %s to %s: %s", path, xdg, err)
+		return path, source, nil
+	}
+
+	l.Printf("Moved config %s to %s", path, xdg)
+	return xdg, configSourceXDG, nil
+}
+
+// findConfig returns the config file to use and where that choice came from:
+// -config, TIMETRACKING_CONFIG, timetracking/config.json in the user config
+// directory or ~/.timetracking when only that one exists. Nothing is moved.
+func findConfig() (string, string, error) {
+	if overrides.Path != "" {
+		return overrides.Path, configSourceFlag, nil
+	}
+	if p := os.Getenv("TIMETRACKING_CONFIG"); p != "" {
+		return p, configSourceEnv, nil
+	}
+
+	xdg, err := xdgConfig()
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := os.Stat(xdg); err == nil {
+		return xdg, configSourceXDG, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", err
+	}
+	legacy := filepath.Join(home, ".timetracking")
+	if _, err := os.Stat(legacy); err != nil {
+		return xdg, configSourceXDG, nil
+	}
+
+	return legacy, configSourceLegacy, nil
+}
+
+// xdgConfig returns timetracking/config.json in the user config directory.
+func xdgConfig() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "timetracking", "config.json"), nil
+}
+
+func configLoader(l *log.Logger) (*config.ConfigLoader, error) {
+	path, _, err := configPath(l)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.New(path, defaultConfig()), nil
+}
+
+// loadConfig reads the config file and selects the profile, returns a nil
+// config if the config file did not exist yet.
+func loadConfig(l *log.Logger, profile string) (*config.ConfigLoader, *timetracking.Config, error) {
+	confLoader, err := configLoader(l)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	conf := &Config{}
+	o := overrides.env()
+	migrated, err := confLoader.Migrate(configMigrations)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+	if migrated {
+		l.Printf(
+			"Migrated %s to version %d, the previous version is kept in %s.bak",
+			confLoader.Path(),
+			configVersion(),
+			confLoader.Path(),
+		)
+	}
+
+	conf := &timetracking.Config{}
 	if err := confLoader.Read(conf); err != nil {
+		if os.IsNotExist(err) && o.Complete() {
+			conf = defaultConfig()
+			conf.ForecastAccountID = ""
+			o.Apply(conf)
+			return confLoader, conf, conf.Validate()
+		}
+
 		if os.IsNotExist(err) {
 			l.Printf(
 				"Config file %s does not exist, creating example. [https://id.getharvest.com/developers to create an access token]",
@@ -43,79 +197,42 @@ func getConfig(l *log.Logger) (*config.ConfigLoader, *Config, error) {
 		return nil, nil, err
 	}
 
-	if conf.Token == defaultToken {
-		l.Printf(
-			"You should fill in your access token and account id in '%s'",
-			confLoader.Path(),
-		)
+	if profile == "" {
+		profile = conf.DefaultProfile
+	}
 
-		return nil, nil, nil
+	if profile != "" {
+		if conf, err = conf.Profile(profile); err != nil {
+			return nil, nil, err
+		}
 	}
 
+	o.Apply(conf)
+
 	return confLoader, conf, nil
 }
 
-type Config struct {
-	AccountID         string   `json:"account_id"`
-	ForecastAccountID string   `json:"forecast_account_id"`
-	Token             string   `json:"token"`
-	WeekdaysOff       []string `json:"weekdays_off"`
-	ExcludedDates     []string `json:"exclude_dates"`
-	Tasks             Tasks    `json:"tasks"`
-	excludedMap       map[string]struct{}
-	weekdaysOffMap    map[time.Weekday]struct{}
-}
+func getConfig(l *log.Logger, profile string) (*config.ConfigLoader, *timetracking.Config, error) {
+	confLoader, conf, err := loadConfig(l, profile)
+	if err != nil || conf == nil {
+		return nil, nil, err
+	}
 
-func (c *Config) Validate() error {
-	c.excludedMap = make(map[string]struct{})
-	for _, v := range c.ExcludedDates {
-		c.excludedMap[v] = struct{}{}
-		_, err := time.Parse(dateFormat, v)
+	if conf.Token == "" && conf.TokenSource == timetracking.TokenSourceKeyring {
+		conf.Token, err = keyring.Get(keyringService, conf.AccountID)
 		if err != nil {
-			return err
+			return nil, nil, fmt.Errorf("Failed to read token from keyring: %w", err)
 		}
 	}
 
-	c.weekdaysOffMap = make(map[time.Weekday]struct{})
-	wds := map[string]time.Weekday{
-		strings.ToLower(time.Monday.String()):    time.Monday,
-		strings.ToLower(time.Tuesday.String()):   time.Tuesday,
-		strings.ToLower(time.Wednesday.String()): time.Wednesday,
-		strings.ToLower(time.Thursday.String()):  time.Thursday,
-		strings.ToLower(time.Friday.String()):    time.Friday,
-		strings.ToLower(time.Saturday.String()):  time.Saturday,
-		strings.ToLower(time.Sunday.String()):    time.Sunday,
-	}
-	for _, v := range c.WeekdaysOff {
-		wd, ok := wds[strings.ToLower(v)]
-		if !ok {
-			return fmt.Errorf("Invalid weekday '%s'", v)
-		}
-
-		c.weekdaysOffMap[wd] = struct{}{}
-	}
+	if conf.Token == defaultToken && !conf.OAuth.LoggedIn() {
+		l.Printf(
+			"You should fill in your access token and account id in '%s' or run 'timetracking auth login'",
+			confLoader.Path(),
+		)
 
-	if len(c.weekdaysOffMap) > 6 {
-		return errors.New("What are you using this program for, if you take every day off?")
+		return nil, nil, nil
 	}
 
-	return nil
-}
-
-func (c *Config) Excluded(t time.Time) bool {
-	_, ok := c.excludedMap[t.Format(dateFormat)]
-	return ok
-}
-
-func (c *Config) Off(t time.Time) bool {
-	_, ok := c.weekdaysOffMap[t.Weekday()]
-	return ok
-}
-
-func (c *Config) AmountOff() int {
-	return len(c.weekdaysOffMap)
-}
-
-func (c *Config) WorkWeek() int {
-	return 7 - len(c.weekdaysOffMap)
+	return confLoader, conf, nil
 }
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+const enableEchoInput = 0x0004
+
+var (
+	kernel32           = syscall.NewLazyDLL("kernel32.dll")
+	procSetConsoleMode = kernel32.NewProc("SetConsoleMode")
+)
+
+// noEcho stops the console on stdin from echoing input until restore is
+// called, it fails when stdin is not a console.
+func noEcho() (restore func(), err error) {
+	h := syscall.Handle(os.Stdin.Fd())
+	var mode uint32
+	if err := syscall.GetConsoleMode(h, &mode); err != nil {
+		return nil, err
+	}
+
+	set := func(m uint32) error {
+		ok, _, err := procSetConsoleMode.Call(uintptr(h), uintptr(m))
+		if ok == 0 {
+			return err
+		}
+		return nil
+	}
+	if err := set(mode &^ enableEchoInput); err != nil {
+		return nil, err
+	}
+
+	return func() { _ = set(mode) }, nil
+}
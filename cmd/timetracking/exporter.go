@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+const formatClockify = "clockify-csv"
+
+// EntryFormatter turns time entries into the csv records of an export
+// format. A new one is created for every export, so it can keep state
+// between records.
+type EntryFormatter interface {
+	Header() []string
+	Record(e *harvest.TimeEntry) []string
+}
+
+// entryFormats are the builtin csv formats time entries can be exported in
+// by -format name, see Command.exportFormats.
+var entryFormats = map[string]func() EntryFormatter{
+	formatCSV:      func() EntryFormatter { return harvestFormatter{} },
+	formatClockify: func() EntryFormatter { return newColumnFormatter(clockifyColumns) },
+}
+
+// clockifyColumns are the columns of the clockify csv import.
+var clockifyColumns = []*timetracking.ExportColumn{
+	{Header: "Project", Field: "project"},
+	{Header: "Client", Field: "client"},
+	{Header: "Description", Field: "notes"},
+	{Header: "Task", Field: "task"},
+	{Header: "User", Field: "user"},
+	{Header: "Tags"},
+	{Header: "Billable", Field: "billable", Layout: "Yes|No"},
+	{Header: "Start Date", Field: "date", Layout: "01/02/2006"},
+	{Header: "Start Time", Field: "start", Layout: "15:04"},
+	{Header: "End Date", Field: "date", Layout: "01/02/2006"},
+	{Header: "End Time", Field: "end", Layout: "15:04"},
+	{Header: "Duration (h)", Field: "duration"},
+	{Header: "Duration (decimal)", Field: "hours"},
+}
+
+// registerExportFormats makes the export_formats of the config available
+// to c, next to the builtin entryFormats.
+func registerExportFormats(c *Command, config *timetracking.Config) error {
+	formats := make(map[string]func() EntryFormatter, len(entryFormats)+len(config.ExportFormats))
+	for name, f := range entryFormats {
+		formats[name] = f
+	}
+	for name, columns := range config.ExportFormats {
+		if _, err := NewRenderer(name, c.l, entryFormats, c.formatting()); err == nil {
+			return fmt.Errorf("Export format '%s' is a builtin format, rename it", name)
+		}
+		columns := columns
+		formats[name] = func() EntryFormatter { return newColumnFormatter(columns) }
+	}
+	c.formats = formats
+
+	return nil
+}
+
+// exportFormats returns the csv formats time entries can be rendered in by
+// c, the builtin ones unless registerExportFormats added those of the
+// config.
+func (c *Command) exportFormats() map[string]func() EntryFormatter {
+	if c.formats != nil {
+		return c.formats
+	}
+	return entryFormats
+}
+
+// writeEntries writes the header and a record for every entry.
+func writeEntries(w *csv.Writer, f EntryFormatter, entries harvest.TimeEntries) error {
+	if err := w.Write(f.Header()); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if err := w.Write(f.Record(e)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// harvestFormatter is the default csv format of export.
+type harvestFormatter struct{}
+
+func (harvestFormatter) Header() []string {
+	return []string{"date", "client", "project", "task", "notes", "hours", "billable"}
+}
+
+func (harvestFormatter) Record(e *harvest.TimeEntry) []string {
+	return []string{
+		e.SpentDate.Format(timetracking.DateFormat),
+		e.Client.Name,
+		e.Project.Name,
+		e.Task.Name,
+		e.Notes,
+		strconv.FormatFloat(e.Hours.Hours(), 'f', 2, 64),
+		strconv.FormatBool(e.Billable),
+	}
+}
+
+// dayStart is where entries without a start time are placed, after each
+// other, as most trackers require one.
+const dayStart = 9 * time.Hour
+
+// columnFormatter writes the columns of an export format.
+type columnFormatter struct {
+	columns []*timetracking.ExportColumn
+	next    map[string]time.Time
+}
+
+func newColumnFormatter(columns []*timetracking.ExportColumn) *columnFormatter {
+	return &columnFormatter{columns: columns, next: make(map[string]time.Time)}
+}
+
+func (f *columnFormatter) Header() []string {
+	header := make([]string, len(f.columns))
+	for i, col := range f.columns {
+		header[i] = col.Header
+	}
+
+	return header
+}
+
+func (f *columnFormatter) Record(e *harvest.TimeEntry) []string {
+	start, end := f.span(e)
+	rec := make([]string, len(f.columns))
+	for i, col := range f.columns {
+		rec[i] = f.value(col, e, start, end)
+	}
+
+	return rec
+}
+
+// span returns the start and end of an entry. Entries tracked with a timer
+// have their own, others start when the previous one of that day ended.
+func (f *columnFormatter) span(e *harvest.TimeEntry) (time.Time, time.Time) {
+	if e.SpentDate == nil {
+		return time.Time{}, time.Time{}
+	}
+
+	if start, err := parseClock(e.SpentDate.Time, e.StartedTime); err == nil {
+		end, err := parseClock(e.SpentDate.Time, e.EndedTime)
+		if err != nil || !end.After(start) {
+			end = start.Add(e.Hours.Duration)
+		}
+		return start, end
+	}
+
+	day := e.SpentDate.Format(timetracking.DateFormat)
+	start, ok := f.next[day]
+	if !ok {
+		start = timetracking.Day(e.SpentDate.Time).Add(dayStart)
+	}
+	end := start.Add(e.Hours.Duration)
+	f.next[day] = end
+
+	return start, end
+}
+
+func (f *columnFormatter) value(
+	col *timetracking.ExportColumn,
+	e *harvest.TimeEntry,
+	start time.Time,
+	end time.Time,
+) string {
+	format := func(t time.Time, layout string) string {
+		if t.IsZero() {
+			return ""
+		}
+		if col.Layout != "" {
+			layout = col.Layout
+		}
+		return t.Format(layout)
+	}
+
+	switch col.Field {
+	case "":
+		return col.Value
+	case "id":
+		return strconv.Itoa(e.ID)
+	case "date":
+		if e.SpentDate == nil {
+			return ""
+		}
+		return format(e.SpentDate.Time, timetracking.DateFormat)
+	case "start":
+		return format(start, "15:04")
+	case "end":
+		return format(end, "15:04")
+	case "user":
+		return e.User.Name
+	case "client":
+		return e.Client.Name
+	case "project":
+		return e.Project.Name
+	case "project_code":
+		return e.Project.Code
+	case "task":
+		return e.Task.Name
+	case "notes":
+		return e.Notes
+	case "hours":
+		return strconv.FormatFloat(e.Hours.Hours(), 'f', 2, 64)
+	case "duration":
+		s := int(e.Hours.Round(time.Second).Seconds())
+		return fmt.Sprintf("%02d:%02d:%02d", s/3600, s%3600/60, s%60)
+	case "billable":
+		if yes, no, ok := strings.Cut(col.Layout, "|"); ok {
+			if e.Billable {
+				return yes
+			}
+			return no
+		}
+		return strconv.FormatBool(e.Billable)
+	case "reference":
+		if e.ExternalReference.Permalink != "" {
+			return e.ExternalReference.Permalink
+		}
+		return e.ExternalReference.ID
+	}
+
+	return ""
+}
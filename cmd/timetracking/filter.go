@@ -0,0 +1,137 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+// entryFilter holds the flags that select the fetched entries a report
+// or export includes.
+type entryFilter struct {
+	project  string
+	client   string
+	task     string
+	billable string
+	notes    string
+	tag      string
+}
+
+// filterKeys are the keys -filter accepts, each is also a flag of its own.
+var filterKeys = []string{"project", "client", "task", "billable", "notes-regex", "tag"}
+
+// Flags registers -project, -client, -task, -billable, -notes-regex, -tag
+// and -filter key=value for any of them.
+func (f *entryFilter) Flags() {
+	flag.StringVar(&f.project, "project", "", "Only include entries of this project (name or id)")
+	flag.StringVar(&f.client, "client", "", "Only include entries of this client (name or id)")
+	flag.StringVar(&f.task, "task", "", "Only include entries of this task (name or id)")
+	flag.StringVar(&f.billable, "billable", "", "Only include billable (true) or non-billable (false) entries")
+	flag.StringVar(&f.notes, "notes-regex", "", "Only include entries with notes matching this regex (case insensitive)")
+	flag.StringVar(&f.tag, "tag", "", "Only include entries with this #tag in their notes")
+	flag.Var(
+		f,
+		"filter",
+		fmt.Sprintf("Only include entries matching key=value, key is one of %s, repeat it to combine filters, e.g. -filter tag=infra", strings.Join(filterKeys, ", ")),
+	)
+}
+
+// field returns the value of the flag named key.
+func (f *entryFilter) field(key string) *string {
+	switch key {
+	case "project":
+		return &f.project
+	case "client":
+		return &f.client
+	case "task":
+		return &f.task
+	case "billable":
+		return &f.billable
+	case "notes-regex":
+		return &f.notes
+	case "tag":
+		return &f.tag
+	}
+
+	return nil
+}
+
+func (f *entryFilter) String() string {
+	s := make([]string, 0, len(filterKeys))
+	for _, key := range filterKeys {
+		if v := *f.field(key); v != "" {
+			s = append(s, key+"="+v)
+		}
+	}
+
+	return strings.Join(s, ",")
+}
+
+// Set sets the filter of a -filter key=value.
+func (f *entryFilter) Set(v string) error {
+	key, value, ok := strings.Cut(v, "=")
+	field := f.field(strings.ToLower(strings.TrimSpace(key)))
+	if !ok || field == nil || value == "" {
+		return fmt.Errorf("Invalid filter '%s' expected key=value, key is one of %s", v, strings.Join(filterKeys, ", "))
+	}
+	if *field != "" {
+		return fmt.Errorf("Filter %s is set twice", key)
+	}
+
+	*field = value
+	return nil
+}
+
+// Filter returns a filter for the flags that were set combined with prev,
+// nil when neither filters anything.
+func (f *entryFilter) Filter(prev harvest.Filter) (harvest.Filter, error) {
+	filter := prev
+	and := func(fn harvest.Filter) {
+		prev := filter
+		filter = func(e *harvest.TimeEntry) bool {
+			return fn(e) && (prev == nil || prev(e))
+		}
+	}
+
+	if f.project != "" {
+		and(func(e *harvest.TimeEntry) bool { return nameOrID(f.project, e.Project.Name, e.Project.ID) })
+	}
+	if f.client != "" {
+		and(func(e *harvest.TimeEntry) bool { return nameOrID(f.client, e.Client.Name, e.Client.ID) })
+	}
+	if f.task != "" {
+		and(func(e *harvest.TimeEntry) bool { return nameOrID(f.task, e.Task.Name, e.Task.ID) })
+	}
+
+	if f.billable != "" {
+		b, err := strconv.ParseBool(f.billable)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid -billable '%s' expected true or false", f.billable)
+		}
+		and(func(e *harvest.TimeEntry) bool { return e.Billable == b })
+	}
+
+	if f.notes != "" {
+		re, err := regexp.Compile("(?i)" + f.notes)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid -notes-regex '%s': %w", f.notes, err)
+		}
+		and(func(e *harvest.TimeEntry) bool { return re.MatchString(e.Notes) })
+	}
+
+	if f.tag != "" {
+		and(func(e *harvest.TimeEntry) bool { return timetracking.HasTag(e, f.tag) })
+	}
+
+	return filter, nil
+}
+
+// nameOrID reports whether value is the id or (case insensitive) name.
+func nameOrID(value, name string, id int) bool {
+	return strings.EqualFold(value, name) || value == strconv.Itoa(id)
+}
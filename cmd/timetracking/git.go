@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type Commit struct {
+	Hash    string    `json:"hash"`
+	Time    time.Time `json:"time"`
+	Subject string    `json:"subject"`
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		if e, ok := err.(*exec.ExitError); ok && len(e.Stderr) != 0 {
+			return "", fmt.Errorf("git: %s", strings.TrimSpace(string(e.Stderr)))
+		}
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// gitRoot returns the top level directory of the repository dir is in.
+func gitRoot(dir string) (string, error) {
+	return git(dir, "rev-parse", "--show-toplevel")
+}
+
+// gitAuthor returns the configured author email of the repository.
+func gitAuthor(dir string) (string, error) {
+	return git(dir, "config", "user.email")
+}
+
+// gitCommits returns the commits of author on any branch between from
+// and to, in the order of git log.
+func gitCommits(dir, author string, from, to time.Time) ([]*Commit, error) {
+	out, err := git(
+		dir,
+		"log",
+		"--all",
+		"--author="+regexp.QuoteMeta(author),
+		"--since="+from.Format(time.RFC3339),
+		"--until="+to.Format(time.RFC3339),
+		"--format=%H%x09%aI%x09%s",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]*Commit, 0)
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, parts[1])
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, &Commit{Hash: parts[0], Time: t, Subject: parts[2]})
+	}
+
+	return commits, nil
+}
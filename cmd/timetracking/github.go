@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const githubAPI = "https://api.github.com"
+
+// spendRe matches the gitlab style /spend <hours> [date] lines in comments.
+var spendRe = regexp.MustCompile(`(?m)^/spend\s+(\S+)(?:\s+(\d{4}-\d{2}-\d{2}))?\s*$`)
+
+// linkNextRe matches the url of the next page in a Link header.
+var linkNextRe = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+type githubClient struct {
+	url   string
+	token string
+}
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubComment struct {
+	ID        int64      `json:"id"`
+	HTMLURL   string     `json:"html_url"`
+	IssueURL  string     `json:"issue_url"`
+	Body      string     `json:"body"`
+	User      githubUser `json:"user"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Issue returns the number of the issue the comment is on.
+func (g *githubComment) Issue() string {
+	return g.IssueURL[strings.LastIndex(g.IssueURL, "/")+1:]
+}
+
+// get decodes every page of a list endpoint into a slice, calling fn with
+// each page.
+func (g *githubClient) get(ctx context.Context, uri string, v interface{}, fn func() error) error {
+	for uri != "" {
+		req, err := http.NewRequestWithContext(ctx, "GET", uri, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if g.token != "" {
+			req.Header.Set("Authorization", "Bearer "+g.token)
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		body, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return err
+		}
+		if res.StatusCode < 200 || res.StatusCode >= 300 {
+			return fmt.Errorf("Unexpected github error (%d): %s", res.StatusCode, strings.TrimSpace(string(body)))
+		}
+
+		if err := json.Unmarshal(body, v); err != nil {
+			return err
+		}
+		if err := fn(); err != nil {
+			return err
+		}
+
+		uri = ""
+		if m := linkNextRe.FindStringSubmatch(res.Header.Get("Link")); m != nil {
+			uri = m[1]
+		}
+	}
+
+	return nil
+}
+
+// user returns the login of the owner of the token.
+func (g *githubClient) user(ctx context.Context) (string, error) {
+	u := &githubUser{}
+	if err := g.get(ctx, g.url+"/user", u, func() error { return nil }); err != nil {
+		return "", err
+	}
+
+	return u.Login, nil
+}
+
+// comments returns the issue comments of repo (owner/name) updated since.
+func (g *githubClient) comments(ctx context.Context, repo string, since time.Time) ([]*githubComment, error) {
+	q := url.Values{}
+	q.Set("since", since.UTC().Format(time.RFC3339))
+	q.Set("per_page", "100")
+	uri := fmt.Sprintf("%s/repos/%s/issues/comments?%s", g.url, repo, q.Encode())
+
+	var all, page []*githubComment
+	err := g.get(ctx, uri, &page, func() error {
+		all = append(all, page...)
+		page = nil
+		return nil
+	})
+
+	return all, err
+}
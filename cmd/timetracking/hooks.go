@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/frizinak/harvest-timetracking/cache"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+// postReport runs the post_report hook with sh, the report is passed as json
+// on stdin and the command and format in $TIMETRACKING_COMMAND and
+// $TIMETRACKING_FORMAT. Its output goes to stderr so it does not mix with
+// the report.
+func (c *Command) postReport(v interface{}) error {
+	if c.hooks == nil || c.hooks.PostReport == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(c.ctx, "sh", "-c", c.hooks.PostReport)
+	cmd.Env = append(
+		os.Environ(),
+		"TIMETRACKING_COMMAND="+c.name,
+		"TIMETRACKING_FORMAT="+c.format,
+	)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("post_report hook failed: %w", err)
+	}
+
+	return nil
+}
+
+// overdueInvoices runs the overdue_invoice hook with sh once for every
+// overdue invoice, its json is passed on stdin and its id and the days it is
+// overdue in $TIMETRACKING_INVOICE_ID and $TIMETRACKING_OVERDUE_DAYS.
+// Invoices the hook ran for are remembered per account in the cache and
+// skipped.
+func (c *Command) overdueInvoices(accountID string, invoices []*timetracking.OutstandingInvoice) error {
+	if c.hooks == nil || c.hooks.OverdueInvoice == "" {
+		return nil
+	}
+
+	store, err := cache.UserCache("timetracking", 0)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range invoices {
+		if i.Overdue == 0 {
+			continue
+		}
+
+		key := fmt.Sprintf("overdue_invoice|%s|%d", accountID, i.ID)
+		var days int
+		if ok, err := store.Get(key, &days); err == nil && ok {
+			continue
+		}
+
+		body, err := json.Marshal(i)
+		if err != nil {
+			return err
+		}
+
+		cmd := exec.CommandContext(c.ctx, "sh", "-c", c.hooks.OverdueInvoice)
+		cmd.Env = append(
+			os.Environ(),
+			"TIMETRACKING_INVOICE_ID="+strconv.Itoa(i.ID),
+			"TIMETRACKING_OVERDUE_DAYS="+strconv.Itoa(i.Overdue),
+		)
+		cmd.Stdin = bytes.NewReader(body)
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("overdue_invoice hook failed for invoice %d: %w", i.ID, err)
+		}
+
+		if err := store.Set(key, i.Overdue); err != nil {
+			c.logger().Warn("Failed to write cache", "err", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+// templateFS holds the default layouts of the html and pdf reports.
+//
+//go:embed templates
+var templateFS embed.FS
+
+var pieColors = []string{
+	"#4e79a7",
+	"#f28e2b",
+	"#e15759",
+	"#76b7b2",
+	"#59a14f",
+	"#edc948",
+	"#b07aa1",
+	"#ff9da7",
+	"#9c755f",
+	"#bab0ac",
+}
+
+// PieSlice is one project in the pie chart of an html report.
+type PieSlice struct {
+	Name       string
+	Hours      timetracking.Duration
+	Percentage float64
+	Color      string
+	Path       string
+}
+
+// pie divides a circle of radius r around (r, r) in slices proportional to
+// the hours of each group.
+func pie(groups []*ReportGroup, r float64) []PieSlice {
+	var total timetracking.Duration
+	for _, g := range groups {
+		total += g.Hours
+	}
+
+	slices := make([]PieSlice, 0, len(groups))
+	if total <= 0 {
+		return slices
+	}
+
+	point := func(angle float64) (float64, float64) {
+		return r + r*math.Sin(angle), r - r*math.Cos(angle)
+	}
+
+	var angle float64
+	for i, g := range groups {
+		if g.Hours <= 0 {
+			continue
+		}
+
+		frac := float64(g.Hours) / float64(total)
+		x1, y1 := point(angle)
+		angle += 2 * math.Pi * frac
+		x2, y2 := point(angle)
+
+		large := 0
+		if frac > 0.5 {
+			large = 1
+		}
+
+		path := fmt.Sprintf(
+			"M%.2f,%.2f L%.2f,%.2f A%.2f,%.2f 0 %d 1 %.2f,%.2f Z",
+			r, r, x1, y1, r, r, large, x2, y2,
+		)
+		if frac >= 1 {
+			// An arc from a point to the same point draws nothing.
+			path = fmt.Sprintf(
+				"M%.2f,0 A%.2f,%.2f 0 1 1 %.2f,%.2f A%.2f,%.2f 0 1 1 %.2f,0 Z",
+				r, r, r, r, 2*r, r, r, r,
+			)
+		}
+
+		slices = append(slices, PieSlice{
+			Name:       g.Name,
+			Hours:      g.Hours,
+			Percentage: 100 * frac,
+			Color:      pieColors[i%len(pieColors)],
+			Path:       path,
+		})
+	}
+
+	return slices
+}
+
+var htmlReport = template.Must(
+	template.New("report.html").Funcs(
+		template.FuncMap{
+			"pie":       pie,
+			"dateGroup": timetracking.DateGroup,
+			"hours":     NewFormatting(nil).Hours,
+			"style": func(r *Report, g *ReportGroup) string {
+				if !timetracking.DateGroup(r.Group) {
+					return timetracking.StyleNone
+				}
+				return groupStyle(r.Group, g)
+			},
+		},
+	).ParseFS(templateFS, "templates/report.html"),
+)
+
+func (r *Report) HTML(w io.Writer, f *Formatting) error {
+	tmpl, err := htmlReport.Clone()
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Funcs(template.FuncMap{"hours": f.Hours}).Execute(w, r)
+}
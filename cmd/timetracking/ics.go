@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	icsDate     = "20060102"
+	icsDateTime = "20060102T150405"
+)
+
+var icsEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+// ICSWriter writes iCalendar (RFC 5545) content lines,
+// escaping text values and folding long lines.
+type ICSWriter struct {
+	w   io.Writer
+	err error
+}
+
+func NewICSWriter(w io.Writer) *ICSWriter {
+	return &ICSWriter{w: w}
+}
+
+// Line writes a raw content line.
+func (i *ICSWriter) Line(name, value string) {
+	if i.err != nil {
+		return
+	}
+
+	line := name + ":" + value
+	for len(line) > 75 {
+		n := 75
+		// Do not split utf-8 sequences.
+		for n > 0 && line[n]&0xC0 == 0x80 {
+			n--
+		}
+		if _, i.err = io.WriteString(i.w, line[:n]+"\r\n"); i.err != nil {
+			return
+		}
+		line = " " + line[n:]
+	}
+
+	_, i.err = io.WriteString(i.w, line+"\r\n")
+}
+
+// Text writes an escaped text value.
+func (i *ICSWriter) Text(name, value string) {
+	i.Line(name, icsEscaper.Replace(value))
+}
+
+// Time writes a floating local date-time value.
+func (i *ICSWriter) Time(name string, t time.Time) {
+	i.Line(name, t.Format(icsDateTime))
+}
+
+// UTC writes a date-time value in UTC.
+func (i *ICSWriter) UTC(name string, t time.Time) {
+	i.Line(name, t.UTC().Format(icsDateTime)+"Z")
+}
+
+// Date writes a date value.
+func (i *ICSWriter) Date(name string, t time.Time) {
+	i.Line(name+";VALUE=DATE", t.Format(icsDate))
+}
+
+func (i *ICSWriter) Begin(component string) {
+	i.Line("BEGIN", component)
+}
+
+func (i *ICSWriter) End(component string) {
+	i.Line("END", component)
+}
+
+func (i *ICSWriter) Err() error {
+	return i.err
+}
+
+// parseClock parses a harvest started_time or ended_time (e.g. "8:00am" or
+// "08:00") on the given day, in its timezone.
+func parseClock(d time.Time, clock string) (time.Time, error) {
+	for _, f := range []string{"3:04pm", "15:04"} {
+		if t, err := time.Parse(f, clock); err == nil {
+			y, m, dd := d.Date()
+			return time.Date(y, m, dd, t.Hour(), t.Minute(), 0, 0, d.Location()), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("Invalid time '%s'", clock)
+}
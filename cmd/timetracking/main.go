@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
-	"time"
+	"os/signal"
 )
 
 var v = "unknown"
@@ -13,45 +15,111 @@ const (
 	endOfWeek    = "end-of-week"
 	nextWeek     = "next-week"
 	defaultToken = "-- your account token --"
-
-	groupByDay   = "day"
-	groupByWeek  = "week"
-	groupByMonth = "month"
-	groupByYear  = "year"
 )
 
-type Duration time.Duration
-
-func (d Duration) String() string {
-	s := time.Duration(d)
-	h := s / time.Hour
-	m := (s % time.Hour) / time.Minute
-	return fmt.Sprintf("%dh%02d", h, m)
-}
-
 func main() {
-	arg := ""
-	if len(os.Args) > 1 && os.Args[1][0] != '-' {
-		arg = os.Args[1]
-		os.Args = append(os.Args[:1], os.Args[2:]...)
-	}
+	arg := shiftArg()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
 
 	l := log.New(os.Stdout, "", 0)
 	c := &Command{
+		ctx:      ctx,
 		l:        l,
 		commands: make(map[string]*Cmd),
 	}
+	flag.StringVar(
+		&c.format,
+		"format",
+		formatText,
+		fmt.Sprintf(
+			"Output format %s|%s|%s|%s|%s|%s|%s|%s, export also takes %s and the export_formats of the config",
+			formatText,
+			formatJSON,
+			formatCSV,
+			formatICS,
+			formatHTML,
+			formatPDF,
+			formatWaybar,
+			formatLine,
+			formatClockify,
+		),
+	)
+	flag.Var(
+		&c.outputs,
+		"output",
+		"Render to format[:file] instead of -format, repeat it to write several formats from a single fetch, e.g. -output table -output json:report.json",
+	)
+	flag.StringVar(&c.profile, "profile", "", "Name of the config profile to use")
+	flag.BoolVar(&c.noCache, "no-cache", false, "Do not use the local time entry cache")
+	flag.BoolVar(&noColor, "no-color", false, "Do not color the output (env: NO_COLOR)")
+	flag.StringVar(&overrides.Path, "config", "", "Path of the config file (env: TIMETRACKING_CONFIG)")
+	flag.StringVar(&overrides.AccountID, "account-id", "", "Harvest account id, overrides the config (env: HARVEST_ACCOUNT_ID)")
+	flag.StringVar(&overrides.ForecastAccountID, "forecast-account-id", "", "Forecast account id, overrides the config (env: FORECAST_ACCOUNT_ID)")
+	flag.StringVar(&overrides.Token, "token", "", "Harvest access token, overrides the config (env: HARVEST_TOKEN)")
+	flag.BoolVar(&c.verbose, "v", false, "Log progress to stderr")
+	flag.BoolVar(&c.debug, "vv", false, "Log progress and every api request to stderr")
+	flag.BoolVar(&c.logJSON, "log-json", false, "Log as json lines, e.g. for cron jobs")
+	flag.StringVar(&c.record, "record", "", "Write every api request and response to this directory")
+	flag.StringVar(&c.replay, "replay", "", "Answer api requests with the responses recorded in this directory, offline")
+	flag.IntVar(&c.maxReqs, "max-requests", 0, "Abort when a command needs more api requests than this (default: no limit)")
+	flag.StringVar(&c.tz, "tz", "", "Timezone that decides which day it is, e.g. Europe/Brussels (default: timezone from config or harvest profile)")
 	c.commands["version"] = &Cmd{"print version", commandVersion}
 	c.commands["help"] = &Cmd{"print list of commands", commandHelp}
 	c.commands["tracking"] = &Cmd{"show tracked hours", commandTracking}
 	c.commands["off"] = &Cmd{"get a list of days off using the forecast api", commandDaysOff}
+	c.commands["absence"] = &Cmd{"mark a day as sick or half-day, or list marked days", commandAbsence}
 	c.commands["tasks"] = &Cmd{"get a list of projects and their tasks", commandTasks}
 	c.commands["start"] = &Cmd{"Start a timetracker", commandStart}
+	c.commands["stop"] = &Cmd{"Stop the running timetracker", commandStop}
+	c.commands["status"] = &Cmd{"Show the running timetracker", commandStatus}
+	c.commands["pomodoro"] = &Cmd{"track a task in pomodoros of work with breaks in between", commandPomodoro}
+	c.commands["tray"] = &Cmd{"show the running timer in the system tray or macOS menu bar", commandTray}
+	c.commands["export"] = &Cmd{"export tracked time entries", commandExport}
+	c.commands["auth"] = &Cmd{"manage authentication (oauth2 login/logout, keyring)", commandAuth}
+	c.commands["balance"] = &Cmd{"show surplus or deficit of tracked hours over a period", commandBalance}
+	c.commands["tui"] = &Cmd{"interactive dashboard of today and this week", commandTUI}
+	c.commands["actuals"] = &Cmd{"compare forecast allocations with tracked hours", commandActuals}
+	c.commands["edit"] = &Cmd{"edit a time entry", commandEdit}
+	c.commands["delete"] = &Cmd{"delete a time entry", commandDelete}
+	c.commands["profit"] = &Cmd{"revenue, labor cost and margin per project or client", commandProfit}
+	c.commands["projects"] = &Cmd{"list the projects you can log time on, or all with their budget", commandProjects}
+	c.commands["invoice"] = &Cmd{"list invoices or draft one from uninvoiced billable hours", commandInvoice}
+	c.commands["expenses"] = &Cmd{"list or create expenses", commandExpenses}
+	c.commands["team"] = &Cmd{"matrix of tracked hours of multiple users", commandTeam}
+	c.commands["import"] = &Cmd{"create time entries from csv, github, toggl or calendar events", commandImport}
+	c.commands["week-status"] = &Cmd{"show the approval state of a weekly timesheet", commandWeekStatus}
+	c.commands["search"] = &Cmd{"find time entries by their notes", commandSearch}
+	c.commands["stats"] = &Cmd{"rolling average, spread, busiest weekday and streaks of tracked hours", commandStats}
+	c.commands["submit-week"] = &Cmd{"open a weekly timesheet in harvest to submit it for approval", commandSubmitWeek}
+	c.commands["lint"] = &Cmd{"check time entries against the lint rules of the config", commandLint}
+	c.commands["serve"] = &Cmd{"expose tracked hours as prometheus metrics", commandServe}
+	c.commands["watch"] = &Cmd{"notify when idle without a timer or past a daily limit", commandWatch}
+	c.commands["notify"] = &Cmd{"post a summary of your tracked hours to slack", commandNotify}
+	c.commands["suggest"] = &Cmd{"suggest time entries from today's git commits", commandSuggest}
+	c.commands["plan"] = &Cmd{"list, add or delete forecast assignments", commandPlan}
+	c.commands["forecast"] = &Cmd{"show upcoming forecast milestones of your projects", commandForecast}
+	c.commands["missing"] = &Cmd{"list recent working days with no or too few tracked hours", commandMissing}
+	c.commands["remaining"] = &Cmd{"show the hours left to reach your capacity today and this week", commandRemaining}
+	c.commands["leave"] = &Cmd{"show vacation days taken, planned and remaining this year", commandLeave}
+	c.commands["breakdown"] = &Cmd{"share of hours per task across projects and its trend", commandBreakdown}
+	c.commands["budget"] = &Cmd{"show budget use and projected exhaustion per project", commandBudget}
+	c.commands["retainer"] = &Cmd{"delivered hours per month against the retainer of a project", commandRetainer}
+	c.commands["config"] = &Cmd{"show which config file is in use", commandConfig}
+	c.commands["completion"] = &Cmd{"generate bash, zsh or fish completion scripts", commandCompletion}
+	c.commands["log"] = &Cmd{"log hours on a project task", commandLog}
+	c.commands["sync-recurring"] = &Cmd{"log the recurring entries from the config that are missing", commandSyncRecurring}
 
 	exit, err := c.Run(arg)
 	if err != nil {
-		l.Println(err)
+		if c.logJSON {
+			c.logger().Error(err.Error(), "command", c.name)
+		} else {
+			l.Println(explain(err))
+		}
 	}
 
+	cancel()
 	os.Exit(exit)
 }
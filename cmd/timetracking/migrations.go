@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/frizinak/harvest-timetracking/config"
+)
+
+// configMigrations upgrade older config files on load, append a migration
+// when a change to Config would otherwise break existing configs.
+var configMigrations = []config.Migration{
+	// 1: add the sections older configs lack, empty so nothing changes.
+	// Profiles are left alone, an empty section would override the config's.
+	func(raw map[string]interface{}, profile bool) error {
+		if profile {
+			return nil
+		}
+		for _, key := range []string{"weekdays_off", "exclude_dates", "tasks"} {
+			if raw[key] == nil {
+				raw[key] = []interface{}{}
+			}
+		}
+		return nil
+	},
+}
+
+func configVersion() int {
+	return len(configMigrations)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// formatTable is accepted by -output as another name for formatText.
+const formatTable = "table"
+
+// output is a format and the file it is written to, stdout when path is
+// empty.
+type output struct {
+	format string
+	path   string
+}
+
+// outputs is the value of the repeatable -output flag.
+type outputs []*output
+
+func (o *outputs) String() string {
+	s := make([]string, 0, len(*o))
+	for _, out := range *o {
+		if out.path == "" {
+			s = append(s, out.format)
+			continue
+		}
+		s = append(s, out.format+":"+out.path)
+	}
+
+	return strings.Join(s, ",")
+}
+
+func (o *outputs) Set(v string) error {
+	format, path, _ := strings.Cut(v, ":")
+	if format == "" {
+		return errors.New("Output should be format[:file]")
+	}
+	if format == formatTable {
+		format = formatText
+	}
+
+	*o = append(*o, &output{format, path})
+	return nil
+}
+
+// wants reports whether the output includes format, commands use it to add
+// what only that format shows.
+func (c *Command) wants(format string) bool {
+	if len(c.outputs) == 0 {
+		return c.format == format
+	}
+
+	for _, o := range c.outputs {
+		if o.format == format {
+			return true
+		}
+	}
+
+	return false
+}
+
+// renderOutputs renders v, or the value of its format in as, in every
+// -output, one after the other, the formats are checked before anything is
+// written.
+func (c *Command) renderOutputs(v interface{}, as map[string]interface{}) error {
+	for _, o := range c.outputs {
+		if _, err := NewRenderer(o.format, c.l, c.exportFormats(), c.formatting()); err != nil {
+			return err
+		}
+	}
+
+	for _, o := range c.outputs {
+		ov := v
+		if alt, ok := as[o.format]; ok {
+			ov = alt
+		}
+		if err := c.renderOutput(o, ov); err != nil {
+			return fmt.Errorf("Could not write %s output: %w", o.format, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Command) renderOutput(o *output, v interface{}) error {
+	if o.path == "" {
+		r, err := NewRenderer(o.format, c.l, c.exportFormats(), c.formatting())
+		if err != nil {
+			return err
+		}
+		return r.Render(v)
+	}
+
+	f, err := os.Create(o.path)
+	if err != nil {
+		return err
+	}
+
+	// Files never get terminal colors.
+	color := noColor
+	noColor = true
+	defer func() { noColor = color }()
+
+	r, err := NewRenderer(o.format, log.New(f, "", 0), c.exportFormats(), c.formatting())
+	if err == nil {
+		err = r.Render(v)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(o.path)
+	}
+
+	return err
+}
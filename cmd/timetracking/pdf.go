@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	pdfWidth    = 595 // A4 in points
+	pdfHeight   = 842
+	pdfMargin   = 50
+	pdfFontSize = 10
+	pdfLeading  = 12
+	// Courier glyphs are 0.6em wide.
+	pdfColumns = (pdfWidth - 2*pdfMargin) * 10 / (6 * pdfFontSize)
+	pdfRows    = (pdfHeight - 2*pdfMargin) / pdfLeading
+)
+
+var pdfEscaper = strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`)
+
+// winAnsi maps the characters of 0x80-0x9f in WinAnsiEncoding,
+// the rest of latin-1 maps to itself.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// PDFWriter typesets lines of monospaced text on A4 pages, lines starting
+// with "# " are bold and a form feed starts a new page.
+type PDFWriter struct {
+	w     io.Writer
+	pages [][]string
+}
+
+func NewPDFWriter(w io.Writer) *PDFWriter {
+	return &PDFWriter{w: w}
+}
+
+// Page starts a new page.
+func (p *PDFWriter) Page() {
+	p.pages = append(p.pages, nil)
+}
+
+// Line adds a line of text, long lines are wrapped.
+func (p *PDFWriter) Line(s string) {
+	if strings.HasPrefix(s, "\f") {
+		p.Page()
+		s = s[1:]
+	}
+
+	bold := strings.HasPrefix(s, "# ")
+	for {
+		if len(p.pages) == 0 || len(p.pages[len(p.pages)-1]) >= pdfRows {
+			p.Page()
+		}
+
+		line := s
+		if utf8.RuneCountInString(s) > pdfColumns {
+			r := []rune(s)
+			line, s = string(r[:pdfColumns]), string(r[pdfColumns:])
+			if bold {
+				s = "# " + s
+			}
+		} else {
+			s = ""
+		}
+
+		i := len(p.pages) - 1
+		p.pages[i] = append(p.pages[i], line)
+		if s == "" {
+			return
+		}
+	}
+}
+
+// Close writes the document.
+func (p *PDFWriter) Close() error {
+	if len(p.pages) == 0 {
+		p.Page()
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+	obj := func(format string, args ...interface{}) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n", len(offsets))
+		fmt.Fprintf(&buf, format, args...)
+		buf.WriteString("\nendobj\n")
+	}
+
+	// 1: catalog, 2: pages, 3-4: fonts, then a page and its contents per page.
+	const firstPage = 5
+	kids := make([]string, len(p.pages))
+	for i := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+	for i, lines := range p.pages {
+		obj(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfWidth,
+			pdfHeight,
+			firstPage+2*i+1,
+		)
+
+		var content bytes.Buffer
+		fmt.Fprintf(
+			&content,
+			"BT\n%d TL\n%d %d Td\n",
+			pdfLeading,
+			pdfMargin,
+			pdfHeight-pdfMargin-pdfFontSize,
+		)
+		for _, line := range lines {
+			font := "F1"
+			if strings.HasPrefix(line, "# ") {
+				font, line = "F2", line[2:]
+			}
+			fmt.Fprintf(&content, "/%s %d Tf (%s) Tj T*\n", font, pdfFontSize, pdfText(line))
+		}
+		content.WriteString("ET")
+		obj("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String())
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, o := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", o)
+	}
+	fmt.Fprintf(
+		&buf,
+		"trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1,
+		xref,
+	)
+
+	_, err := buf.WriteTo(p.w)
+	return err
+}
+
+// pdfText encodes s as an escaped WinAnsi string,
+// characters that do not exist in it are replaced by '?'.
+func pdfText(s string) string {
+	b := make([]byte, 0, len(s))
+	for _, r := range strings.TrimRight(s, "\r") {
+		c, ok := winAnsi[r]
+		switch {
+		case ok:
+		case r < 0x80 || (r >= 0xa0 && r <= 0xff):
+			c = byte(r)
+		default:
+			c = '?'
+		}
+		b = append(b, c)
+	}
+
+	return pdfEscaper.Replace(string(b))
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+// entryID returns the time entry id given as argument or lets the user
+// pick one of today's entries.
+func entryID(c *Command, t *timetracking.Timetracking, arg string) (int, error) {
+	if arg != "" {
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			return 0, fmt.Errorf("Invalid entry id '%s'", arg)
+		}
+		return id, nil
+	}
+
+	today := t.Today()
+	entries, err := t.GetTimeEntriesBetween(c.ctx, today, today)
+	if err != nil {
+		return 0, err
+	}
+
+	e, err := pickEntry(c, entries)
+	if err != nil {
+		return 0, err
+	}
+
+	return e.ID, nil
+}
+
+// confirm asks question and reports whether it was answered with yes.
+// It refuses to guess when stdin is not a terminal.
+func confirm(c *Command, question string) (bool, error) {
+	stat, err := os.Stdin.Stat()
+	if err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+		return false, fmt.Errorf("Run %s in a terminal to confirm, or pass -yes", c.name)
+	}
+
+	c.l.Printf("%s [y/N] ", question)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+func pickEntry(c *Command, entries harvest.TimeEntries) (*harvest.TimeEntry, error) {
+	if len(entries) == 0 {
+		return nil, errors.New("No entries to pick from")
+	}
+
+	for i, e := range entries {
+		c.l.Printf(
+			"%3d) %6s  [%s] %s / %s  %s",
+			i+1,
+			c.formatting().Hours(timetracking.Duration(e.Hours.Duration)),
+			e.Client.Name,
+			e.Project.Name,
+			e.Task.Name,
+			e.Notes,
+		)
+	}
+
+	c.l.Print("Entry: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || n < 1 || n > len(entries) {
+		return nil, fmt.Errorf("Invalid choice '%s'", strings.TrimSpace(line))
+	}
+
+	return entries[n-1], nil
+}
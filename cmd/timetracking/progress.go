@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// progressMinPages is the least amount of pages a request needs
+// before a progress bar is drawn.
+const progressMinPages = 3
+
+const progressWidth = 30
+
+// progressBar draws the pages fetched of long requests on a terminal.
+type progressBar struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// newProgressBar returns nil when stderr is not a terminal.
+func newProgressBar() *progressBar {
+	stat, err := os.Stderr.Stat()
+	if err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+		return nil
+	}
+
+	return &progressBar{w: os.Stderr}
+}
+
+func (p *progressBar) Progress(done, total int) {
+	if total < progressMinPages {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if done >= total {
+		fmt.Fprint(p.w, "\r\033[K")
+		return
+	}
+
+	n := done * progressWidth / total
+	fmt.Fprintf(
+		p.w,
+		"\r[%s%s] %d/%d pages",
+		strings.Repeat("#", n),
+		strings.Repeat(" ", progressWidth-n),
+		done,
+		total,
+	)
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/currency"
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+	formatCSV  = "csv"
+	formatICS  = "ics"
+	formatHTML = "html"
+	formatPDF  = "pdf"
+
+	formatWaybar = "waybar"
+	formatLine   = "line"
+)
+
+// Formatting is how a command prints hours, money and times and colors its
+// tables, from the config and harvest company of its Timetracking.
+type Formatting struct {
+	Location   *time.Location
+	TimeFormat string
+	Separators currency.Separators
+	Theme      timetracking.Theme
+}
+
+// NewFormatting returns the formatting of t, the defaults when t is nil.
+func NewFormatting(t *timetracking.Timetracking) *Formatting {
+	f := &Formatting{Location: time.Local, Theme: timetracking.DefaultTheme}
+	if t == nil {
+		return f
+	}
+
+	f.Location, f.TimeFormat, f.Separators = t.Location(), t.TimeFormat(), t.Separators()
+	if theme, err := t.Config().Theme.Merge(); err == nil {
+		f.Theme = theme
+	}
+	return f
+}
+
+// Money prints amount in the currency code with the separators of the
+// harvest company.
+func (f *Formatting) Money(amount float64, code string) string {
+	return f.Separators.Format(amount, code)
+}
+
+// Hours prints d in the time format of the config or harvest company.
+func (f *Formatting) Hours(d timetracking.Duration) string {
+	return d.Format(f.TimeFormat)
+}
+
+// Signed prints d with a + or - sign.
+func (f *Formatting) Signed(d timetracking.Duration) string {
+	if d < 0 {
+		return "-" + f.Hours(-d)
+	}
+	return "+" + f.Hours(d)
+}
+
+// Printer writes text output with the formatting of the running command.
+type Printer struct {
+	*log.Logger
+	*Formatting
+}
+
+// Texter is implemented by every value that can be rendered
+// in the default human readable format.
+type Texter interface {
+	Text(l *Printer)
+}
+
+// CSVer is implemented by values that can be rendered as csv records.
+type CSVer interface {
+	CSV(w *csv.Writer) error
+}
+
+// Entrier is implemented by values that hold time entries, which can be
+// rendered in any of the entry formats.
+type Entrier interface {
+	Entries() harvest.TimeEntries
+}
+
+// ICSer is implemented by values that can be rendered as an iCalendar.
+type ICSer interface {
+	ICS(w *ICSWriter, f *Formatting) error
+}
+
+// HTMLer is implemented by values that can be rendered as a standalone
+// html document.
+type HTMLer interface {
+	HTML(w io.Writer, f *Formatting) error
+}
+
+// PDFer is implemented by values that can be rendered as a pdf document.
+type PDFer interface {
+	PDF(w *PDFWriter, f *Formatting) error
+}
+
+// StatusBarer is implemented by values that fit in a status bar, as a single
+// line of text or a waybar custom module.
+type StatusBarer interface {
+	Line() string
+	Waybar() *Waybar
+}
+
+// Waybar is the json a waybar custom module with "return-type": "json" reads.
+type Waybar struct {
+	Text       string `json:"text"`
+	Alt        string `json:"alt,omitempty"`
+	Tooltip    string `json:"tooltip,omitempty"`
+	Class      string `json:"class,omitempty"`
+	Percentage int    `json:"percentage"`
+}
+
+type Renderer interface {
+	Render(v interface{}) error
+}
+
+// NewRenderer returns the renderer of format, formats are the csv formats
+// time entries can be rendered in besides the builtin ones and f how hours,
+// money and times are printed.
+func NewRenderer(
+	format string,
+	l *log.Logger,
+	formats map[string]func() EntryFormatter,
+	f *Formatting,
+) (Renderer, error) {
+	switch format {
+	case formatText:
+		return &TextRenderer{&Printer{l, f}}, nil
+	case formatJSON:
+		return &JSONRenderer{l.Writer()}, nil
+	case formatCSV:
+		return &CSVRenderer{l.Writer()}, nil
+	case formatICS:
+		return &ICSRenderer{l.Writer(), f}, nil
+	case formatHTML:
+		return &HTMLRenderer{l.Writer(), f}, nil
+	case formatPDF:
+		return &PDFRenderer{l.Writer(), f}, nil
+	case formatWaybar, formatLine:
+		return &StatusBarRenderer{l.Writer(), format == formatWaybar}, nil
+	}
+
+	if f, ok := formats[format]; ok {
+		return &EntryRenderer{l.Writer(), f()}, nil
+	}
+
+	return nil, fmt.Errorf("Invalid format '%s'", format)
+}
+
+type TextRenderer struct {
+	l *Printer
+}
+
+func (r *TextRenderer) Render(v interface{}) error {
+	t, ok := v.(Texter)
+	if !ok {
+		return fmt.Errorf("Can not render %T as text", v)
+	}
+
+	t.Text(r.l)
+	return nil
+}
+
+type JSONRenderer struct {
+	w io.Writer
+}
+
+func (r *JSONRenderer) Render(v interface{}) error {
+	e := json.NewEncoder(r.w)
+	e.SetIndent("", "    ")
+	return e.Encode(v)
+}
+
+type CSVRenderer struct {
+	w io.Writer
+}
+
+func (r *CSVRenderer) Render(v interface{}) error {
+	c, ok := v.(CSVer)
+	if !ok {
+		return fmt.Errorf("Can not render %T as csv", v)
+	}
+
+	w := csv.NewWriter(r.w)
+	if err := c.CSV(w); err != nil {
+		return err
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// EntryRenderer renders time entries as csv records of an EntryFormatter.
+type EntryRenderer struct {
+	w io.Writer
+	f EntryFormatter
+}
+
+func (r *EntryRenderer) Render(v interface{}) error {
+	e, ok := v.(Entrier)
+	if !ok {
+		return fmt.Errorf("Can not render %T as time entries", v)
+	}
+
+	w := csv.NewWriter(r.w)
+	if err := writeEntries(w, r.f, e.Entries()); err != nil {
+		return err
+	}
+	w.Flush()
+	return w.Error()
+}
+
+type ICSRenderer struct {
+	w io.Writer
+	f *Formatting
+}
+
+func (r *ICSRenderer) Render(v interface{}) error {
+	c, ok := v.(ICSer)
+	if !ok {
+		return fmt.Errorf("Can not render %T as ics", v)
+	}
+
+	w := NewICSWriter(r.w)
+	w.Begin("VCALENDAR")
+	w.Line("VERSION", "2.0")
+	w.Line("PRODID", "-//frizinak//timetracking//EN")
+	if err := c.ICS(w, r.f); err != nil {
+		return err
+	}
+	w.End("VCALENDAR")
+	return w.Err()
+}
+
+type HTMLRenderer struct {
+	w io.Writer
+	f *Formatting
+}
+
+func (r *HTMLRenderer) Render(v interface{}) error {
+	h, ok := v.(HTMLer)
+	if !ok {
+		return fmt.Errorf("Can not render %T as html", v)
+	}
+
+	return h.HTML(r.w, r.f)
+}
+
+type PDFRenderer struct {
+	w io.Writer
+	f *Formatting
+}
+
+func (r *PDFRenderer) Render(v interface{}) error {
+	p, ok := v.(PDFer)
+	if !ok {
+		return fmt.Errorf("Can not render %T as pdf", v)
+	}
+
+	w := NewPDFWriter(r.w)
+	if err := p.PDF(w, r.f); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+type StatusBarRenderer struct {
+	w      io.Writer
+	waybar bool
+}
+
+func (r *StatusBarRenderer) Render(v interface{}) error {
+	s, ok := v.(StatusBarer)
+	if !ok {
+		return fmt.Errorf("Can not render %T for a status bar", v)
+	}
+
+	if r.waybar {
+		return json.NewEncoder(r.w).Encode(s.Waybar())
+	}
+
+	_, err := fmt.Fprintln(r.w, s.Line())
+	return err
+}
+
+// formatDate formats an optional harvest date, empty if nil.
+func formatDate(d *harvest.Date) string {
+	if d == nil {
+		return ""
+	}
+	return d.Format(timetracking.DateFormat)
+}
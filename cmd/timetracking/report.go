@@ -0,0 +1,432 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+type ReportUser struct {
+	ID        int                   `json:"id"`
+	FirstName string                `json:"first_name"`
+	LastName  string                `json:"last_name"`
+	Capacity  timetracking.Duration `json:"weekly_capacity"`
+}
+
+func NewReportUser(u *harvest.User, capacity timetracking.Duration) ReportUser {
+	return ReportUser{u.ID, u.FirstName, u.LastName, capacity}
+}
+
+type ReportGroup struct {
+	Name     string                `json:"name"`
+	Date     time.Time             `json:"date"`
+	Days     int                   `json:"days"`
+	Hours    timetracking.Duration `json:"hours"`
+	Billable timetracking.Duration `json:"billable_hours"`
+	Revenue  float64               `json:"revenue"`
+	Cost     float64               `json:"cost"`
+	Target   timetracking.Duration `json:"target"`
+	Running  bool                  `json:"running"`
+	// Off reports whether the first day of the group is a weekday off.
+	Off bool `json:"off"`
+}
+
+func (g *ReportGroup) NonBillable() timetracking.Duration {
+	return g.Hours - g.Billable
+}
+
+func (g *ReportGroup) Profit() float64 {
+	return g.Revenue - g.Cost
+}
+
+// Percentage returns the hours as a percentage of the target, 0 for groups
+// without a target.
+func (g *ReportGroup) Percentage() float64 {
+	if g.Target == 0 {
+		return 0
+	}
+	return 100 * float64(g.Hours) / float64(g.Target)
+}
+
+func newReportGroup(e *harvest.Group, conf *timetracking.Config, capacity timetracking.Duration) *ReportGroup {
+	days := make(map[string]struct{}, 1)
+	var target timetracking.Duration
+	for _, d := range e.SpentDates {
+		key := d.Format(timetracking.DateFormat)
+		if _, ok := days[key]; !ok {
+			target += conf.DayTarget(capacity, d)
+		}
+		days[key] = struct{}{}
+	}
+
+	return &ReportGroup{
+		Name:     e.Key.Name,
+		Date:     e.FirstSpentDate,
+		Days:     len(days),
+		Hours:    timetracking.Duration(e.Hours),
+		Billable: timetracking.Duration(e.BillableHours),
+		Revenue:  e.Revenue,
+		Cost:     e.Cost,
+		Target:   target,
+		Running:  e.Running,
+		Off:      conf.Off(e.FirstSpentDate),
+	}
+}
+
+// workedTarget returns the target of the days entries were tracked on,
+// hours tracked on days off count for the working day before them.
+func workedTarget(conf *timetracking.Config, capacity timetracking.Duration, entries harvest.TimeEntries) timetracking.Duration {
+	days := make(map[string]struct{})
+	var target timetracking.Duration
+	for _, e := range entries {
+		if e.SpentDate == nil {
+			continue
+		}
+
+		d := e.SpentDate.Time
+		for conf.Excluded(d) || conf.Off(d) {
+			d = d.AddDate(0, 0, -1)
+		}
+		key := d.Format(timetracking.DateFormat)
+		if _, ok := days[key]; !ok {
+			days[key] = struct{}{}
+			target += conf.DayTarget(capacity, d)
+		}
+	}
+
+	return target
+}
+
+// reportQuery selects the entries of a tracking report: the days up to
+// from or, with to, the range from to.
+type reportQuery struct {
+	from     time.Time
+	to       *time.Time
+	days     int
+	worked   bool
+	group    string
+	capacity timetracking.Duration
+	filter   harvest.Filter
+	rounding *timetracking.Rounding
+	rates    bool
+}
+
+// newReport fetches the entries of q and groups them in a report,
+// the fetched entries and groups are returned as well.
+func newReport(
+	ctx context.Context,
+	t *timetracking.Timetracking,
+	conf *timetracking.Config,
+	q reportQuery,
+) (*Report, harvest.TimeEntries, harvest.Grouped, error) {
+	// Time off pushes the first of the recent days further back, look a
+	// few months past them without it.
+	if q.to != nil {
+		t = t.WithTimeOff(ctx, q.from, *q.to)
+	} else {
+		t = t.WithTimeOff(ctx, conf.FirstWorkingDay(q.from, q.days).AddDate(0, -3, 0), q.from)
+	}
+	conf = t.Config()
+
+	days := q.days
+	var daysWorked int
+	var entries harvest.TimeEntries
+	var err error
+	if q.to != nil {
+		days = conf.WorkingDays(q.from, *q.to)
+		daysWorked, entries, err = t.GetRange(ctx, q.from, *q.to, !q.worked)
+	} else {
+		daysWorked, entries, err = t.GetRecentDays(ctx, days, q.from, !q.worked)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if q.rates {
+		if err := t.FillRates(ctx, entries); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	code, err := t.ConvertRates(ctx, entries)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if q.rounding != nil {
+		// Round what is reported, not the hours that are filtered out.
+		if q.filter != nil {
+			entries = entries.Filter(
+				func(e *harvest.TimeEntry) bool { return e.ID == 0 || q.filter(e) },
+			)
+		}
+		entries = q.rounding.Apply(entries)
+	}
+
+	grouped, err := t.Group(entries, q.group, q.filter)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	report := &Report{
+		User:     NewReportUser(t.User(), conf.WeekTarget(q.capacity)),
+		From:     q.from,
+		Group:    q.group,
+		Days:     days,
+		Estimate: q.worked,
+		Worked:   daysWorked,
+		Currency: code,
+		Groups:   make([]*ReportGroup, 0, len(grouped)),
+		Target:   workedTarget(conf, q.capacity, entries),
+	}
+	switch {
+	case q.to != nil:
+		report.To = q.to
+		report.Capacity = conf.Target(q.capacity, q.from, *q.to)
+	case q.worked && days > 0:
+		report.Capacity = conf.Target(q.capacity, conf.FirstWorkingDay(q.from, days), q.from)
+	case !q.worked:
+		// Every working day of the period has an entry.
+		report.Capacity = report.Target
+	}
+
+	if timetracking.DateGroup(q.group) {
+		grouped = grouped.SortSpent()
+	} else {
+		grouped = grouped.SortHours()
+	}
+
+	for _, e := range grouped {
+		report.Groups = append(report.Groups, newReportGroup(e, conf, q.capacity))
+		report.Total += timetracking.Duration(e.Hours)
+		report.Billable += timetracking.Duration(e.BillableHours)
+		report.Revenue += e.Revenue
+		report.Cost += e.Cost
+	}
+
+	return report, entries, grouped, nil
+}
+
+type Report struct {
+	User     ReportUser            `json:"user"`
+	From     time.Time             `json:"from"`
+	To       *time.Time            `json:"to,omitempty"`
+	Group    string                `json:"group"`
+	Days     int                   `json:"days"`
+	Estimate bool                  `json:"estimate"`
+	Split    bool                  `json:"-"`
+	Profits  bool                  `json:"-"`
+	Chart    bool                  `json:"-"`
+	Capacity timetracking.Duration `json:"capacity"`
+	Worked   int                   `json:"days_worked"`
+	Groups   []*ReportGroup        `json:"groups"`
+	Total    timetracking.Duration `json:"total"`
+	Billable timetracking.Duration `json:"billable_hours"`
+	Revenue  float64               `json:"revenue"`
+	Cost     float64               `json:"cost"`
+	Currency string                `json:"currency,omitempty"`
+	Target   timetracking.Duration `json:"target"`
+	Expenses *ExpenseSummary       `json:"expenses,omitempty"`
+	Projects []*ReportGroup        `json:"projects,omitempty"`
+}
+
+func (r *Report) Remaining() timetracking.Duration {
+	return r.Target - r.Total
+}
+
+func (r *Report) NonBillable() timetracking.Duration {
+	return r.Total - r.Billable
+}
+
+func (r *Report) Profit() float64 {
+	return r.Revenue - r.Cost
+}
+
+// margin returns profit as a percentage of revenue.
+func margin(revenue, profit float64) string {
+	if revenue == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", 100*profit/revenue)
+}
+
+// Percentage returns the total as a percentage of the target, 0 when there
+// is no target (e.g. a range without entries or working days).
+func (r *Report) Percentage() float64 {
+	if r.Target == 0 {
+		return 0
+	}
+	return 100 * float64(r.Total) / float64(r.Target)
+}
+
+// Share returns the hours of g as a percentage of the total.
+func (r *Report) Share(g *ReportGroup) float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return 100 * float64(g.Hours) / float64(r.Total)
+}
+
+func (r *Report) MarshalJSON() ([]byte, error) {
+	type report Report
+	return json.Marshal(
+		struct {
+			*report
+			NonBillable timetracking.Duration `json:"non_billable_hours"`
+			Remaining   timetracking.Duration `json:"remaining"`
+			Percentage  float64               `json:"percentage"`
+			Profit      float64               `json:"profit"`
+		}{(*report)(r), r.Total - r.Billable, r.Remaining(), r.Percentage(), r.Profit()},
+	)
+}
+
+func (r *Report) Text(l *Printer) {
+	estimate := ""
+	if r.Estimate {
+		estimate = " (estimate)"
+	}
+
+	to := ""
+	if r.To != nil {
+		to = "\nTo: " + r.To.Format("Mon Jan 02 2006")
+	}
+
+	l.Printf(
+		"Running for %s %s\nID: %d\nWeek: %s\nOver %d days%s: %s\nFrom: %s%s\n\n",
+		r.User.FirstName,
+		r.User.LastName,
+		r.User.ID,
+		l.Hours(r.User.Capacity),
+		r.Days,
+		estimate,
+		l.Hours(r.Capacity),
+		r.From.Format("Mon Jan 02 2006"),
+		to,
+	)
+
+	if r.Chart {
+		r.chart(l.Formatting).Text(l)
+	} else {
+		r.table(l.Formatting).Text(l)
+	}
+
+	diff := r.Remaining()
+	diffStr := fmt.Sprintf("%s remaining...", l.Hours(diff))
+	if diff < 0 {
+		diffStr = "target reached!"
+	}
+
+	l.Printf(
+		"\nTotal: %s / %s (%.2f%%)\n%s",
+		l.Hours(r.Total),
+		l.Hours(r.Target),
+		r.Percentage(),
+		diffStr,
+	)
+
+	if r.Split {
+		l.Printf(
+			"Billable: %s\nNon-billable: %s\nRevenue: %s",
+			l.Hours(r.Billable),
+			l.Hours(r.Total-r.Billable),
+			l.Money(r.Revenue, r.Currency),
+		)
+	}
+
+	if r.Profits {
+		l.Printf(
+			"Revenue: %s\nCost: %s\nProfit: %s (%s)",
+			l.Money(r.Revenue, r.Currency),
+			l.Money(r.Cost, r.Currency),
+			l.Money(r.Profit(), r.Currency),
+			margin(r.Revenue, r.Profit()),
+		)
+	}
+
+	if r.Expenses != nil {
+		l.Println()
+		r.Expenses.Text(l)
+	}
+}
+
+func (r *Report) table(f *Formatting) *Table {
+	var t *Table
+	if timetracking.DateGroup(r.Group) {
+		t = NewTable("Date", "Hours", "Target", "%").Right(1, 2, 3)
+	} else {
+		t = NewTable("Name", "Hours", "%").Right(1, 2)
+	}
+
+	n := len(t.header)
+	if r.Split {
+		t.header = append(t.header, "Billable", "Non-billable", "Revenue")
+		t.Right(n, n+1, n+2)
+	}
+
+	n = len(t.header)
+	if r.Profits {
+		t.header = append(t.header, "Revenue", "Cost", "Profit", "Margin")
+		t.Right(n, n+1, n+2, n+3)
+	}
+
+	for _, g := range r.Groups {
+		var cells []string
+		style := timetracking.StyleNone
+		if timetracking.DateGroup(r.Group) {
+			cells = []string{
+				g.Date.Format("Mon Jan 02 2006"),
+				f.Hours(g.Hours),
+				f.Hours(g.Target),
+				fmt.Sprintf("%.2f%%", g.Percentage()),
+			}
+			style = groupStyle(r.Group, g)
+		} else {
+			cells = []string{
+				g.Name,
+				f.Hours(g.Hours),
+				fmt.Sprintf("%.2f%%", r.Share(g)),
+			}
+		}
+
+		if r.Split {
+			cells = append(
+				cells,
+				f.Hours(g.Billable),
+				f.Hours(g.NonBillable()),
+				f.Money(g.Revenue, r.Currency),
+			)
+		}
+
+		if r.Profits {
+			cells = append(
+				cells,
+				f.Money(g.Revenue, r.Currency),
+				f.Money(g.Cost, r.Currency),
+				f.Money(g.Profit(), r.Currency),
+				margin(g.Revenue, g.Profit()),
+			)
+		}
+
+		t.Row(style, cells...)
+	}
+
+	return t
+}
+
+// groupStyle dims weekdays off and colors groups by whether their target
+// was reached.
+func groupStyle(group string, g *ReportGroup) string {
+	switch {
+	case group == timetracking.GroupByDay && g.Off:
+		return timetracking.StyleWeekend
+	case g.Target == 0:
+		return timetracking.StyleNone
+	case g.Hours < g.Target:
+		return timetracking.StyleUnder
+	}
+	return timetracking.StyleOnTarget
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+func TestReportMarshalJSON(t *testing.T) {
+	hour := timetracking.Duration(time.Hour)
+	tests := []struct {
+		name   string
+		report *Report
+		want   float64
+	}{
+		{"empty", &Report{}, 0},
+		{"only days off", &Report{Groups: []*ReportGroup{{Name: "Sat", Off: true}}}, 0},
+		{"hours without a target", &Report{Total: hour, Groups: []*ReportGroup{{Name: "Sat", Hours: hour}}}, 0},
+		{"half", &Report{Total: 4 * hour, Target: 8 * hour, Groups: []*ReportGroup{{Hours: 4 * hour, Target: 8 * hour}}}, 50},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := json.Marshal(test.report)
+			if err != nil {
+				t.Fatalf("json.Marshal: %s", err)
+			}
+
+			var got struct {
+				Percentage float64 `json:"percentage"`
+			}
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Percentage != test.want {
+				t.Errorf("percentage = %g, want %g", got.Percentage, test.want)
+			}
+
+			for _, g := range test.report.Groups {
+				if p := g.Percentage(); p != test.want {
+					t.Errorf("group percentage = %g, want %g", p, test.want)
+				}
+				if s := test.report.Share(g); test.report.Total == 0 && s != 0 {
+					t.Errorf("share of a report without hours = %g, want 0", s)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// slackEscaper escapes the characters slack reserves for links and mentions.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// slackEscape makes s show up as is in a slack message.
+func slackEscape(s string) string {
+	return slackEscaper.Replace(s)
+}
+
+// postSlack posts a message to a slack incoming webhook.
+func postSlack(ctx context.Context, webhook, text string) error {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		all, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("Unexpected slack error (%d): %s", res.StatusCode, strings.TrimSpace(string(all)))
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+// noColor is set by the -no-color flag.
+var noColor bool
+
+// colors reports whether output should be colored: not disabled with
+// -no-color or NO_COLOR and stdout is a terminal.
+func colors() bool {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	stat, err := os.Stdout.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// Table aligns cells in columns, every row can be colored with a style.
+type Table struct {
+	header []string
+	right  map[int]bool
+	rows   []tableRow
+}
+
+type tableRow struct {
+	style string
+	cells []string
+}
+
+func NewTable(header ...string) *Table {
+	return &Table{header: header, right: make(map[int]bool)}
+}
+
+// Right aligns the given columns to the right, e.g. for numbers.
+func (t *Table) Right(cols ...int) *Table {
+	for _, c := range cols {
+		t.right[c] = true
+	}
+	return t
+}
+
+func (t *Table) Row(style string, cells ...string) {
+	t.rows = append(t.rows, tableRow{style, cells})
+}
+
+func (t *Table) Text(l *Printer) {
+	rows := t.rows
+	if len(t.header) != 0 {
+		rows = append([]tableRow{{timetracking.StyleHeader, t.header}}, rows...)
+	}
+
+	var widths []int
+	for _, r := range rows {
+		for i, c := range r.cells {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if n := utf8.RuneCountInString(c); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	color, theme := colors(), l.Theme
+	for _, r := range rows {
+		cells := make([]string, len(r.cells))
+		for i, c := range r.cells {
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c))
+			switch {
+			case t.right[i]:
+				cells[i] = pad + c
+			case i == len(r.cells)-1:
+				cells[i] = c
+			default:
+				cells[i] = c + pad
+			}
+		}
+
+		line := strings.Join(cells, "  ")
+		if code, _ := timetracking.SGR(theme.Style(r.style)); color && code != "" {
+			line = code + line + ansiReset
+		}
+		l.Println(line)
+	}
+}
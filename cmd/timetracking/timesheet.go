@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+// timesheetTemplate is the name of the template in the config directory
+// that overrides the layout of pdf timesheets.
+const timesheetTemplate = "timesheet.tmpl"
+
+type TimesheetDay struct {
+	Date  time.Time
+	Hours timetracking.Duration
+}
+
+type TimesheetProject struct {
+	Name  string
+	Days  []*TimesheetDay
+	Total timetracking.Duration
+}
+
+type TimesheetClient struct {
+	Name     string
+	Projects []*TimesheetProject
+	Total    timetracking.Duration
+}
+
+// Timesheet is a formal overview of the hours of one user per client,
+// project and day, rendered as pdf with a text/template.
+type Timesheet struct {
+	User    string
+	From    time.Time
+	To      time.Time
+	Clients []*TimesheetClient
+	Total   timetracking.Duration
+
+	tmpl *template.Template
+}
+
+func NewTimesheet(entries ExportEntries, from, to time.Time, tmpl *template.Template) *Timesheet {
+	ts := &Timesheet{From: from, To: to, tmpl: tmpl}
+	clients := make(map[int]*TimesheetClient)
+	projects := make(map[int]*TimesheetProject)
+	days := make(map[int]map[string]*TimesheetDay)
+	for _, e := range entries {
+		if e.SpentDate == nil {
+			continue
+		}
+		if ts.User == "" {
+			ts.User = e.User.Name
+		}
+
+		client, ok := clients[e.Client.ID]
+		if !ok {
+			client = &TimesheetClient{Name: e.Client.Name}
+			clients[e.Client.ID] = client
+			ts.Clients = append(ts.Clients, client)
+		}
+
+		project, ok := projects[e.Project.ID]
+		if !ok {
+			project = &TimesheetProject{Name: e.Project.Name}
+			projects[e.Project.ID] = project
+			days[e.Project.ID] = make(map[string]*TimesheetDay)
+			client.Projects = append(client.Projects, project)
+		}
+
+		key := e.SpentDate.Format(timetracking.DateFormat)
+		d, ok := days[e.Project.ID][key]
+		if !ok {
+			d = &TimesheetDay{Date: e.SpentDate.Time}
+			days[e.Project.ID][key] = d
+			project.Days = append(project.Days, d)
+		}
+
+		hours := timetracking.Duration(e.Hours.Duration)
+		d.Hours += hours
+		project.Total += hours
+		client.Total += hours
+		ts.Total += hours
+	}
+
+	sort.Slice(ts.Clients, func(i, j int) bool {
+		return ts.Clients[i].Name < ts.Clients[j].Name
+	})
+	for _, c := range ts.Clients {
+		sort.Slice(c.Projects, func(i, j int) bool {
+			return c.Projects[i].Name < c.Projects[j].Name
+		})
+		for _, p := range c.Projects {
+			sort.Slice(p.Days, func(i, j int) bool {
+				return p.Days[i].Date.Before(p.Days[j].Date)
+			})
+		}
+	}
+
+	return ts
+}
+
+func (ts *Timesheet) PDF(w *PDFWriter, f *Formatting) error {
+	tmpl, err := ts.tmpl.Clone()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Funcs(template.FuncMap{"hours": f.Hours}).Execute(&buf, ts); err != nil {
+		return err
+	}
+
+	s := bufio.NewScanner(&buf)
+	for s.Scan() {
+		w.Line(s.Text())
+	}
+
+	return s.Err()
+}
+
+// loadTimesheetTemplate parses timesheet.tmpl next to the config file,
+// or the default layout if there is none. Its hours are printed with the
+// formatting the pdf is rendered with.
+func loadTimesheetTemplate(l *log.Logger) (*template.Template, error) {
+	path, _, err := configPath(l)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := os.ReadFile(filepath.Join(filepath.Dir(path), timesheetTemplate))
+	if os.IsNotExist(err) {
+		raw, err = templateFS.ReadFile("templates/" + timesheetTemplate)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return template.New(timesheetTemplate).
+		Funcs(template.FuncMap{"hours": NewFormatting(nil).Hours}).
+		Parse(string(raw))
+}
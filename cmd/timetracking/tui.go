@@ -0,0 +1,409 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/frizinak/harvest-timetracking/forecast"
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+const (
+	ansiClear   = "\033[H\033[2J"
+	ansiReverse = "\033[7m"
+	ansiBold    = "\033[1m"
+	ansiReset   = "\033[0m"
+
+	keyEnter     = '\r'
+	keyNewline   = '\n'
+	keyEscape    = 27
+	keyBackspace = 127
+	keyCtrlH     = 8
+)
+
+// terminal puts the controlling terminal in raw mode using stty.
+type terminal struct {
+	state string
+}
+
+func rawTerminal() (*terminal, error) {
+	cmd := exec.Command("stty", "-g")
+	cmd.Stdin = os.Stdin
+	state, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("Could not read terminal state (is stdin a terminal?): %w", err)
+	}
+
+	cmd = exec.Command("stty", "-icanon", "-echo", "min", "1")
+	cmd.Stdin = os.Stdin
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+
+	fmt.Print("\033[?25l")
+	return &terminal{strings.TrimSpace(string(state))}, nil
+}
+
+func (t *terminal) Restore() error {
+	fmt.Print("\033[?25h")
+	cmd := exec.Command("stty", t.state)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
+
+type dashboard struct {
+	ctx context.Context
+	t   *timetracking.Timetracking
+
+	sem         sync.Mutex
+	today       harvest.TimeEntries
+	week        time.Duration
+	assignments map[string][]*forecast.Assignment
+	weekStart   time.Time
+	fetched     time.Time
+	selected    int
+	editing     bool
+	input       []rune
+	status      string
+}
+
+func newDashboard(ctx context.Context, t *timetracking.Timetracking) *dashboard {
+	return &dashboard{ctx: ctx, t: t}
+}
+
+func (d *dashboard) refresh() error {
+	now := d.t.Now()
+	today := timetracking.Day(now)
+	entries, err := d.t.GetTimeEntriesBetween(d.ctx, today, today)
+	if err != nil {
+		return err
+	}
+
+	week, err := d.t.GetTimeEntriesBetween(d.ctx, d.t.StartOfWeek(today), today)
+	if err != nil {
+		return err
+	}
+
+	var weekTotal time.Duration
+	for _, e := range week {
+		weekTotal += e.Hours.Duration
+	}
+
+	var assignments map[string][]*forecast.Assignment
+	if d.t.ForecastUser() != nil {
+		assignments, err = d.t.GetForecastAssignments(
+			d.ctx,
+			d.t.StartOfWeek(today),
+			d.t.StartOfWeek(today).AddDate(0, 0, 6),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	sort.SliceStable(
+		entries,
+		func(i, j int) bool { return entries[i].ID < entries[j].ID },
+	)
+
+	d.sem.Lock()
+	d.today = entries
+	d.week = weekTotal
+	d.assignments = assignments
+	d.weekStart = d.t.StartOfWeek(today)
+	d.fetched = now
+	if d.selected >= len(entries) {
+		d.selected = len(entries) - 1
+	}
+	if d.selected < 0 {
+		d.selected = 0
+	}
+	d.sem.Unlock()
+	return nil
+}
+
+// elapsed returns the hours of an entry including the time passed since
+// the last refresh if it is running.
+func (d *dashboard) elapsed(e *harvest.TimeEntry) time.Duration {
+	h := e.Hours.Duration
+	if e.Running {
+		h += time.Since(d.fetched)
+	}
+	return h
+}
+
+func (d *dashboard) draw(w io.Writer) {
+	d.sem.Lock()
+	defer d.sem.Unlock()
+
+	f := NewFormatting(d.t)
+	buf := bytes.NewBuffer(nil)
+	buf.WriteString(ansiClear)
+	u := d.t.User()
+	fmt.Fprintf(
+		buf,
+		"%s%s %s - %s%s\r\n\r\n",
+		ansiBold,
+		u.FirstName,
+		u.LastName,
+		d.t.Now().Format("Mon Jan 02 2006 15:04:05"),
+		ansiReset,
+	)
+
+	var total time.Duration
+	var running *harvest.TimeEntry
+	fmt.Fprintf(buf, "%sToday%s\r\n", ansiBold, ansiReset)
+	if len(d.today) == 0 {
+		buf.WriteString("  no entries\r\n")
+	}
+	for i, e := range d.today {
+		h := d.elapsed(e)
+		total += h
+		marker := " "
+		if e.Running {
+			marker = "▶"
+			running = e
+		}
+
+		line := fmt.Sprintf(
+			"%s %6s  %s / %s  %s",
+			marker,
+			f.Hours(timetracking.Duration(h)),
+			e.Project.Name,
+			e.Task.Name,
+			e.Notes,
+		)
+		if i == d.selected {
+			line = ansiReverse + line + ansiReset
+		}
+		buf.WriteString("  " + line + "\r\n")
+	}
+
+	fmt.Fprintf(buf, "\r\nToday: %s\r\n", f.Hours(timetracking.Duration(total)))
+	week := d.week
+	if running != nil {
+		week += time.Since(d.fetched)
+	}
+	fmt.Fprintf(
+		buf,
+		"Week:  %s / %s\r\n",
+		f.Hours(timetracking.Duration(week)),
+		f.Hours(d.t.Capacity()),
+	)
+
+	if running != nil {
+		fmt.Fprintf(
+			buf,
+			"\r\n%sRunning:%s %s / %s - %s\r\n",
+			ansiBold,
+			ansiReset,
+			running.Project.Name,
+			running.Task.Name,
+			f.Hours(timetracking.Duration(d.elapsed(running))),
+		)
+	}
+
+	if len(d.assignments) != 0 {
+		fmt.Fprintf(buf, "\r\n%sForecast this week%s\r\n", ansiBold, ansiReset)
+		names := make([]string, 0, len(d.assignments))
+		for n := range d.assignments {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		weekEnd := d.weekStart.AddDate(0, 0, 6)
+		for _, n := range names {
+			var alloc time.Duration
+			for _, a := range d.assignments[n] {
+				alloc += d.t.Allocated(a, d.weekStart, weekEnd)
+			}
+			fmt.Fprintf(buf, "  %-40s %s\r\n", n, f.Hours(timetracking.Duration(alloc)))
+		}
+	}
+
+	buf.WriteString("\r\n")
+	if d.editing {
+		fmt.Fprintf(buf, "Notes: %s█\r\n", string(d.input))
+		buf.WriteString("[enter] save  [esc] cancel\r\n")
+	} else {
+		buf.WriteString("[j/k] select  [s] start  [x] stop  [e] edit notes  [r] refresh  [q] quit\r\n")
+	}
+
+	if d.status != "" {
+		fmt.Fprintf(buf, "\r\n%s\r\n", d.status)
+	}
+
+	w.Write(buf.Bytes())
+}
+
+func (d *dashboard) selectedEntry() *harvest.TimeEntry {
+	d.sem.Lock()
+	defer d.sem.Unlock()
+	if d.selected < 0 || d.selected >= len(d.today) {
+		return nil
+	}
+	return d.today[d.selected]
+}
+
+func (d *dashboard) setStatus(err error, format string, args ...interface{}) {
+	d.sem.Lock()
+	defer d.sem.Unlock()
+	if err != nil {
+		d.status = err.Error()
+		return
+	}
+	d.status = fmt.Sprintf(format, args...)
+}
+
+// key handles a single key press, returns false when the dashboard should quit.
+func (d *dashboard) key(k rune) bool {
+	if d.editing {
+		d.sem.Lock()
+		switch k {
+		case keyEnter, keyNewline:
+			d.editing = false
+			notes := string(d.input)
+			d.sem.Unlock()
+			if e := d.selectedEntry(); e != nil {
+				_, err := d.t.UpdateNotes(d.ctx, e.ID, notes)
+				if err == nil {
+					err = d.refresh()
+				}
+				d.setStatus(err, "Updated notes")
+			}
+			return true
+		case keyEscape:
+			d.editing = false
+		case keyBackspace, keyCtrlH:
+			if len(d.input) != 0 {
+				d.input = d.input[:len(d.input)-1]
+			}
+		default:
+			if k >= 32 {
+				d.input = append(d.input, k)
+			}
+		}
+		d.sem.Unlock()
+		return true
+	}
+
+	switch k {
+	case 'q':
+		return false
+	case 'j':
+		d.sem.Lock()
+		if d.selected < len(d.today)-1 {
+			d.selected++
+		}
+		d.sem.Unlock()
+	case 'k':
+		d.sem.Lock()
+		if d.selected > 0 {
+			d.selected--
+		}
+		d.sem.Unlock()
+	case 'r':
+		d.setStatus(d.refresh(), "Refreshed")
+	case 's':
+		e := d.selectedEntry()
+		if e == nil || e.Running {
+			return true
+		}
+		_, err := d.t.RestartTracker(d.ctx, e.ID)
+		if err == nil {
+			err = d.refresh()
+		}
+		d.setStatus(err, "Started %s / %s", e.Project.Name, e.Task.Name)
+	case 'x':
+		e, err := d.t.StopTracker(d.ctx)
+		if err == nil && e == nil {
+			d.setStatus(nil, "No timer running")
+			return true
+		}
+		if err == nil {
+			err = d.refresh()
+		}
+		d.setStatus(err, "Stopped timer")
+	case 'e':
+		e := d.selectedEntry()
+		if e == nil {
+			return true
+		}
+		d.sem.Lock()
+		d.editing = true
+		d.input = []rune(e.Notes)
+		d.sem.Unlock()
+	}
+
+	return true
+}
+
+// readKeys sends the utf-8 decoded key presses read from r to keys until
+// reading fails or done is closed, keys is closed when reading fails.
+func readKeys(r io.Reader, keys chan<- rune, done <-chan struct{}) {
+	buf := make([]byte, 0, 64)
+	b := make([]byte, 32)
+	for {
+		n, err := r.Read(b)
+		if err != nil {
+			close(keys)
+			return
+		}
+		buf = append(buf, b[:n]...)
+		for len(buf) != 0 && (utf8.FullRune(buf) || len(buf) >= utf8.UTFMax) {
+			k, size := utf8.DecodeRune(buf)
+			buf = buf[size:]
+			select {
+			case keys <- k:
+			case <-done:
+				return
+			}
+		}
+	}
+}
+
+func (d *dashboard) Run(w io.Writer, r io.Reader) error {
+	if err := d.refresh(); err != nil {
+		return err
+	}
+
+	keys := make(chan rune)
+	done := make(chan struct{})
+	defer close(done)
+	if f, ok := r.(*os.File); ok {
+		// Unblocks the pending read where stdin supports deadlines, else
+		// the reader stops after the next key.
+		defer f.SetReadDeadline(time.Now())
+	}
+	go readKeys(r, keys, done)
+
+	redraw := time.NewTicker(time.Second)
+	defer redraw.Stop()
+	refresh := time.NewTicker(time.Minute)
+	defer refresh.Stop()
+
+	d.draw(w)
+	for {
+		select {
+		case <-d.ctx.Done():
+			return nil
+		case k, ok := <-keys:
+			if !ok || !d.key(k) {
+				return nil
+			}
+		case <-refresh.C:
+			d.setStatus(d.refresh(), "")
+		case <-redraw.C:
+		}
+		d.draw(w)
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/timetracking"
+)
+
+// Harvest does not sign or send webhooks itself, these headers are set by
+// the relay that forwards harvest events.
+const (
+	webhookSignatureHeader = "X-Harvest-Signature"
+	webhookTimestampHeader = "X-Harvest-Timestamp"
+	webhookEventHeader     = "X-Harvest-Event"
+
+	// webhookMaxBody limits the size of a webhook request.
+	webhookMaxBody = 1 << 20
+	// webhookMaxAge is how far the timestamp of a request may be off.
+	webhookMaxAge = 5 * time.Minute
+	// webhookWorkers is the amount of hooks that run at the same time.
+	webhookWorkers = 4
+)
+
+// runHook passes the event to the hook, commands are run with sh and get the
+// body on stdin and the event in $TIMETRACKING_EVENT.
+func runHook(ctx context.Context, h *timetracking.WebhookHook, event string, body []byte) error {
+	if h.Command != "" {
+		cmd := exec.CommandContext(ctx, "sh", "-c", h.Command)
+		cmd.Env = append(os.Environ(), "TIMETRACKING_EVENT="+event)
+		cmd.Stdin = bytes.NewReader(body)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%s: %w: %s", h.Command, err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", h.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, event)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		all, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("%s responded %d: %s", h.URL, res.StatusCode, strings.TrimSpace(string(all)))
+	}
+
+	return nil
+}
+
+// webhooks receives harvest events signed with an HMAC-SHA256 of the unix
+// timestamp, a dot and the body (hex encoded, optionally prefixed with
+// "sha256=") and dispatches them to the configured hooks. Requests with a
+// timestamp more than webhookMaxAge off or a signature that was seen before
+// are rejected.
+type webhooks struct {
+	ctx  context.Context
+	l    *slog.Logger
+	conf *timetracking.WebhooksConfig
+
+	// running limits the hooks that run at the same time.
+	running chan struct{}
+
+	sem  sync.Mutex
+	seen map[string]time.Time
+}
+
+func newWebhooks(ctx context.Context, l *slog.Logger, conf *timetracking.WebhooksConfig) *webhooks {
+	return &webhooks{
+		ctx:     ctx,
+		l:       l,
+		conf:    conf,
+		running: make(chan struct{}, webhookWorkers),
+		seen:    make(map[string]time.Time),
+	}
+}
+
+type webhookEvent struct {
+	Event string `json:"event"`
+}
+
+func (wh *webhooks) verify(timestamp string, body []byte, signature string) bool {
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(wh.conf.Secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
+
+// fresh reports whether a request signed at timestamp is recent and its
+// signature was not seen before, it then remembers the signature.
+func (wh *webhooks) fresh(timestamp, signature string) bool {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	now := time.Now()
+	at := time.Unix(unix, 0)
+	if at.Before(now.Add(-webhookMaxAge)) || at.After(now.Add(webhookMaxAge)) {
+		return false
+	}
+
+	sig := strings.TrimPrefix(signature, "sha256=")
+	wh.sem.Lock()
+	defer wh.sem.Unlock()
+	for s, t := range wh.seen {
+		if now.Sub(t) > 2*webhookMaxAge {
+			delete(wh.seen, s)
+		}
+	}
+	if _, ok := wh.seen[sig]; ok {
+		return false
+	}
+	wh.seen[sig] = now
+
+	return true
+}
+
+func (wh *webhooks) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, webhookMaxBody))
+	if err != nil {
+		http.Error(w, "Could not read body", http.StatusBadRequest)
+		return
+	}
+
+	timestamp, signature := r.Header.Get(webhookTimestampHeader), r.Header.Get(webhookSignatureHeader)
+	if !wh.verify(timestamp, body, signature) {
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	if !wh.fresh(timestamp, signature) {
+		http.Error(w, "Expired or replayed request", http.StatusUnauthorized)
+		return
+	}
+
+	event := r.Header.Get(webhookEventHeader)
+	if event == "" {
+		var e webhookEvent
+		if err := json.Unmarshal(body, &e); err != nil {
+			http.Error(w, "Invalid json", http.StatusBadRequest)
+			return
+		}
+		event = e.Event
+	}
+
+	if event == "" {
+		http.Error(w, "Missing event", http.StatusBadRequest)
+		return
+	}
+
+	wh.l.Info("Received webhook", "event", event)
+	for _, h := range wh.conf.Hooks {
+		if !h.Handles(event) {
+			continue
+		}
+
+		// Wait for a running hook to finish when there are too many.
+		select {
+		case wh.running <- struct{}{}:
+		case <-r.Context().Done():
+			return
+		}
+		go func(h *timetracking.WebhookHook) {
+			defer func() { <-wh.running }()
+			if err := runHook(wh.ctx, h, event, body); err != nil {
+				wh.l.Error("Webhook failed", "event", event, "err", err)
+			}
+		}(h)
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -49,19 +49,35 @@ func (c *ConfigLoader) Read(v Config) error {
 }
 
 func (c *ConfigLoader) Create(v Config) error {
+	return c.write(v)
+}
+
+func (c *ConfigLoader) write(v interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return err
+	}
+
 	tmp := c.path + ".tmp"
 	file, err := os.Create(tmp)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
 	e := json.NewEncoder(file)
 	e.SetIndent("", "    ")
-	if err := e.Encode(v); err != nil {
+	err = e.Encode(v)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, c.path)
+	}
+	if err != nil {
+		os.Remove(tmp)
 		return err
 	}
-	return os.Rename(tmp, c.path)
+
+	return nil
 }
 
 func (c *ConfigLoader) CreateDefault() error {
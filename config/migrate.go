@@ -0,0 +1,90 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// VersionKey is the key of the schema version in a config file.
+const VersionKey = "version"
+
+// ProfilesKey is the key of the profiles in a config file, every profile is
+// a partial config.
+const ProfilesKey = "profiles"
+
+// Migration upgrades a raw config by one version. It is called for the
+// config and for each of its profiles, profile reports which.
+type Migration func(raw map[string]interface{}, profile bool) error
+
+// Migrate upgrades the config file to the latest version, migrations[i]
+// upgrades a config of version i to version i+1. Files without a version
+// are version 0. The file is only rewritten when a migration changed it,
+// the original is then kept with a .bak suffix.
+// Reports whether the file was rewritten.
+func (c *ConfigLoader) Migrate(migrations []Migration) (bool, error) {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return false, err
+	}
+
+	raw := make(map[string]interface{})
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return false, err
+	}
+
+	version := 0
+	if v, ok := raw[VersionKey].(float64); ok {
+		version = int(v)
+	}
+
+	if version > len(migrations) {
+		return false, fmt.Errorf(
+			"Config %s is version %d, this version only supports up to %d",
+			c.path,
+			version,
+			len(migrations),
+		)
+	}
+
+	if version == len(migrations) {
+		return false, nil
+	}
+
+	before, err := json.Marshal(raw)
+	if err != nil {
+		return false, err
+	}
+
+	profiles, _ := raw[ProfilesKey].(map[string]interface{})
+	for i := version; i < len(migrations); i++ {
+		if err := migrations[i](raw, false); err != nil {
+			return false, fmt.Errorf("Failed to migrate config to version %d: %w", i+1, err)
+		}
+		for name, p := range profiles {
+			profile, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if err := migrations[i](profile, true); err != nil {
+				return false, fmt.Errorf("Failed to migrate profile '%s' to version %d: %w", name, i+1, err)
+			}
+		}
+	}
+
+	after, err := json.Marshal(raw)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(before, after) {
+		return false, nil
+	}
+	raw[VersionKey] = len(migrations)
+
+	if err := os.WriteFile(c.path+".bak", data, 0600); err != nil {
+		return false, err
+	}
+
+	return true, c.write(raw)
+}
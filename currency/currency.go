@@ -0,0 +1,205 @@
+// Package currency formats amounts of money and converts them between
+// currencies with exchange rates from a pluggable provider.
+package currency
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Currency is how amounts in an ISO 4217 currency are written.
+type Currency struct {
+	Code     string
+	Symbol   string
+	Decimals int
+	// After places the symbol after the amount, e.g. 10,00 kr.
+	After bool
+}
+
+var currencies = map[string]Currency{
+	"AUD": {"AUD", "A$", 2, false},
+	"BRL": {"BRL", "R$", 2, false},
+	"CAD": {"CAD", "CA$", 2, false},
+	"CHF": {"CHF", "CHF ", 2, false},
+	"CNY": {"CNY", "¥", 2, false},
+	"CZK": {"CZK", " Kč", 2, true},
+	"DKK": {"DKK", " kr.", 2, true},
+	"EUR": {"EUR", "€", 2, false},
+	"GBP": {"GBP", "£", 2, false},
+	"HUF": {"HUF", " Ft", 0, true},
+	"INR": {"INR", "₹", 2, false},
+	"JPY": {"JPY", "¥", 0, false},
+	"KRW": {"KRW", "₩", 0, false},
+	"MXN": {"MXN", "MX$", 2, false},
+	"NOK": {"NOK", " kr", 2, true},
+	"NZD": {"NZD", "NZ$", 2, false},
+	"PLN": {"PLN", " zł", 2, true},
+	"SEK": {"SEK", " kr", 2, true},
+	"USD": {"USD", "$", 2, false},
+	"ZAR": {"ZAR", "R", 2, false},
+}
+
+// Get returns the currency with the given code, unknown codes are written
+// with two decimals and the code as symbol.
+func Get(code string) Currency {
+	code = strings.ToUpper(code)
+	if c, ok := currencies[code]; ok {
+		return c
+	}
+	if code == "" {
+		return Currency{Decimals: 2}
+	}
+	return Currency{code, " " + code, 2, true}
+}
+
+// Separators are the decimal symbol and thousands separator amounts are
+// written with, e.g. those of a harvest account.
+type Separators struct {
+	Decimal   string
+	Thousands string
+}
+
+// DefaultSeparators write 1,234.50.
+var DefaultSeparators = Separators{Decimal: ".", Thousands: ","}
+
+// Format writes amount with the default separators, see Separators.Format.
+func Format(amount float64, code string) string {
+	return DefaultSeparators.Format(amount, code)
+}
+
+// Format writes amount with the symbol and decimals of the currency code,
+// e.g. €1,234.50 or -1.234,50 kr. Empty separators are the default ones.
+func (s Separators) Format(amount float64, code string) string {
+	decimal, thousands := s.Decimal, s.Thousands
+	if decimal == "" {
+		decimal = DefaultSeparators.Decimal
+	}
+	if thousands == "" {
+		thousands = DefaultSeparators.Thousands
+	}
+
+	c := Get(code)
+	abs := strconv.FormatFloat(math.Abs(amount), 'f', c.Decimals, 64)
+	whole, frac, _ := strings.Cut(abs, ".")
+
+	var b strings.Builder
+	for i, r := range whole {
+		if i != 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(thousands)
+		}
+		b.WriteRune(r)
+	}
+	if frac != "" {
+		b.WriteString(decimal)
+		b.WriteString(frac)
+	}
+
+	sign := ""
+	if amount < 0 && math.Round(amount*math.Pow10(c.Decimals)) != 0 {
+		sign = "-"
+	}
+	if c.After {
+		return sign + b.String() + c.Symbol
+	}
+	return sign + c.Symbol + b.String()
+}
+
+// Provider returns how many units of to one unit of from is worth.
+type Provider interface {
+	Rate(ctx context.Context, from, to string) (float64, error)
+}
+
+var providers = map[string]Provider{}
+
+// Register makes a provider available under the given name.
+func Register(name string, p Provider) {
+	providers[strings.ToLower(name)] = p
+}
+
+// GetProvider returns the provider registered as name.
+func GetProvider(name string) (Provider, bool) {
+	p, ok := providers[strings.ToLower(name)]
+	return p, ok
+}
+
+// Providers returns the names of all registered providers.
+func Providers() []string {
+	names := make([]string, 0, len(providers))
+	for n := range providers {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Static are fixed rates: the amount of base one unit of each currency is
+// worth.
+type Static struct {
+	Base  string
+	Rates map[string]float64
+}
+
+func (s *Static) rate(code string) (float64, bool) {
+	if strings.EqualFold(code, s.Base) {
+		return 1, true
+	}
+	for c, r := range s.Rates {
+		if strings.EqualFold(c, code) && r > 0 {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
+func (s *Static) Rate(ctx context.Context, from, to string) (float64, error) {
+	f, ok := s.rate(from)
+	if !ok {
+		return 0, fmt.Errorf("No exchange rate for %s", from)
+	}
+	t, ok := s.rate(to)
+	if !ok {
+		return 0, fmt.Errorf("No exchange rate for %s", to)
+	}
+	return f / t, nil
+}
+
+// Converter converts amounts to a base currency, asking the provider
+// for every rate only once.
+type Converter struct {
+	Base     string
+	Provider Provider
+
+	mu    sync.Mutex
+	rates map[string]float64
+}
+
+func NewConverter(base string, p Provider) *Converter {
+	return &Converter{Base: strings.ToUpper(base), Provider: p, rates: make(map[string]float64)}
+}
+
+// Convert returns amount in from in the base currency. Amounts without a
+// currency are assumed to be in the base currency.
+func (c *Converter) Convert(ctx context.Context, amount float64, from string) (float64, error) {
+	from = strings.ToUpper(from)
+	if from == "" || from == c.Base || amount == 0 {
+		return amount, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rate, ok := c.rates[from]
+	if !ok {
+		var err error
+		if rate, err = c.Provider.Rate(ctx, from, c.Base); err != nil {
+			return 0, err
+		}
+		c.rates[from] = rate
+	}
+
+	return amount * rate, nil
+}
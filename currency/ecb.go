@@ -0,0 +1,63 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+func init() {
+	Register("ecb", &ECB{URL: "https://api.frankfurter.app/latest"})
+}
+
+// ECB fetches the daily reference rates of the European Central Bank from a
+// frankfurter api, which does not require a key. It returns the latest rate,
+// amounts of earlier days are converted at today's rate as well.
+type ECB struct {
+	URL    string
+	Client *http.Client
+}
+
+func (e *ECB) Rate(ctx context.Context, from, to string) (float64, error) {
+	v := make(url.Values)
+	v.Set("from", strings.ToUpper(from))
+	v.Set("to", strings.ToUpper(to))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", e.URL+"?"+v.Encode(), nil)
+	if err != nil {
+		return 0, err
+	}
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(res.Body, 1024))
+		return 0, fmt.Errorf("Could not fetch the %s rate (%d): %s", from, res.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var body struct {
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return 0, err
+	}
+
+	rate, ok := body.Rates[strings.ToUpper(to)]
+	if !ok || rate <= 0 {
+		return 0, fmt.Errorf("No exchange rate from %s to %s", from, to)
+	}
+
+	return rate, nil
+}
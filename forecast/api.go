@@ -1,6 +1,7 @@
 package forecast
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 
@@ -15,18 +16,47 @@ type Forecast struct {
 	api harvest.Api
 }
 
-func (f *Forecast) get(path string, query url.Values, v interface{}) error {
-	return f.api.Get(path, query, v)
+// SetTokenSource authenticates all requests using the tokens provided by ts
+// instead of the static token.
+func (f *Forecast) SetTokenSource(ts harvest.TokenSource) {
+	f.api.Tokens = ts
 }
 
-func New(accountID int, token string) *Forecast {
-	return &Forecast{
+// SetClient replaces the http client used to talk to the api.
+func (f *Forecast) SetClient(c *http.Client) {
+	f.api.Client = c
+}
+
+func (f *Forecast) get(ctx context.Context, path string, query url.Values, v interface{}) error {
+	return f.api.Get(ctx, path, query, v)
+}
+
+func (f *Forecast) post(ctx context.Context, path string, body interface{}, v interface{}) error {
+	return f.api.Post(ctx, path, nil, body, v)
+}
+
+func (f *Forecast) put(ctx context.Context, path string, body interface{}, v interface{}) error {
+	return f.api.Put(ctx, path, nil, body, v)
+}
+
+func (f *Forecast) delete(ctx context.Context, path string) error {
+	return f.api.Delete(ctx, path, nil)
+}
+
+// New creates a forecast client, opts are the harvest client options
+// (harvest.WithHTTPClient, harvest.WithBaseURL, harvest.WithUserAgent,
+// harvest.WithLogger).
+func New(accountID int, token string, opts ...harvest.Option) *Forecast {
+	f := &Forecast{
 		harvest.Api{
-			http.DefaultClient,
-			accountID,
-			token,
-			"https://api.forecastapp.com",
-			"Forecast-Account-ID",
+			Client:          harvest.NewRetryClient(harvest.DefaultMaxAttempts),
+			AccountID:       accountID,
+			Token:           token,
+			Endpoint:        endpoint,
+			AccountIDHeader: "Forecast-Account-ID",
 		},
 	}
+	f.api.Apply(opts...)
+
+	return f
 }
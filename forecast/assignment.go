@@ -1,6 +1,8 @@
 package forecast
 
 import (
+	"context"
+	"fmt"
 	"net/url"
 	"strconv"
 	"time"
@@ -24,10 +26,10 @@ func (a *AssignmentsParams) Values() url.Values {
 		v.Set("project_id", strconv.Itoa(*a.ProjectID))
 	}
 	if a.StartDate != nil {
-		v.Set("start_date", a.StartDate.Format(harvest.TimeFormatDateTime))
+		v.Set("start_date", a.StartDate.Format(harvest.TimeFormatDate))
 	}
 	if a.EndDate != nil {
-		v.Set("end_date", a.EndDate.Format(harvest.TimeFormatDateTime))
+		v.Set("end_date", a.EndDate.Format(harvest.TimeFormatDate))
 	}
 
 	return v
@@ -52,7 +54,50 @@ type AssignmentsResponse struct {
 	Assignments []*Assignment `json:"assignments"`
 }
 
-func (f *Forecast) GetAssignments(p *AssignmentsParams) (*AssignmentsResponse, error) {
+func (f *Forecast) GetAssignments(ctx context.Context, p *AssignmentsParams) (*AssignmentsResponse, error) {
 	v := &AssignmentsResponse{}
-	return v, f.get("/assignments", p.Values(), v)
+	return v, f.get(ctx, "/assignments", p.Values(), v)
+}
+
+type AssignmentResponse struct {
+	Assignment *Assignment `json:"assignment"`
+}
+
+// AssignmentBody creates or updates an assignment, Allocation is the time
+// per day, nil means the entire day.
+type AssignmentBody struct {
+	ProjectID       *int                     `json:"project_id,omitempty"`
+	PersonID        *int                     `json:"person_id,omitempty"`
+	PlaceholderID   *int                     `json:"placeholder_id,omitempty"`
+	StartDate       *harvest.Date            `json:"start_date,omitempty"`
+	EndDate         *harvest.Date            `json:"end_date,omitempty"`
+	Allocation      *harvest.DurationSeconds `json:"allocation,omitempty"`
+	Notes           *string                  `json:"notes,omitempty"`
+	ActiveOnDaysOff *bool                    `json:"active_on_days_off,omitempty"`
+}
+
+type assignmentRequest struct {
+	Assignment *AssignmentBody `json:"assignment"`
+}
+
+func (f *Forecast) GetAssignment(ctx context.Context, id int) (*Assignment, error) {
+	v := &AssignmentResponse{}
+	err := f.get(ctx, fmt.Sprintf("/assignments/%d", id), nil, v)
+	return v.Assignment, err
+}
+
+func (f *Forecast) CreateAssignment(ctx context.Context, p *AssignmentBody) (*Assignment, error) {
+	v := &AssignmentResponse{}
+	err := f.post(ctx, "/assignments", &assignmentRequest{p}, v)
+	return v.Assignment, err
+}
+
+func (f *Forecast) UpdateAssignment(ctx context.Context, id int, p *AssignmentBody) (*Assignment, error) {
+	v := &AssignmentResponse{}
+	err := f.put(ctx, fmt.Sprintf("/assignments/%d", id), &assignmentRequest{p}, v)
+	return v.Assignment, err
+}
+
+func (f *Forecast) DeleteAssignment(ctx context.Context, id int) error {
+	return f.delete(ctx, fmt.Sprintf("/assignments/%d", id))
 }
@@ -0,0 +1,36 @@
+package forecast
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
+
+type Client struct {
+	ID          int               `json:"id"`
+	Name        string            `json:"name"`
+	HarvestID   int               `json:"harvest_id"`
+	Archived    bool              `json:"archived"`
+	UpdatedAt   *harvest.DateTime `json:"updated_at"`
+	UpdatedByID int               `json:"updated_by_id"`
+}
+
+type ClientsResponse struct {
+	Clients []*Client `json:"clients"`
+}
+
+type ClientResponse struct {
+	Client *Client `json:"client"`
+}
+
+func (f *Forecast) GetClients(ctx context.Context) (*ClientsResponse, error) {
+	v := &ClientsResponse{}
+	return v, f.get(ctx, "/clients", nil, v)
+}
+
+func (f *Forecast) GetClient(ctx context.Context, id int) (*Client, error) {
+	v := &ClientResponse{}
+	err := f.get(ctx, fmt.Sprintf("/clients/%d", id), nil, v)
+	return v.Client, err
+}
@@ -0,0 +1,49 @@
+package forecast
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
+
+type MilestonesParams struct {
+	ProjectID *int
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+func (m *MilestonesParams) Values() url.Values {
+	v := make(url.Values)
+	if m.ProjectID != nil {
+		v.Set("project_id", strconv.Itoa(*m.ProjectID))
+	}
+	if m.StartDate != nil {
+		v.Set("start_date", m.StartDate.Format(harvest.TimeFormatDate))
+	}
+	if m.EndDate != nil {
+		v.Set("end_date", m.EndDate.Format(harvest.TimeFormatDate))
+	}
+
+	return v
+}
+
+type Milestone struct {
+	ID          int               `json:"id"`
+	Name        string            `json:"name"`
+	Date        *harvest.Date     `json:"date"`
+	ProjectID   int               `json:"project_id"`
+	UpdatedAt   *harvest.DateTime `json:"updated_at"`
+	UpdatedByID int               `json:"updated_by_id"`
+}
+
+type MilestonesResponse struct {
+	Milestones []*Milestone `json:"milestones"`
+}
+
+func (f *Forecast) GetMilestones(ctx context.Context, p *MilestonesParams) (*MilestonesResponse, error) {
+	v := &MilestonesResponse{}
+	return v, f.get(ctx, "/milestones", p.Values(), v)
+}
@@ -0,0 +1,26 @@
+package forecast
+
+import (
+	"context"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
+
+// Placeholder stands in for a person that is not hired or known yet.
+type Placeholder struct {
+	ID          int               `json:"id"`
+	Name        string            `json:"name"`
+	Archived    bool              `json:"archived"`
+	Roles       []string          `json:"roles"`
+	UpdatedAt   *harvest.DateTime `json:"updated_at"`
+	UpdatedByID int               `json:"updated_by_id"`
+}
+
+type PlaceholdersResponse struct {
+	Placeholders []*Placeholder `json:"placeholders"`
+}
+
+func (f *Forecast) GetPlaceholders(ctx context.Context) (*PlaceholdersResponse, error) {
+	v := &PlaceholdersResponse{}
+	return v, f.get(ctx, "/placeholders", nil, v)
+}
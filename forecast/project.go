@@ -1,6 +1,11 @@
 package forecast
 
-import "github.com/frizinak/harvest-timetracking/harvest"
+import (
+	"context"
+	"fmt"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
 
 type Project struct {
 	ID          int               `json:"id"`
@@ -11,6 +16,8 @@ type Project struct {
 	StartDate   *harvest.Date     `json:"start_date"`
 	EndDate     *harvest.Date     `json:"end_date"`
 	HarvestID   int               `json:"harvest_id"`
+	ClientID    int               `json:"client_id"`
+	Tags        []string          `json:"tags"`
 	Archived    bool              `json:"archived"`
 	UpdatedAt   *harvest.DateTime `json:"updated_at"`
 	UpdatedByID int               `json:"updated_by_id"`
@@ -20,7 +27,17 @@ type ProjectsResponse struct {
 	Projects []*Project `json:"projects"`
 }
 
-func (f *Forecast) GetProjects() (*ProjectsResponse, error) {
+func (f *Forecast) GetProjects(ctx context.Context) (*ProjectsResponse, error) {
 	v := &ProjectsResponse{}
-	return v, f.get("/projects", nil, v)
+	return v, f.get(ctx, "/projects", nil, v)
+}
+
+type ProjectResponse struct {
+	Project *Project `json:"project"`
+}
+
+func (f *Forecast) GetProject(ctx context.Context, id int) (*Project, error) {
+	v := &ProjectResponse{}
+	err := f.get(ctx, fmt.Sprintf("/projects/%d", id), nil, v)
+	return v.Project, err
 }
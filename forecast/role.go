@@ -0,0 +1,22 @@
+package forecast
+
+import (
+	"context"
+)
+
+type Role struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	HarvestRoleID  int    `json:"harvest_role_id"`
+	PersonIDs      []int  `json:"person_ids"`
+	PlaceholderIDs []int  `json:"placeholder_ids"`
+}
+
+type RolesResponse struct {
+	Roles []*Role `json:"roles"`
+}
+
+func (f *Forecast) GetRoles(ctx context.Context) (*RolesResponse, error) {
+	v := &RolesResponse{}
+	return v, f.get(ctx, "/roles", nil, v)
+}
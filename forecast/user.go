@@ -1,7 +1,12 @@
 package forecast
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
 )
 
 type MeResponse struct {
@@ -16,23 +21,72 @@ type UserResponse struct {
 	Person *User `json:"person"`
 }
 
+type PeopleResponse struct {
+	People []*User `json:"people"`
+}
+
 type User struct {
-	ID         int    `json:"id"`
-	HarvestID  int    `json:"harvest_user_id"`
-	Admin      bool   `json:"admin"`
-	Archived   bool   `json:"archived"`
-	AvatarURL  string `json:"avatar_url"`
-	ColorBlind bool   `json:"color_blind"`
-	Email      string `json:"email"`
-	FirstName  string `json:"first_name"`
+	ID             int                     `json:"id"`
+	HarvestID      int                     `json:"harvest_user_id"`
+	Admin          bool                    `json:"admin"`
+	Archived       bool                    `json:"archived"`
+	AvatarURL      string                  `json:"avatar_url"`
+	ColorBlind     bool                    `json:"color_blind"`
+	Email          string                  `json:"email"`
+	FirstName      string                  `json:"first_name"`
+	LastName       string                  `json:"last_name"`
+	Roles          []string                `json:"roles"`
+	WeeklyCapacity harvest.DurationSeconds `json:"weekly_capacity"`
+	WorkingDays    WorkingDays             `json:"working_days"`
+}
+
+// Name returns the first and last name.
+func (u *User) Name() string {
+	return strings.TrimSpace(u.FirstName + " " + u.LastName)
+}
+
+// WorkingDays are the weekdays a person works on.
+type WorkingDays struct {
+	Monday    bool `json:"monday"`
+	Tuesday   bool `json:"tuesday"`
+	Wednesday bool `json:"wednesday"`
+	Thursday  bool `json:"thursday"`
+	Friday    bool `json:"friday"`
+	Saturday  bool `json:"saturday"`
+	Sunday    bool `json:"sunday"`
 }
 
-func (f *Forecast) GetMe() (*Me, error) {
+// Works reports whether d is a working day.
+func (w WorkingDays) Works(d time.Weekday) bool {
+	return [...]bool{w.Sunday, w.Monday, w.Tuesday, w.Wednesday, w.Thursday, w.Friday, w.Saturday}[d]
+}
+
+// Count returns the amount of working days in a week.
+func (w WorkingDays) Count() int {
+	n := 0
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if w.Works(d) {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (f *Forecast) GetMe(ctx context.Context) (*Me, error) {
 	v := &MeResponse{}
-	return v.Me, f.get("/whoami", nil, v)
+	err := f.get(ctx, "/whoami", nil, v)
+	return v.Me, err
+}
+
+// GetPeople returns every person of the account, including archived ones.
+func (f *Forecast) GetPeople(ctx context.Context) (*PeopleResponse, error) {
+	v := &PeopleResponse{}
+	return v, f.get(ctx, "/people", nil, v)
 }
 
-func (f *Forecast) GetUser(id int) (*User, error) {
+func (f *Forecast) GetUser(ctx context.Context, id int) (*User, error) {
 	v := &UserResponse{}
-	return v.Person, f.get(fmt.Sprintf("/people/%d", id), nil, v)
+	err := f.get(ctx, fmt.Sprintf("/people/%d", id), nil, v)
+	return v.Person, err
 }
@@ -2,12 +2,15 @@ package harvest
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
-	"io/ioutil"
+	"io"
+	"log/slog"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 const (
@@ -18,24 +21,46 @@ type Harvest struct {
 	api Api
 }
 
-func New(accountID int, token string) *Harvest {
-	return &Harvest{
+func New(accountID int, token string, opts ...Option) *Harvest {
+	h := &Harvest{
 		Api{
-			http.DefaultClient,
-			accountID,
-			token,
-			"https://api.harvestapp.com/v2",
-			"Harvest-Account-ID",
+			Client:          NewRetryClient(DefaultMaxAttempts),
+			AccountID:       accountID,
+			Token:           token,
+			Endpoint:        endpoint,
+			AccountIDHeader: "Harvest-Account-ID",
 		},
 	}
+	h.api.Apply(opts...)
+
+	return h
+}
+
+// SetTokenSource authenticates all requests using the tokens provided by ts
+// instead of the static token.
+func (h *Harvest) SetTokenSource(ts TokenSource) {
+	h.api.Tokens = ts
+}
+
+// SetClient replaces the http client used to talk to the api.
+func (h *Harvest) SetClient(c *http.Client) {
+	h.api.Client = c
+}
+
+func (h *Harvest) get(ctx context.Context, path string, query url.Values, v interface{}) error {
+	return h.api.Get(ctx, path, query, v)
+}
+
+func (h *Harvest) post(ctx context.Context, path string, query url.Values, body interface{}, v interface{}) error {
+	return h.api.Post(ctx, path, query, body, v)
 }
 
-func (h *Harvest) get(path string, query url.Values, v interface{}) error {
-	return h.api.Get(path, query, v)
+func (h *Harvest) patch(ctx context.Context, path string, query url.Values, body interface{}, v interface{}) error {
+	return h.api.Patch(ctx, path, query, body, v)
 }
 
-func (h *Harvest) post(path string, query url.Values, body interface{}, v interface{}) error {
-	return h.api.Post(path, query, body, v)
+func (h *Harvest) delete(ctx context.Context, path string, query url.Values) error {
+	return h.api.Delete(ctx, path, query)
 }
 
 type Api struct {
@@ -44,57 +69,129 @@ type Api struct {
 	Token           string
 	Endpoint        string
 	AccountIDHeader string
+	UserAgent       string
+	Tokens          TokenSource
+	Logger          *slog.Logger
+	RequestBudget   *RequestBudget
+	Progress        Progress
 }
 
-func (a *Api) Get(path string, query url.Values, v interface{}) error {
-	req, err := a.prepareRequest(path, query)
+func (a *Api) Get(ctx context.Context, path string, query url.Values, v interface{}) error {
+	req, err := a.prepareRequest(ctx, "GET", path, query, nil)
 	if err != nil {
 		return err
 	}
 
-	res, err := a.Client.Do(req)
+	return a.do(req, v)
+}
+
+func (a *Api) Post(ctx context.Context, path string, query url.Values, body interface{}, v interface{}) error {
+	return a.send(ctx, "POST", path, query, body, v)
+}
+
+func (a *Api) Patch(ctx context.Context, path string, query url.Values, body interface{}, v interface{}) error {
+	return a.send(ctx, "PATCH", path, query, body, v)
+}
+
+func (a *Api) Put(ctx context.Context, path string, query url.Values, body interface{}, v interface{}) error {
+	return a.send(ctx, "PUT", path, query, body, v)
+}
+
+func (a *Api) Delete(ctx context.Context, path string, query url.Values) error {
+	return a.send(ctx, "DELETE", path, query, nil, nil)
+}
+
+func (a *Api) send(ctx context.Context, method, path string, query url.Values, body interface{}, v interface{}) error {
+	var rw bytes.Buffer
+	if body != nil {
+		e := json.NewEncoder(&rw)
+		if err := e.Encode(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := a.prepareRequest(ctx, method, path, query, &rw)
 	if err != nil {
 		return err
 	}
-	defer res.Body.Close()
-	if res.StatusCode < 200 || res.StatusCode >= 400 {
-		all, _ := ioutil.ReadAll(res.Body)
-		return errors.New("Unexpected api error: " + string(all))
-	}
+	req.Header.Set("Content-Type", "application/json")
 
-	return json.NewDecoder(res.Body).Decode(v)
+	return a.do(req, v)
 }
 
-func (a *Api) Post(path string, query url.Values, body interface{}, v interface{}) error {
-	req, err := a.prepareRequest(path, query)
-	if err != nil {
+// PostMultipart posts fields and an optional file as multipart/form-data.
+func (a *Api) PostMultipart(
+	ctx context.Context,
+	path string,
+	fields url.Values,
+	fileField string,
+	fileName string,
+	file io.Reader,
+	v interface{},
+) error {
+	var rw bytes.Buffer
+	w := multipart.NewWriter(&rw)
+	for k := range fields {
+		if err := w.WriteField(k, fields.Get(k)); err != nil {
+			return err
+		}
+	}
+
+	if file != nil {
+		fw, err := w.CreateFormFile(fileField, fileName)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(fw, file); err != nil {
+			return err
+		}
+	}
+
+	if err := w.Close(); err != nil {
 		return err
 	}
 
-	var rw bytes.Buffer
-	e := json.NewEncoder(&rw)
-	if err := e.Encode(body); err != nil {
+	req, err := a.prepareRequest(ctx, "POST", path, nil, &rw)
+	if err != nil {
 		return err
 	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
 
-	req.Method = "POST"
-	req.Header.Set("Content-Type", "application/json")
-	req.Body = ioutil.NopCloser(&rw)
+	return a.do(req, v)
+}
+
+func (a *Api) do(req *http.Request, v interface{}) error {
+	if err := a.RequestBudget.take(); err != nil {
+		return err
+	}
 
+	start := time.Now()
 	res, err := a.Client.Do(req)
 	if err != nil {
 		return err
 	}
 	defer res.Body.Close()
+	if a.Logger != nil {
+		a.Logger.Debug(
+			"Request",
+			"method", req.Method,
+			"url", req.URL.Redacted(),
+			"status", res.StatusCode,
+			"duration", time.Since(start),
+		)
+	}
 	if res.StatusCode < 200 || res.StatusCode >= 400 {
-		all, _ := ioutil.ReadAll(res.Body)
-		return errors.New("Unexpected api error: " + string(all))
+		return newError(res)
+	}
+
+	if v == nil {
+		return nil
 	}
 
 	return json.NewDecoder(res.Body).Decode(v)
 }
 
-func (a *Api) prepareRequest(path string, query url.Values) (*http.Request, error) {
+func (a *Api) prepareRequest(ctx context.Context, method, path string, query url.Values, body io.Reader) (*http.Request, error) {
 	u, err := url.Parse(a.Endpoint + path)
 	if err != nil {
 		return nil, err
@@ -105,12 +202,22 @@ func (a *Api) prepareRequest(path string, query url.Values) (*http.Request, erro
 	}
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
 	if err != nil {
 		return nil, err
 	}
 
+	token := a.Token
+	if a.Tokens != nil {
+		if token, err = a.Tokens.Token(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	req.Header.Set(a.AccountIDHeader, strconv.Itoa(a.AccountID))
-	req.Header.Set("Authorization", "Bearer "+a.Token)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if a.UserAgent != "" {
+		req.Header.Set("User-Agent", a.UserAgent)
+	}
 	return req, nil
 }
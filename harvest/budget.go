@@ -0,0 +1,81 @@
+package harvest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrBudgetExceeded is returned instead of sending a request that would
+// exceed the RequestBudget.
+var ErrBudgetExceeded = errors.New("Request budget exceeded")
+
+// RequestBudget limits the amount of requests of all clients it is given to
+// (see WithRequestBudget), e.g. to abort queries that would need thousands
+// of pages.
+type RequestBudget struct {
+	Max int
+
+	mu   sync.Mutex
+	used int
+}
+
+// take claims a single request.
+func (b *RequestBudget) take() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.Max > 0 && b.used >= b.Max {
+		return fmt.Errorf("%w: more than %d requests", ErrBudgetExceeded, b.Max)
+	}
+	b.used++
+	return nil
+}
+
+// allows fails when n more requests would exceed the budget,
+// before any of them is sent.
+func (b *RequestBudget) allows(n int) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.Max > 0 && b.used+n > b.Max {
+		return fmt.Errorf(
+			"%w: %d more pages needed, %d of %d requests left",
+			ErrBudgetExceeded,
+			n,
+			b.Max-b.used,
+			b.Max,
+		)
+	}
+	return nil
+}
+
+// Progress is called after every page of a request that spans multiple
+// pages with the amount of pages fetched and the total amount of pages.
+// Pages can be fetched concurrently, Progress must be safe to call from
+// multiple goroutines.
+type Progress func(done, total int)
+
+// pageProgress counts the pages of a single paginated request.
+type pageProgress struct {
+	fn   Progress
+	done int64
+}
+
+func (a *Api) pageProgress() *pageProgress {
+	return &pageProgress{fn: a.Progress}
+}
+
+func (p *pageProgress) page(total int) {
+	if p.fn == nil || total <= 1 {
+		return
+	}
+	p.fn(int(atomic.AddInt64(&p.done, 1)), total)
+}
@@ -0,0 +1,92 @@
+package harvest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type ClientsParams struct {
+	Active       *bool
+	UpdatedSince *time.Time
+	Page         *int
+	PerPage      *int
+}
+
+func (c *ClientsParams) Values() url.Values {
+	v := make(url.Values)
+	v.Set("page", "1")
+
+	if c.Active != nil {
+		v.Set("is_active", boolToString(*c.Active))
+	}
+	if c.UpdatedSince != nil {
+		v.Set("updated_since", c.UpdatedSince.Format(TimeFormatDateTime))
+	}
+	if c.Page != nil {
+		v.Set("page", strconv.Itoa(*c.Page))
+	}
+	if c.PerPage != nil {
+		v.Set("per_page", strconv.Itoa(*c.PerPage))
+	}
+
+	return v
+}
+
+type ClientsResponse struct {
+	NextPage     *int    `json:"next_page"`
+	TotalEntries int     `json:"total_entries"`
+	Page         int     `json:"page"`
+	Clients      Clients `json:"clients"`
+}
+
+type Client struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Active    bool      `json:"is_active"`
+	Address   string    `json:"address"`
+	Statement string    `json:"statement_key"`
+	Currency  string    `json:"currency"`
+	CreatedAt *DateTime `json:"created_at"`
+	UpdatedAt *DateTime `json:"updated_at"`
+}
+
+type Clients []*Client
+
+// ByID indexes the clients by their id.
+func (c Clients) ByID() map[int]*Client {
+	m := make(map[int]*Client, len(c))
+	for _, client := range c {
+		m[client.ID] = client
+	}
+
+	return m
+}
+
+func (h *Harvest) GetClients(ctx context.Context, p *ClientsParams) (*ClientsResponse, error) {
+	v := &ClientsResponse{}
+	return v, h.get(ctx, "/clients", p.Values(), v)
+}
+
+func (h *Harvest) GetClient(ctx context.Context, id int) (*Client, error) {
+	v := &Client{}
+	return v, h.get(ctx, fmt.Sprintf("/clients/%d", id), nil, v)
+}
+
+func (h *Harvest) Clients(ctx context.Context, p *ClientsParams) *Pager[*Client] {
+	params := *p
+	return NewPager(
+		ctx,
+		params.Page,
+		func(ctx context.Context, page *int) ([]*Client, *int, error) {
+			params.Page = page
+			res, err := h.GetClients(ctx, &params)
+			if err != nil {
+				return nil, nil, err
+			}
+			return res.Clients, res.NextPage, nil
+		},
+	)
+}
@@ -1,5 +1,7 @@
 package harvest
 
+import "context"
+
 type Company struct {
 	BaseURL              *URL   `json:"base_uri"`
 	FullDomain           string `json:"full_domain"`
@@ -19,7 +21,7 @@ type Company struct {
 	ApprovalFeature      bool   `json:"approval_feature"`
 }
 
-func (h *Harvest) GetCompany() (*Company, error) {
+func (h *Harvest) GetCompany(ctx context.Context) (*Company, error) {
 	v := &Company{}
-	return v, h.get("/company", nil, v)
+	return v, h.get(ctx, "/company", nil, v)
 }
@@ -0,0 +1,101 @@
+package harvest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrUnauthorized = errors.New("Unauthorized")
+	ErrForbidden    = errors.New("Forbidden")
+	ErrNotFound     = errors.New("Not found")
+
+	// ErrTokenExpired is returned by a RefreshingTokenSource that
+	// can no longer refresh its token.
+	ErrTokenExpired = errors.New("Token expired, please login again")
+)
+
+// APIError is returned for every response with an unsuccessful status code.
+// Use errors.Is with ErrUnauthorized, ErrForbidden or ErrNotFound
+// to check for the common cases.
+type APIError struct {
+	StatusCode int
+	Message    string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("Unexpected api error (%d): %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("Unexpected api error (%d): %s", e.StatusCode, e.Body)
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+// RateLimitError is returned when requests are still being rate limited
+// after retrying. RetryAfter is how long the api asked to wait, 0 if unknown.
+type RateLimitError struct {
+	APIError
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("Rate limited, retry after %s", e.RetryAfter)
+	}
+	return "Rate limited"
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return &e.APIError
+}
+
+// newError reads the body of an unsuccessful response into an APIError
+// or RateLimitError.
+func newError(res *http.Response) error {
+	all, _ := ioutil.ReadAll(res.Body)
+	e := APIError{StatusCode: res.StatusCode, Body: string(all)}
+
+	var body struct {
+		Message     string `json:"message"`
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+	}
+	if json.Unmarshal(all, &body) == nil {
+		switch {
+		case body.Message != "":
+			e.Message = body.Message
+		case body.Description != "":
+			e.Message = body.Description
+		default:
+			e.Message = body.Error
+		}
+	}
+
+	if res.StatusCode != http.StatusTooManyRequests {
+		return &e
+	}
+
+	rl := &RateLimitError{APIError: e}
+	if s, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && s > 0 {
+		rl.RetryAfter = time.Duration(s) * time.Second
+	}
+
+	return rl
+}
@@ -0,0 +1,224 @@
+package harvest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	EstimateStateDraft    = "draft"
+	EstimateStateSent     = "sent"
+	EstimateStateAccepted = "accepted"
+	EstimateStateDeclined = "declined"
+)
+
+type EstimatesParams struct {
+	ClientID     *int
+	UpdatedSince *time.Time
+	From         *time.Time
+	To           *time.Time
+	State        *string
+	Page         *int
+	PerPage      *int
+}
+
+func (e *EstimatesParams) Values() url.Values {
+	v := make(url.Values)
+	v.Set("page", "1")
+
+	if e.ClientID != nil {
+		v.Set("client_id", strconv.Itoa(*e.ClientID))
+	}
+	if e.UpdatedSince != nil {
+		v.Set("updated_since", e.UpdatedSince.Format(TimeFormatDateTime))
+	}
+	if e.From != nil {
+		v.Set("from", e.From.Format(TimeFormatDate))
+	}
+	if e.To != nil {
+		v.Set("to", e.To.Format(TimeFormatDate))
+	}
+	if e.State != nil {
+		v.Set("state", *e.State)
+	}
+	if e.Page != nil {
+		v.Set("page", strconv.Itoa(*e.Page))
+	}
+	if e.PerPage != nil {
+		v.Set("per_page", strconv.Itoa(*e.PerPage))
+	}
+
+	return v
+}
+
+type EstimatesResponse struct {
+	NextPage     *int        `json:"next_page"`
+	TotalEntries int         `json:"total_entries"`
+	Page         int         `json:"page"`
+	Estimates    []*Estimate `json:"estimates"`
+}
+
+type Estimate struct {
+	ID             int                 `json:"id"`
+	Client         ClientRef           `json:"client"`
+	LineItems      []*EstimateLineItem `json:"line_items"`
+	Creator        UserRef             `json:"creator"`
+	ClientKey      string              `json:"client_key"`
+	Number         string              `json:"number"`
+	PurchaseOrder  string              `json:"purchase_order"`
+	Amount         float64             `json:"amount"`
+	Tax            *float64            `json:"tax"`
+	TaxAmount      float64             `json:"tax_amount"`
+	Tax2           *float64            `json:"tax2"`
+	Tax2Amount     float64             `json:"tax2_amount"`
+	Discount       *float64            `json:"discount"`
+	DiscountAmount float64             `json:"discount_amount"`
+	Subject        string              `json:"subject"`
+	Notes          string              `json:"notes"`
+	Currency       string              `json:"currency"`
+	State          string              `json:"state"`
+	IssueDate      *Date               `json:"issue_date"`
+	SentAt         *DateTime           `json:"sent_at"`
+	AcceptedAt     *DateTime           `json:"accepted_at"`
+	DeclinedAt     *DateTime           `json:"declined_at"`
+	CreatedAt      *DateTime           `json:"created_at"`
+	UpdatedAt      *DateTime           `json:"updated_at"`
+}
+
+type EstimateLineItem struct {
+	ID          int     `json:"id,omitempty"`
+	Kind        string  `json:"kind"`
+	Description string  `json:"description"`
+	Quantity    float64 `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Amount      float64 `json:"amount,omitempty"`
+	Taxed       bool    `json:"taxed"`
+	Taxed2      bool    `json:"taxed2"`
+}
+
+// Quantity returns the summed quantity of all line items of the given kind
+// (an estimate item category name), e.g. the estimated hours of "Service".
+func (e *Estimate) Quantity(kind string) float64 {
+	var n float64
+	for _, item := range e.LineItems {
+		if item.Kind == kind {
+			n += item.Quantity
+		}
+	}
+
+	return n
+}
+
+type CreateEstimateBody struct {
+	ClientID      int                 `json:"client_id"`
+	Number        *string             `json:"number,omitempty"`
+	PurchaseOrder *string             `json:"purchase_order,omitempty"`
+	Tax           *float64            `json:"tax,omitempty"`
+	Tax2          *float64            `json:"tax2,omitempty"`
+	Discount      *float64            `json:"discount,omitempty"`
+	Subject       *string             `json:"subject,omitempty"`
+	Notes         *string             `json:"notes,omitempty"`
+	Currency      *string             `json:"currency,omitempty"`
+	IssueDate     *Date               `json:"issue_date,omitempty"`
+	LineItems     []*EstimateLineItem `json:"line_items,omitempty"`
+}
+
+func (h *Harvest) GetEstimates(ctx context.Context, p *EstimatesParams) (*EstimatesResponse, error) {
+	v := &EstimatesResponse{}
+	return v, h.get(ctx, "/estimates", p.Values(), v)
+}
+
+func (h *Harvest) GetEstimate(ctx context.Context, id int) (*Estimate, error) {
+	v := &Estimate{}
+	return v, h.get(ctx, fmt.Sprintf("/estimates/%d", id), nil, v)
+}
+
+func (h *Harvest) Estimates(ctx context.Context, p *EstimatesParams) *Pager[*Estimate] {
+	params := *p
+	return NewPager(
+		ctx,
+		params.Page,
+		func(ctx context.Context, page *int) ([]*Estimate, *int, error) {
+			params.Page = page
+			res, err := h.GetEstimates(ctx, &params)
+			if err != nil {
+				return nil, nil, err
+			}
+			return res.Estimates, res.NextPage, nil
+		},
+	)
+}
+
+func (h *Harvest) CreateEstimate(ctx context.Context, body *CreateEstimateBody) (*Estimate, error) {
+	v := &Estimate{}
+	return v, h.post(ctx, "/estimates", nil, body, v)
+}
+
+type EstimateItemCategoriesParams struct {
+	UpdatedSince *time.Time
+	Page         *int
+	PerPage      *int
+}
+
+func (e *EstimateItemCategoriesParams) Values() url.Values {
+	v := make(url.Values)
+	v.Set("page", "1")
+
+	if e.UpdatedSince != nil {
+		v.Set("updated_since", e.UpdatedSince.Format(TimeFormatDateTime))
+	}
+	if e.Page != nil {
+		v.Set("page", strconv.Itoa(*e.Page))
+	}
+	if e.PerPage != nil {
+		v.Set("per_page", strconv.Itoa(*e.PerPage))
+	}
+
+	return v
+}
+
+type EstimateItemCategoriesResponse struct {
+	NextPage               *int                    `json:"next_page"`
+	TotalEntries           int                     `json:"total_entries"`
+	Page                   int                     `json:"page"`
+	EstimateItemCategories []*EstimateItemCategory `json:"estimate_item_categories"`
+}
+
+type EstimateItemCategory struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt *DateTime `json:"created_at"`
+	UpdatedAt *DateTime `json:"updated_at"`
+}
+
+func (h *Harvest) GetEstimateItemCategories(ctx context.Context, p *EstimateItemCategoriesParams) (*EstimateItemCategoriesResponse, error) {
+	v := &EstimateItemCategoriesResponse{}
+	return v, h.get(ctx, "/estimate_item_categories", p.Values(), v)
+}
+
+func (h *Harvest) EstimateItemCategories(ctx context.Context, p *EstimateItemCategoriesParams) *Pager[*EstimateItemCategory] {
+	params := *p
+	return NewPager(
+		ctx,
+		params.Page,
+		func(ctx context.Context, page *int) ([]*EstimateItemCategory, *int, error) {
+			params.Page = page
+			res, err := h.GetEstimateItemCategories(ctx, &params)
+			if err != nil {
+				return nil, nil, err
+			}
+			return res.EstimateItemCategories, res.NextPage, nil
+		},
+	)
+}
+
+func (h *Harvest) CreateEstimateItemCategory(ctx context.Context, name string) (*EstimateItemCategory, error) {
+	v := &EstimateItemCategory{}
+	body := struct {
+		Name string `json:"name"`
+	}{name}
+	return v, h.post(ctx, "/estimate_item_categories", nil, body, v)
+}
@@ -0,0 +1,250 @@
+package harvest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type ExpensesParams struct {
+	UserID       *int
+	ClientID     *int
+	ProjectID    *int
+	Billed       *bool
+	UpdatedSince *time.Time
+	From         *time.Time
+	To           *time.Time
+	Page         *int
+	PerPage      *int
+}
+
+func (e *ExpensesParams) Values() url.Values {
+	v := make(url.Values)
+	v.Set("page", "1")
+
+	if e.UserID != nil {
+		v.Set("user_id", strconv.Itoa(*e.UserID))
+	}
+	if e.ClientID != nil {
+		v.Set("client_id", strconv.Itoa(*e.ClientID))
+	}
+	if e.ProjectID != nil {
+		v.Set("project_id", strconv.Itoa(*e.ProjectID))
+	}
+	if e.Billed != nil {
+		v.Set("is_billed", boolToString(*e.Billed))
+	}
+	if e.UpdatedSince != nil {
+		v.Set("updated_since", e.UpdatedSince.Format(TimeFormatDateTime))
+	}
+	if e.From != nil {
+		v.Set("from", e.From.Format(TimeFormatDate))
+	}
+	if e.To != nil {
+		v.Set("to", e.To.Format(TimeFormatDate))
+	}
+	if e.Page != nil {
+		v.Set("page", strconv.Itoa(*e.Page))
+	}
+	if e.PerPage != nil {
+		v.Set("per_page", strconv.Itoa(*e.PerPage))
+	}
+
+	return v
+}
+
+type ExpensesResponse struct {
+	NextPage     *int       `json:"next_page"`
+	TotalEntries int        `json:"total_entries"`
+	Page         int        `json:"page"`
+	Expenses     []*Expense `json:"expenses"`
+}
+
+type ExpenseCategoryRef struct {
+	ID        int     `json:"id"`
+	Name      string  `json:"name"`
+	UnitName  string  `json:"unit_name"`
+	UnitPrice float64 `json:"unit_price"`
+}
+
+type Receipt struct {
+	URL         URL    `json:"url"`
+	FileName    string `json:"file_name"`
+	FileSize    int    `json:"file_size"`
+	ContentType string `json:"content_type"`
+}
+
+type Expense struct {
+	ID              int                `json:"id"`
+	Client          ClientRef          `json:"client"`
+	Project         ProjectRef         `json:"project"`
+	ExpenseCategory ExpenseCategoryRef `json:"expense_category"`
+	User            UserRef            `json:"user"`
+	UserAssignment  UserAssignmentRef  `json:"user_assignment"`
+	Receipt         *Receipt           `json:"receipt"`
+	Invoice         *InvoiceRef        `json:"invoice"`
+	Notes           string             `json:"notes"`
+	Units           float64            `json:"units"`
+	TotalCost       float64            `json:"total_cost"`
+	Billable        bool               `json:"billable"`
+	Closed          bool               `json:"is_closed"`
+	Locked          bool               `json:"is_locked"`
+	Billed          bool               `json:"is_billed"`
+	LockedReason    string             `json:"locked_reason"`
+	SpentDate       *Date              `json:"spent_date"`
+	CreatedAt       *DateTime          `json:"created_at"`
+	UpdatedAt       *DateTime          `json:"updated_at"`
+}
+
+type CreateExpenseBody struct {
+	UserID            *int
+	ProjectID         int
+	ExpenseCategoryID int
+	SpentDate         time.Time
+	Units             *float64
+	TotalCost         *float64
+	Notes             *string
+	Billable          *bool
+
+	// Receipt is uploaded as the receipt of the expense if not nil.
+	Receipt     io.Reader
+	ReceiptName string
+}
+
+func (c *CreateExpenseBody) Values() url.Values {
+	v := make(url.Values)
+	v.Set("project_id", strconv.Itoa(c.ProjectID))
+	v.Set("expense_category_id", strconv.Itoa(c.ExpenseCategoryID))
+	v.Set("spent_date", c.SpentDate.Format(TimeFormatDate))
+
+	if c.UserID != nil {
+		v.Set("user_id", strconv.Itoa(*c.UserID))
+	}
+	if c.Units != nil {
+		v.Set("units", strconv.FormatFloat(*c.Units, 'f', -1, 64))
+	}
+	if c.TotalCost != nil {
+		v.Set("total_cost", strconv.FormatFloat(*c.TotalCost, 'f', -1, 64))
+	}
+	if c.Notes != nil {
+		v.Set("notes", *c.Notes)
+	}
+	if c.Billable != nil {
+		v.Set("billable", boolToString(*c.Billable))
+	}
+
+	return v
+}
+
+func (h *Harvest) GetExpenses(ctx context.Context, p *ExpensesParams) (*ExpensesResponse, error) {
+	v := &ExpensesResponse{}
+	return v, h.get(ctx, "/expenses", p.Values(), v)
+}
+
+func (h *Harvest) GetExpense(ctx context.Context, id int) (*Expense, error) {
+	v := &Expense{}
+	return v, h.get(ctx, fmt.Sprintf("/expenses/%d", id), nil, v)
+}
+
+func (h *Harvest) Expenses(ctx context.Context, p *ExpensesParams) *Pager[*Expense] {
+	params := *p
+	return NewPager(
+		ctx,
+		params.Page,
+		func(ctx context.Context, page *int) ([]*Expense, *int, error) {
+			params.Page = page
+			res, err := h.GetExpenses(ctx, &params)
+			if err != nil {
+				return nil, nil, err
+			}
+			return res.Expenses, res.NextPage, nil
+		},
+	)
+}
+
+// CreateExpense creates an expense, as multipart/form-data so a receipt
+// can be uploaded along with it.
+func (h *Harvest) CreateExpense(ctx context.Context, body *CreateExpenseBody) (*Expense, error) {
+	v := &Expense{}
+	return v, h.api.PostMultipart(
+		ctx,
+		"/expenses",
+		body.Values(),
+		"receipt",
+		body.ReceiptName,
+		body.Receipt,
+		v,
+	)
+}
+
+func (h *Harvest) DeleteExpense(ctx context.Context, id int) error {
+	return h.delete(ctx, fmt.Sprintf("/expenses/%d", id), nil)
+}
+
+type ExpenseCategoriesParams struct {
+	Active       *bool
+	UpdatedSince *time.Time
+	Page         *int
+	PerPage      *int
+}
+
+func (e *ExpenseCategoriesParams) Values() url.Values {
+	v := make(url.Values)
+	v.Set("page", "1")
+
+	if e.Active != nil {
+		v.Set("is_active", boolToString(*e.Active))
+	}
+	if e.UpdatedSince != nil {
+		v.Set("updated_since", e.UpdatedSince.Format(TimeFormatDateTime))
+	}
+	if e.Page != nil {
+		v.Set("page", strconv.Itoa(*e.Page))
+	}
+	if e.PerPage != nil {
+		v.Set("per_page", strconv.Itoa(*e.PerPage))
+	}
+
+	return v
+}
+
+type ExpenseCategoriesResponse struct {
+	NextPage          *int               `json:"next_page"`
+	TotalEntries      int                `json:"total_entries"`
+	Page              int                `json:"page"`
+	ExpenseCategories []*ExpenseCategory `json:"expense_categories"`
+}
+
+type ExpenseCategory struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	UnitName  *string   `json:"unit_name"`
+	UnitPrice *float64  `json:"unit_price"`
+	Active    bool      `json:"is_active"`
+	CreatedAt *DateTime `json:"created_at"`
+	UpdatedAt *DateTime `json:"updated_at"`
+}
+
+func (h *Harvest) GetExpenseCategories(ctx context.Context, p *ExpenseCategoriesParams) (*ExpenseCategoriesResponse, error) {
+	v := &ExpenseCategoriesResponse{}
+	return v, h.get(ctx, "/expense_categories", p.Values(), v)
+}
+
+func (h *Harvest) ExpenseCategories(ctx context.Context, p *ExpenseCategoriesParams) *Pager[*ExpenseCategory] {
+	params := *p
+	return NewPager(
+		ctx,
+		params.Page,
+		func(ctx context.Context, page *int) ([]*ExpenseCategory, *int, error) {
+			params.Page = page
+			res, err := h.GetExpenseCategories(ctx, &params)
+			if err != nil {
+				return nil, nil, err
+			}
+			return res.ExpenseCategories, res.NextPage, nil
+		},
+	)
+}
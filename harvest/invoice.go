@@ -0,0 +1,285 @@
+package harvest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	InvoiceStateDraft  = "draft"
+	InvoiceStateOpen   = "open"
+	InvoiceStatePaid   = "paid"
+	InvoiceStateClosed = "closed"
+
+	InvoiceSummaryProject  = "project"
+	InvoiceSummaryTask     = "task"
+	InvoiceSummaryPeople   = "people"
+	InvoiceSummaryDetailed = "detailed"
+)
+
+type InvoicesParams struct {
+	ClientID     *int
+	ProjectID    *int
+	UpdatedSince *time.Time
+	From         *time.Time
+	To           *time.Time
+	State        *string
+	Page         *int
+	PerPage      *int
+}
+
+func (i *InvoicesParams) Values() url.Values {
+	v := make(url.Values)
+	v.Set("page", "1")
+
+	if i.ClientID != nil {
+		v.Set("client_id", strconv.Itoa(*i.ClientID))
+	}
+	if i.ProjectID != nil {
+		v.Set("project_id", strconv.Itoa(*i.ProjectID))
+	}
+	if i.UpdatedSince != nil {
+		v.Set("updated_since", i.UpdatedSince.Format(TimeFormatDateTime))
+	}
+	if i.From != nil {
+		v.Set("from", i.From.Format(TimeFormatDate))
+	}
+	if i.To != nil {
+		v.Set("to", i.To.Format(TimeFormatDate))
+	}
+	if i.State != nil {
+		v.Set("state", *i.State)
+	}
+	if i.Page != nil {
+		v.Set("page", strconv.Itoa(*i.Page))
+	}
+	if i.PerPage != nil {
+		v.Set("per_page", strconv.Itoa(*i.PerPage))
+	}
+
+	return v
+}
+
+type InvoicesResponse struct {
+	NextPage     *int       `json:"next_page"`
+	TotalEntries int        `json:"total_entries"`
+	Page         int        `json:"page"`
+	Invoices     []*Invoice `json:"invoices"`
+}
+
+type Invoice struct {
+	ID             int                `json:"id"`
+	Client         ClientRef          `json:"client"`
+	LineItems      []*InvoiceLineItem `json:"line_items"`
+	ClientKey      string             `json:"client_key"`
+	Number         string             `json:"number"`
+	PurchaseOrder  string             `json:"purchase_order"`
+	Amount         float64            `json:"amount"`
+	DueAmount      float64            `json:"due_amount"`
+	Tax            *float64           `json:"tax"`
+	TaxAmount      float64            `json:"tax_amount"`
+	Tax2           *float64           `json:"tax2"`
+	Tax2Amount     float64            `json:"tax2_amount"`
+	Discount       *float64           `json:"discount"`
+	DiscountAmount float64            `json:"discount_amount"`
+	Subject        string             `json:"subject"`
+	Notes          string             `json:"notes"`
+	Currency       string             `json:"currency"`
+	State          string             `json:"state"`
+	PeriodStart    *Date              `json:"period_start"`
+	PeriodEnd      *Date              `json:"period_end"`
+	IssueDate      *Date              `json:"issue_date"`
+	DueDate        *Date              `json:"due_date"`
+	PaymentTerm    string             `json:"payment_term"`
+	SentAt         *DateTime          `json:"sent_at"`
+	PaidAt         *DateTime          `json:"paid_at"`
+	PaidDate       *Date              `json:"paid_date"`
+	ClosedAt       *DateTime          `json:"closed_at"`
+	CreatedAt      *DateTime          `json:"created_at"`
+	UpdatedAt      *DateTime          `json:"updated_at"`
+}
+
+// Outstanding reports whether the invoice was sent and is not fully paid.
+func (i *Invoice) Outstanding() bool {
+	return i.State == InvoiceStateOpen && i.DueAmount > 0
+}
+
+// DaysOverdue returns how many days after its due date today is, 0 when
+// it is not due yet or not outstanding.
+func (i *Invoice) DaysOverdue(today time.Time) int {
+	if !i.Outstanding() || i.DueDate == nil {
+		return 0
+	}
+
+	y, m, d := today.Date()
+	dy, dm, dd := i.DueDate.Date()
+	days := int(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Sub(time.Date(dy, dm, dd, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+type InvoicePaymentsParams struct {
+	UpdatedSince *time.Time
+	Page         *int
+	PerPage      *int
+}
+
+func (i *InvoicePaymentsParams) Values() url.Values {
+	v := make(url.Values)
+	v.Set("page", "1")
+
+	if i.UpdatedSince != nil {
+		v.Set("updated_since", i.UpdatedSince.Format(TimeFormatDateTime))
+	}
+	if i.Page != nil {
+		v.Set("page", strconv.Itoa(*i.Page))
+	}
+	if i.PerPage != nil {
+		v.Set("per_page", strconv.Itoa(*i.PerPage))
+	}
+
+	return v
+}
+
+type InvoicePaymentsResponse struct {
+	NextPage     *int              `json:"next_page"`
+	TotalEntries int               `json:"total_entries"`
+	Page         int               `json:"page"`
+	Payments     []*InvoicePayment `json:"invoice_payments"`
+}
+
+type InvoicePayment struct {
+	ID              int            `json:"id"`
+	Amount          float64        `json:"amount"`
+	PaidAt          *DateTime      `json:"paid_at"`
+	PaidDate        *Date          `json:"paid_date"`
+	RecordedBy      string         `json:"recorded_by"`
+	RecordedByEmail string         `json:"recorded_by_email"`
+	Notes           string         `json:"notes"`
+	TransactionID   string         `json:"transaction_id"`
+	PaymentGateway  PaymentGateway `json:"payment_gateway"`
+	CreatedAt       *DateTime      `json:"created_at"`
+	UpdatedAt       *DateTime      `json:"updated_at"`
+}
+
+type PaymentGateway struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type InvoiceLineItem struct {
+	ID          int         `json:"id,omitempty"`
+	Project     *ProjectRef `json:"project,omitempty"`
+	ProjectID   *int        `json:"project_id,omitempty"`
+	Kind        string      `json:"kind"`
+	Description string      `json:"description"`
+	Quantity    float64     `json:"quantity"`
+	UnitPrice   float64     `json:"unit_price"`
+	Amount      float64     `json:"amount,omitempty"`
+	Taxed       bool        `json:"taxed"`
+	Taxed2      bool        `json:"taxed2"`
+	Destroy     bool        `json:"_destroy,omitempty"`
+}
+
+// InvoiceTimeImport imports uninvoiced billable time of a period
+// as line items, summarized by one of the InvoiceSummary* types.
+type InvoiceTimeImport struct {
+	SummaryType string `json:"summary_type"`
+	From        *Date  `json:"from,omitempty"`
+	To          *Date  `json:"to,omitempty"`
+}
+
+type InvoiceLineItemsImport struct {
+	ProjectIDs []int              `json:"project_ids"`
+	Time       *InvoiceTimeImport `json:"time,omitempty"`
+}
+
+type CreateInvoiceBody struct {
+	ClientID        int                     `json:"client_id"`
+	Number          *string                 `json:"number,omitempty"`
+	PurchaseOrder   *string                 `json:"purchase_order,omitempty"`
+	Tax             *float64                `json:"tax,omitempty"`
+	Tax2            *float64                `json:"tax2,omitempty"`
+	Discount        *float64                `json:"discount,omitempty"`
+	Subject         *string                 `json:"subject,omitempty"`
+	Notes           *string                 `json:"notes,omitempty"`
+	Currency        *string                 `json:"currency,omitempty"`
+	IssueDate       *Date                   `json:"issue_date,omitempty"`
+	DueDate         *Date                   `json:"due_date,omitempty"`
+	PaymentTerm     *string                 `json:"payment_term,omitempty"`
+	LineItemsImport *InvoiceLineItemsImport `json:"line_items_import,omitempty"`
+	LineItems       []*InvoiceLineItem      `json:"line_items,omitempty"`
+}
+
+func (h *Harvest) GetInvoices(ctx context.Context, p *InvoicesParams) (*InvoicesResponse, error) {
+	v := &InvoicesResponse{}
+	return v, h.get(ctx, "/invoices", p.Values(), v)
+}
+
+func (h *Harvest) GetInvoice(ctx context.Context, id int) (*Invoice, error) {
+	v := &Invoice{}
+	return v, h.get(ctx, fmt.Sprintf("/invoices/%d", id), nil, v)
+}
+
+func (h *Harvest) Invoices(ctx context.Context, p *InvoicesParams) *Pager[*Invoice] {
+	params := *p
+	return NewPager(
+		ctx,
+		params.Page,
+		func(ctx context.Context, page *int) ([]*Invoice, *int, error) {
+			params.Page = page
+			res, err := h.GetInvoices(ctx, &params)
+			if err != nil {
+				return nil, nil, err
+			}
+			return res.Invoices, res.NextPage, nil
+		},
+	)
+}
+
+func (h *Harvest) GetInvoicePayments(ctx context.Context, invoiceID int, p *InvoicePaymentsParams) (*InvoicePaymentsResponse, error) {
+	v := &InvoicePaymentsResponse{}
+	return v, h.get(ctx, fmt.Sprintf("/invoices/%d/payments", invoiceID), p.Values(), v)
+}
+
+// InvoicePayments pages through the payments recorded on an invoice.
+func (h *Harvest) InvoicePayments(ctx context.Context, invoiceID int, p *InvoicePaymentsParams) *Pager[*InvoicePayment] {
+	params := *p
+	return NewPager(
+		ctx,
+		params.Page,
+		func(ctx context.Context, page *int) ([]*InvoicePayment, *int, error) {
+			params.Page = page
+			res, err := h.GetInvoicePayments(ctx, invoiceID, &params)
+			if err != nil {
+				return nil, nil, err
+			}
+			return res.Payments, res.NextPage, nil
+		},
+	)
+}
+
+func (h *Harvest) CreateInvoice(ctx context.Context, body *CreateInvoiceBody) (*Invoice, error) {
+	v := &Invoice{}
+	return v, h.post(ctx, "/invoices", nil, body, v)
+}
+
+// UpdateInvoiceLineItems creates, updates or (with Destroy set) removes
+// line items of an invoice. Harvest has no separate line items endpoint,
+// they are patched through the invoice itself.
+func (h *Harvest) UpdateInvoiceLineItems(ctx context.Context, id int, items []*InvoiceLineItem) (*Invoice, error) {
+	v := &Invoice{}
+	body := struct {
+		LineItems []*InvoiceLineItem `json:"line_items"`
+	}{items}
+	return v, h.patch(ctx, fmt.Sprintf("/invoices/%d", id), nil, body, v)
+}
+
+func (h *Harvest) DeleteInvoice(ctx context.Context, id int) error {
+	return h.delete(ctx, fmt.Sprintf("/invoices/%d", id), nil)
+}
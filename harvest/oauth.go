@@ -0,0 +1,168 @@
+package harvest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	oauthAuthorizeURL = "https://id.getharvest.com/oauth2/authorize"
+	oauthTokenURL     = "https://id.getharvest.com/api/v2/oauth2/token"
+)
+
+// TokenSource provides the bearer token used to authenticate requests.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticTokenSource always returns the same token, it is never refreshed.
+type StaticTokenSource string
+
+func (s StaticTokenSource) Token(ctx context.Context) (string, error) {
+	return string(s), nil
+}
+
+// OAuth implements harvest's OAuth2 authorization code flow.
+type OAuth struct {
+	Client       *http.Client
+	ClientID     string
+	ClientSecret string
+}
+
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	TokenType    string    `json:"token_type"`
+	ExpiresIn    int       `json:"expires_in"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// Valid reports whether the access token is set and will not expire
+// within the next minute.
+func (t *Token) Valid() bool {
+	return t != nil &&
+		t.AccessToken != "" &&
+		(t.Expiry.IsZero() || time.Until(t.Expiry) > time.Minute)
+}
+
+func (o *OAuth) AuthorizeURL(state string) string {
+	v := make(url.Values)
+	v.Set("client_id", o.ClientID)
+	v.Set("response_type", "code")
+	v.Set("state", state)
+	return oauthAuthorizeURL + "?" + v.Encode()
+}
+
+// Exchange trades the code received on the redirect url for a token.
+func (o *OAuth) Exchange(ctx context.Context, code string) (*Token, error) {
+	v := make(url.Values)
+	v.Set("code", code)
+	v.Set("grant_type", "authorization_code")
+	return o.token(ctx, v)
+}
+
+func (o *OAuth) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	v := make(url.Values)
+	v.Set("refresh_token", refreshToken)
+	v.Set("grant_type", "refresh_token")
+	return o.token(ctx, v)
+}
+
+func (o *OAuth) token(ctx context.Context, v url.Values) (*Token, error) {
+	v.Set("client_id", o.ClientID)
+	v.Set("client_secret", o.ClientSecret)
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"POST",
+		oauthTokenURL,
+		strings.NewReader(v.Encode()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := o.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 400 {
+		return nil, newError(res)
+	}
+
+	t := &Token{}
+	if err := json.NewDecoder(res.Body).Decode(t); err != nil {
+		return nil, err
+	}
+
+	if t.ExpiresIn != 0 {
+		t.Expiry = time.Now().Add(time.Duration(t.ExpiresIn) * time.Second)
+	}
+
+	return t, nil
+}
+
+// RefreshingTokenSource returns the current access token
+// and refreshes it once it has expired.
+type RefreshingTokenSource struct {
+	oauth     *OAuth
+	onRefresh func(*Token) error
+
+	sem   sync.Mutex
+	token *Token
+}
+
+// NewRefreshingTokenSource creates a TokenSource starting from token,
+// onRefresh (if not nil) is called with every newly obtained token
+// so it can be persisted.
+func NewRefreshingTokenSource(o *OAuth, token *Token, onRefresh func(*Token) error) *RefreshingTokenSource {
+	return &RefreshingTokenSource{oauth: o, token: token, onRefresh: onRefresh}
+}
+
+func (r *RefreshingTokenSource) Token(ctx context.Context) (string, error) {
+	r.sem.Lock()
+	defer r.sem.Unlock()
+	if r.token.Valid() {
+		return r.token.AccessToken, nil
+	}
+
+	if r.token == nil || r.token.RefreshToken == "" {
+		return "", ErrTokenExpired
+	}
+
+	t, err := r.oauth.Refresh(ctx, r.token.RefreshToken)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode < 500 {
+			return "", fmt.Errorf("%w: %w", ErrTokenExpired, err)
+		}
+		return "", err
+	}
+
+	if t.RefreshToken == "" {
+		t.RefreshToken = r.token.RefreshToken
+	}
+	r.token = t
+
+	if r.onRefresh != nil {
+		if err := r.onRefresh(t); err != nil {
+			return "", err
+		}
+	}
+
+	return t.AccessToken, nil
+}
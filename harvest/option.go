@@ -0,0 +1,66 @@
+package harvest
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Option customizes the Api used by a client, see New.
+type Option func(*Api)
+
+// WithHTTPClient replaces the default retrying http client,
+// e.g. to use a proxy or an instrumented transport.
+func WithHTTPClient(c *http.Client) Option {
+	return func(a *Api) {
+		a.Client = c
+	}
+}
+
+// WithBaseURL points the client at another endpoint, e.g. a mock server.
+func WithBaseURL(u string) Option {
+	return func(a *Api) {
+		a.Endpoint = strings.TrimRight(u, "/")
+	}
+}
+
+// WithUserAgent sets the User-Agent header of all requests.
+func WithUserAgent(ua string) Option {
+	return func(a *Api) {
+		a.UserAgent = ua
+	}
+}
+
+// WithLogger logs every request and rate limited or failed attempts
+// at debug level. Apply it after WithHTTPClient so the retries of that client
+// are logged too.
+func WithLogger(l *slog.Logger) Option {
+	return func(a *Api) {
+		a.Logger = l
+		if rt, ok := a.Client.Transport.(*RetryTransport); ok {
+			rt.Logger = l
+		}
+	}
+}
+
+// WithRequestBudget counts every request against b, pass the same budget
+// to multiple clients to limit their combined requests.
+func WithRequestBudget(b *RequestBudget) Option {
+	return func(a *Api) {
+		a.RequestBudget = b
+	}
+}
+
+// WithProgress reports the pages fetched of requests that span multiple pages.
+func WithProgress(p Progress) Option {
+	return func(a *Api) {
+		a.Progress = p
+	}
+}
+
+// Apply applies opts to the api.
+func (a *Api) Apply(opts ...Option) {
+	for _, o := range opts {
+		o(a)
+	}
+}
@@ -0,0 +1,121 @@
+package harvest
+
+import "context"
+
+// PageFetcher fetches a single page and returns its items
+// and the number of the next page, nil if it was the last one.
+type PageFetcher[T any] func(ctx context.Context, page *int) (items []T, next *int, err error)
+
+// Pager transparently walks all pages of a paginated endpoint.
+//
+//	it := h.TimeEntries(ctx, params)
+//	for it.Next() {
+//		e := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type Pager[T any] struct {
+	ctx   context.Context
+	fetch PageFetcher[T]
+	page  *int
+	items []T
+	cur   T
+	done  bool
+	err   error
+}
+
+func NewPager[T any](ctx context.Context, page *int, fetch PageFetcher[T]) *Pager[T] {
+	return &Pager[T]{ctx: ctx, fetch: fetch, page: page}
+}
+
+// SlicePager wraps already fetched items in a Pager.
+func SlicePager[T any](items []T) *Pager[T] {
+	return &Pager[T]{items: items, done: true}
+}
+
+func (p *Pager[T]) Next() bool {
+	for len(p.items) == 0 {
+		if p.done || p.err != nil {
+			return false
+		}
+
+		items, next, err := p.fetch(p.ctx, p.page)
+		if err != nil {
+			p.err = err
+			return false
+		}
+
+		p.items = items
+		p.page = next
+		p.done = next == nil
+	}
+
+	p.cur, p.items = p.items[0], p.items[1:]
+	return true
+}
+
+func (p *Pager[T]) Value() T {
+	return p.cur
+}
+
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// All consumes the remaining pages and returns all items.
+func (p *Pager[T]) All() ([]T, error) {
+	items := make([]T, 0)
+	for p.Next() {
+		items = append(items, p.Value())
+	}
+
+	return items, p.Err()
+}
+
+// DefaultPageWorkers is the amount of pages FetchConcurrent fetches at once
+// when no positive amount is given.
+const DefaultPageWorkers = 4
+
+// PageCounter fetches a single (1-based) page and returns its items
+// and the total amount of pages.
+type PageCounter[T any] func(ctx context.Context, page int) (items []T, totalPages int, err error)
+
+// FetchConcurrent fetches the first page to learn the total amount of pages
+// and then fetches the remaining pages using at most workers goroutines.
+// Items are returned in page order.
+func FetchConcurrent[T any](ctx context.Context, workers int, fetch PageCounter[T]) ([]T, error) {
+	if workers < 1 {
+		workers = DefaultPageWorkers
+	}
+
+	first, total, err := fetch(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+	if total <= 1 {
+		return first, nil
+	}
+
+	pages := make([][]T, total)
+	pages[0] = first
+	err = Parallel(ctx, total-1, workers, func(ctx context.Context, i int) error {
+		items, _, err := fetch(ctx, i+2)
+		pages[i+1] = items
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	n := 0
+	for _, p := range pages {
+		n += len(p)
+	}
+
+	items := make([]T, 0, n)
+	for _, p := range pages {
+		items = append(items, p...)
+	}
+
+	return items, nil
+}
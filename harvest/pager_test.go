@@ -0,0 +1,138 @@
+package harvest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/harvesttest"
+)
+
+func TestFetchConcurrent(t *testing.T) {
+	errPage := errors.New("page failed")
+	tests := []struct {
+		name    string
+		pages   int
+		workers int
+		fail    int
+	}{
+		{name: "single page", pages: 1, workers: 4},
+		{name: "fewer pages than workers", pages: 3, workers: 4},
+		{name: "more pages than workers", pages: 20, workers: 3},
+		{name: "default workers", pages: 10, workers: 0},
+		{name: "first page fails", pages: 5, workers: 2, fail: 1},
+		{name: "later page fails", pages: 12, workers: 3, fail: 7},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var running, peak int32
+			items, err := harvest.FetchConcurrent(
+				context.Background(),
+				test.workers,
+				func(ctx context.Context, page int) ([]string, int, error) {
+					n := atomic.AddInt32(&running, 1)
+					defer atomic.AddInt32(&running, -1)
+					for {
+						p := atomic.LoadInt32(&peak)
+						if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+							break
+						}
+					}
+					// Let later pages finish first so the order is not
+					// the order they come in.
+					time.Sleep(time.Duration(test.pages-page) * time.Millisecond)
+
+					if page == test.fail {
+						return nil, 0, errPage
+					}
+					return []string{fmt.Sprintf("%d.a", page), fmt.Sprintf("%d.b", page)}, test.pages, nil
+				},
+			)
+
+			if test.fail != 0 {
+				if !errors.Is(err, errPage) {
+					t.Errorf("FetchConcurrent error = %v, want %v", err, errPage)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			workers := test.workers
+			if workers < 1 {
+				workers = harvest.DefaultPageWorkers
+			}
+			if int(peak) > workers {
+				t.Errorf("FetchConcurrent fetched %d pages at once, want at most %d", peak, workers)
+			}
+
+			if len(items) != 2*test.pages {
+				t.Fatalf("FetchConcurrent returned %d items, want %d", len(items), 2*test.pages)
+			}
+			for i, item := range items {
+				if want := fmt.Sprintf("%d.%c", i/2+1, 'a'+i%2); item != want {
+					t.Errorf("item %d = %s, want %s", i, item, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAllTimeEntries(t *testing.T) {
+	s := harvesttest.NewServer()
+	defer s.Close()
+
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 95; i++ {
+		s.AddTimeEntry(&harvest.TimeEntry{
+			User:      harvest.UserRef{ID: 1 + i%2},
+			SpentDate: &harvest.Date{start.AddDate(0, 0, i)},
+		})
+	}
+
+	h := harvest.New(1, "token", s.Options()...)
+	perPage := 10
+	uid := 1
+	tests := []struct {
+		name    string
+		params  *harvest.TimeEntriesParams
+		workers int
+		want    int
+	}{
+		{"all", &harvest.TimeEntriesParams{PerPage: &perPage}, 3, 95},
+		{"one user", &harvest.TimeEntriesParams{PerPage: &perPage, UserID: &uid}, 4, 48},
+		{"one worker", &harvest.TimeEntriesParams{PerPage: &perPage}, 1, 95},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entries, err := h.AllTimeEntries(context.Background(), test.params, test.workers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != test.want {
+				t.Fatalf("AllTimeEntries returned %d entries, want %d", len(entries), test.want)
+			}
+
+			// Most recent first, like harvest returns them.
+			for i := 1; i < len(entries); i++ {
+				if !entries[i].SpentDate.Before(entries[i-1].SpentDate.Time) {
+					t.Errorf(
+						"entry %d (%s) is not before entry %d (%s)",
+						i,
+						entries[i].SpentDate.Format(harvest.TimeFormatDate),
+						i-1,
+						entries[i-1].SpentDate.Format(harvest.TimeFormatDate),
+					)
+					break
+				}
+			}
+		})
+	}
+}
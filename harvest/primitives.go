@@ -79,6 +79,10 @@ func (d *DurationSeconds) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+func (d DurationSeconds) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Seconds())
+}
+
 type DurationHours struct {
 	time.Duration
 }
@@ -94,6 +98,10 @@ func (d *DurationHours) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+func (d DurationHours) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Hours())
+}
+
 type URL struct {
 	url.URL
 }
@@ -112,6 +120,10 @@ func (u *URL) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+func (u *URL) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
 type UserRef struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
@@ -140,14 +152,15 @@ type UserAssignmentRef struct {
 }
 
 type TaskAssignment struct {
-	ID         int       `json:"id"`
-	Billable   bool      `json:"billable"`
-	Active     bool      `json:"is_active"`
-	CreatedAt  *DateTime `json:"created_at"`
-	UpdatedAt  *DateTime `json:"updated_at"`
-	HourlyRate float64   `json:"hourly_rate"`
-	Budget     Budget    `json:"budget"`
-	Task       TaskRef   `json:"task"`
+	ID         int         `json:"id"`
+	Billable   bool        `json:"billable"`
+	Active     bool        `json:"is_active"`
+	CreatedAt  *DateTime   `json:"created_at"`
+	UpdatedAt  *DateTime   `json:"updated_at"`
+	HourlyRate float64     `json:"hourly_rate"`
+	Budget     Budget      `json:"budget"`
+	Task       TaskRef     `json:"task"`
+	Project    *ProjectRef `json:"project,omitempty"`
 }
 
 type TaskRef struct {
@@ -155,8 +168,19 @@ type TaskRef struct {
 	Name string `json:"name"`
 }
 
-type ExternalReference struct{}
+// ExternalReference links a time entry to an item in another service,
+// e.g. a Jira issue, or marks entries created by an integration.
+type ExternalReference struct {
+	ID        string `json:"id"`
+	GroupID   string `json:"group_id,omitempty"`
+	AccountID string `json:"account_id,omitempty"`
+	Permalink string `json:"permalink,omitempty"`
+	Service   string `json:"service,omitempty"`
+}
 
-type InvoiceRef struct{}
+type InvoiceRef struct {
+	ID     int    `json:"id"`
+	Number string `json:"number"`
+}
 
-type Budget struct{}
+type Budget float64
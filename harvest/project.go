@@ -0,0 +1,176 @@
+package harvest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type ProjectsParams struct {
+	Active       *bool
+	ClientID     *int
+	UpdatedSince *time.Time
+	Page         *int
+	PerPage      *int
+}
+
+func (p *ProjectsParams) Values() url.Values {
+	v := make(url.Values)
+	v.Set("page", "1")
+
+	if p.Active != nil {
+		v.Set("is_active", boolToString(*p.Active))
+	}
+	if p.ClientID != nil {
+		v.Set("client_id", strconv.Itoa(*p.ClientID))
+	}
+	if p.UpdatedSince != nil {
+		v.Set("updated_since", p.UpdatedSince.Format(TimeFormatDateTime))
+	}
+	if p.Page != nil {
+		v.Set("page", strconv.Itoa(*p.Page))
+	}
+	if p.PerPage != nil {
+		v.Set("per_page", strconv.Itoa(*p.PerPage))
+	}
+
+	return v
+}
+
+type ProjectsResponse struct {
+	NextPage     *int       `json:"next_page"`
+	TotalEntries int        `json:"total_entries"`
+	Page         int        `json:"page"`
+	Projects     []*Project `json:"projects"`
+}
+
+type Project struct {
+	ID                int       `json:"id"`
+	Client            ClientRef `json:"client"`
+	Name              string    `json:"name"`
+	Code              string    `json:"code"`
+	Active            bool      `json:"is_active"`
+	Billable          bool      `json:"is_billable"`
+	FixedFee          bool      `json:"is_fixed_fee"`
+	BillBy            string    `json:"bill_by"`
+	HourlyRate        float64   `json:"hourly_rate"`
+	Budget            Budget    `json:"budget"`
+	BudgetBy          string    `json:"budget_by"`
+	BudgetIsMonthly   bool      `json:"budget_is_monthly"`
+	NotifyWhenOver    bool      `json:"notify_when_over_budget"`
+	OverBudgetPercent float64   `json:"over_budget_notification_percentage"`
+	ShowBudgetToAll   bool      `json:"show_budget_to_all"`
+	CostBudget        Budget    `json:"cost_budget"`
+	CostBudgetExpense bool      `json:"cost_budget_include_expenses"`
+	Fee               float64   `json:"fee"`
+	Notes             string    `json:"notes"`
+	StartsOn          *Date     `json:"starts_on"`
+	EndsOn            *Date     `json:"ends_on"`
+	CreatedAt         *DateTime `json:"created_at"`
+	UpdatedAt         *DateTime `json:"updated_at"`
+}
+
+const (
+	BudgetByProject     = "project"
+	BudgetByProjectCost = "project_cost"
+	BudgetByTask        = "task"
+	BudgetByTaskFees    = "task_fees"
+	BudgetByPerson      = "person"
+	BudgetByNone        = "none"
+)
+
+// MoneyBudget reports whether the budget is an amount of money
+// instead of hours.
+func (p *Project) MoneyBudget() bool {
+	return p.BudgetBy == BudgetByProjectCost || p.BudgetBy == BudgetByTaskFees
+}
+
+type TaskAssignmentsParams struct {
+	Active       *bool
+	UpdatedSince *time.Time
+	Page         *int
+	PerPage      *int
+}
+
+func (t *TaskAssignmentsParams) Values() url.Values {
+	v := make(url.Values)
+	v.Set("page", "1")
+
+	if t.Active != nil {
+		v.Set("is_active", boolToString(*t.Active))
+	}
+	if t.UpdatedSince != nil {
+		v.Set("updated_since", t.UpdatedSince.Format(TimeFormatDateTime))
+	}
+	if t.Page != nil {
+		v.Set("page", strconv.Itoa(*t.Page))
+	}
+	if t.PerPage != nil {
+		v.Set("per_page", strconv.Itoa(*t.PerPage))
+	}
+
+	return v
+}
+
+type TaskAssignmentsResponse struct {
+	NextPage        *int              `json:"next_page"`
+	TotalEntries    int               `json:"total_entries"`
+	Page            int               `json:"page"`
+	TaskAssignments []*TaskAssignment `json:"task_assignments"`
+}
+
+func (h *Harvest) GetProjects(ctx context.Context, p *ProjectsParams) (*ProjectsResponse, error) {
+	v := &ProjectsResponse{}
+	return v, h.get(ctx, "/projects", p.Values(), v)
+}
+
+func (h *Harvest) GetProject(ctx context.Context, id int) (*Project, error) {
+	v := &Project{}
+	return v, h.get(ctx, fmt.Sprintf("/projects/%d", id), nil, v)
+}
+
+func (h *Harvest) Projects(ctx context.Context, p *ProjectsParams) *Pager[*Project] {
+	params := *p
+	return NewPager(
+		ctx,
+		params.Page,
+		func(ctx context.Context, page *int) ([]*Project, *int, error) {
+			params.Page = page
+			res, err := h.GetProjects(ctx, &params)
+			if err != nil {
+				return nil, nil, err
+			}
+			return res.Projects, res.NextPage, nil
+		},
+	)
+}
+
+// GetTaskAssignments lists the task assignments of a project,
+// or of all projects if projectID is 0.
+func (h *Harvest) GetTaskAssignments(ctx context.Context, projectID int, p *TaskAssignmentsParams) (*TaskAssignmentsResponse, error) {
+	path := "/task_assignments"
+	if projectID != 0 {
+		path = fmt.Sprintf("/projects/%d/task_assignments", projectID)
+	}
+
+	v := &TaskAssignmentsResponse{}
+	return v, h.get(ctx, path, p.Values(), v)
+}
+
+func (h *Harvest) TaskAssignments(ctx context.Context, projectID int, p *TaskAssignmentsParams) *Pager[*TaskAssignment] {
+	params := *p
+	return NewPager(
+		ctx,
+		params.Page,
+		func(ctx context.Context, page *int) ([]*TaskAssignment, *int, error) {
+			params.Page = page
+			res, err := h.GetTaskAssignments(ctx, projectID, &params)
+			if err != nil {
+				return nil, nil, err
+			}
+			return res.TaskAssignments, res.NextPage, nil
+		},
+	)
+}
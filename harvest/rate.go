@@ -0,0 +1,117 @@
+package harvest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type RatesParams struct {
+	Page    *int
+	PerPage *int
+}
+
+func (r *RatesParams) Values() url.Values {
+	v := make(url.Values)
+	v.Set("page", "1")
+
+	if r.Page != nil {
+		v.Set("page", strconv.Itoa(*r.Page))
+	}
+	if r.PerPage != nil {
+		v.Set("per_page", strconv.Itoa(*r.PerPage))
+	}
+
+	return v
+}
+
+type CostRatesResponse struct {
+	NextPage     *int  `json:"next_page"`
+	TotalEntries int   `json:"total_entries"`
+	Page         int   `json:"page"`
+	CostRates    Rates `json:"cost_rates"`
+}
+
+type BillableRatesResponse struct {
+	NextPage      *int  `json:"next_page"`
+	TotalEntries  int   `json:"total_entries"`
+	Page          int   `json:"page"`
+	BillableRates Rates `json:"billable_rates"`
+}
+
+// Rate is an hourly cost or billable rate of a user from StartDate until
+// EndDate, the current rate has no EndDate.
+type Rate struct {
+	ID        int       `json:"id"`
+	Amount    float64   `json:"amount"`
+	StartDate *Date     `json:"start_date"`
+	EndDate   *Date     `json:"end_date"`
+	CreatedAt *DateTime `json:"created_at"`
+	UpdatedAt *DateTime `json:"updated_at"`
+}
+
+type Rates []*Rate
+
+// At returns the amount of the rate that applied on the given day, 0 if
+// none did.
+func (r Rates) At(day time.Time) float64 {
+	d := day.Format(TimeFormatDate)
+	for _, rate := range r {
+		if rate.StartDate != nil && d < rate.StartDate.Format(TimeFormatDate) {
+			continue
+		}
+		if rate.EndDate != nil && d > rate.EndDate.Format(TimeFormatDate) {
+			continue
+		}
+		return rate.Amount
+	}
+
+	return 0
+}
+
+func (h *Harvest) GetCostRates(ctx context.Context, userID int, p *RatesParams) (*CostRatesResponse, error) {
+	v := &CostRatesResponse{}
+	return v, h.get(ctx, fmt.Sprintf("/users/%d/cost_rates", userID), p.Values(), v)
+}
+
+func (h *Harvest) GetBillableRates(ctx context.Context, userID int, p *RatesParams) (*BillableRatesResponse, error) {
+	v := &BillableRatesResponse{}
+	return v, h.get(ctx, fmt.Sprintf("/users/%d/billable_rates", userID), p.Values(), v)
+}
+
+// CostRates pages through the cost rates userID had, requires administrator
+// permissions.
+func (h *Harvest) CostRates(ctx context.Context, userID int, p *RatesParams) *Pager[*Rate] {
+	params := *p
+	return NewPager(
+		ctx,
+		params.Page,
+		func(ctx context.Context, page *int) ([]*Rate, *int, error) {
+			params.Page = page
+			res, err := h.GetCostRates(ctx, userID, &params)
+			if err != nil {
+				return nil, nil, err
+			}
+			return res.CostRates, res.NextPage, nil
+		},
+	)
+}
+
+// BillableRates pages through the default billable rates userID had.
+func (h *Harvest) BillableRates(ctx context.Context, userID int, p *RatesParams) *Pager[*Rate] {
+	params := *p
+	return NewPager(
+		ctx,
+		params.Page,
+		func(ctx context.Context, page *int) ([]*Rate, *int, error) {
+			params.Page = page
+			res, err := h.GetBillableRates(ctx, userID, &params)
+			if err != nil {
+				return nil, nil, err
+			}
+			return res.BillableRates, res.NextPage, nil
+		},
+	)
+}
@@ -0,0 +1,153 @@
+package harvest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// Recording is a request and its response as stored by RecordTransport.
+// Credentials are never stored.
+type Recording struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Request string      `json:"request,omitempty"`
+	Status  int         `json:"status"`
+	Header  http.Header `json:"header"`
+	Body    string      `json:"body"`
+}
+
+// recordingPath returns the file of the recording of req in dir, requests to
+// the same host and uri with the same body share one.
+func recordingPath(dir string, req *http.Request) (string, []byte, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return "", nil, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s%s\n", req.Method, req.URL.Host, req.URL.RequestURI())
+	h.Write(body)
+	name := fmt.Sprintf("%s-%s.json", req.Method, hex.EncodeToString(h.Sum(nil))[:16])
+
+	return filepath.Join(dir, name), body, nil
+}
+
+// RecordTransport writes every request and its response to Dir,
+// ReplayTransport serves them back.
+type RecordTransport struct {
+	Dir       string
+	Transport http.RoundTripper
+}
+
+func (r *RecordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	path, body, err := recordingPath(r.Dir, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+
+	all, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(all))
+
+	header := res.Header.Clone()
+	header.Del("Set-Cookie")
+	rec := &Recording{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Request: string(body),
+		Status:  res.StatusCode,
+		Header:  header,
+		Body:    string(all),
+	}
+
+	return res, writeRecording(path, rec)
+}
+
+func writeRecording(path string, rec *Recording) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(path), ".recording")
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "    ")
+	err = enc.Encode(rec)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// ReplayTransport answers requests with the responses RecordTransport
+// stored in Dir without touching the network, requests that were not
+// recorded fail.
+type ReplayTransport struct {
+	Dir string
+}
+
+func (r *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path, _, err := recordingPath(r.Dir, req)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("No recorded response for %s %s in %s", req.Method, req.URL, r.Dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rec := &Recording{}
+	if err := json.NewDecoder(f).Decode(rec); err != nil {
+		return nil, fmt.Errorf("Invalid recording %s: %w", path, err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		StatusCode:    rec.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        rec.Header,
+		Body:          ioutil.NopCloser(bytes.NewReader([]byte(rec.Body))),
+		ContentLength: int64(len(rec.Body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,137 @@
+package harvest
+
+import (
+	"io"
+	"io/ioutil"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultMaxAttempts = 5
+
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 30 * time.Second
+)
+
+// RetryTransport retries requests that were rate limited (429) by honoring
+// the Retry-After header, and idempotent requests that failed
+// with a transient error (5xx or network), backing off exponentially with jitter.
+type RetryTransport struct {
+	Transport   http.RoundTripper
+	MaxAttempts int
+	// Logger, if set, logs every retry at debug level.
+	Logger *slog.Logger
+}
+
+func NewRetryClient(maxAttempts int) *http.Client {
+	return &http.Client{
+		Transport: &RetryTransport{Transport: http.DefaultTransport, MaxAttempts: maxAttempts},
+	}
+}
+
+func (r *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	max := r.MaxAttempts
+	if max < 1 {
+		max = 1
+	}
+
+	ctx := req.Context()
+	try := req
+	for attempt := 1; ; attempt++ {
+		res, err := transport.RoundTrip(try)
+		if attempt >= max {
+			return res, err
+		}
+
+		var wait time.Duration
+		var reason string
+		switch {
+		case err != nil:
+			if ctx.Err() != nil || !idempotent(req.Method) {
+				return res, err
+			}
+			wait, reason = backoff(attempt), err.Error()
+		case res.StatusCode == http.StatusTooManyRequests:
+			wait, reason = retryAfter(res, attempt), "rate limited"
+		case res.StatusCode >= 500 && idempotent(req.Method):
+			wait, reason = backoff(attempt), res.Status
+		default:
+			return res, err
+		}
+
+		if r.Logger != nil {
+			r.Logger.Debug(
+				"Retrying request",
+				"method", req.Method,
+				"url", req.URL.Redacted(),
+				"attempt", attempt,
+				"wait", wait,
+				"reason", reason,
+			)
+		}
+
+		if res != nil {
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+
+		try = req.Clone(ctx)
+		if req.Body != nil && req.GetBody != nil {
+			if try.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	}
+	return false
+}
+
+func backoff(attempt int) time.Duration {
+	d := retryBaseDelay << uint(attempt-1)
+	if d > retryMaxDelay || d <= 0 {
+		d = retryMaxDelay
+	}
+
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func retryAfter(res *http.Response, attempt int) time.Duration {
+	h := res.Header.Get("Retry-After")
+	if h == "" {
+		return backoff(attempt)
+	}
+
+	if s, err := strconv.Atoi(h); err == nil && s >= 0 {
+		return time.Duration(s)*time.Second + time.Duration(rand.Int63n(int64(time.Second)))
+	}
+
+	if t, err := http.ParseTime(h); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+		return 0
+	}
+
+	return backoff(attempt)
+}
@@ -0,0 +1,70 @@
+package harvest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+type RolesParams struct {
+	Page    *int
+	PerPage *int
+}
+
+func (r *RolesParams) Values() url.Values {
+	v := make(url.Values)
+	v.Set("page", "1")
+
+	if r.Page != nil {
+		v.Set("page", strconv.Itoa(*r.Page))
+	}
+	if r.PerPage != nil {
+		v.Set("per_page", strconv.Itoa(*r.PerPage))
+	}
+
+	return v
+}
+
+type RolesResponse struct {
+	NextPage     *int    `json:"next_page"`
+	TotalEntries int     `json:"total_entries"`
+	Page         int     `json:"page"`
+	Roles        []*Role `json:"roles"`
+}
+
+// Role is a custom role of the account, e.g. "Designer", with the users that
+// have it. It is unrelated to the access roles that grant permissions.
+type Role struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	UserIDs   []int     `json:"user_ids"`
+	CreatedAt *DateTime `json:"created_at"`
+	UpdatedAt *DateTime `json:"updated_at"`
+}
+
+func (h *Harvest) GetRoles(ctx context.Context, p *RolesParams) (*RolesResponse, error) {
+	v := &RolesResponse{}
+	return v, h.get(ctx, "/roles", p.Values(), v)
+}
+
+func (h *Harvest) GetRole(ctx context.Context, id int) (*Role, error) {
+	v := &Role{}
+	return v, h.get(ctx, fmt.Sprintf("/roles/%d", id), nil, v)
+}
+
+func (h *Harvest) Roles(ctx context.Context, p *RolesParams) *Pager[*Role] {
+	params := *p
+	return NewPager(
+		ctx,
+		params.Page,
+		func(ctx context.Context, page *int) ([]*Role, *int, error) {
+			params.Page = page
+			res, err := h.GetRoles(ctx, &params)
+			if err != nil {
+				return nil, nil, err
+			}
+			return res.Roles, res.NextPage, nil
+		},
+	)
+}
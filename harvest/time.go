@@ -1,9 +1,13 @@
 package harvest
 
 import (
+	"context"
+	"fmt"
 	"net/url"
+	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -61,14 +65,96 @@ func (t *TimeEntriesParams) Values() url.Values {
 type TimeEntriesResponse struct {
 	NextPage     *int        `json:"next_page"`
 	TotalEntries int         `json:"total_entries"`
+	TotalPages   int         `json:"total_pages"`
 	Page         int         `json:"page"`
 	TimeEntries  TimeEntries `json:"time_entries"`
 }
 
-type Grouper func(t *TimeEntry) (key string, include bool)
+// GroupKey identifies a group, all Parts together make up the (composite) key,
+// Name is a human readable label.
+type GroupKey struct {
+	Parts []string
+	Name  string
+}
+
+func Key(name string, parts ...string) GroupKey {
+	return GroupKey{parts, name}
+}
+
+func (k GroupKey) String() string {
+	return strings.Join(k.Parts, "|")
+}
+
+type Grouper func(t *TimeEntry) (key GroupKey, include bool)
+
+// Filter reports whether an entry should be included.
+type Filter func(t *TimeEntry) bool
+
+// Uninvoiced is a Filter for billable entries that are not on an invoice yet.
+func Uninvoiced(t *TimeEntry) bool {
+	return t.Billable && !t.Billed
+}
+
+func (t TimeEntries) Filter(f Filter) TimeEntries {
+	n := make(TimeEntries, 0, len(t))
+	for _, e := range t {
+		if f(e) {
+			n = append(n, e)
+		}
+	}
+
+	return n
+}
 
 type TimeEntries []*TimeEntry
 
+const (
+	ApprovalUnsubmitted = "unsubmitted"
+	ApprovalPending     = "pending"
+	ApprovalApproved    = "approved"
+)
+
+// ApprovalStatus derives the approval state of a timesheet from its entries:
+// approved entries are closed, submitted entries are locked awaiting approval.
+// Entries locked for another reason, e.g. because they are invoiced, are
+// unsubmitted.
+func (t *TimeEntry) ApprovalStatus() string {
+	switch {
+	case t.Closed:
+		return ApprovalApproved
+	case t.Locked && approvalLock(t.LockedReason):
+		return ApprovalPending
+	}
+	return ApprovalUnsubmitted
+}
+
+// approvalLock reports whether reason is a locked_reason of timesheet
+// approval rather than of an invoice or a locked period.
+func approvalLock(reason string) bool {
+	reason = strings.ToLower(reason)
+	return strings.Contains(reason, "approv") || strings.Contains(reason, "submit")
+}
+
+// ApprovalStatus returns the least advanced approval state of all entries,
+// ApprovalUnsubmitted if there are none.
+func (t TimeEntries) ApprovalStatus() string {
+	if len(t) == 0 {
+		return ApprovalUnsubmitted
+	}
+
+	status := ApprovalApproved
+	for _, e := range t {
+		switch e.ApprovalStatus() {
+		case ApprovalUnsubmitted:
+			return ApprovalUnsubmitted
+		case ApprovalPending:
+			status = ApprovalPending
+		}
+	}
+
+	return status
+}
+
 func (t TimeEntries) SortSpent() TimeEntries {
 	sort.SliceStable(
 		t,
@@ -91,11 +177,12 @@ func (t TimeEntries) Group(groupBy Grouper) Grouped {
 	d := make(Grouped, 0, len(t))
 	lookup := make(map[string]int)
 	for _, e := range t {
-		k, ok := groupBy(e)
+		key, ok := groupBy(e)
 		if !ok {
 			continue
 		}
 
+		k := key.String()
 		if _, ok := lookup[k]; !ok {
 			lookup[k] = len(d)
 			var spent time.Time
@@ -106,6 +193,7 @@ func (t TimeEntries) Group(groupBy Grouper) Grouped {
 			d = append(
 				d,
 				&Group{
+					Key:            key,
 					FirstSpentDate: spent,
 					SpentDates:     make([]time.Time, 0, 1),
 				},
@@ -114,6 +202,11 @@ func (t TimeEntries) Group(groupBy Grouper) Grouped {
 
 		group := d[lookup[k]]
 		group.Hours += e.Hours.Duration
+		if e.Billable {
+			group.BillableHours += e.Hours.Duration
+			group.Revenue += e.Hours.Hours() * e.BillableRate
+		}
+		group.Cost += e.Hours.Hours() * e.CostRate
 		if e.SpentDate != nil {
 			group.SpentDates = append(group.SpentDates, e.SpentDate.Time)
 		}
@@ -139,11 +232,26 @@ func (g Grouped) SortSpent() Grouped {
 	return g
 }
 
+func (g Grouped) SortHours() Grouped {
+	sort.SliceStable(
+		g,
+		func(i, j int) bool {
+			return g[i].Hours > g[j].Hours
+		},
+	)
+
+	return g
+}
+
 type Group struct {
+	Key            GroupKey
 	Running        bool
 	FirstSpentDate time.Time
 	SpentDates     []time.Time
 	Hours          time.Duration
+	BillableHours  time.Duration
+	Revenue        float64
+	Cost           float64
 }
 
 type TimeEntry struct {
@@ -154,7 +262,7 @@ type TimeEntry struct {
 	Client            ClientRef         `json:"client"`
 	Project           ProjectRef        `json:"project"`
 	Task              TaskRef           `json:"task"`
-	TaskAssignment    TaskAssignment    `json:"task"`
+	TaskAssignment    TaskAssignment    `json:"task_assignment"`
 	ExternalReference ExternalReference `json:"external_reference"`
 	Invoice           InvoiceRef        `json:"invoice"`
 
@@ -177,9 +285,14 @@ type TimeEntry struct {
 	UpdatedAt      *DateTime     `json:"updated_at"`
 }
 
-func (h *Harvest) GetTimeEntries(p *TimeEntriesParams) (*TimeEntriesResponse, error) {
+// IssueKey returns the first match of re in the notes, e.g. a jira key.
+func (t *TimeEntry) IssueKey(re *regexp.Regexp) string {
+	return re.FindString(t.Notes)
+}
+
+func (h *Harvest) GetTimeEntries(ctx context.Context, p *TimeEntriesParams) (*TimeEntriesResponse, error) {
 	v := &TimeEntriesResponse{}
-	return v, h.get("/time_entries", p.Values(), v)
+	return v, h.get(ctx, "/time_entries", p.Values(), v)
 }
 
 type CreateTimeEntryBody struct {
@@ -189,10 +302,115 @@ type CreateTimeEntryBody struct {
 	SpentDate   Date      `json:"spent_date"`
 	StartedTime *DateTime `json:"started_time"`
 	EndedTime   *DateTime `json:"ended_time"`
+	Hours       *float64  `json:"hours,omitempty"`
 	Notes       *string   `json:"notes"`
+
+	ExternalReference *ExternalReference `json:"external_reference,omitempty"`
+}
+
+func (h *Harvest) CreateTimeEntry(ctx context.Context, p *CreateTimeEntryBody) (*TimeEntry, error) {
+	v := &TimeEntry{}
+	return v, h.post(ctx, "/time_entries", nil, p, v)
 }
 
-func (h *Harvest) CreateTimeEntry(p *CreateTimeEntryBody) (*TimeEntry, error) {
+func (h *Harvest) RestartTimeEntry(ctx context.Context, id int) (*TimeEntry, error) {
 	v := &TimeEntry{}
-	return v, h.post("/time_entries", nil, p, v)
+	return v, h.patch(ctx, fmt.Sprintf("/time_entries/%d/restart", id), nil, nil, v)
+}
+
+func (h *Harvest) StopTimeEntry(ctx context.Context, id int) (*TimeEntry, error) {
+	v := &TimeEntry{}
+	return v, h.patch(ctx, fmt.Sprintf("/time_entries/%d/stop", id), nil, nil, v)
+}
+
+func (h *Harvest) TimeEntries(ctx context.Context, p *TimeEntriesParams) *Pager[*TimeEntry] {
+	params := *p
+	return NewPager(
+		ctx,
+		params.Page,
+		func(ctx context.Context, page *int) ([]*TimeEntry, *int, error) {
+			params.Page = page
+			res, err := h.GetTimeEntries(ctx, &params)
+			if err != nil {
+				return nil, nil, err
+			}
+			return res.TimeEntries, res.NextPage, nil
+		},
+	)
+}
+
+// ForEachTimeEntry calls fn with every time entry, one page at a time,
+// only the current page is kept in memory. It stops at the first error of fn.
+func (h *Harvest) ForEachTimeEntry(ctx context.Context, p *TimeEntriesParams, fn func(*TimeEntry) error) error {
+	params := *p
+	progress := h.api.pageProgress()
+	for {
+		res, err := h.GetTimeEntries(ctx, &params)
+		if err != nil {
+			return err
+		}
+		progress.page(res.TotalPages)
+
+		for _, e := range res.TimeEntries {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+
+		if res.NextPage == nil {
+			return nil
+		}
+		params.Page = res.NextPage
+	}
+}
+
+// AllTimeEntries fetches all pages of time entries, fetching up to workers
+// pages concurrently once the total amount of pages is known.
+func (h *Harvest) AllTimeEntries(ctx context.Context, p *TimeEntriesParams, workers int) (TimeEntries, error) {
+	params := *p
+	progress := h.api.pageProgress()
+	return FetchConcurrent(
+		ctx,
+		workers,
+		func(ctx context.Context, page int) ([]*TimeEntry, int, error) {
+			params := params
+			params.Page = &page
+			res, err := h.GetTimeEntries(ctx, &params)
+			if err != nil {
+				return nil, 0, err
+			}
+			if page == 1 {
+				// Refuse runaway queries before fetching any other page.
+				if err := h.api.RequestBudget.allows(res.TotalPages - 1); err != nil {
+					return nil, 0, err
+				}
+			}
+			progress.page(res.TotalPages)
+			return res.TimeEntries, res.TotalPages, nil
+		},
+	)
+}
+
+type UpdateTimeEntryBody struct {
+	ProjectID   *int      `json:"project_id,omitempty"`
+	TaskID      *int      `json:"task_id,omitempty"`
+	SpentDate   *Date     `json:"spent_date,omitempty"`
+	StartedTime *DateTime `json:"started_time,omitempty"`
+	EndedTime   *DateTime `json:"ended_time,omitempty"`
+	Hours       *float64  `json:"hours,omitempty"`
+	Notes       *string   `json:"notes,omitempty"`
+}
+
+func (h *Harvest) GetTimeEntry(ctx context.Context, id int) (*TimeEntry, error) {
+	v := &TimeEntry{}
+	return v, h.get(ctx, fmt.Sprintf("/time_entries/%d", id), nil, v)
+}
+
+func (h *Harvest) UpdateTimeEntry(ctx context.Context, id int, p *UpdateTimeEntryBody) (*TimeEntry, error) {
+	v := &TimeEntry{}
+	return v, h.patch(ctx, fmt.Sprintf("/time_entries/%d", id), nil, p, v)
+}
+
+func (h *Harvest) DeleteTimeEntry(ctx context.Context, id int) error {
+	return h.delete(ctx, fmt.Sprintf("/time_entries/%d", id), nil)
 }
@@ -1,6 +1,7 @@
 package harvest
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -57,6 +58,25 @@ func (u *UserAssignmentParams) Values() url.Values {
 	return v
 }
 
+type TeammatesParams struct {
+	Page    *int
+	PerPage *int
+}
+
+func (t *TeammatesParams) Values() url.Values {
+	v := make(url.Values)
+	v.Set("page", "1")
+
+	if t.Page != nil {
+		v.Set("page", strconv.Itoa(*t.Page))
+	}
+	if t.PerPage != nil {
+		v.Set("per_page", strconv.Itoa(*t.PerPage))
+	}
+
+	return v
+}
+
 type UsersResponse struct {
 	NextPage     *int    `json:"next_page"`
 	TotalEntries int     `json:"total_entries"`
@@ -83,6 +103,7 @@ type User struct {
 	DefaultHourRate     float64   `json:"default_hourly_rate"`
 	CostRate            float64   `json:"cost_rate"`
 	Roles               []string  `json:"roles"`
+	AccessRoles         []string  `json:"access_roles"`
 	AvatarURL           string    `json:"avatar_url"`
 	CreatedAt           *DateTime `json:"created_at"`
 	UpdatedAt           *DateTime `json:"updated_at"`
@@ -102,26 +123,182 @@ type UserAssignment struct {
 	TaskAssignments []*TaskAssignment `json:"task_assignments"`
 }
 
+type TeammatesResponse struct {
+	NextPage     *int        `json:"next_page"`
+	TotalEntries int         `json:"total_entries"`
+	Page         int         `json:"page"`
+	Teammates    []*Teammate `json:"teammates"`
+}
+
+// Teammate is a user a manager manages, i.e. whose time they can see and
+// approve.
+type Teammate struct {
+	ID        int    `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+}
+
+type CreateUserBody struct {
+	FirstName           string   `json:"first_name"`
+	LastName            string   `json:"last_name"`
+	Email               string   `json:"email"`
+	TZ                  *string  `json:"timezone,omitempty"`
+	FutureProjectAccess *bool    `json:"has_access_to_all_future_projects,omitempty"`
+	Contractor          *bool    `json:"is_contractor,omitempty"`
+	Active              *bool    `json:"is_active,omitempty"`
+	WeeklyCapacity      *int     `json:"weekly_capacity,omitempty"`
+	DefaultHourRate     *float64 `json:"default_hourly_rate,omitempty"`
+	CostRate            *float64 `json:"cost_rate,omitempty"`
+	Roles               []string `json:"roles,omitempty"`
+	AccessRoles         []string `json:"access_roles,omitempty"`
+}
+
+// UpdateUserBody only changes the fields that are set.
+type UpdateUserBody struct {
+	FirstName           *string  `json:"first_name,omitempty"`
+	LastName            *string  `json:"last_name,omitempty"`
+	Email               *string  `json:"email,omitempty"`
+	TZ                  *string  `json:"timezone,omitempty"`
+	FutureProjectAccess *bool    `json:"has_access_to_all_future_projects,omitempty"`
+	Contractor          *bool    `json:"is_contractor,omitempty"`
+	Active              *bool    `json:"is_active,omitempty"`
+	WeeklyCapacity      *int     `json:"weekly_capacity,omitempty"`
+	DefaultHourRate     *float64 `json:"default_hourly_rate,omitempty"`
+	CostRate            *float64 `json:"cost_rate,omitempty"`
+	Roles               []string `json:"roles,omitempty"`
+	AccessRoles         []string `json:"access_roles,omitempty"`
+}
+
+// AccessRoleAdministrator is the access role of account administrators, the
+// others are manager and member.
+const AccessRoleAdministrator = "administrator"
+
+// Administrator reports whether the user can see the time of everyone in the
+// account. Users fetched without access_roles fall back to is_admin.
+func (u *User) Administrator() bool {
+	for _, r := range u.AccessRoles {
+		if r == AccessRoleAdministrator {
+			return true
+		}
+	}
+
+	return u.Admin
+}
+
 func (u *User) Capacity() time.Duration {
 	return time.Duration(u.WeeklyCapacity) * time.Second
 }
 
-func (h *Harvest) GetUsers(u *UsersParams) (*UsersResponse, error) {
+func (h *Harvest) GetUsers(ctx context.Context, u *UsersParams) (*UsersResponse, error) {
 	v := &UsersResponse{}
-	return v, h.get("/users", u.Values(), v)
+	return v, h.get(ctx, "/users", u.Values(), v)
+}
+
+// ListUsers fetches all pages of users matching p.
+func (h *Harvest) ListUsers(ctx context.Context, p *UsersParams) ([]*User, error) {
+	return h.Users(ctx, p).All()
+}
+
+func (h *Harvest) CreateUser(ctx context.Context, body *CreateUserBody) (*User, error) {
+	v := &User{}
+	return v, h.post(ctx, "/users", nil, body, v)
 }
 
-func (h *Harvest) GetMe() (*User, error) {
+func (h *Harvest) UpdateUser(ctx context.Context, id int, body *UpdateUserBody) (*User, error) {
 	v := &User{}
-	return v, h.get("/users/me", nil, v)
+	return v, h.patch(ctx, fmt.Sprintf("/users/%d", id), nil, body, v)
 }
 
-func (h *Harvest) GetUser(id int) (*User, error) {
+func (h *Harvest) GetMe(ctx context.Context) (*User, error) {
 	v := &User{}
-	return v, h.get(fmt.Sprintf("/users/%d", id), nil, v)
+	return v, h.get(ctx, "/users/me", nil, v)
+}
+
+func (h *Harvest) GetUser(ctx context.Context, id int) (*User, error) {
+	v := &User{}
+	return v, h.get(ctx, fmt.Sprintf("/users/%d", id), nil, v)
+}
+
+func (h *Harvest) GetUserAssignments(ctx context.Context, userID int, p *UserAssignmentParams) (*UserAssignmentsResponse, error) {
+	v := &UserAssignmentsResponse{}
+	return v, h.get(ctx, fmt.Sprintf("/users/%d/project_assignments", userID), p.Values(), v)
+}
+
+func (h *Harvest) GetTeammates(ctx context.Context, userID int, p *TeammatesParams) (*TeammatesResponse, error) {
+	v := &TeammatesResponse{}
+	return v, h.get(ctx, fmt.Sprintf("/users/%d/teammates", userID), p.Values(), v)
 }
 
-func (h *Harvest) GetUserAssignments(userID int, p *UserAssignmentParams) (*UserAssignmentsResponse, error) {
+// Teammates pages through the users userID manages.
+func (h *Harvest) Teammates(ctx context.Context, userID int, p *TeammatesParams) *Pager[*Teammate] {
+	params := *p
+	return NewPager(
+		ctx,
+		params.Page,
+		func(ctx context.Context, page *int) ([]*Teammate, *int, error) {
+			params.Page = page
+			res, err := h.GetTeammates(ctx, userID, &params)
+			if err != nil {
+				return nil, nil, err
+			}
+			return res.Teammates, res.NextPage, nil
+		},
+	)
+}
+
+func (h *Harvest) GetMyAssignments(ctx context.Context, p *UserAssignmentParams) (*UserAssignmentsResponse, error) {
 	v := &UserAssignmentsResponse{}
-	return v, h.get(fmt.Sprintf("/users/%d/project_assignments", userID), p.Values(), v)
+	return v, h.get(ctx, "/users/me/project_assignments", p.Values(), v)
+}
+
+// MyAssignments pages through the active projects, and their tasks, the
+// authenticated user can log time on. Unlike UserAssignments it does not
+// require administrator or manager permissions.
+func (h *Harvest) MyAssignments(ctx context.Context, p *UserAssignmentParams) *Pager[*UserAssignment] {
+	params := *p
+	return NewPager(
+		ctx,
+		params.Page,
+		func(ctx context.Context, page *int) ([]*UserAssignment, *int, error) {
+			params.Page = page
+			res, err := h.GetMyAssignments(ctx, &params)
+			if err != nil {
+				return nil, nil, err
+			}
+			return res.Assignments, res.NextPage, nil
+		},
+	)
+}
+
+func (h *Harvest) Users(ctx context.Context, p *UsersParams) *Pager[*User] {
+	params := *p
+	return NewPager(
+		ctx,
+		params.Page,
+		func(ctx context.Context, page *int) ([]*User, *int, error) {
+			params.Page = page
+			res, err := h.GetUsers(ctx, &params)
+			if err != nil {
+				return nil, nil, err
+			}
+			return res.Users, res.NextPage, nil
+		},
+	)
+}
+
+func (h *Harvest) UserAssignments(ctx context.Context, userID int, p *UserAssignmentParams) *Pager[*UserAssignment] {
+	params := *p
+	return NewPager(
+		ctx,
+		params.Page,
+		func(ctx context.Context, page *int) ([]*UserAssignment, *int, error) {
+			params.Page = page
+			res, err := h.GetUserAssignments(ctx, userID, &params)
+			if err != nil {
+				return nil, nil, err
+			}
+			return res.Assignments, res.NextPage, nil
+		},
+	)
 }
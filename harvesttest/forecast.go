@@ -0,0 +1,243 @@
+package harvesttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/forecast"
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
+
+// ForecastServer is a fake forecast api. Its fields can be modified between
+// requests, Lock should be held when doing so concurrently.
+type ForecastServer struct {
+	*httptest.Server
+	sync.Mutex
+
+	Me          *forecast.User
+	People      []*forecast.User
+	Projects    []*forecast.Project
+	Assignments []*forecast.Assignment
+	Milestones  []*forecast.Milestone
+
+	nextID int
+}
+
+// NewForecastServer starts a fake forecast api.
+func NewForecastServer() *ForecastServer {
+	s := &ForecastServer{}
+
+	var mux router
+	mux.handle("GET", "/whoami", s.whoami)
+	mux.handle("GET", "/people", s.people)
+	mux.handle("GET", "/people/{id}", s.person)
+	mux.handle("GET", "/projects", s.projects)
+	mux.handle("GET", "/assignments", s.assignments)
+	mux.handle("POST", "/assignments", s.createAssignment)
+	mux.handle("PUT", "/assignments/{id}", s.updateAssignment)
+	mux.handle("DELETE", "/assignments/{id}", s.deleteAssignment)
+	mux.handle("GET", "/milestones", s.milestones)
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Options configures a forecast client to talk to this server.
+func (s *ForecastServer) Options() []harvest.Option {
+	return options(s.Server)
+}
+
+func (s *ForecastServer) whoami(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	if s.Me == nil {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	writeJSON(w, http.StatusOK, forecast.MeResponse{Me: &forecast.Me{ID: s.Me.ID}})
+}
+
+func (s *ForecastServer) people(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	people := s.People
+	if s.Me != nil {
+		people = append([]*forecast.User{s.Me}, people...)
+	}
+	writeJSON(w, http.StatusOK, forecast.PeopleResponse{People: people})
+}
+
+func (s *ForecastServer) person(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	id, ok := pathID(w, rawID)
+	if !ok {
+		return
+	}
+
+	people := s.People
+	if s.Me != nil {
+		people = append([]*forecast.User{s.Me}, people...)
+	}
+	for _, p := range people {
+		if p.ID == id {
+			writeJSON(w, http.StatusOK, forecast.UserResponse{Person: p})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not found")
+}
+
+func (s *ForecastServer) projects(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	writeJSON(w, http.StatusOK, forecast.ProjectsResponse{Projects: s.Projects})
+}
+
+func (s *ForecastServer) assignments(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	q := r.URL.Query()
+	start, _ := time.Parse(harvest.TimeFormatDate, q.Get("start_date"))
+	end, _ := time.Parse(harvest.TimeFormatDate, q.Get("end_date"))
+	person := q.Get("person_id")
+	project := q.Get("project_id")
+
+	list := make([]*forecast.Assignment, 0, len(s.Assignments))
+	for _, a := range s.Assignments {
+		if person != "" && person != strconv.Itoa(a.PersonID) {
+			continue
+		}
+		if project != "" && project != strconv.Itoa(a.ProjectID) {
+			continue
+		}
+		if !start.IsZero() && a.EndDate != nil && a.EndDate.Before(start) {
+			continue
+		}
+		if !end.IsZero() && a.StartDate != nil && a.StartDate.After(end) {
+			continue
+		}
+		list = append(list, a)
+	}
+
+	writeJSON(w, http.StatusOK, forecast.AssignmentsResponse{Assignments: list})
+}
+
+func (s *ForecastServer) findAssignment(w http.ResponseWriter, rawID string) (int, *forecast.Assignment) {
+	id, ok := pathID(w, rawID)
+	if !ok {
+		return 0, nil
+	}
+	for i, a := range s.Assignments {
+		if a.ID == id {
+			return i, a
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not found")
+	return 0, nil
+}
+
+func decodeAssignment(w http.ResponseWriter, r *http.Request) (*forecast.AssignmentBody, bool) {
+	body := struct {
+		Assignment *forecast.AssignmentBody `json:"assignment"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Assignment == nil {
+		writeError(w, http.StatusBadRequest, "Invalid assignment")
+		return nil, false
+	}
+	return body.Assignment, true
+}
+
+func applyAssignment(a *forecast.Assignment, b *forecast.AssignmentBody) {
+	if b.ProjectID != nil {
+		a.ProjectID = *b.ProjectID
+	}
+	if b.PersonID != nil {
+		a.PersonID = *b.PersonID
+	}
+	if b.PlaceholderID != nil {
+		a.PlaceholderID = *b.PlaceholderID
+	}
+	if b.StartDate != nil {
+		a.StartDate = b.StartDate
+	}
+	if b.EndDate != nil {
+		a.EndDate = b.EndDate
+	}
+	if b.Allocation != nil {
+		a.Allocation = *b.Allocation
+	}
+	if b.Notes != nil {
+		a.Notes = *b.Notes
+	}
+	if b.ActiveOnDaysOff != nil {
+		a.ActiveOnDaysOff = *b.ActiveOnDaysOff
+	}
+}
+
+func (s *ForecastServer) createAssignment(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	body, ok := decodeAssignment(w, r)
+	if !ok {
+		return
+	}
+	if body.ProjectID == nil || body.StartDate == nil || body.EndDate == nil {
+		writeError(w, http.StatusUnprocessableEntity, "project_id, start_date and end_date are required")
+		return
+	}
+
+	s.nextID++
+	a := &forecast.Assignment{ID: s.nextID}
+	applyAssignment(a, body)
+	s.Assignments = append(s.Assignments, a)
+	writeJSON(w, http.StatusCreated, forecast.AssignmentResponse{Assignment: a})
+}
+
+func (s *ForecastServer) updateAssignment(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	_, a := s.findAssignment(w, rawID)
+	if a == nil {
+		return
+	}
+	body, ok := decodeAssignment(w, r)
+	if !ok {
+		return
+	}
+
+	applyAssignment(a, body)
+	writeJSON(w, http.StatusOK, forecast.AssignmentResponse{Assignment: a})
+}
+
+func (s *ForecastServer) deleteAssignment(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	i, a := s.findAssignment(w, rawID)
+	if a == nil {
+		return
+	}
+
+	s.Assignments = append(s.Assignments[:i], s.Assignments[i+1:]...)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *ForecastServer) milestones(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	project := r.URL.Query().Get("project_id")
+
+	list := make([]*forecast.Milestone, 0, len(s.Milestones))
+	for _, m := range s.Milestones {
+		if project != "" && project != strconv.Itoa(m.ProjectID) {
+			continue
+		}
+		list = append(list, m)
+	}
+
+	writeJSON(w, http.StatusOK, forecast.MilestonesResponse{Milestones: list})
+}
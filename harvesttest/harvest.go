@@ -0,0 +1,623 @@
+package harvesttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
+
+// Server is a fake harvest api. Its fields can be modified between
+// requests, Lock should be held when doing so concurrently.
+type Server struct {
+	*httptest.Server
+	sync.Mutex
+
+	Me              *harvest.User
+	Company         *harvest.Company
+	Users           []*harvest.User
+	Clients         []*harvest.Client
+	Projects        []*harvest.Project
+	TaskAssignments []*harvest.TaskAssignment
+	UserAssignments map[int][]*harvest.UserAssignment
+	Roles           []*harvest.Role
+	Teammates       map[int][]*harvest.Teammate
+	CostRates       map[int]harvest.Rates
+	BillableRates   map[int]harvest.Rates
+	Invoices        []*harvest.Invoice
+	Payments        map[int][]*harvest.InvoicePayment
+	TimeEntries     []*harvest.TimeEntry
+
+	// Now is used as the time timers are started and stopped.
+	Now func() time.Time
+
+	nextID int
+}
+
+// NewServer starts a fake harvest api with an empty company.
+func NewServer() *Server {
+	s := &Server{
+		Company:         &harvest.Company{WeekStart: "Monday", TimeFormat: "hours_minutes"},
+		UserAssignments: make(map[int][]*harvest.UserAssignment),
+		Teammates:       make(map[int][]*harvest.Teammate),
+		CostRates:       make(map[int]harvest.Rates),
+		BillableRates:   make(map[int]harvest.Rates),
+		Payments:        make(map[int][]*harvest.InvoicePayment),
+		Now:             time.Now,
+		nextID:          1000,
+	}
+
+	var mux router
+	mux.handle("GET", "/company", s.company)
+	mux.handle("GET", "/users", s.users)
+	mux.handle("GET", "/users/me", s.me)
+	mux.handle("GET", "/users/{id}", s.user)
+	mux.handle("GET", "/users/me/project_assignments", s.myAssignments)
+	mux.handle("GET", "/users/{id}/project_assignments", s.userAssignments)
+	mux.handle("GET", "/users/{id}/teammates", s.teammates)
+	mux.handle("GET", "/users/{id}/cost_rates", s.costRates)
+	mux.handle("GET", "/users/{id}/billable_rates", s.billableRates)
+	mux.handle("GET", "/roles", s.roles)
+	mux.handle("GET", "/clients", s.clients)
+	mux.handle("GET", "/projects", s.projects)
+	mux.handle("GET", "/projects/{id}", s.project)
+	mux.handle("GET", "/projects/{id}/task_assignments", s.taskAssignments)
+	mux.handle("GET", "/task_assignments", s.taskAssignments)
+	mux.handle("GET", "/invoices", s.invoices)
+	mux.handle("GET", "/invoices/{id}/payments", s.payments)
+	mux.handle("GET", "/time_entries", s.timeEntries)
+	mux.handle("POST", "/time_entries", s.createTimeEntry)
+	mux.handle("GET", "/time_entries/{id}", s.getTimeEntry)
+	mux.handle("PATCH", "/time_entries/{id}", s.updateTimeEntry)
+	mux.handle("DELETE", "/time_entries/{id}", s.deleteTimeEntry)
+	mux.handle("PATCH", "/time_entries/{id}/restart", s.restartTimeEntry)
+	mux.handle("PATCH", "/time_entries/{id}/stop", s.stopTimeEntry)
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Options configures a harvest client to talk to this server.
+func (s *Server) Options() []harvest.Option {
+	return options(s.Server)
+}
+
+// AddTimeEntry adds an entry, assigning it an id if it has none.
+func (s *Server) AddTimeEntry(e *harvest.TimeEntry) *harvest.TimeEntry {
+	s.Lock()
+	defer s.Unlock()
+	if e.ID == 0 {
+		e.ID = s.id()
+	}
+	s.TimeEntries = append(s.TimeEntries, e)
+	return e
+}
+
+func (s *Server) id() int {
+	s.nextID++
+	return s.nextID
+}
+
+func (s *Server) findUser(id int) *harvest.User {
+	if s.Me != nil && s.Me.ID == id {
+		return s.Me
+	}
+	for _, u := range s.Users {
+		if u.ID == id {
+			return u
+		}
+	}
+	return nil
+}
+
+func (s *Server) findProject(id int) *harvest.Project {
+	for _, p := range s.Projects {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (s *Server) findTimeEntry(w http.ResponseWriter, rawID string) (int, *harvest.TimeEntry) {
+	id, ok := pathID(w, rawID)
+	if !ok {
+		return 0, nil
+	}
+	for i, e := range s.TimeEntries {
+		if e.ID == id {
+			return i, e
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not found")
+	return 0, nil
+}
+
+func (s *Server) company(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	writeJSON(w, http.StatusOK, s.Company)
+}
+
+func (s *Server) me(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	if s.Me == nil {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.Me)
+}
+
+func (s *Server) user(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	id, ok := pathID(w, rawID)
+	if !ok {
+		return
+	}
+	u := s.findUser(id)
+	if u == nil {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, u)
+}
+
+func (s *Server) users(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	active := r.URL.Query().Get("is_active")
+	list := make([]*harvest.User, 0, len(s.Users))
+	for _, u := range s.Users {
+		if active == "" || active == strconv.FormatBool(u.Active) {
+			list = append(list, u)
+		}
+	}
+
+	items, p := paginate(r, list)
+	writeJSON(w, http.StatusOK, struct {
+		page
+		Users []*harvest.User `json:"users"`
+	}{p, items})
+}
+
+func (s *Server) userAssignments(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	id, ok := pathID(w, rawID)
+	if !ok {
+		return
+	}
+
+	items, p := paginate(r, s.UserAssignments[id])
+	writeJSON(w, http.StatusOK, struct {
+		page
+		Assignments []*harvest.UserAssignment `json:"project_assignments"`
+	}{p, items})
+}
+
+func (s *Server) myAssignments(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	if s.Me == nil {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	items, p := paginate(r, s.UserAssignments[s.Me.ID])
+	writeJSON(w, http.StatusOK, struct {
+		page
+		Assignments []*harvest.UserAssignment `json:"project_assignments"`
+	}{p, items})
+}
+
+func (s *Server) teammates(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	id, ok := pathID(w, rawID)
+	if !ok {
+		return
+	}
+
+	items, p := paginate(r, s.Teammates[id])
+	writeJSON(w, http.StatusOK, struct {
+		page
+		Teammates []*harvest.Teammate `json:"teammates"`
+	}{p, items})
+}
+
+func (s *Server) costRates(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	id, ok := pathID(w, rawID)
+	if !ok {
+		return
+	}
+
+	items, p := paginate(r, s.CostRates[id])
+	writeJSON(w, http.StatusOK, struct {
+		page
+		Rates []*harvest.Rate `json:"cost_rates"`
+	}{p, items})
+}
+
+func (s *Server) billableRates(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	id, ok := pathID(w, rawID)
+	if !ok {
+		return
+	}
+
+	items, p := paginate(r, s.BillableRates[id])
+	writeJSON(w, http.StatusOK, struct {
+		page
+		Rates []*harvest.Rate `json:"billable_rates"`
+	}{p, items})
+}
+
+func (s *Server) invoices(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	q := r.URL.Query()
+	list := make([]*harvest.Invoice, 0, len(s.Invoices))
+	for _, i := range s.Invoices {
+		if state := q.Get("state"); state != "" && state != i.State {
+			continue
+		}
+		if client := q.Get("client_id"); client != "" && client != strconv.Itoa(i.Client.ID) {
+			continue
+		}
+		list = append(list, i)
+	}
+
+	items, p := paginate(r, list)
+	writeJSON(w, http.StatusOK, struct {
+		page
+		Invoices []*harvest.Invoice `json:"invoices"`
+	}{p, items})
+}
+
+func (s *Server) payments(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	id, ok := pathID(w, rawID)
+	if !ok {
+		return
+	}
+
+	items, p := paginate(r, s.Payments[id])
+	writeJSON(w, http.StatusOK, struct {
+		page
+		Payments []*harvest.InvoicePayment `json:"invoice_payments"`
+	}{p, items})
+}
+
+func (s *Server) roles(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	items, p := paginate(r, s.Roles)
+	writeJSON(w, http.StatusOK, struct {
+		page
+		Roles []*harvest.Role `json:"roles"`
+	}{p, items})
+}
+
+func (s *Server) clients(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	active := r.URL.Query().Get("is_active")
+	list := make([]*harvest.Client, 0, len(s.Clients))
+	for _, c := range s.Clients {
+		if active == "" || active == strconv.FormatBool(c.Active) {
+			list = append(list, c)
+		}
+	}
+
+	items, p := paginate(r, list)
+	writeJSON(w, http.StatusOK, struct {
+		page
+		Clients []*harvest.Client `json:"clients"`
+	}{p, items})
+}
+
+func (s *Server) projects(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	q := r.URL.Query()
+	active := q.Get("is_active")
+	client := q.Get("client_id")
+	list := make([]*harvest.Project, 0, len(s.Projects))
+	for _, pr := range s.Projects {
+		if active != "" && active != strconv.FormatBool(pr.Active) {
+			continue
+		}
+		if client != "" && client != strconv.Itoa(pr.Client.ID) {
+			continue
+		}
+		list = append(list, pr)
+	}
+
+	items, p := paginate(r, list)
+	writeJSON(w, http.StatusOK, struct {
+		page
+		Projects []*harvest.Project `json:"projects"`
+	}{p, items})
+}
+
+func (s *Server) project(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	id, ok := pathID(w, rawID)
+	if !ok {
+		return
+	}
+	pr := s.findProject(id)
+	if pr == nil {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, pr)
+}
+
+func (s *Server) taskAssignments(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	project := 0
+	if rawID != "" {
+		var ok bool
+		if project, ok = pathID(w, rawID); !ok {
+			return
+		}
+	}
+
+	active := r.URL.Query().Get("is_active")
+	list := make([]*harvest.TaskAssignment, 0, len(s.TaskAssignments))
+	for _, ta := range s.TaskAssignments {
+		if project != 0 && (ta.Project == nil || ta.Project.ID != project) {
+			continue
+		}
+		if active != "" && active != strconv.FormatBool(ta.Active) {
+			continue
+		}
+		list = append(list, ta)
+	}
+
+	items, p := paginate(r, list)
+	writeJSON(w, http.StatusOK, struct {
+		page
+		TaskAssignments []*harvest.TaskAssignment `json:"task_assignments"`
+	}{p, items})
+}
+
+func (s *Server) timeEntries(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	q := r.URL.Query()
+	var from, to time.Time
+	var err error
+	if v := q.Get("from"); v != "" {
+		if from, err = time.Parse(harvest.TimeFormatDate, v); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid from")
+			return
+		}
+	}
+	if v := q.Get("to"); v != "" {
+		if to, err = time.Parse(harvest.TimeFormatDate, v); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid to")
+			return
+		}
+	}
+
+	match := func(key string, v string) bool {
+		f := q.Get(key)
+		return f == "" || f == v
+	}
+
+	list := make(harvest.TimeEntries, 0, len(s.TimeEntries))
+	for _, e := range s.TimeEntries {
+		if !match("user_id", strconv.Itoa(e.User.ID)) ||
+			!match("client_id", strconv.Itoa(e.Client.ID)) ||
+			!match("project_id", strconv.Itoa(e.Project.ID)) ||
+			!match("is_billed", strconv.FormatBool(e.Billed)) ||
+			!match("is_running", strconv.FormatBool(e.Running)) {
+			continue
+		}
+		if e.SpentDate != nil {
+			d, _ := time.Parse(harvest.TimeFormatDate, e.SpentDate.Format(harvest.TimeFormatDate))
+			if (!from.IsZero() && d.Before(from)) || (!to.IsZero() && d.After(to)) {
+				continue
+			}
+		}
+		list = append(list, e)
+	}
+
+	// Like harvest, most recent first.
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].SpentDate == nil || list[j].SpentDate == nil {
+			return list[j].SpentDate == nil && list[i].SpentDate != nil
+		}
+		return list[i].SpentDate.After(list[j].SpentDate.Time)
+	})
+
+	items, p := paginate(r, list)
+	writeJSON(w, http.StatusOK, struct {
+		page
+		TimeEntries []*harvest.TimeEntry `json:"time_entries"`
+	}{p, items})
+}
+
+func (s *Server) createTimeEntry(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	body := &harvest.CreateTimeEntryBody{}
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user := s.Me
+	if body.UserID != nil {
+		user = s.findUser(*body.UserID)
+	}
+	project := s.findProject(body.ProjectID)
+	if user == nil || project == nil {
+		writeError(w, http.StatusUnprocessableEntity, "Unknown user or project")
+		return
+	}
+
+	date := body.SpentDate
+	e := &harvest.TimeEntry{
+		ID:        s.id(),
+		User:      harvest.UserRef{ID: user.ID, Name: user.FirstName + " " + user.LastName},
+		Client:    project.Client,
+		Project:   harvest.ProjectRef{ID: project.ID, Name: project.Name, Code: project.Code},
+		SpentDate: &date,
+		Billable:  project.Billable,
+	}
+	for _, ta := range s.TaskAssignments {
+		if ta.Task.ID == body.TaskID && (ta.Project == nil || ta.Project.ID == project.ID) {
+			e.Task = ta.Task
+			e.TaskAssignment = *ta
+			e.Billable = e.Billable && ta.Billable
+			e.BillableRate = ta.HourlyRate
+			break
+		}
+	}
+	if body.Notes != nil {
+		e.Notes = *body.Notes
+	}
+	if body.ExternalReference != nil {
+		e.ExternalReference = *body.ExternalReference
+	}
+	if body.Hours != nil {
+		e.Hours.Duration = time.Duration(*body.Hours * float64(time.Hour))
+	} else {
+		s.startTimer(e)
+	}
+
+	s.TimeEntries = append(s.TimeEntries, e)
+	writeJSON(w, http.StatusCreated, e)
+}
+
+func (s *Server) getTimeEntry(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	if _, e := s.findTimeEntry(w, rawID); e != nil {
+		writeJSON(w, http.StatusOK, e)
+	}
+}
+
+func (s *Server) updateTimeEntry(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	_, e := s.findTimeEntry(w, rawID)
+	if e == nil {
+		return
+	}
+
+	body := &harvest.UpdateTimeEntryBody{}
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if body.ProjectID != nil {
+		project := s.findProject(*body.ProjectID)
+		if project == nil {
+			writeError(w, http.StatusUnprocessableEntity, "Unknown project")
+			return
+		}
+		e.Project = harvest.ProjectRef{ID: project.ID, Name: project.Name, Code: project.Code}
+		e.Client = project.Client
+	}
+	if body.TaskID != nil {
+		for _, ta := range s.TaskAssignments {
+			if ta.Task.ID == *body.TaskID {
+				e.Task = ta.Task
+				break
+			}
+		}
+	}
+	if body.SpentDate != nil {
+		e.SpentDate = body.SpentDate
+	}
+	if body.Hours != nil {
+		e.Hours.Duration = time.Duration(*body.Hours * float64(time.Hour))
+	}
+	if body.Notes != nil {
+		e.Notes = *body.Notes
+	}
+
+	writeJSON(w, http.StatusOK, e)
+}
+
+func (s *Server) deleteTimeEntry(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	i, e := s.findTimeEntry(w, rawID)
+	if e == nil {
+		return
+	}
+
+	s.TimeEntries = append(s.TimeEntries[:i], s.TimeEntries[i+1:]...)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) restartTimeEntry(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	_, e := s.findTimeEntry(w, rawID)
+	if e == nil {
+		return
+	}
+	if e.Running {
+		writeError(w, http.StatusUnprocessableEntity, "Time entry is already running")
+		return
+	}
+
+	s.startTimer(e)
+	writeJSON(w, http.StatusOK, e)
+}
+
+func (s *Server) stopTimeEntry(w http.ResponseWriter, r *http.Request, rawID string) {
+	s.Lock()
+	defer s.Unlock()
+	_, e := s.findTimeEntry(w, rawID)
+	if e == nil {
+		return
+	}
+	if !e.Running {
+		writeError(w, http.StatusUnprocessableEntity, "Time entry is not running")
+		return
+	}
+
+	s.stopTimer(e)
+	writeJSON(w, http.StatusOK, e)
+}
+
+// startTimer stops the running timer of the user and starts e.
+func (s *Server) startTimer(e *harvest.TimeEntry) {
+	for _, o := range s.TimeEntries {
+		if o.Running && o.User.ID == e.User.ID {
+			s.stopTimer(o)
+		}
+	}
+
+	e.Running = true
+	e.TimerStartedAt = &harvest.DateTime{s.Now()}
+}
+
+func (s *Server) stopTimer(e *harvest.TimeEntry) {
+	if e.TimerStartedAt != nil {
+		e.Hours.Duration += s.Now().Sub(e.TimerStartedAt.Time)
+	}
+	e.Running = false
+	e.TimerStartedAt = nil
+}
@@ -0,0 +1,230 @@
+package harvesttest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
+
+func TestServerPaginate(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	start := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 25; i++ {
+		s.AddTimeEntry(&harvest.TimeEntry{SpentDate: &harvest.Date{start.AddDate(0, 0, i)}})
+	}
+
+	h := harvest.New(1, "token", s.Options()...)
+	perPage := 10
+	tests := []struct {
+		page    int
+		entries int
+		next    *int
+		first   string
+	}{
+		{1, 10, intp(2), "2024-03-25"},
+		{2, 10, intp(3), "2024-03-15"},
+		{3, 5, nil, "2024-03-05"},
+		{4, 0, nil, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(strconv.Itoa(test.page), func(t *testing.T) {
+			page := test.page
+			res, err := h.GetTimeEntries(context.Background(), &harvest.TimeEntriesParams{Page: &page, PerPage: &perPage})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.TotalEntries != 25 || res.TotalPages != 3 || res.Page != test.page {
+				t.Errorf("page %d = %d entries in %d pages, page %d", test.page, res.TotalEntries, res.TotalPages, res.Page)
+			}
+			if len(res.TimeEntries) != test.entries {
+				t.Errorf("page %d has %d entries, want %d", test.page, len(res.TimeEntries), test.entries)
+			}
+			if (res.NextPage == nil) != (test.next == nil) || (res.NextPage != nil && *res.NextPage != *test.next) {
+				t.Errorf("page %d next = %v, want %v", test.page, res.NextPage, test.next)
+			}
+			if test.first != "" && res.TimeEntries[0].SpentDate.Format(harvest.TimeFormatDate) != test.first {
+				t.Errorf(
+					"page %d starts at %s, want %s",
+					test.page,
+					res.TimeEntries[0].SpentDate.Format(harvest.TimeFormatDate),
+					test.first,
+				)
+			}
+		})
+	}
+}
+
+func TestServerFilterTimeEntries(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	date := func(s string) *harvest.Date {
+		d, err := time.Parse(harvest.TimeFormatDate, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &harvest.Date{d}
+	}
+
+	s.AddTimeEntry(&harvest.TimeEntry{ID: 1, User: harvest.UserRef{ID: 1}, Project: harvest.ProjectRef{ID: 10}, SpentDate: date("2024-03-04")})
+	s.AddTimeEntry(&harvest.TimeEntry{ID: 2, User: harvest.UserRef{ID: 1}, Project: harvest.ProjectRef{ID: 20}, SpentDate: date("2024-03-05"), Billed: true})
+	s.AddTimeEntry(&harvest.TimeEntry{ID: 3, User: harvest.UserRef{ID: 2}, Project: harvest.ProjectRef{ID: 10}, SpentDate: date("2024-03-06"), Running: true})
+
+	h := harvest.New(1, "token", s.Options()...)
+	yes := true
+	tests := []struct {
+		name   string
+		params *harvest.TimeEntriesParams
+		want   []int
+	}{
+		{"all", &harvest.TimeEntriesParams{}, []int{3, 2, 1}},
+		{"user", &harvest.TimeEntriesParams{UserID: intp(1)}, []int{2, 1}},
+		{"project", &harvest.TimeEntriesParams{ProjectID: intp(10)}, []int{3, 1}},
+		{"billed", &harvest.TimeEntriesParams{Billed: &yes}, []int{2}},
+		{"running", &harvest.TimeEntriesParams{Running: &yes}, []int{3}},
+		{"from", &harvest.TimeEntriesParams{From: &date("2024-03-05").Time}, []int{3, 2}},
+		{"to", &harvest.TimeEntriesParams{To: &date("2024-03-05").Time}, []int{2, 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := h.GetTimeEntries(context.Background(), test.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]int, 0, len(res.TimeEntries))
+			for _, e := range res.TimeEntries {
+				got = append(got, e.ID)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("GetTimeEntries = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestServerTimeEntryLifecycle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	now := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+	s.Now = func() time.Time { return now }
+	s.Me = &harvest.User{ID: 1, FirstName: "Bob"}
+	s.Projects = []*harvest.Project{{ID: 10, Name: "Acme", Billable: true}}
+	s.TaskAssignments = []*harvest.TaskAssignment{{Task: harvest.TaskRef{ID: 20, Name: "Dev"}, Billable: true, HourlyRate: 100}}
+
+	ctx := context.Background()
+	h := harvest.New(1, "token", s.Options()...)
+
+	if _, err := h.CreateTimeEntry(ctx, &harvest.CreateTimeEntryBody{ProjectID: 99, TaskID: 20}); err == nil {
+		t.Error("CreateTimeEntry of an unknown project did not fail")
+	}
+
+	notes := "ACME-1"
+	e, err := h.CreateTimeEntry(ctx, &harvest.CreateTimeEntryBody{
+		ProjectID:         10,
+		TaskID:            20,
+		SpentDate:         harvest.Date{now},
+		Notes:             &notes,
+		ExternalReference: &harvest.ExternalReference{ID: "ext-1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.Running || e.User.ID != 1 || e.Task.Name != "Dev" || !e.Billable || e.BillableRate != 100 {
+		t.Errorf("CreateTimeEntry = %+v, want a running billable Dev entry of user 1", e)
+	}
+	if e.ExternalReference.ID != "ext-1" {
+		t.Errorf("CreateTimeEntry external reference = %q, want ext-1", e.ExternalReference.ID)
+	}
+
+	if _, err := h.RestartTimeEntry(ctx, e.ID); err == nil {
+		t.Error("RestartTimeEntry of a running entry did not fail")
+	}
+
+	now = now.Add(90 * time.Minute)
+	e, err = h.StopTimeEntry(ctx, e.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Running || e.Hours.Duration != 90*time.Minute {
+		t.Errorf("StopTimeEntry = %s running %t, want 1h30m stopped", e.Hours.Duration, e.Running)
+	}
+	if _, err := h.StopTimeEntry(ctx, e.ID); err == nil {
+		t.Error("StopTimeEntry of a stopped entry did not fail")
+	}
+
+	hours := 2.0
+	other, err := h.CreateTimeEntry(ctx, &harvest.CreateTimeEntryBody{ProjectID: 10, TaskID: 20, Hours: &hours})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Running || other.Hours.Duration != 2*time.Hour {
+		t.Errorf("CreateTimeEntry with hours = %s running %t, want 2h stopped", other.Hours.Duration, other.Running)
+	}
+
+	// Restarting an entry stops the other timers of the user.
+	if _, err := h.RestartTimeEntry(ctx, other.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.RestartTimeEntry(ctx, e.ID); err != nil {
+		t.Fatal(err)
+	}
+	if other, err = h.GetTimeEntry(ctx, other.ID); err != nil {
+		t.Fatal(err)
+	}
+	if other.Running {
+		t.Error("restarting an entry did not stop the running timer")
+	}
+
+	updated := "ACME-2"
+	e, err = h.UpdateTimeEntry(ctx, e.ID, &harvest.UpdateTimeEntryBody{Notes: &updated, Hours: &hours})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Notes != updated || e.Hours.Duration != 2*time.Hour {
+		t.Errorf("UpdateTimeEntry = %q %s, want %q 2h", e.Notes, e.Hours.Duration, updated)
+	}
+
+	if err := h.DeleteTimeEntry(ctx, e.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.GetTimeEntry(ctx, e.ID); !errors.Is(err, harvest.ErrNotFound) {
+		t.Errorf("GetTimeEntry of a deleted entry = %v, want %v", err, harvest.ErrNotFound)
+	}
+	if err := h.DeleteTimeEntry(ctx, e.ID); !errors.Is(err, harvest.ErrNotFound) {
+		t.Errorf("DeleteTimeEntry of a deleted entry = %v, want %v", err, harvest.ErrNotFound)
+	}
+}
+
+func TestServerMe(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	ctx := context.Background()
+	h := harvest.New(1, "token", s.Options()...)
+	if _, err := h.GetMe(ctx); !errors.Is(err, harvest.ErrUnauthorized) {
+		t.Errorf("GetMe without a user = %v, want %v", err, harvest.ErrUnauthorized)
+	}
+
+	s.Me = &harvest.User{ID: 7, FirstName: "Bob"}
+	me, err := h.GetMe(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if me.ID != 7 || me.FirstName != "Bob" {
+		t.Errorf("GetMe = %d %s, want 7 Bob", me.ID, me.FirstName)
+	}
+}
+
+func intp(i int) *int {
+	return &i
+}
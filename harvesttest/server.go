@@ -0,0 +1,138 @@
+// Package harvesttest provides in-memory fakes of the harvest and forecast
+// apis for integration tests.
+//
+//	s := harvesttest.NewServer()
+//	defer s.Close()
+//	s.Me = &harvest.User{ID: 1, FirstName: "Bob"}
+//	h := harvest.New(1, "token", s.Options()...)
+package harvesttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
+
+// DefaultPerPage is the page size used when a request does not specify one.
+const DefaultPerPage = 100
+
+func options(s *httptest.Server) []harvest.Option {
+	return []harvest.Option{
+		harvest.WithBaseURL(s.URL),
+		harvest.WithHTTPClient(s.Client()),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"message": msg})
+}
+
+// route is a handler for a method and path pattern, a {id} segment in the
+// pattern matches any single segment which is passed on to the handler.
+type route struct {
+	method  string
+	pattern []string
+	handler func(w http.ResponseWriter, r *http.Request, rawID string)
+}
+
+type router []route
+
+func (rt *router) handle(method, pattern string, h func(w http.ResponseWriter, r *http.Request, rawID string)) {
+	*rt = append(*rt, route{method, strings.Split(strings.Trim(pattern, "/"), "/"), h})
+}
+
+func (rt router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	for _, rte := range rt {
+		if rte.method != r.Method || len(rte.pattern) != len(parts) {
+			continue
+		}
+
+		id, ok := "", true
+		for i, p := range rte.pattern {
+			switch {
+			case p == "{id}":
+				id = parts[i]
+			case p != parts[i]:
+				ok = false
+			}
+		}
+		if ok {
+			rte.handler(w, r, id)
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "Not found")
+}
+
+func pathID(w http.ResponseWriter, rawID string) (int, bool) {
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Not found")
+		return 0, false
+	}
+	return id, true
+}
+
+type page struct {
+	PerPage      int  `json:"per_page"`
+	TotalPages   int  `json:"total_pages"`
+	TotalEntries int  `json:"total_entries"`
+	NextPage     *int `json:"next_page"`
+	PreviousPage *int `json:"previous_page"`
+	Page         int  `json:"page"`
+}
+
+// paginate slices items according to the page and per_page query
+// parameters and returns the page metadata harvest responds with.
+func paginate[T any](r *http.Request, items []T) ([]T, page) {
+	q := r.URL.Query()
+	perPage, _ := strconv.Atoi(q.Get("per_page"))
+	if perPage < 1 {
+		perPage = DefaultPerPage
+	}
+	n, _ := strconv.Atoi(q.Get("page"))
+	if n < 1 {
+		n = 1
+	}
+
+	p := page{
+		PerPage:      perPage,
+		TotalEntries: len(items),
+		TotalPages:   (len(items) + perPage - 1) / perPage,
+		Page:         n,
+	}
+	if p.TotalPages == 0 {
+		p.TotalPages = 1
+	}
+	if n < p.TotalPages {
+		next := n + 1
+		p.NextPage = &next
+	}
+	if n > 1 {
+		prev := n - 1
+		p.PreviousPage = &prev
+	}
+
+	from := (n - 1) * perPage
+	if from > len(items) {
+		from = len(items)
+	}
+	to := from + perPage
+	if to > len(items) {
+		to = len(items)
+	}
+
+	return items[from:to], p
+}
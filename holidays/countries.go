@@ -0,0 +1,88 @@
+package holidays
+
+import "time"
+
+func init() {
+	Register("BE", Rules{
+		Fixed(time.January, 1, "New Year's Day"),
+		EasterOffset(1, "Easter Monday"),
+		Fixed(time.May, 1, "Labour Day"),
+		EasterOffset(39, "Ascension Day"),
+		EasterOffset(50, "Whit Monday"),
+		Fixed(time.July, 21, "National Day"),
+		Fixed(time.August, 15, "Assumption Day"),
+		Fixed(time.November, 1, "All Saints' Day"),
+		Fixed(time.November, 11, "Armistice Day"),
+		Fixed(time.December, 25, "Christmas Day"),
+	})
+
+	Register("NL", Rules{
+		Fixed(time.January, 1, "New Year's Day"),
+		EasterOffset(1, "Easter Monday"),
+		kingsDay,
+		EasterOffset(39, "Ascension Day"),
+		EasterOffset(50, "Whit Monday"),
+		Fixed(time.December, 25, "Christmas Day"),
+		Fixed(time.December, 26, "Second Day of Christmas"),
+	})
+
+	Register("DE", Rules{
+		Fixed(time.January, 1, "New Year's Day"),
+		EasterOffset(-2, "Good Friday"),
+		EasterOffset(1, "Easter Monday"),
+		Fixed(time.May, 1, "Labour Day"),
+		EasterOffset(39, "Ascension Day"),
+		EasterOffset(50, "Whit Monday"),
+		Fixed(time.October, 3, "German Unity Day"),
+		Fixed(time.December, 25, "Christmas Day"),
+		Fixed(time.December, 26, "Second Day of Christmas"),
+	})
+
+	Register("FR", Rules{
+		Fixed(time.January, 1, "New Year's Day"),
+		EasterOffset(1, "Easter Monday"),
+		Fixed(time.May, 1, "Labour Day"),
+		Fixed(time.May, 8, "Victory in Europe Day"),
+		EasterOffset(39, "Ascension Day"),
+		EasterOffset(50, "Whit Monday"),
+		Fixed(time.July, 14, "Bastille Day"),
+		Fixed(time.August, 15, "Assumption Day"),
+		Fixed(time.November, 1, "All Saints' Day"),
+		Fixed(time.November, 11, "Armistice Day"),
+		Fixed(time.December, 25, "Christmas Day"),
+	})
+
+	Register("GB", Rules{
+		Fixed(time.January, 1, "New Year's Day"),
+		EasterOffset(-2, "Good Friday"),
+		EasterOffset(1, "Easter Monday"),
+		NthWeekday(time.May, time.Monday, 1, "Early May Bank Holiday"),
+		NthWeekday(time.May, time.Monday, -1, "Spring Bank Holiday"),
+		NthWeekday(time.August, time.Monday, -1, "Summer Bank Holiday"),
+		Fixed(time.December, 25, "Christmas Day"),
+		Fixed(time.December, 26, "Boxing Day"),
+	})
+
+	Register("US", Rules{
+		Fixed(time.January, 1, "New Year's Day"),
+		NthWeekday(time.January, time.Monday, 3, "Martin Luther King Jr. Day"),
+		NthWeekday(time.February, time.Monday, 3, "Presidents' Day"),
+		NthWeekday(time.May, time.Monday, -1, "Memorial Day"),
+		Since(2021, Fixed(time.June, 19, "Juneteenth")),
+		Fixed(time.July, 4, "Independence Day"),
+		NthWeekday(time.September, time.Monday, 1, "Labor Day"),
+		NthWeekday(time.October, time.Monday, 2, "Columbus Day"),
+		Fixed(time.November, 11, "Veterans Day"),
+		NthWeekday(time.November, time.Thursday, 4, "Thanksgiving Day"),
+		Fixed(time.December, 25, "Christmas Day"),
+	})
+}
+
+// kingsDay is moved to the 26th when the 27th of april is a sunday.
+func kingsDay(year int) (Holiday, bool) {
+	d := date(year, time.April, 27)
+	if d.Weekday() == time.Sunday {
+		d = d.AddDate(0, 0, -1)
+	}
+	return Holiday{d, "King's Day"}, year >= 2014
+}
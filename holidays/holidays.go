@@ -0,0 +1,127 @@
+// Package holidays computes public holidays per country.
+package holidays
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+type Holiday struct {
+	Date time.Time
+	Name string
+}
+
+// Provider returns the public holidays of a given year.
+type Provider interface {
+	Holidays(year int) []Holiday
+}
+
+var providers = map[string]Provider{}
+
+// Register makes a provider available under the given (country) code.
+func Register(code string, p Provider) {
+	providers[strings.ToUpper(code)] = p
+}
+
+// Get returns the provider registered for code.
+func Get(code string) (Provider, bool) {
+	p, ok := providers[strings.ToUpper(code)]
+	return p, ok
+}
+
+// Codes returns all registered codes.
+func Codes() []string {
+	codes := make([]string, 0, len(providers))
+	for c := range providers {
+		codes = append(codes, c)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Rule calculates a single holiday for a year, ok is false
+// if the holiday does not occur in that year.
+type Rule func(year int) (h Holiday, ok bool)
+
+// Rules is a Provider built from a list of rules.
+type Rules []Rule
+
+func (r Rules) Holidays(year int) []Holiday {
+	list := make([]Holiday, 0, len(r))
+	for _, rule := range r {
+		if h, ok := rule(year); ok {
+			list = append(list, h)
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Date.Before(list[j].Date) })
+	return list
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// Fixed is a holiday on the same date every year.
+func Fixed(month time.Month, day int, name string) Rule {
+	return func(year int) (Holiday, bool) {
+		return Holiday{date(year, month, day), name}, true
+	}
+}
+
+// Since limits a rule to years starting at first.
+func Since(first int, r Rule) Rule {
+	return func(year int) (Holiday, bool) {
+		if year < first {
+			return Holiday{}, false
+		}
+		return r(year)
+	}
+}
+
+// EasterOffset is a holiday a number of days relative to easter sunday.
+func EasterOffset(days int, name string) Rule {
+	return func(year int) (Holiday, bool) {
+		return Holiday{Easter(year).AddDate(0, 0, days), name}, true
+	}
+}
+
+// NthWeekday is the nth weekday of a month (e.g. the 3rd monday of january),
+// a negative n counts from the end of the month (-1 is the last).
+func NthWeekday(month time.Month, weekday time.Weekday, n int, name string) Rule {
+	return func(year int) (Holiday, bool) {
+		if n < 0 {
+			d := date(year, month+1, 1).AddDate(0, 0, -1)
+			for d.Weekday() != weekday {
+				d = d.AddDate(0, 0, -1)
+			}
+			return Holiday{d.AddDate(0, 0, 7*(n+1)), name}, true
+		}
+
+		d := date(year, month, 1)
+		for d.Weekday() != weekday {
+			d = d.AddDate(0, 0, 1)
+		}
+		return Holiday{d.AddDate(0, 0, 7*(n-1)), name}, true
+	}
+}
+
+// Easter returns easter sunday of the given year (gregorian calendar).
+func Easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
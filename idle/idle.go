@@ -0,0 +1,32 @@
+// Package idle reports how long the machine has been without keyboard or
+// mouse input by shelling out to the platform's native tooling (ioreg on
+// macOS, xprintidle or the gnome idle monitor on linux, powershell on windows).
+package idle
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+var ErrUnsupported = errors.New("Idle detection is not supported on this platform")
+
+// Duration returns the time since the last user input.
+func Duration() (time.Duration, error) {
+	return duration()
+}
+
+func output(name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	out, err := cmd.Output()
+	if err != nil {
+		if e, ok := err.(*exec.ExitError); ok && len(e.Stderr) != 0 {
+			return "", fmt.Errorf("%s: %s", name, strings.TrimSpace(string(e.Stderr)))
+		}
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,29 @@
+package idle
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var hidIdleRe = regexp.MustCompile(`"HIDIdleTime" = (\d+)`)
+
+func duration() (time.Duration, error) {
+	out, err := output("ioreg", "-c", "IOHIDSystem", "-d", "4")
+	if err != nil {
+		return 0, err
+	}
+
+	m := hidIdleRe.FindStringSubmatch(out)
+	if m == nil {
+		return 0, fmt.Errorf("ioreg: no HIDIdleTime")
+	}
+
+	ns, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(ns), nil
+}
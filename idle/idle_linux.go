@@ -0,0 +1,46 @@
+package idle
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var mutterRe = regexp.MustCompile(`uint64 (\d+)`)
+
+// duration asks xprintidle on X11 and the gnome idle monitor otherwise,
+// both report milliseconds.
+func duration() (time.Duration, error) {
+	var ms string
+	if _, err := exec.LookPath("xprintidle"); err == nil {
+		out, err := output("xprintidle")
+		if err != nil {
+			return 0, err
+		}
+		ms = out
+	} else {
+		out, err := output(
+			"gdbus", "call", "--session",
+			"--dest", "org.gnome.Mutter.IdleMonitor",
+			"--object-path", "/org/gnome/Mutter/IdleMonitor/Core",
+			"--method", "org.gnome.Mutter.IdleMonitor.GetIdletime",
+		)
+		if err != nil {
+			return 0, fmt.Errorf("%w (install xprintidle on X11)", err)
+		}
+		m := mutterRe.FindStringSubmatch(out)
+		if m == nil {
+			return 0, fmt.Errorf("gdbus: unexpected idle time '%s'", out)
+		}
+		ms = m[1]
+	}
+
+	n, err := strconv.ParseInt(ms, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(n) * time.Millisecond, nil
+}
@@ -0,0 +1,9 @@
+//go:build !darwin && !linux && !windows
+
+package idle
+
+import "time"
+
+func duration() (time.Duration, error) {
+	return 0, ErrUnsupported
+}
@@ -0,0 +1,40 @@
+package idle
+
+import (
+	"strconv"
+	"time"
+)
+
+const lastInput = `
+Add-Type @'
+using System;
+using System.Runtime.InteropServices;
+public static class Idle {
+    [StructLayout(LayoutKind.Sequential)]
+    struct LASTINPUTINFO { public uint cbSize; public uint dwTime; }
+    [DllImport("user32.dll")]
+    static extern bool GetLastInputInfo(ref LASTINPUTINFO plii);
+    public static uint Millis() {
+        LASTINPUTINFO i = new LASTINPUTINFO();
+        i.cbSize = (uint)Marshal.SizeOf(i);
+        GetLastInputInfo(ref i);
+        return (uint)Environment.TickCount - i.dwTime;
+    }
+}
+'@
+[Idle]::Millis()
+`
+
+func duration() (time.Duration, error) {
+	out, err := output("powershell", "-NoProfile", "-NonInteractive", "-Command", lastInput)
+	if err != nil {
+		return 0, err
+	}
+
+	ms, err := strconv.ParseUint(out, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(ms) * time.Millisecond, nil
+}
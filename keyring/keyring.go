@@ -0,0 +1,63 @@
+// Package keyring stores secrets in the operating system's credential store
+// by shelling out to the platform's native tooling
+// (security on macOS, secret-tool (libsecret) on linux) or, on windows, by
+// calling the Credential Manager api of advapi32.dll directly.
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+var (
+	ErrNotFound    = errors.New("Secret not found in keyring")
+	ErrUnsupported = errors.New("Keyring is not supported on this platform")
+)
+
+// Get returns the secret stored for service and user.
+func Get(service, user string) (string, error) {
+	return get(service, user)
+}
+
+// Set stores secret for service and user, replacing any existing secret.
+func Set(service, user, secret string) error {
+	return set(service, user, secret)
+}
+
+// Delete removes the secret stored for service and user.
+func Delete(service, user string) error {
+	return del(service, user)
+}
+
+func run(stdin string, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		if e, ok := err.(*exec.ExitError); ok && len(e.Stderr) != 0 {
+			return "", fmt.Errorf("%s: %s", name, strings.TrimSpace(string(e.Stderr)))
+		}
+		return "", err
+	}
+
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// lookup runs a lookup command, a non-zero exit code means the secret
+// does not exist.
+func lookup(name string, args ...string) (string, error) {
+	s, err := run("", name, args...)
+	if err != nil {
+		if _, ok := err.(*exec.Error); ok {
+			return "", err
+		}
+		return "", ErrNotFound
+	}
+
+	return s, nil
+}
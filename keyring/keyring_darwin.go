@@ -0,0 +1,25 @@
+package keyring
+
+func get(service, user string) (string, error) {
+	return lookup("security", "find-generic-password", "-s", service, "-a", user, "-w")
+}
+
+// set passes -w without a value as the last option so security reads the
+// secret from stdin instead of it showing up in the process list, it asks
+// for the secret twice.
+func set(service, user, secret string) error {
+	_, err := run(
+		secret+"\n"+secret+"\n",
+		"security", "add-generic-password",
+		"-U",
+		"-s", service,
+		"-a", user,
+		"-w",
+	)
+	return err
+}
+
+func del(service, user string) error {
+	_, err := run("", "security", "delete-generic-password", "-s", service, "-a", user)
+	return err
+}
@@ -0,0 +1,21 @@
+package keyring
+
+func get(service, user string) (string, error) {
+	return lookup("secret-tool", "lookup", "service", service, "username", user)
+}
+
+func set(service, user, secret string) error {
+	_, err := run(
+		secret,
+		"secret-tool", "store",
+		"--label", service+" "+user,
+		"service", service,
+		"username", user,
+	)
+	return err
+}
+
+func del(service, user string) error {
+	_, err := run("", "secret-tool", "clear", "service", service, "username", user)
+	return err
+}
@@ -0,0 +1,112 @@
+package keyring
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	errorNotFound           = syscall.Errno(1168)
+)
+
+var (
+	advapi32        = syscall.NewLazyDLL("advapi32.dll")
+	procCredReadW   = advapi32.NewProc("CredReadW")
+	procCredWriteW  = advapi32.NewProc("CredWriteW")
+	procCredDeleteW = advapi32.NewProc("CredDeleteW")
+	procCredFree    = advapi32.NewProc("CredFree")
+)
+
+// credential is CREDENTIALW of wincred.h.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+func target(service, user string) (*uint16, error) {
+	return syscall.UTF16PtrFromString(service + ":" + user)
+}
+
+// call wraps the error of a Cred* call, which return false on failure.
+func call(proc *syscall.LazyProc, args ...uintptr) error {
+	ok, _, err := proc.Call(args...)
+	if ok != 0 {
+		return nil
+	}
+	if err == errorNotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+func get(service, user string) (string, error) {
+	name, err := target(service, user)
+	if err != nil {
+		return "", err
+	}
+
+	var cred *credential
+	err = call(
+		procCredReadW,
+		uintptr(unsafe.Pointer(name)),
+		credTypeGeneric,
+		0,
+		uintptr(unsafe.Pointer(&cred)),
+	)
+	if err != nil {
+		return "", err
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+
+	if cred.CredentialBlobSize == 0 {
+		return "", nil
+	}
+
+	return string(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), nil
+}
+
+func set(service, user, secret string) error {
+	name, err := target(service, user)
+	if err != nil {
+		return err
+	}
+	username, err := syscall.UTF16PtrFromString(user)
+	if err != nil {
+		return err
+	}
+
+	blob := []byte(secret)
+	cred := &credential{
+		Type:               credTypeGeneric,
+		TargetName:         name,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+		UserName:           username,
+	}
+	if len(blob) != 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+
+	return call(procCredWriteW, uintptr(unsafe.Pointer(cred)), 0)
+}
+
+func del(service, user string) error {
+	name, err := target(service, user)
+	if err != nil {
+		return err
+	}
+
+	return call(procCredDeleteW, uintptr(unsafe.Pointer(name)), credTypeGeneric, 0)
+}
@@ -0,0 +1,30 @@
+// Package notify shows desktop notifications by shelling out to the
+// platform's native tooling (osascript on macOS, notify-send on linux,
+// powershell on windows).
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+var ErrUnsupported = errors.New("Notifications are not supported on this platform")
+
+// Send shows a notification with a title and a message.
+func Send(title, message string) error {
+	return send(title, message)
+}
+
+func run(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if _, err := cmd.Output(); err != nil {
+		if e, ok := err.(*exec.ExitError); ok && len(e.Stderr) != 0 {
+			return fmt.Errorf("%s: %s", name, strings.TrimSpace(string(e.Stderr)))
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,9 @@
+package notify
+
+import "strconv"
+
+func send(title, message string) error {
+	script := "display notification " + strconv.Quote(message) +
+		" with title " + strconv.Quote(title)
+	return run("osascript", "-e", script)
+}
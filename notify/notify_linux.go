@@ -0,0 +1,5 @@
+package notify
+
+func send(title, message string) error {
+	return run("notify-send", "--app-name", "timetracking", title, message)
+}
@@ -0,0 +1,7 @@
+//go:build !darwin && !linux && !windows
+
+package notify
+
+func send(title, message string) error {
+	return ErrUnsupported
+}
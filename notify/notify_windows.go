@@ -0,0 +1,21 @@
+package notify
+
+import "strings"
+
+const toast = `
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+$t = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$x = $t.GetElementsByTagName('text')
+$x.Item(0).AppendChild($t.CreateTextNode('%TITLE%')) | Out-Null
+$x.Item(1).AppendChild($t.CreateTextNode('%MESSAGE%')) | Out-Null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('timetracking').Show([Windows.UI.Notifications.ToastNotification]::new($t))
+`
+
+func send(title, message string) error {
+	quote := strings.NewReplacer("'", "''")
+	script := strings.NewReplacer(
+		"%TITLE%", quote.Replace(title),
+		"%MESSAGE%", quote.Replace(message),
+	).Replace(toast)
+	return run("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+}
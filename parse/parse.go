@@ -0,0 +1,202 @@
+// Package parse reads the durations and dates people type on the command line:
+// 1h30m, 90m, :45, 1:30, 2.5 and 2024-03-01, yesterday, last friday, mon.
+package parse
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateFormat = "2006-01-02"
+
+// maxBareHours is the largest number without unit that is read as hours,
+// anything above it is as likely to be minutes.
+const maxBareHours = 24
+
+var (
+	hoursMinutes = regexp.MustCompile(`^(\d*):(\d{1,2})$`)
+	hoursNoUnit  = regexp.MustCompile(`^\d+h(\d+)$`)
+	hoursDecimal = regexp.MustCompile(`^(\d+|\d*[.,]\d+)$`)
+)
+
+// Hours parses a duration: decimal hours (2.5, 2,5), hours and minutes
+// (1:30, :45) or a go duration (1h30m, 90m, 1h30).
+func Hours(s string) (time.Duration, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	d, err := hours(s)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("Invalid hours '%s' expected e.g. 2.5, 1:30, :45, 1h30m or 90m", s)
+	}
+
+	return d, nil
+}
+
+func hours(s string) (time.Duration, error) {
+	if m := hoursMinutes.FindStringSubmatch(s); m != nil {
+		h := 0
+		if m[1] != "" {
+			h, _ = strconv.Atoi(m[1])
+		}
+		mins, _ := strconv.Atoi(m[2])
+		if mins >= 60 {
+			return 0, fmt.Errorf("Invalid hours '%s', minutes should be below 60", s)
+		}
+		return time.Duration(h)*time.Hour + time.Duration(mins)*time.Minute, nil
+	}
+
+	// Only plain decimals, ParseFloat also reads 1e1, nan, inf and 0x1p3.
+	if hoursDecimal.MatchString(s) {
+		f, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) || f < 0 {
+			return 0, fmt.Errorf("Invalid hours '%s' expected e.g. 2.5, 1:30, :45, 1h30m or 90m", s)
+		}
+		if f > maxBareHours && !strings.ContainsAny(s, ".,") {
+			return 0, fmt.Errorf("Ambiguous hours '%s', add a unit: %sm or %sh", s, s, s)
+		}
+		return time.Duration(f * float64(time.Hour)), nil
+	}
+
+	// 1h30 is how hours and minutes are printed.
+	if m := hoursNoUnit.FindStringSubmatch(s); m != nil {
+		if mins, _ := strconv.Atoi(m[1]); mins >= 60 {
+			return 0, fmt.Errorf("Invalid hours '%s', minutes should be below 60", s)
+		}
+		s += "m"
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid hours '%s' expected e.g. 2.5, 1:30, :45, 1h30m or 90m", s)
+	}
+
+	return d, nil
+}
+
+var weekdays = []time.Weekday{
+	time.Monday,
+	time.Tuesday,
+	time.Wednesday,
+	time.Thursday,
+	time.Friday,
+	time.Saturday,
+	time.Sunday,
+}
+
+var (
+	daysAgo     = regexp.MustCompile(`^(\d+) days? ago$`)
+	numericDate = regexp.MustCompile(`^\d{1,4}[/.-]\d{1,2}([/.-]\d{1,4})?$`)
+)
+
+// Date parses a date relative to now, in the location of now:
+//
+//	2024-03-01                    that day
+//	today, yesterday, tomorrow
+//	3 days ago
+//	friday, fri                   the last friday, today if it is friday
+//	last friday                   the friday before today
+//	next friday                   the friday after today
+//
+// Words may be abbreviated as long as only one matches, "t" could be today,
+// tomorrow, tuesday or thursday and is refused.
+func Date(s string, now time.Time) (time.Time, error) {
+	s = strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	y, m, d := now.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+
+	if t, err := time.ParseInLocation(dateFormat, s, now.Location()); err == nil {
+		return t, nil
+	}
+
+	if m := daysAgo.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return today.AddDate(0, 0, -n), nil
+	}
+
+	if numericDate.MatchString(s) {
+		return time.Time{}, fmt.Errorf("Ambiguous date '%s', use YYYY-MM-DD", s)
+	}
+
+	modifier := ""
+	if p, rest, ok := strings.Cut(s, " "); ok && (p == "last" || p == "next") {
+		modifier, s = p, rest
+	}
+
+	word, err := match(s, modifier == "")
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	switch word {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	var wd time.Weekday
+	for _, w := range weekdays {
+		if strings.ToLower(w.String()) == word {
+			wd = w
+		}
+	}
+
+	diff := (int(today.Weekday()) - int(wd) + 7) % 7
+	switch modifier {
+	case "last":
+		if diff == 0 {
+			diff = 7
+		}
+		return today.AddDate(0, 0, -diff), nil
+	case "next":
+		ahead := (7 - diff) % 7
+		if ahead == 0 {
+			ahead = 7
+		}
+		return today.AddDate(0, 0, ahead), nil
+	}
+
+	return today.AddDate(0, 0, -diff), nil
+}
+
+// match returns the only word that starts with s,
+// relative words (today, ...) can not follow last or next.
+func match(s string, relative bool) (string, error) {
+	words := make([]string, 0, 10)
+	if relative {
+		words = append(words, "today", "yesterday", "tomorrow")
+	}
+	for _, w := range weekdays {
+		words = append(words, strings.ToLower(w.String()))
+	}
+
+	var matches []string
+	for _, w := range words {
+		if w == s {
+			return w, nil
+		}
+		if s != "" && strings.HasPrefix(w, s) {
+			matches = append(matches, w)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf(
+			"Invalid date '%s' expected YYYY-MM-DD, today, yesterday, a weekday, last <weekday> or <n> days ago",
+			s,
+		)
+	case 1:
+		return matches[0], nil
+	}
+
+	return "", fmt.Errorf("Ambiguous date '%s', could be %s", s, strings.Join(matches, ", "))
+}
@@ -0,0 +1,117 @@
+package parse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHours(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		err  bool
+	}{
+		{in: "2.5", want: 2*time.Hour + 30*time.Minute},
+		{in: "2,5", want: 2*time.Hour + 30*time.Minute},
+		{in: ".5", want: 30 * time.Minute},
+		{in: "8", want: 8 * time.Hour},
+		{in: "24", want: 24 * time.Hour},
+		{in: "1:30", want: time.Hour + 30*time.Minute},
+		{in: ":45", want: 45 * time.Minute},
+		{in: "0:05", want: 5 * time.Minute},
+		{in: "1h30m", want: time.Hour + 30*time.Minute},
+		{in: "90m", want: 90 * time.Minute},
+		{in: "1h30", want: time.Hour + 30*time.Minute},
+		{in: " 1H30M ", want: time.Hour + 30*time.Minute},
+		{in: "1:75", err: true},
+		{in: "1h75", err: true},
+		{in: "25", err: true},
+		{in: "0", err: true},
+		{in: "0:00", err: true},
+		{in: "-1", err: true},
+		{in: "-1.5", err: true},
+		{in: "1e1", err: true},
+		{in: "nan", err: true},
+		{in: "inf", err: true},
+		{in: "+inf", err: true},
+		{in: "0x1p3", err: true},
+		{in: "1.", err: true},
+		{in: "1.5.5", err: true},
+		{in: "", err: true},
+		{in: "abc", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			got, err := Hours(test.in)
+			if test.err {
+				if err == nil {
+					t.Errorf("Hours(%q) = %s, want an error", test.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Hours(%q): %s", test.in, err)
+			}
+			if got != test.want {
+				t.Errorf("Hours(%q) = %s, want %s", test.in, got, test.want)
+			}
+		})
+	}
+}
+
+func TestDate(t *testing.T) {
+	brussels, err := time.LoadLocation("Europe/Brussels")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	// A wednesday afternoon.
+	now := time.Date(2024, time.July, 3, 15, 4, 0, 0, brussels)
+	tests := []struct {
+		in   string
+		want string
+		err  bool
+	}{
+		{in: "2024-03-01", want: "2024-03-01"},
+		{in: "today", want: "2024-07-03"},
+		{in: "yesterday", want: "2024-07-02"},
+		{in: "tomorrow", want: "2024-07-04"},
+		{in: "yest", want: "2024-07-02"},
+		{in: "3 days ago", want: "2024-06-30"},
+		{in: "1 day ago", want: "2024-07-02"},
+		{in: "wednesday", want: "2024-07-03"},
+		{in: "fri", want: "2024-06-28"},
+		{in: "Mon", want: "2024-07-01"},
+		{in: "last wed", want: "2024-06-26"},
+		{in: "last  friday", want: "2024-06-28"},
+		{in: "next wed", want: "2024-07-10"},
+		{in: "next mon", want: "2024-07-08"},
+		{in: "t", err: true},
+		{in: "3/4", err: true},
+		{in: "2024-3-1", err: true},
+		{in: "last today", err: true},
+		{in: "someday", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			got, err := Date(test.in, now)
+			if test.err {
+				if err == nil {
+					t.Errorf("Date(%q) = %s, want an error", test.in, got.Format(dateFormat))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Date(%q): %s", test.in, err)
+			}
+			if got.Format(dateFormat) != test.want {
+				t.Errorf("Date(%q) = %s, want %s", test.in, got.Format(dateFormat), test.want)
+			}
+			if got.Location() != brussels || got.Hour() != 0 || got.Minute() != 0 {
+				t.Errorf("Date(%q) = %s, want the start of the day in %s", test.in, got, brussels)
+			}
+		})
+	}
+}
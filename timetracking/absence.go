@@ -0,0 +1,157 @@
+package timetracking
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// The kinds of absence. Sick days and half days are configured in absences,
+// excluded days and holidays come from exclude_dates and holidays, time off
+// is planned in forecast.
+const (
+	AbsenceSick     = "sick"
+	AbsenceHalfDay  = "half-day"
+	AbsenceExcluded = "excluded"
+	AbsenceHoliday  = "holiday"
+	AbsenceTimeOff  = "time-off"
+)
+
+// Absence marks a single day as partly or entirely not worked without
+// excluding it. Hours overrides the hours expected on that day, by default
+// none when sick and half of the usual target on a half day.
+type Absence struct {
+	Type  string   `json:"type"`
+	Hours *float64 `json:"hours,omitempty"`
+
+	// off is the time off allocated in forecast, 0 for an entire day.
+	off Duration
+}
+
+func (a *Absence) Validate(date string) error {
+	if _, err := time.Parse(DateFormat, date); err != nil {
+		return fmt.Errorf("Invalid absences date '%s' expected YYYY-MM-DD", date)
+	}
+
+	switch a.Type {
+	case AbsenceSick, AbsenceHalfDay:
+	default:
+		return fmt.Errorf(
+			"Invalid absence type '%s' on %s expected %s or %s",
+			a.Type,
+			date,
+			AbsenceSick,
+			AbsenceHalfDay,
+		)
+	}
+
+	if a.Hours != nil && (*a.Hours < 0 || *a.Hours > 24) {
+		return fmt.Errorf("Invalid absence hours %g on %s, expected 0 to 24 hours", *a.Hours, date)
+	}
+
+	return nil
+}
+
+// Planned reports whether the absence was known in advance, sick days are not.
+func (a *Absence) Planned() bool {
+	return a.Type != AbsenceSick
+}
+
+// target returns the hours expected on a day that would normally have target.
+func (a *Absence) target(target Duration) Duration {
+	switch {
+	case a.Hours != nil:
+		return Duration(*a.Hours * float64(time.Hour))
+	case a.Type == AbsenceHalfDay:
+		return target / 2
+	case a.off != 0:
+		return max(0, target-a.off)
+	}
+
+	return 0
+}
+
+// whole reports whether no hours are expected at all.
+func (a *Absence) whole() bool {
+	if a.Hours != nil {
+		return *a.Hours == 0
+	}
+	if a.Type == AbsenceTimeOff {
+		return a.off == 0
+	}
+	return a.Type != AbsenceHalfDay
+}
+
+// DayAbsence is the part of the usual target of a day that is not expected
+// because of an absence.
+type DayAbsence struct {
+	Date    time.Time
+	Type    string
+	Hours   Duration
+	Planned bool
+}
+
+// Absence returns why less than the usual target is expected on d,
+// nil for days off and regular working days.
+func (c *Config) Absence(capacity Duration, d time.Time) *DayAbsence {
+	if c.Off(d) {
+		return nil
+	}
+
+	usual := c.weekdayTarget(capacity, d.Weekday())
+	if a, ok := c.absence(d); ok {
+		return &DayAbsence{d, a.Type, usual - a.target(usual), a.Planned()}
+	}
+
+	switch {
+	case c.excluded(d):
+		return &DayAbsence{d, AbsenceExcluded, usual, true}
+	case c.Holiday(d):
+		return &DayAbsence{d, AbsenceHoliday, usual, true}
+	}
+
+	return nil
+}
+
+// AbsenceTotal sums the absences of one type.
+type AbsenceTotal struct {
+	Type    string   `json:"type"`
+	Planned bool     `json:"planned"`
+	Days    float64  `json:"days"`
+	Hours   Duration `json:"hours"`
+}
+
+// AbsenceTotals returns the total absence per type between from and to
+// (inclusive), days are counted relative to the usual target of each day.
+func (c *Config) AbsenceTotals(capacity Duration, from, to time.Time) []*AbsenceTotal {
+	totals := make(map[string]*AbsenceTotal)
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		a := c.Absence(capacity, d)
+		if a == nil || a.Hours <= 0 {
+			continue
+		}
+
+		t, ok := totals[a.Type]
+		if !ok {
+			t = &AbsenceTotal{Type: a.Type, Planned: a.Planned}
+			totals[a.Type] = t
+		}
+		t.Hours += a.Hours
+		if usual := c.weekdayTarget(capacity, d.Weekday()); usual > 0 {
+			t.Days += float64(a.Hours) / float64(usual)
+		}
+	}
+
+	list := make([]*AbsenceTotal, 0, len(totals))
+	for _, t := range totals {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Planned != list[j].Planned {
+			return list[i].Planned
+		}
+		return list[i].Type < list[j].Type
+	})
+
+	return list
+}
@@ -0,0 +1,125 @@
+package timetracking
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/forecast"
+)
+
+// ProjectActuals compares the hours allocated in forecast
+// with the hours logged in harvest for a single project.
+type ProjectActuals struct {
+	Name      string   `json:"name"`
+	Client    string   `json:"client"`
+	HarvestID int      `json:"harvest_id"`
+	Allocated Duration `json:"allocated"`
+	Logged    Duration `json:"logged"`
+}
+
+func (p *ProjectActuals) Delta() Duration {
+	return p.Logged - p.Allocated
+}
+
+// Allocated returns the total allocation of an assignment between from and
+// to, the sum of its allocation on every day it is active.
+func (t *Timetracking) Allocated(a *forecast.Assignment, from, to time.Time) time.Duration {
+	if a.StartDate == nil || a.EndDate == nil {
+		return 0
+	}
+
+	from, to = t.Day(from), t.Day(to)
+	start, end := t.Day(a.StartDate.Time), t.Day(a.EndDate.Time)
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+
+	var total time.Duration
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if !a.ActiveOnDaysOff && t.conf.Off(d) {
+			continue
+		}
+		total += a.Allocation.Duration
+	}
+
+	return total
+}
+
+// GetActuals joins the forecast assignments and the harvest time entries
+// between from and to on the forecast project's harvest_id.
+func (t *Timetracking) GetActuals(ctx context.Context, from, to time.Time) ([]*ProjectActuals, error) {
+	if t.forecastUser == nil || t.forecastUser.ID == 0 {
+		return nil, errors.New("No forecast user set")
+	}
+
+	ps, err := t.forecast.GetProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make(map[int]*forecast.Project, len(ps.Projects))
+	for _, p := range ps.Projects {
+		projects[p.ID] = p
+	}
+
+	as, err := t.forecast.GetAssignments(
+		ctx,
+		&forecast.AssignmentsParams{
+			PersonID:  &t.forecastUser.ID,
+			StartDate: &from,
+			EndDate:   &to,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := t.GetTimeEntriesBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	byHarvestID := make(map[int]*ProjectActuals)
+	list := make([]*ProjectActuals, 0)
+	get := func(harvestID int, name string) *ProjectActuals {
+		if harvestID != 0 {
+			if p, ok := byHarvestID[harvestID]; ok {
+				return p
+			}
+		}
+		p := &ProjectActuals{Name: name, HarvestID: harvestID}
+		if harvestID != 0 {
+			byHarvestID[harvestID] = p
+		}
+		list = append(list, p)
+		return p
+	}
+
+	for _, a := range as.Assignments {
+		fp, ok := projects[a.ProjectID]
+		if !ok {
+			continue
+		}
+		p := get(fp.HarvestID, fp.Name)
+		p.Allocated += Duration(t.Allocated(a, from, to))
+	}
+
+	for _, e := range entries {
+		p := get(e.Project.ID, e.Project.Name)
+		p.Name = e.Project.Name
+		p.Client = e.Client.Name
+		p.Logged += Duration(e.Hours.Duration)
+	}
+
+	sort.SliceStable(
+		list,
+		func(i, j int) bool { return list[i].Name < list[j].Name },
+	)
+
+	return list, nil
+}
@@ -0,0 +1,1112 @@
+package timetracking
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/currency"
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/holidays"
+)
+
+// TokenSourceKeyring is the token_source that reads the token from the
+// system keyring instead of the config.
+const TokenSourceKeyring = "keyring"
+
+type OAuthConfig struct {
+	ClientID     string    `json:"client_id"`
+	ClientSecret string    `json:"client_secret"`
+	AccessToken  string    `json:"access_token,omitempty"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+}
+
+func (o *OAuthConfig) LoggedIn() bool {
+	return o != nil && o.RefreshToken != ""
+}
+
+func (o *OAuthConfig) OAuth() *harvest.OAuth {
+	return &harvest.OAuth{ClientID: o.ClientID, ClientSecret: o.ClientSecret}
+}
+
+func (o *OAuthConfig) Token() *harvest.Token {
+	return &harvest.Token{
+		AccessToken:  o.AccessToken,
+		RefreshToken: o.RefreshToken,
+		Expiry:       o.Expiry,
+	}
+}
+
+func (o *OAuthConfig) SetToken(t *harvest.Token) {
+	o.AccessToken = t.AccessToken
+	o.RefreshToken = t.RefreshToken
+	o.Expiry = t.Expiry
+}
+
+type Config struct {
+	Version           int                 `json:"version,omitempty"`
+	AccountID         string              `json:"account_id"`
+	ForecastAccountID string              `json:"forecast_account_id"`
+	Token             string              `json:"token"`
+	WeekdaysOff       []string            `json:"weekdays_off"`
+	Targets           map[string]float64  `json:"targets,omitempty"`
+	WeeklyCapacity    float64             `json:"weekly_capacity,omitempty"`
+	CapacityPercent   float64             `json:"capacity_percent,omitempty"`
+	ExcludedDates     []string            `json:"exclude_dates"`
+	Absences          map[string]*Absence `json:"absences,omitempty"`
+	Tasks             Tasks               `json:"tasks"`
+	MaxAttempts       int                 `json:"max_attempts,omitempty"`
+	OAuth             *OAuthConfig        `json:"oauth,omitempty"`
+	TokenSource       string              `json:"token_source,omitempty"`
+	Holidays          string              `json:"holidays,omitempty"`
+	WeekStart         string              `json:"week_start,omitempty"`
+	TimeFormat        string              `json:"time_format,omitempty"`
+	HarvestURL        string              `json:"harvest_url,omitempty"`
+	ForecastURL       string              `json:"forecast_url,omitempty"`
+	SlackWebhook      string              `json:"slack_webhook,omitempty"`
+	IssueRegex        string              `json:"issue_regex,omitempty"`
+	Timezone          string              `json:"timezone,omitempty"`
+	Leave             *LeaveConfig        `json:"leave,omitempty"`
+	Theme             *Theme              `json:"theme,omitempty"`
+	Hooks             *HooksConfig        `json:"hooks,omitempty"`
+	Rounding          *Rounding           `json:"rounding,omitempty"`
+	Lint              *LintConfig         `json:"lint,omitempty"`
+	ForecastTimeOff   *bool               `json:"forecast_time_off,omitempty"`
+	Currency          *CurrencyConfig     `json:"currency,omitempty"`
+
+	Webhooks *WebhooksConfig `json:"webhooks,omitempty"`
+	API      *APIConfig      `json:"api,omitempty"`
+	GitHub   *GitHubConfig   `json:"github,omitempty"`
+	Toggl    *TogglConfig    `json:"toggl,omitempty"`
+	Calendar *CalendarConfig `json:"calendar,omitempty"`
+
+	Repositories map[string]*RepositoryMapping `json:"repositories,omitempty"`
+	Aliases      map[string]*Alias             `json:"aliases,omitempty"`
+	Templates    map[string]*Template          `json:"templates,omitempty"`
+	Recurring    []*Recurring                  `json:"recurring,omitempty"`
+	Retainers    map[string]*Retainer          `json:"retainers,omitempty"`
+
+	ExportFormats map[string][]*ExportColumn `json:"export_formats,omitempty"`
+
+	DefaultProfile string             `json:"default_profile,omitempty"`
+	Profiles       map[string]*Config `json:"profiles,omitempty"`
+
+	weekStart      *time.Weekday
+	location       *time.Location
+	excludedMap    map[string]struct{}
+	exclusions     []exclusion
+	weekdaysOffMap map[time.Weekday]struct{}
+	targetsMap     map[time.Weekday]Duration
+	holidays       holidays.Provider
+	holidayYears   *holidayCache
+	issueRegex     *regexp.Regexp
+	// timeOff is only set on the copies WithTimeOff returns.
+	timeOff map[string]*Absence
+}
+
+var defaultIssue = regexp.MustCompile(DefaultIssueRegex)
+
+// Issue returns the regex that extracts issue keys from notes.
+func (c *Config) Issue() *regexp.Regexp {
+	if c.issueRegex == nil {
+		return defaultIssue
+	}
+	return c.issueRegex
+}
+
+func (c *Config) Validate() error {
+	c.excludedMap = make(map[string]struct{})
+	c.exclusions = nil
+	for _, v := range c.ExcludedDates {
+		if err := c.parseExclusion(v); err != nil {
+			return err
+		}
+	}
+	for date, a := range c.Absences {
+		if a == nil {
+			return fmt.Errorf("Invalid absence on %s", date)
+		}
+		if err := a.Validate(date); err != nil {
+			return err
+		}
+	}
+
+	c.weekdaysOffMap = make(map[time.Weekday]struct{})
+	wds := map[string]time.Weekday{
+		strings.ToLower(time.Monday.String()):    time.Monday,
+		strings.ToLower(time.Tuesday.String()):   time.Tuesday,
+		strings.ToLower(time.Wednesday.String()): time.Wednesday,
+		strings.ToLower(time.Thursday.String()):  time.Thursday,
+		strings.ToLower(time.Friday.String()):    time.Friday,
+		strings.ToLower(time.Saturday.String()):  time.Saturday,
+		strings.ToLower(time.Sunday.String()):    time.Sunday,
+	}
+	for _, v := range c.WeekdaysOff {
+		wd, ok := wds[strings.ToLower(v)]
+		if !ok {
+			return fmt.Errorf("Invalid weekday '%s'", v)
+		}
+
+		c.weekdaysOffMap[wd] = struct{}{}
+	}
+
+	c.targetsMap = nil
+	if len(c.Targets) != 0 {
+		c.targetsMap = make(map[time.Weekday]Duration, len(c.Targets))
+		for v, hours := range c.Targets {
+			wd, ok := wds[strings.ToLower(v)]
+			if !ok {
+				return fmt.Errorf("Invalid weekday '%s' in targets", v)
+			}
+			if hours < 0 || hours > 24 {
+				return fmt.Errorf("Invalid target %g for %s, expected 0 to 24 hours", hours, v)
+			}
+			c.targetsMap[wd] = Duration(hours * float64(time.Hour))
+		}
+
+		// Weekdays without a target are days off.
+		for _, wd := range wds {
+			if c.targetsMap[wd] == 0 {
+				c.weekdaysOffMap[wd] = struct{}{}
+			}
+		}
+	}
+
+	c.holidays = nil
+	c.holidayYears = &holidayCache{years: make(map[int]map[string]struct{})}
+	if c.Holidays != "" {
+		p, ok := holidays.Get(c.Holidays)
+		if !ok {
+			return fmt.Errorf(
+				"Invalid holidays '%s', expected one of %s",
+				c.Holidays,
+				strings.Join(holidays.Codes(), ", "),
+			)
+		}
+		c.holidays = p
+	}
+
+	c.weekStart = nil
+	if c.WeekStart != "" {
+		wd, ok := ParseWeekday(c.WeekStart)
+		if !ok {
+			return fmt.Errorf("Invalid week_start '%s'", c.WeekStart)
+		}
+		c.weekStart = &wd
+	}
+
+	switch c.TimeFormat {
+	case "", HoursFormatDecimal, HoursFormatHoursMinutes:
+	default:
+		return fmt.Errorf(
+			"Invalid time_format '%s' expected %s or %s",
+			c.TimeFormat,
+			HoursFormatDecimal,
+			HoursFormatHoursMinutes,
+		)
+	}
+
+	c.location = nil
+	if c.Timezone != "" {
+		loc, err := LoadLocation(c.Timezone)
+		if err != nil {
+			return err
+		}
+		c.location = loc
+	}
+
+	if _, err := c.Theme.Merge(); err != nil {
+		return fmt.Errorf("Invalid theme: %w", err)
+	}
+
+	if c.Webhooks != nil {
+		if err := c.Webhooks.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.API != nil && c.API.Token == "" {
+		return errors.New("Api token is required")
+	}
+
+	if c.GitHub != nil {
+		if err := c.GitHub.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.Toggl != nil {
+		if err := c.Toggl.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.Calendar != nil {
+		if err := c.Calendar.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.WeeklyCapacity < 0 || c.WeeklyCapacity > 168 {
+		return fmt.Errorf("Invalid weekly_capacity %g, expected 0 to 168 hours", c.WeeklyCapacity)
+	}
+	if c.CapacityPercent < 0 || c.CapacityPercent > 100 {
+		return fmt.Errorf("Invalid capacity_percent %g, expected 0 to 100", c.CapacityPercent)
+	}
+	if c.WeeklyCapacity != 0 && c.CapacityPercent != 0 {
+		return errors.New("Use either weekly_capacity or capacity_percent")
+	}
+
+	if c.Rounding != nil {
+		if err := c.Rounding.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.Lint != nil {
+		if err := c.Lint.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.Currency != nil {
+		if err := c.Currency.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.Leave != nil && c.Leave.Allowance < 0 {
+		return errors.New("Leave allowance should not be negative")
+	}
+
+	c.issueRegex = nil
+	if c.IssueRegex != "" {
+		re, err := regexp.Compile(c.IssueRegex)
+		if err != nil {
+			return fmt.Errorf("Invalid issue_regex '%s': %w", c.IssueRegex, err)
+		}
+		c.issueRegex = re
+	}
+
+	if c.TokenSource != "" && c.TokenSource != TokenSourceKeyring {
+		return fmt.Errorf("Invalid token_source '%s'", c.TokenSource)
+	}
+
+	if c.MaxAttempts < 0 {
+		return errors.New("max_attempts should not be negative")
+	}
+
+	if len(c.weekdaysOffMap) > 6 {
+		return errors.New("What are you using this program for, if you take every day off?")
+	}
+
+	for name, a := range c.Aliases {
+		if a == nil || a.ProjectID <= 0 {
+			return fmt.Errorf("Alias '%s' requires a project_id", name)
+		}
+		if a.TaskID < 0 {
+			return fmt.Errorf("Alias '%s' has an invalid task_id", name)
+		}
+	}
+
+	for name, t := range c.Templates {
+		if t == nil {
+			return fmt.Errorf("Template '%s' requires a project_id and task_id", name)
+		}
+		if err := t.validate("Template", name); err != nil {
+			return err
+		}
+	}
+
+	recurring := make(map[string]struct{}, len(c.Recurring))
+	for _, r := range c.Recurring {
+		if r == nil {
+			return errors.New("Recurring entries can not be null")
+		}
+		if err := r.parse(); err != nil {
+			return err
+		}
+		if _, ok := recurring[r.Name]; ok {
+			return fmt.Errorf("Recurring entry '%s' is defined twice", r.Name)
+		}
+		recurring[r.Name] = struct{}{}
+	}
+
+	for project, r := range c.Retainers {
+		if r == nil {
+			return fmt.Errorf("Retainer '%s' requires hours", project)
+		}
+		if err := r.validate(project); err != nil {
+			return err
+		}
+	}
+
+	for name, columns := range c.ExportFormats {
+		if len(columns) == 0 {
+			return fmt.Errorf("Export format '%s' requires columns", name)
+		}
+		for _, col := range columns {
+			if err := col.validate(name); err != nil {
+				return err
+			}
+		}
+	}
+
+	if c.DefaultProfile != "" && c.Profiles[c.DefaultProfile] == nil {
+		return fmt.Errorf("default_profile '%s' does not exist", c.DefaultProfile)
+	}
+
+	for name := range c.Profiles {
+		if _, err := c.Profile(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Profile returns the configuration of the named profile,
+// unset profile values are inherited from c.
+func (c *Config) Profile(name string) (*Config, error) {
+	p, ok := c.Profiles[name]
+	if !ok || p == nil {
+		return nil, fmt.Errorf("Profile '%s' does not exist", name)
+	}
+
+	m := *c
+	m.DefaultProfile = ""
+	m.Profiles = nil
+	if p.AccountID != "" {
+		m.AccountID = p.AccountID
+	}
+	if p.ForecastAccountID != "" {
+		m.ForecastAccountID = p.ForecastAccountID
+	}
+	if p.Token != "" {
+		m.Token = p.Token
+	}
+	if p.WeekdaysOff != nil {
+		m.WeekdaysOff = p.WeekdaysOff
+	}
+	if p.Targets != nil {
+		m.Targets = p.Targets
+	}
+	if p.WeeklyCapacity != 0 || p.CapacityPercent != 0 {
+		m.WeeklyCapacity = p.WeeklyCapacity
+		m.CapacityPercent = p.CapacityPercent
+	}
+	if p.ExcludedDates != nil {
+		m.ExcludedDates = p.ExcludedDates
+	}
+	if p.Absences != nil {
+		m.Absences = p.Absences
+	}
+	if p.Tasks != nil {
+		m.Tasks = p.Tasks
+	}
+	if p.MaxAttempts != 0 {
+		m.MaxAttempts = p.MaxAttempts
+	}
+	if p.OAuth != nil {
+		m.OAuth = p.OAuth
+	}
+	if p.Holidays != "" {
+		m.Holidays = p.Holidays
+	}
+	if p.WeekStart != "" {
+		m.WeekStart = p.WeekStart
+	}
+	if p.TimeFormat != "" {
+		m.TimeFormat = p.TimeFormat
+	}
+	if p.HarvestURL != "" {
+		m.HarvestURL = p.HarvestURL
+	}
+	if p.ForecastURL != "" {
+		m.ForecastURL = p.ForecastURL
+	}
+	if p.SlackWebhook != "" {
+		m.SlackWebhook = p.SlackWebhook
+	}
+	if p.Leave != nil {
+		m.Leave = p.Leave
+	}
+	if p.Theme != nil {
+		m.Theme = p.Theme
+	}
+	if p.Hooks != nil {
+		m.Hooks = p.Hooks
+	}
+	if p.Webhooks != nil {
+		m.Webhooks = p.Webhooks
+	}
+	if p.API != nil {
+		m.API = p.API
+	}
+	if p.GitHub != nil {
+		m.GitHub = p.GitHub
+	}
+	if p.Toggl != nil {
+		m.Toggl = p.Toggl
+	}
+	if p.Calendar != nil {
+		m.Calendar = p.Calendar
+	}
+	if p.Rounding != nil {
+		m.Rounding = p.Rounding
+	}
+	if p.Lint != nil {
+		m.Lint = p.Lint
+	}
+	if p.ForecastTimeOff != nil {
+		m.ForecastTimeOff = p.ForecastTimeOff
+	}
+	if p.Currency != nil {
+		m.Currency = p.Currency
+	}
+	if p.Timezone != "" {
+		m.Timezone = p.Timezone
+	}
+	if p.IssueRegex != "" {
+		m.IssueRegex = p.IssueRegex
+	}
+	if p.Repositories != nil {
+		m.Repositories = p.Repositories
+	}
+	if p.Aliases != nil {
+		m.Aliases = p.Aliases
+	}
+	if p.Templates != nil {
+		m.Templates = p.Templates
+	}
+	if p.Recurring != nil {
+		m.Recurring = p.Recurring
+	}
+	if p.Retainers != nil {
+		m.Retainers = p.Retainers
+	}
+	if p.ExportFormats != nil {
+		m.ExportFormats = p.ExportFormats
+	}
+	if p.TokenSource != "" {
+		m.TokenSource = p.TokenSource
+		m.Token = p.Token
+	}
+
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("Profile '%s': %w", name, err)
+	}
+
+	return &m, nil
+}
+
+// Writable returns the part of the configuration file that should
+// be modified when saving values for the given profile.
+func (c *Config) Writable(profile string) *Config {
+	if profile == "" {
+		profile = c.DefaultProfile
+	}
+
+	if p, ok := c.Profiles[profile]; ok && p != nil {
+		return p
+	}
+
+	return c
+}
+
+// Excluded reports whether t is in exclude_dates, matches one of its rules,
+// is a public holiday or an absence without any expected hours.
+func (c *Config) Excluded(t time.Time) bool {
+	if a, ok := c.absence(t); ok {
+		return a.whole()
+	}
+
+	return c.excluded(t) || c.Holiday(t)
+}
+
+// excluded reports whether t is in exclude_dates or matches one of its rules.
+func (c *Config) excluded(t time.Time) bool {
+	if _, ok := c.excludedMap[t.Format(DateFormat)]; ok {
+		return true
+	}
+
+	for _, e := range c.exclusions {
+		if e.matches(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// holidayCache holds the dates of the holidays of every year that was asked
+// for. Copies of a Config share it and can be used concurrently.
+type holidayCache struct {
+	sync.Mutex
+	years map[int]map[string]struct{}
+}
+
+// Holiday reports whether t is a public holiday in the configured country.
+func (c *Config) Holiday(t time.Time) bool {
+	if c.holidays == nil {
+		return false
+	}
+
+	c.holidayYears.Lock()
+	defer c.holidayYears.Unlock()
+	year, ok := c.holidayYears.years[t.Year()]
+	if !ok {
+		list := c.holidays.Holidays(t.Year())
+		year = make(map[string]struct{}, len(list))
+		for _, h := range list {
+			year[h.Date.Format(DateFormat)] = struct{}{}
+		}
+		c.holidayYears.years[t.Year()] = year
+	}
+
+	_, ok = year[t.Format(DateFormat)]
+	return ok
+}
+
+func (c *Config) Off(t time.Time) bool {
+	_, ok := c.weekdaysOffMap[t.Weekday()]
+	return ok
+}
+
+func (c *Config) AmountOff() int {
+	return len(c.weekdaysOffMap)
+}
+
+func (c *Config) WorkWeek() int {
+	return 7 - len(c.weekdaysOffMap)
+}
+
+// FullTimeWeek is the weekly capacity capacity_percent is a part of.
+const FullTimeWeek = 40 * Duration(time.Hour)
+
+// Capacity returns the weekly capacity to measure against: weekly_capacity,
+// capacity_percent of a full-time week or the given capacity from harvest.
+func (c *Config) Capacity(harvest Duration) Duration {
+	switch {
+	case c.WeeklyCapacity != 0:
+		return Duration(c.WeeklyCapacity * float64(time.Hour))
+	case c.CapacityPercent != 0:
+		return Duration(float64(FullTimeWeek) * c.CapacityPercent / 100)
+	}
+
+	return harvest
+}
+
+// WeekTarget returns the hours that should be tracked in a week, the sum of
+// the targets of the weekdays that are not off or the weekly capacity if
+// there are none.
+func (c *Config) WeekTarget(capacity Duration) Duration {
+	if c.targetsMap == nil {
+		return capacity
+	}
+
+	var total Duration
+	for wd, t := range c.targetsMap {
+		if _, ok := c.weekdaysOffMap[wd]; !ok {
+			total += t
+		}
+	}
+
+	return total
+}
+
+// UsualTarget returns the hours that should be tracked on d if there were
+// no absence, none on excluded days and days off.
+func (c *Config) UsualTarget(capacity Duration, d time.Time) Duration {
+	if c.Excluded(d) || c.Off(d) {
+		return 0
+	}
+
+	return c.weekdayTarget(capacity, d.Weekday())
+}
+
+// DayTarget returns the hours that should be tracked on d, its target or
+// an equal part of the weekly capacity less any absence.
+// Excluded days and days off have none.
+func (c *Config) DayTarget(capacity Duration, d time.Time) Duration {
+	target := c.UsualTarget(capacity, d)
+	if target == 0 {
+		return 0
+	}
+
+	if a, ok := c.absence(d); ok {
+		return a.target(target)
+	}
+
+	return target
+}
+
+// weekdayTarget returns the usual target of a weekday that is not off.
+func (c *Config) weekdayTarget(capacity Duration, wd time.Weekday) Duration {
+	if c.targetsMap != nil {
+		return c.targetsMap[wd]
+	}
+
+	return Duration(float64(capacity) / float64(c.WorkWeek()))
+}
+
+// Target returns the hours that should be tracked between from and to
+// (inclusive) given a weekly capacity.
+func (c *Config) Target(capacity Duration, from, to time.Time) Duration {
+	var target Duration
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		target += c.DayTarget(capacity, d)
+	}
+
+	return target
+}
+
+// WorkingDays returns the amount of days between from and to (inclusive)
+// that are neither excluded nor a weekday off.
+func (c *Config) WorkingDays(from, to time.Time) int {
+	n := 0
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if !c.Excluded(d) && !c.Off(d) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// FirstWorkingDay returns the first of the last amount working days
+// up to and including to.
+func (c *Config) FirstWorkingDay(to time.Time, amount int) time.Time {
+	d := to
+	for n := 0; ; d = d.AddDate(0, 0, -1) {
+		if c.Excluded(d) || c.Off(d) {
+			continue
+		}
+		if n++; n >= amount {
+			return d
+		}
+	}
+}
+
+// DefaultTimeOffProject is the forecast project time off is planned on.
+const DefaultTimeOffProject = "Time Off"
+
+// TimeOffProject returns the forecast project whose assignments are
+// absences: forecast_project of leave or DefaultTimeOffProject, empty
+// unless there is a forecast account and forecast_time_off is true.
+func (c *Config) TimeOffProject() string {
+	if c.ForecastAccountID == "" || c.ForecastTimeOff == nil || !*c.ForecastTimeOff {
+		return ""
+	}
+	if c.Leave != nil && c.Leave.ForecastProject != "" {
+		return c.Leave.ForecastProject
+	}
+
+	return DefaultTimeOffProject
+}
+
+// WithTimeOff returns a copy of c with the planned time off per day, 0 for
+// an entire day, as absences. Days in absences keep their configured
+// absence. c itself is not changed.
+func (c *Config) WithTimeOff(off map[string]time.Duration) *Config {
+	m := *c
+	m.timeOff = make(map[string]*Absence, len(off))
+	for date, d := range off {
+		m.timeOff[date] = &Absence{Type: AbsenceTimeOff, off: Duration(d)}
+	}
+
+	return &m
+}
+
+// absence returns the configured absence on t or else its time off.
+func (c *Config) absence(t time.Time) (*Absence, bool) {
+	date := t.Format(DateFormat)
+	if a, ok := c.Absences[date]; ok {
+		return a, true
+	}
+
+	a, ok := c.timeOff[date]
+	return a, ok
+}
+
+// LeaveConfig defines the yearly vacation allowance in days and which
+// harvest projects and tasks count as leave.
+type LeaveConfig struct {
+	Allowance       float64  `json:"allowance"`
+	Projects        []string `json:"projects,omitempty"`
+	Tasks           []string `json:"tasks,omitempty"`
+	ForecastProject string   `json:"forecast_project,omitempty"`
+}
+
+// IsLeave reports whether the entry was tracked on a leave project or task.
+func (l *LeaveConfig) IsLeave(e *harvest.TimeEntry) bool {
+	for _, p := range l.Projects {
+		if strings.EqualFold(p, e.Project.Name) {
+			return true
+		}
+	}
+	for _, t := range l.Tasks {
+		if strings.EqualFold(t, e.Task.Name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type RepositoryMapping struct {
+	Project string `json:"project"`
+	Task    string `json:"task"`
+}
+
+// Repository returns the mapping of the most specific configured
+// repository path that contains dir.
+func (c *Config) Repository(dir string) *RepositoryMapping {
+	var match *RepositoryMapping
+	length := -1
+	for path, m := range c.Repositories {
+		p := filepath.Clean(ExpandHome(path))
+		if (dir == p || strings.HasPrefix(dir, p+string(filepath.Separator))) && len(p) > length {
+			match, length = m, len(p)
+		}
+	}
+
+	return match
+}
+
+// Alias is a short name for a project, or a task in it, to log time on
+// and filter reports by.
+type Alias struct {
+	ProjectID int `json:"project_id"`
+	TaskID    int `json:"task_id,omitempty"`
+}
+
+// Alias returns the alias with the given name (case-insensitive).
+func (c *Config) Alias(name string) (*Alias, error) {
+	for n, a := range c.Aliases {
+		if strings.EqualFold(n, name) {
+			return a, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Alias '%s' does not exist, add it to \"aliases\" in the config", name)
+}
+
+// Matches reports whether e was logged on the project (and task) of a.
+func (a *Alias) Matches(e *harvest.TimeEntry) bool {
+	return e.Project.ID == a.ProjectID && (a.TaskID == 0 || e.Task.ID == a.TaskID)
+}
+
+// Template is a time entry that is logged often, e.g. a recurring meeting.
+type Template struct {
+	ProjectID int     `json:"project_id"`
+	TaskID    int     `json:"task_id"`
+	Hours     float64 `json:"hours"`
+	Notes     string  `json:"notes,omitempty"`
+}
+
+func (t *Template) validate(kind, name string) error {
+	if t.ProjectID <= 0 || t.TaskID <= 0 {
+		return fmt.Errorf("%s '%s' requires a project_id and task_id", kind, name)
+	}
+	if t.Hours <= 0 || t.Hours > 24 {
+		return fmt.Errorf("%s '%s' requires hours, up to 24", kind, name)
+	}
+
+	return nil
+}
+
+// Template returns the template with the given name (case-insensitive).
+func (c *Config) Template(name string) (*Template, error) {
+	for n, t := range c.Templates {
+		if strings.EqualFold(n, name) {
+			return t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Template '%s' does not exist, add it to \"templates\" in the config", name)
+}
+
+// ExpandHome replaces a leading ~ in path with the home directory.
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(home, path[1:])
+}
+
+// HooksConfig configures external commands that run after a command,
+// to integrate with other tools.
+type HooksConfig struct {
+	// PostReport receives the json of every rendered report on stdin.
+	PostReport string `json:"post_report,omitempty"`
+	// OverdueInvoice receives the json of every overdue invoice on stdin
+	// the first time invoice outstanding lists it.
+	OverdueInvoice string `json:"overdue_invoice,omitempty"`
+}
+
+// APIConfig configures serve -api, every request needs the token
+// as a bearer token.
+type APIConfig struct {
+	Token string `json:"token"`
+}
+
+// GitHubConfig maps repositories (owner/name) to the project and task
+// import github logs the time spent on their issues on. The token
+// defaults to $GITHUB_TOKEN, url to the api of github.com.
+type GitHubConfig struct {
+	Token        string                        `json:"token,omitempty"`
+	URL          string                        `json:"url,omitempty"`
+	Repositories map[string]*RepositoryMapping `json:"repositories"`
+}
+
+func (g *GitHubConfig) Validate() error {
+	for repo, m := range g.Repositories {
+		if owner, name, ok := strings.Cut(repo, "/"); !ok || owner == "" || name == "" {
+			return fmt.Errorf("Invalid github repository '%s' expected owner/name", repo)
+		}
+		if m == nil || m.Project == "" || m.Task == "" {
+			return fmt.Errorf("github repository '%s' requires a project and task", repo)
+		}
+	}
+
+	return nil
+}
+
+// Repository returns the mapping of a repository (owner/name), case-insensitive.
+func (g *GitHubConfig) Repository(repo string) *RepositoryMapping {
+	for r, m := range g.Repositories {
+		if strings.EqualFold(r, repo) {
+			return m
+		}
+	}
+
+	return nil
+}
+
+// TogglConfig maps toggl projects and tags to the harvest project and task
+// import toggl logs their entries on, a mapped tag wins over the project.
+type TogglConfig struct {
+	Projects map[string]*RepositoryMapping `json:"projects,omitempty"`
+	Tags     map[string]*RepositoryMapping `json:"tags,omitempty"`
+}
+
+func (t *TogglConfig) Validate() error {
+	for kind, mappings := range map[string]map[string]*RepositoryMapping{"project": t.Projects, "tag": t.Tags} {
+		for name, m := range mappings {
+			if m == nil || m.Project == "" || m.Task == "" {
+				return fmt.Errorf("toggl %s '%s' requires a project and task", kind, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Mapping returns the mapping of the first mapped tag or else of the
+// project, case-insensitive.
+func (t *TogglConfig) Mapping(project string, tags []string) *RepositoryMapping {
+	for _, tag := range tags {
+		for name, m := range t.Tags {
+			if strings.EqualFold(name, tag) {
+				return m
+			}
+		}
+	}
+
+	for name, m := range t.Projects {
+		if strings.EqualFold(name, project) {
+			return m
+		}
+	}
+
+	return nil
+}
+
+// CurrencyConfig converts revenue in the currencies of clients to the Base
+// currency, with the exchange rates of a currency.Provider, e.g. "ecb", or
+// else the fixed Rates: the amount of base one unit of a currency is worth.
+// Cost rates are in the Cost currency, that of the harvest account, which
+// defaults to the base currency.
+type CurrencyConfig struct {
+	Base     string             `json:"base"`
+	Cost     string             `json:"cost,omitempty"`
+	Provider string             `json:"provider,omitempty"`
+	Rates    map[string]float64 `json:"rates,omitempty"`
+
+	converter *currency.Converter
+}
+
+func (c *CurrencyConfig) Validate() error {
+	if len(c.Base) != 3 {
+		return fmt.Errorf("Invalid currency base '%s' expected a currency code, e.g. EUR", c.Base)
+	}
+	if c.Cost != "" && len(c.Cost) != 3 {
+		return fmt.Errorf("Invalid currency cost '%s' expected a currency code, e.g. USD", c.Cost)
+	}
+
+	var p currency.Provider = &currency.Static{Base: c.Base, Rates: c.Rates}
+	if c.Provider != "" {
+		var ok bool
+		if p, ok = currency.GetProvider(c.Provider); !ok {
+			return fmt.Errorf(
+				"Invalid currency provider '%s', expected one of %s",
+				c.Provider,
+				strings.Join(currency.Providers(), ", "),
+			)
+		}
+	}
+	for code, rate := range c.Rates {
+		if rate <= 0 {
+			return fmt.Errorf("Invalid currency rate of %s, it should be positive", code)
+		}
+	}
+
+	c.converter = currency.NewConverter(c.Base, p)
+	return nil
+}
+
+// Converter returns the converter to the base currency, nil when revenue is
+// not converted.
+func (c *Config) Converter() *currency.Converter {
+	if c.Currency == nil {
+		return nil
+	}
+	return c.Currency.converter
+}
+
+// CalendarConfig configures import calendar: the ics file or url or the
+// caldav url of the calendar, the working hours (09:00 to 18:00 by
+// default) events are counted in and the rules that map their titles
+// to a project and task, the first matching rule wins.
+type CalendarConfig struct {
+	ICS      string          `json:"ics,omitempty"`
+	CalDAV   string          `json:"caldav,omitempty"`
+	Username string          `json:"username,omitempty"`
+	Password string          `json:"password,omitempty"`
+	Start    string          `json:"start,omitempty"`
+	End      string          `json:"end,omitempty"`
+	Rules    []*CalendarRule `json:"rules"`
+
+	start, end time.Duration
+}
+
+// CalendarRule maps the events with a title matching the case-insensitive
+// regex to a project and task, a rule without them skips the events.
+type CalendarRule struct {
+	Match   string `json:"match"`
+	Project string `json:"project,omitempty"`
+	Task    string `json:"task,omitempty"`
+
+	re *regexp.Regexp
+}
+
+func (c *CalendarConfig) Validate() error {
+	clock := func(name, v, def string) (time.Duration, error) {
+		if v == "" {
+			v = def
+		}
+		t, err := time.Parse("15:04", v)
+		if err != nil {
+			return 0, fmt.Errorf("Invalid calendar %s '%s' expected HH:MM", name, v)
+		}
+		return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+	}
+
+	var err error
+	if c.start, err = clock("start", c.Start, "09:00"); err != nil {
+		return err
+	}
+	if c.end, err = clock("end", c.End, "18:00"); err != nil {
+		return err
+	}
+	if c.end <= c.start {
+		return errors.New("The calendar end should be after its start")
+	}
+
+	for _, r := range c.Rules {
+		if r == nil || r.Match == "" {
+			return errors.New("Calendar rules require a match")
+		}
+		if (r.Project == "") != (r.Task == "") {
+			return fmt.Errorf("Calendar rule '%s' requires both a project and task, or neither to skip its events", r.Match)
+		}
+		if r.re, err = regexp.Compile("(?i)" + r.Match); err != nil {
+			return fmt.Errorf("Invalid match of calendar rule '%s': %w", r.Match, err)
+		}
+	}
+
+	return nil
+}
+
+// WorkingHours returns the start and end of the working hours as the time
+// since midnight.
+func (c *CalendarConfig) WorkingHours() (time.Duration, time.Duration) {
+	return c.start, c.end
+}
+
+// Rule returns the first rule matching title, nil if there is none.
+func (c *CalendarConfig) Rule(title string) *CalendarRule {
+	for _, r := range c.Rules {
+		if r.re != nil && r.re.MatchString(title) {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// WebhooksConfig configures the hooks serve -webhooks dispatches events to.
+type WebhooksConfig struct {
+	Secret string         `json:"secret"`
+	Hooks  []*WebhookHook `json:"hooks"`
+}
+
+// WebhookHook runs a shell command or posts to a url for the given events,
+// all events if there are none.
+type WebhookHook struct {
+	Events  []string `json:"events,omitempty"`
+	Command string   `json:"command,omitempty"`
+	URL     string   `json:"url,omitempty"`
+}
+
+func (w *WebhooksConfig) Validate() error {
+	if w.Secret == "" {
+		return errors.New("Webhooks secret is required")
+	}
+
+	for i, h := range w.Hooks {
+		if h == nil {
+			return fmt.Errorf("Invalid hook at index %d", i)
+		}
+		if (h.Command == "") == (h.URL == "") {
+			return errors.New("A webhook needs either a command or a url")
+		}
+	}
+
+	return nil
+}
+
+func (h *WebhookHook) Handles(event string) bool {
+	if len(h.Events) == 0 {
+		return true
+	}
+	for _, e := range h.Events {
+		if strings.EqualFold(e, event) {
+			return true
+		}
+	}
+	return false
+}
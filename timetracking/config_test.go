@@ -0,0 +1,27 @@
+package timetracking
+
+import "testing"
+
+func TestWebhooksConfigValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		hooks []*WebhookHook
+		ok    bool
+	}{
+		{"none", nil, true},
+		{"command", []*WebhookHook{{Command: "true"}}, true},
+		{"url", []*WebhookHook{{URL: "http://localhost"}}, true},
+		{"neither", []*WebhookHook{{}}, false},
+		{"both", []*WebhookHook{{Command: "true", URL: "http://localhost"}}, false},
+		{"nil", []*WebhookHook{{Command: "true"}, nil}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := &WebhooksConfig{Secret: "secret", Hooks: test.hooks}
+			if err := w.Validate(); (err == nil) != test.ok {
+				t.Errorf("Validate() = %v, want ok %t", err, test.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+// Package timetracking combines the harvest and forecast apis into the
+// reports of the timetracking command: hours tracked per day, week, project
+// or issue measured against your capacity and working days.
+//
+//	conf := &timetracking.Config{AccountID: "123", Token: "abc"}
+//	if err := conf.Validate(); err != nil {
+//		return err
+//	}
+//
+//	t, err := timetracking.New(slog.Default(), conf)
+//	if err != nil {
+//		return err
+//	}
+//
+//	if err := t.SetUID(ctx, 0); err != nil {
+//		return err
+//	}
+//
+//	days, grouped, err := t.GetRecentDaysGrouped(ctx, 5, t.Now(), true, timetracking.GroupByProject, nil)
+//
+// Days start and end in the timezone of the config (the local one when it
+// is not set), see Timetracking.Now and Timetracking.Day. Weeks start on the
+// week_start of the config or of the harvest company after LoadCompany.
+//
+// Config holds the working days (weekdays off, excluded dates, holidays)
+// that decide the target hours of a period, see Config.WorkingDays and
+// Config.Target.
+package timetracking
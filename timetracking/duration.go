@@ -0,0 +1,50 @@
+package timetracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// The harvest time formats, the default prints 1h30.
+const (
+	HoursFormatDecimal      = "decimal"
+	HoursFormatHoursMinutes = "hours_minutes"
+)
+
+// Duration is an amount of hours, printed as 1h30 or in one of the harvest
+// time formats with Format and marshalled to json as a float of hours.
+type Duration time.Duration
+
+func (d Duration) String() string {
+	return d.Format("")
+}
+
+// Format prints d in a harvest time format, HoursFormatDecimal,
+// HoursFormatHoursMinutes or "" for 1h30.
+func (d Duration) Format(format string) string {
+	s := time.Duration(d)
+	h := s / time.Hour
+	m := (s % time.Hour) / time.Minute
+	switch format {
+	case HoursFormatDecimal:
+		return fmt.Sprintf("%.2f", s.Hours())
+	case HoursFormatHoursMinutes:
+		return fmt.Sprintf("%d:%02d", h, m)
+	}
+	return fmt.Sprintf("%dh%02d", h, m)
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).Hours())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var hours float64
+	if err := json.Unmarshal(b, &hours); err != nil {
+		return err
+	}
+
+	*d = Duration(hours * float64(time.Hour))
+	return nil
+}
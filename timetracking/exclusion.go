@@ -0,0 +1,117 @@
+package timetracking
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxExcludedRange limits the amount of days in a single exclude_dates range.
+const maxExcludedRange = 366
+
+var ordinals = map[string]int{
+	"first":  1,
+	"second": 2,
+	"third":  3,
+	"fourth": 4,
+	"last":   -1,
+}
+
+// exclusion is a recurring exclude_dates rule: a date every year (every 12-25)
+// or the nth weekday of a month (first friday of july).
+type exclusion struct {
+	month   time.Month
+	day     int
+	nth     int
+	weekday time.Weekday
+}
+
+func (e exclusion) matches(t time.Time) bool {
+	if t.Month() != e.month {
+		return false
+	}
+
+	if e.nth == 0 {
+		return t.Day() == e.day
+	}
+
+	if t.Weekday() != e.weekday {
+		return false
+	}
+
+	if e.nth < 0 {
+		return t.AddDate(0, 0, 7).Month() != e.month
+	}
+
+	return (t.Day()-1)/7+1 == e.nth
+}
+
+// parseExclusion adds an exclude_dates value to the excluded days or rules:
+// a date (2024-07-04), a range (2024-07-01..2024-07-21, inclusive),
+// every MM-DD or <first|second|third|fourth|last> <weekday> of <month>.
+func (c *Config) parseExclusion(v string) error {
+	s := strings.Join(strings.Fields(strings.ToLower(v)), " ")
+	if from, to, ok := strings.Cut(s, ".."); ok {
+		f, err := time.Parse(DateFormat, strings.TrimSpace(from))
+		if err != nil {
+			return fmt.Errorf("Invalid exclude_dates range '%s' expected YYYY-MM-DD..YYYY-MM-DD", v)
+		}
+		t, err := time.Parse(DateFormat, strings.TrimSpace(to))
+		if err != nil {
+			return fmt.Errorf("Invalid exclude_dates range '%s' expected YYYY-MM-DD..YYYY-MM-DD", v)
+		}
+		if t.Before(f) {
+			return fmt.Errorf("Invalid exclude_dates range '%s', it ends before it starts", v)
+		}
+		if t.Sub(f) > maxExcludedRange*24*time.Hour {
+			return fmt.Errorf("Invalid exclude_dates range '%s', it spans more than a year", v)
+		}
+
+		for d := f; !d.After(t); d = d.AddDate(0, 0, 1) {
+			c.excludedMap[d.Format(DateFormat)] = struct{}{}
+		}
+		return nil
+	}
+
+	if rest, ok := strings.CutPrefix(s, "every "); ok {
+		d, err := time.Parse("01-02", rest)
+		if err != nil {
+			return fmt.Errorf("Invalid exclude_dates rule '%s' expected every MM-DD", v)
+		}
+		c.exclusions = append(c.exclusions, exclusion{month: d.Month(), day: d.Day()})
+		return nil
+	}
+
+	if f := strings.Fields(s); len(f) == 4 && f[2] == "of" {
+		nth, ok := ordinals[f[0]]
+		wd, wok := ParseWeekday(f[1])
+		m, mok := parseMonth(f[3])
+		if !ok || !wok || !mok {
+			return fmt.Errorf(
+				"Invalid exclude_dates rule '%s' expected e.g. first friday of july (first, second, third, fourth or last)",
+				v,
+			)
+		}
+		c.exclusions = append(c.exclusions, exclusion{month: m, nth: nth, weekday: wd})
+		return nil
+	}
+
+	if _, err := time.Parse(DateFormat, s); err != nil {
+		return fmt.Errorf(
+			"Invalid exclude_dates '%s' expected YYYY-MM-DD, a range YYYY-MM-DD..YYYY-MM-DD, every MM-DD or e.g. first friday of july",
+			v,
+		)
+	}
+	c.excludedMap[s] = struct{}{}
+
+	return nil
+}
+
+func parseMonth(s string) (time.Month, bool) {
+	for m := time.January; m <= time.December; m++ {
+		if strings.EqualFold(m.String(), s) {
+			return m, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,63 @@
+package timetracking
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExportFields are the fields of a time entry an export column can hold.
+var ExportFields = []string{
+	"id",
+	"date",
+	"start",
+	"end",
+	"user",
+	"client",
+	"project",
+	"project_code",
+	"task",
+	"notes",
+	"hours",
+	"duration",
+	"billable",
+	"reference",
+}
+
+// ExportColumn is a column of an export format in export_formats: a field
+// of the time entry or a fixed value. Layout is the go time layout of date,
+// start and end or the "yes|no" values of billable.
+type ExportColumn struct {
+	Header string `json:"header"`
+	Field  string `json:"field,omitempty"`
+	Value  string `json:"value,omitempty"`
+	Layout string `json:"layout,omitempty"`
+}
+
+func (e *ExportColumn) validate(format string) error {
+	if e == nil || e.Header == "" {
+		return fmt.Errorf("Columns of export format '%s' require a header", format)
+	}
+	if e.Field == "" {
+		return nil
+	}
+	if e.Value != "" {
+		return fmt.Errorf("Column '%s' of export format '%s' has both a field and a value", e.Header, format)
+	}
+	if e.Field == "billable" && e.Layout != "" && !strings.Contains(e.Layout, "|") {
+		return fmt.Errorf("Invalid layout '%s' of column '%s' in export format '%s' expected yes|no", e.Layout, e.Header, format)
+	}
+
+	for _, f := range ExportFields {
+		if f == e.Field {
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"Invalid field '%s' of column '%s' in export format '%s' expected one of %s",
+		e.Field,
+		e.Header,
+		format,
+		strings.Join(ExportFields, ", "),
+	)
+}
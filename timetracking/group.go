@@ -0,0 +1,44 @@
+package timetracking
+
+// The ways time entries can be grouped, see Timetracking.Grouper.
+const (
+	GroupByDay   = "day"
+	GroupByWeek  = "week"
+	GroupByMonth = "month"
+	GroupByYear  = "year"
+
+	GroupByProject = "project"
+	GroupByClient  = "client"
+	GroupByTask    = "task"
+	GroupByIssue   = "issue"
+	GroupByTag     = "tag"
+)
+
+const (
+	// DefaultIssueRegex extracts jira style issue keys (e.g. ABC-123).
+	DefaultIssueRegex = `[A-Z][A-Z0-9]+-\d+`
+	// NoIssue is the group of entries without an issue key.
+	NoIssue = "no issue"
+)
+
+// Groups lists every GroupBy value.
+var Groups = []string{
+	GroupByDay,
+	GroupByWeek,
+	GroupByMonth,
+	GroupByYear,
+	GroupByProject,
+	GroupByClient,
+	GroupByTask,
+	GroupByIssue,
+	GroupByTag,
+}
+
+// DateGroup reports whether group is one of the date based groups.
+func DateGroup(group string) bool {
+	switch group {
+	case GroupByDay, GroupByWeek, GroupByMonth, GroupByYear:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+package timetracking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
+
+// The levels of lint rules, warnings are reported but do not fail.
+const (
+	LintWarning = "warning"
+	LintError   = "error"
+)
+
+// LintConfig are the rules the lint command checks time entries against,
+// with submit_week they are also checked before submitting a week.
+type LintConfig struct {
+	SubmitWeek bool        `json:"submit_week,omitempty"`
+	Rules      []*LintRule `json:"rules"`
+}
+
+func (c *LintConfig) Validate() error {
+	names := make(map[string]struct{}, len(c.Rules))
+	for _, r := range c.Rules {
+		if r == nil {
+			return errors.New("Lint rules can not be null")
+		}
+		if err := r.parse(); err != nil {
+			return err
+		}
+		if _, ok := names[r.Name]; ok {
+			return fmt.Errorf("Lint rule '%s' is defined twice", r.Name)
+		}
+		names[r.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+// LintRule checks the entries of a project, client and/or task (name or id,
+// all entries when none is set): notes requires notes, notes_regex notes
+// that match it, max_entry limits the hours of a single entry and min_day
+// and max_day the total of a day. min_day only applies to working days.
+type LintRule struct {
+	Name  string `json:"name"`
+	Level string `json:"level,omitempty"`
+
+	Project string `json:"project,omitempty"`
+	Client  string `json:"client,omitempty"`
+	Task    string `json:"task,omitempty"`
+
+	Notes      bool     `json:"notes,omitempty"`
+	NotesRegex string   `json:"notes_regex,omitempty"`
+	MaxEntry   Duration `json:"max_entry,omitempty"`
+	MinDay     Duration `json:"min_day,omitempty"`
+	MaxDay     Duration `json:"max_day,omitempty"`
+
+	notesRegex *regexp.Regexp
+}
+
+func (r *LintRule) parse() error {
+	if r.Name == "" {
+		return errors.New("Lint rules require a name")
+	}
+
+	r.Level = strings.ToLower(strings.TrimSpace(r.Level))
+	switch r.Level {
+	case "":
+		r.Level = LintWarning
+	case LintWarning, LintError:
+	default:
+		return fmt.Errorf(
+			"Invalid level '%s' of lint rule '%s' expected %s or %s",
+			r.Level,
+			r.Name,
+			LintWarning,
+			LintError,
+		)
+	}
+
+	r.notesRegex = nil
+	if r.NotesRegex != "" {
+		re, err := regexp.Compile(r.NotesRegex)
+		if err != nil {
+			return fmt.Errorf("Invalid notes_regex of lint rule '%s': %w", r.Name, err)
+		}
+		r.notesRegex = re
+	}
+
+	if r.MaxEntry < 0 || r.MinDay < 0 || r.MaxDay < 0 {
+		return fmt.Errorf("Lint rule '%s' can not have negative hours", r.Name)
+	}
+	if r.MaxDay != 0 && r.MinDay > r.MaxDay {
+		return fmt.Errorf("Lint rule '%s' has a min_day above its max_day", r.Name)
+	}
+	if !r.Notes && r.notesRegex == nil && r.MaxEntry == 0 && r.MinDay == 0 && r.MaxDay == 0 {
+		return fmt.Errorf("Lint rule '%s' checks nothing", r.Name)
+	}
+
+	return nil
+}
+
+// applies reports whether e is in the scope of r.
+func (r *LintRule) applies(e *harvest.TimeEntry) bool {
+	match := func(value, name string, id int) bool {
+		return value == "" || strings.EqualFold(value, name) || value == strconv.Itoa(id)
+	}
+
+	return match(r.Project, e.Project.Name, e.Project.ID) &&
+		match(r.Client, e.Client.Name, e.Client.ID) &&
+		match(r.Task, e.Task.Name, e.Task.ID)
+}
+
+// LintResult is a rule an entry or day does not pass, Entry is nil for the
+// daily totals.
+type LintResult struct {
+	Rule    string             `json:"rule"`
+	Level   string             `json:"level"`
+	Date    time.Time          `json:"date"`
+	Entry   *harvest.TimeEntry `json:"entry,omitempty"`
+	Message string             `json:"message"`
+}
+
+// LintResults are sorted by date, errors before warnings.
+type LintResults []*LintResult
+
+// Errors returns the amount of results with level error.
+func (l LintResults) Errors() int {
+	n := 0
+	for _, r := range l {
+		if r.Level == LintError {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Lint checks the entries of the user between from and to against rules.
+// Days after today are not checked for a min_day, use WithTimeOff to skip
+// forecast time off as well.
+func (t *Timetracking) Lint(ctx context.Context, rules []*LintRule, from, to time.Time) (LintResults, error) {
+	entries, err := t.GetTimeEntriesBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var results LintResults
+	add := func(r *LintRule, d time.Time, e *harvest.TimeEntry, format string, args ...interface{}) {
+		results = append(results, &LintResult{r.Name, r.Level, d, e, fmt.Sprintf(format, args...)})
+	}
+
+	today := t.Today()
+	capacity := t.Capacity()
+	for _, r := range rules {
+		days := make(map[string]Duration)
+		for _, e := range entries {
+			if e.SpentDate == nil || !r.applies(e) {
+				continue
+			}
+			d := t.Day(e.SpentDate.Time)
+			hours := Duration(e.Hours.Duration)
+			days[d.Format(DateFormat)] += hours
+
+			notes := strings.TrimSpace(e.Notes)
+			if r.Notes && notes == "" {
+				add(r, d, e, "%s requires notes", e.Project.Name)
+			}
+			if r.notesRegex != nil && notes != "" && !r.notesRegex.MatchString(notes) {
+				add(r, d, e, "Notes '%s' do not match %s", notes, r.NotesRegex)
+			}
+			if r.MaxEntry != 0 && hours > r.MaxEntry {
+				add(r, d, e, "%s on a single entry, at most %s", hours.Format(t.timeFormat), r.MaxEntry.Format(t.timeFormat))
+			}
+		}
+
+		if r.MinDay == 0 && r.MaxDay == 0 {
+			continue
+		}
+		for d := t.Day(from); !d.After(to); d = d.AddDate(0, 0, 1) {
+			hours := days[d.Format(DateFormat)]
+			switch {
+			case r.MaxDay != 0 && hours > r.MaxDay:
+				add(r, d, nil, "%s tracked, at most %s", hours.Format(t.timeFormat), r.MaxDay.Format(t.timeFormat))
+			case r.MinDay != 0 && hours < r.MinDay && !d.After(today) && t.conf.DayTarget(capacity, d) > 0:
+				add(r, d, nil, "%s tracked, at least %s", hours.Format(t.timeFormat), r.MinDay.Format(t.timeFormat))
+			}
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i].Date.Format(DateFormat), results[j].Date.Format(DateFormat)
+		if a != b {
+			return a < b
+		}
+		return results[i].Level == LintError && results[j].Level != LintError
+	})
+
+	return results, nil
+}
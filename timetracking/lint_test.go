@@ -0,0 +1,154 @@
+package timetracking
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+	"github.com/frizinak/harvest-timetracking/harvesttest"
+)
+
+func TestLint(t *testing.T) {
+	s := harvesttest.NewServer()
+	defer s.Close()
+	s.Me = &harvest.User{ID: 1, WeeklyCapacity: 40 * 3600}
+
+	date := func(s string) time.Time {
+		d, err := time.Parse(DateFormat, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	entry := func(day, project, notes string, d time.Duration) {
+		s.AddTimeEntry(&harvest.TimeEntry{
+			User:      harvest.UserRef{ID: 1},
+			Project:   harvest.ProjectRef{ID: 10, Name: project},
+			Task:      harvest.TaskRef{ID: 20, Name: "Development"},
+			SpentDate: &harvest.Date{date(day)},
+			Hours:     harvest.DurationHours{d},
+			Notes:     notes,
+		})
+	}
+
+	// Monday 2024-03-04 to Sunday 2024-03-10, thursday and friday have no
+	// entries and the weekend is off.
+	entry("2024-03-04", "Acme", "ACME-1 build", 8*time.Hour)
+	entry("2024-03-05", "Acme", "", 2*time.Hour)
+	entry("2024-03-05", "Acme", "meeting", 2*time.Hour)
+	entry("2024-03-06", "Acme", "ACME-2 deploy", 7*time.Hour)
+	entry("2024-03-06", "Internal", "ACME-3 review", 4*time.Hour)
+
+	conf := &Config{AccountID: "1", Timezone: "UTC", WeekdaysOff: []string{"saturday", "sunday"}}
+	if err := conf.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tt, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), conf, s.Options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := tt.SetUID(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		date, rule, level string
+		entry             bool
+	}
+
+	tests := []struct {
+		name string
+		rule LintRule
+		want []result
+	}{
+		{
+			name: "notes",
+			rule: LintRule{Name: "notes", Level: "Error", Notes: true},
+			want: []result{{"2024-03-05", "notes", LintError, true}},
+		},
+		{
+			name: "notes_regex",
+			rule: LintRule{Name: "issue", Project: "acme", NotesRegex: `^[A-Z]+-\d+ `},
+			want: []result{{"2024-03-05", "issue", LintWarning, true}},
+		},
+		{
+			name: "max_entry",
+			rule: LintRule{Name: "long", MaxEntry: Duration(6 * time.Hour)},
+			want: []result{
+				{"2024-03-04", "long", LintWarning, true},
+				{"2024-03-06", "long", LintWarning, true},
+			},
+		},
+		{
+			name: "min_day on working days",
+			rule: LintRule{Name: "min", Level: LintError, MinDay: Duration(6 * time.Hour)},
+			want: []result{
+				{"2024-03-05", "min", LintError, false},
+				{"2024-03-07", "min", LintError, false},
+				{"2024-03-08", "min", LintError, false},
+			},
+		},
+		{
+			name: "max_day of a project",
+			rule: LintRule{Name: "max", Project: "10", MaxDay: Duration(10 * time.Hour)},
+			want: []result{{"2024-03-06", "max", LintWarning, false}},
+		},
+		{
+			name: "out of scope",
+			rule: LintRule{Name: "other", Client: "nobody", Notes: true},
+			want: []result{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule := test.rule
+			if err := (&LintConfig{Rules: []*LintRule{&rule}}).Validate(); err != nil {
+				t.Fatal(err)
+			}
+
+			results, err := tt.Lint(ctx, []*LintRule{&rule}, tt.Day(date("2024-03-04")), tt.Day(date("2024-03-10")))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]result, 0, len(results))
+			for _, r := range results {
+				got = append(got, result{r.Date.Format(DateFormat), r.Rule, r.Level, r.Entry != nil})
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Lint = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLintRuleInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		rule LintRule
+	}{
+		{"no name", LintRule{Notes: true}},
+		{"level", LintRule{Name: "a", Level: "fatal", Notes: true}},
+		{"regex", LintRule{Name: "a", NotesRegex: "("}},
+		{"negative", LintRule{Name: "a", MaxEntry: -1}},
+		{"min above max", LintRule{Name: "a", MinDay: Duration(8 * time.Hour), MaxDay: Duration(4 * time.Hour)}},
+		{"nothing", LintRule{Name: "a", Project: "acme"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule := test.rule
+			if err := (&LintConfig{Rules: []*LintRule{&rule}}).Validate(); err == nil {
+				t.Errorf("Validate of rule %+v did not fail", test.rule)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package timetracking
+
+import (
+	"context"
+	"time"
+)
+
+// MissingDay is a working day on which less than its target was tracked.
+type MissingDay struct {
+	Date    time.Time `json:"date"`
+	Target  Duration  `json:"target"`
+	Tracked Duration  `json:"tracked"`
+}
+
+func (m *MissingDay) Missing() Duration {
+	return m.Target - m.Tracked
+}
+
+// GetMissingDays returns the days between from and to (inclusive) that have
+// a target but nothing tracked, or less than their target when short is set.
+// Days off, excluded days, holidays and whole absences have no target.
+func (t *Timetracking) GetMissingDays(
+	ctx context.Context,
+	capacity Duration,
+	from,
+	to time.Time,
+	short bool,
+) ([]*MissingDay, error) {
+	entries, err := t.GetTimeEntriesBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	tracked := make(map[string]Duration)
+	for _, e := range entries {
+		if e.SpentDate != nil {
+			tracked[e.SpentDate.Format(DateFormat)] += Duration(e.Hours.Duration)
+		}
+	}
+
+	var missing []*MissingDay
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		target := t.conf.DayTarget(capacity, d)
+		if target <= 0 {
+			continue
+		}
+
+		m := &MissingDay{Date: d, Target: target, Tracked: tracked[d.Format(DateFormat)]}
+		if m.Tracked == 0 || (short && m.Missing() > 0) {
+			missing = append(missing, m)
+		}
+	}
+
+	return missing, nil
+}
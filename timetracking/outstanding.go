@@ -0,0 +1,80 @@
+package timetracking
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
+
+// OutstandingInvoice is an open invoice that is not fully paid, with the
+// payments made on it so far.
+type OutstandingInvoice struct {
+	*harvest.Invoice
+	Payments []*harvest.InvoicePayment `json:"payments"`
+	// Age is the amount of days since it was issued, Overdue since it was
+	// due.
+	Age     int `json:"age_days"`
+	Overdue int `json:"overdue_days"`
+}
+
+// LastPayment returns the most recent payment, nil if there are none.
+func (o *OutstandingInvoice) LastPayment() *harvest.InvoicePayment {
+	var last *harvest.InvoicePayment
+	for _, p := range o.Payments {
+		if p.PaidDate == nil {
+			continue
+		}
+		if last == nil || p.PaidDate.After(last.PaidDate.Time) {
+			last = p
+		}
+	}
+
+	return last
+}
+
+// GetOutstandingInvoices returns the outstanding invoices of a client, or of
+// all clients if clientID is 0, the longest overdue first.
+func (t *Timetracking) GetOutstandingInvoices(ctx context.Context, clientID int, today time.Time) ([]*OutstandingInvoice, error) {
+	state := harvest.InvoiceStateOpen
+	params := &harvest.InvoicesParams{State: &state}
+	if clientID != 0 {
+		params.ClientID = &clientID
+	}
+
+	invoices, err := t.harvest.Invoices(ctx, params).All()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*OutstandingInvoice, 0, len(invoices))
+	for _, i := range invoices {
+		if !i.Outstanding() {
+			continue
+		}
+		o := &OutstandingInvoice{Invoice: i, Overdue: i.DaysOverdue(today)}
+		if i.IssueDate != nil {
+			o.Age = int(t.Day(today).Sub(t.Day(i.IssueDate.Time)).Hours()/24 + 0.5)
+		}
+		list = append(list, o)
+	}
+
+	err = harvest.Parallel(ctx, len(list), TeamWorkers, func(ctx context.Context, n int) error {
+		payments, err := t.harvest.InvoicePayments(ctx, list[n].ID, &harvest.InvoicePaymentsParams{}).All()
+		list[n].Payments = payments
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Overdue != list[j].Overdue {
+			return list[i].Overdue > list[j].Overdue
+		}
+		return list[i].Age > list[j].Age
+	})
+
+	return list, nil
+}
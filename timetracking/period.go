@@ -0,0 +1,87 @@
+package timetracking
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	PeriodThisWeek  = "this-week"
+	PeriodLastWeek  = "last-week"
+	PeriodThisMonth = "this-month"
+	PeriodLastMonth = "last-month"
+	PeriodYTD       = "ytd"
+)
+
+var Periods = []string{
+	PeriodThisWeek,
+	PeriodLastWeek,
+	PeriodThisMonth,
+	PeriodLastMonth,
+	PeriodYTD,
+}
+
+func Day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek returns the first day of the week t is in, for weeks that
+// start on weekStart.
+func StartOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	wd := (int(t.Weekday()) - int(weekStart) + 7) % 7
+	return Day(t).AddDate(0, 0, -wd)
+}
+
+func ParseWeekday(s string) (time.Weekday, bool) {
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		if strings.EqualFold(wd.String(), s) {
+			return wd, true
+		}
+	}
+	return 0, false
+}
+
+// Period returns the first and last day of a named period relative to now,
+// for weeks that start on weekStart.
+func Period(name string, now time.Time, weekStart time.Weekday) (from, to time.Time, err error) {
+	today := Day(now)
+	switch name {
+	case PeriodThisWeek:
+		from = StartOfWeek(today, weekStart)
+		to = from.AddDate(0, 0, 6)
+	case PeriodLastWeek:
+		from = StartOfWeek(today, weekStart).AddDate(0, 0, -7)
+		to = from.AddDate(0, 0, 6)
+	case PeriodThisMonth:
+		from = today.AddDate(0, 0, 1-today.Day())
+		to = from.AddDate(0, 1, -1)
+	case PeriodLastMonth:
+		from = today.AddDate(0, 0, 1-today.Day()).AddDate(0, -1, 0)
+		to = from.AddDate(0, 1, -1)
+	case PeriodYTD:
+		from = time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, today.Location())
+		to = today
+	default:
+		err = fmt.Errorf("Invalid period '%s'", name)
+	}
+
+	return
+}
+
+// PreviousPeriod returns the period before from to (inclusive) to compare it
+// with. Whole months (from the first up to the last day of a month) are
+// compared with as many months before them, e.g. January to March with
+// October to December, other periods with the same amount of days right
+// before them, e.g. a week with the week before.
+func PreviousPeriod(from, to time.Time) (time.Time, time.Time) {
+	from, to = Day(from), Day(to)
+	if from.Day() == 1 && to.AddDate(0, 0, 1).Day() == 1 {
+		months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+		return from.AddDate(0, -months, 0), from.AddDate(0, 0, -1)
+	}
+
+	days := int(to.Sub(from).Hours()/24+0.5) + 1
+	return from.AddDate(0, 0, -days), from.AddDate(0, 0, -1)
+}
@@ -0,0 +1,48 @@
+package timetracking
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPreviousPeriod(t *testing.T) {
+	brussels, err := time.LoadLocation("Europe/Brussels")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	date := func(s string) time.Time {
+		d, err := time.ParseInLocation(DateFormat, s, brussels)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		name             string
+		from, to         string
+		prevFrom, prevTo string
+	}{
+		{"week", "2024-07-01", "2024-07-07", "2024-06-24", "2024-06-30"},
+		{"week so far", "2024-07-01", "2024-07-03", "2024-06-28", "2024-06-30"},
+		{"single day", "2024-07-03", "2024-07-03", "2024-07-02", "2024-07-02"},
+		{"month", "2024-03-01", "2024-03-31", "2024-02-01", "2024-02-29"},
+		{"month so far", "2024-03-01", "2024-03-15", "2024-02-15", "2024-02-29"},
+		{"quarter", "2024-01-01", "2024-03-31", "2023-10-01", "2023-12-31"},
+		{"year", "2024-01-01", "2024-12-31", "2023-01-01", "2023-12-31"},
+		{"not from the first", "2024-01-02", "2024-03-31", "2023-10-04", "2024-01-01"},
+		{"over dst", "2024-03-25", "2024-04-07", "2024-03-11", "2024-03-24"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			from, to := PreviousPeriod(date(test.from), date(test.to))
+			got := [2]string{from.Format(DateFormat), to.Format(DateFormat)}
+			want := [2]string{test.prevFrom, test.prevTo}
+			if got != want {
+				t.Errorf("PreviousPeriod(%s, %s) = %v, want %v", test.from, test.to, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package timetracking
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/forecast"
+)
+
+// PlannedWeek is the time a person is allocated to projects in a week next
+// to their weekly capacity and planned time off.
+type PlannedWeek struct {
+	Start     time.Time           `json:"start"`
+	Capacity  Duration            `json:"capacity"`
+	TimeOff   Duration            `json:"time_off"`
+	Allocated Duration            `json:"allocated"`
+	Projects  map[string]Duration `json:"projects"`
+}
+
+// Available returns the capacity that is left after time off.
+func (w *PlannedWeek) Available() Duration {
+	if w.TimeOff > w.Capacity {
+		return 0
+	}
+	return w.Capacity - w.TimeOff
+}
+
+// Over reports whether more is allocated than is available.
+func (w *PlannedWeek) Over() bool {
+	return w.Allocated > w.Available()
+}
+
+// Under reports whether less is allocated than is available.
+func (w *PlannedWeek) Under() bool {
+	return w.Allocated < w.Available()
+}
+
+// TeamPlan is the planning of a single person.
+type TeamPlan struct {
+	Person *forecast.User `json:"person"`
+	Weeks  []*PlannedWeek `json:"weeks"`
+}
+
+// GetTeamPlan returns the weekly allocations of every active forecast person
+// in the weeks starting at from, sorted by name. Assignments to the
+// timeOff project count as time off, assignments without an allocation
+// take an entire working day of the person.
+func (t *Timetracking) GetTeamPlan(
+	ctx context.Context,
+	from time.Time,
+	weeks int,
+	timeOff string,
+) ([]*TeamPlan, error) {
+	people, err := t.forecast.GetPeople(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ps, err := t.forecast.GetProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int]string, len(ps.Projects))
+	for _, p := range ps.Projects {
+		names[p.ID] = p.Name
+	}
+
+	from = Day(from)
+	to := from.AddDate(0, 0, 7*weeks-1)
+	as, err := t.forecast.GetAssignments(
+		ctx,
+		&forecast.AssignmentsParams{StartDate: &from, EndDate: &to},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	plans := make([]*TeamPlan, 0, len(people.People))
+	byID := make(map[int]*TeamPlan, len(people.People))
+	for _, p := range people.People {
+		if p.Archived {
+			continue
+		}
+		plan := &TeamPlan{Person: p, Weeks: make([]*PlannedWeek, weeks)}
+		for i := range plan.Weeks {
+			plan.Weeks[i] = &PlannedWeek{
+				Start:    from.AddDate(0, 0, 7*i),
+				Capacity: Duration(p.WeeklyCapacity.Duration),
+				Projects: make(map[string]Duration),
+			}
+		}
+		plans = append(plans, plan)
+		byID[p.ID] = plan
+	}
+
+	for _, a := range as.Assignments {
+		plan, ok := byID[a.PersonID]
+		if !ok || a.StartDate == nil || a.EndDate == nil {
+			continue
+		}
+
+		perDay := a.Allocation.Duration
+		if perDay == 0 {
+			if n := plan.Person.WorkingDays.Count(); n != 0 {
+				perDay = plan.Person.WeeklyCapacity.Duration / time.Duration(n)
+			}
+		}
+
+		name := names[a.ProjectID]
+		off := strings.EqualFold(name, timeOff)
+		first, last := a.StartDate.Format(DateFormat), a.EndDate.Format(DateFormat)
+		for i := 0; i < 7*weeks; i++ {
+			d := from.AddDate(0, 0, i)
+			if f := d.Format(DateFormat); f < first || f > last {
+				continue
+			}
+			if !a.ActiveOnDaysOff && !plan.Person.WorkingDays.Works(d.Weekday()) {
+				continue
+			}
+
+			w := plan.Weeks[i/7]
+			if off {
+				w.TimeOff += Duration(perDay)
+				continue
+			}
+			w.Allocated += Duration(perDay)
+			w.Projects[name] += Duration(perDay)
+		}
+	}
+
+	sort.SliceStable(plans, func(i, j int) bool {
+		return strings.ToLower(plans[i].Person.Name()) < strings.ToLower(plans[j].Person.Name())
+	})
+
+	return plans, nil
+}
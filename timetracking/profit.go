@@ -0,0 +1,103 @@
+package timetracking
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
+
+// Profitability is the revenue and labor cost of the hours everyone logged
+// on a project or client. Revenue is in Currency, the currency of the
+// client or the base currency of the config.
+type Profitability struct {
+	ID       int      `json:"id"`
+	Name     string   `json:"name"`
+	Client   string   `json:"client,omitempty"`
+	Currency string   `json:"currency"`
+	Hours    Duration `json:"hours"`
+	Billable Duration `json:"billable_hours"`
+	Revenue  float64  `json:"revenue"`
+	Cost     float64  `json:"cost"`
+}
+
+func (p *Profitability) Profit() float64 {
+	return p.Revenue - p.Cost
+}
+
+// Margin returns the profit as a percentage of the revenue, 0 without
+// revenue.
+func (p *Profitability) Margin() float64 {
+	if p.Revenue == 0 {
+		return 0
+	}
+	return 100 * p.Profit() / p.Revenue
+}
+
+// GetProfitability sums the revenue and cost of the entries of all users
+// between from and to per project, or per client when group is
+// GroupByClient, the most profitable first. Missing rates are filled in with
+// FillRates, which requires administrator permissions, and converted with
+// ConvertRates.
+func (t *Timetracking) GetProfitability(
+	ctx context.Context,
+	from,
+	to time.Time,
+	group string,
+	filter harvest.Filter,
+) ([]*Profitability, error) {
+	if group != GroupByProject && group != GroupByClient {
+		return nil, fmt.Errorf("Invalid group '%s' expected %s or %s", group, GroupByProject, GroupByClient)
+	}
+
+	entries, err := t.harvest.AllTimeEntries(
+		ctx,
+		&harvest.TimeEntriesParams{From: &from, To: &to},
+		harvest.DefaultPageWorkers,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if filter != nil {
+		entries = entries.Filter(filter)
+	}
+
+	if err := t.FillRates(ctx, entries); err != nil {
+		return nil, err
+	}
+	if _, err := t.ConvertRates(ctx, entries); err != nil {
+		return nil, err
+	}
+
+	var list []*Profitability
+	byID := make(map[int]*Profitability)
+	for _, e := range entries {
+		id := e.Project.ID
+		if group == GroupByClient {
+			id = e.Client.ID
+		}
+
+		p, ok := byID[id]
+		if !ok {
+			p = &Profitability{ID: id, Name: e.Project.Name, Client: e.Client.Name, Currency: e.Client.Currency}
+			if group == GroupByClient {
+				p.Name, p.Client = e.Client.Name, ""
+			}
+			byID[id] = p
+			list = append(list, p)
+		}
+
+		p.Hours += Duration(e.Hours.Duration)
+		if e.Billable {
+			p.Billable += Duration(e.Hours.Duration)
+			p.Revenue += e.Hours.Hours() * e.BillableRate
+		}
+		p.Cost += e.Hours.Hours() * e.CostRate
+	}
+
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Profit() > list[j].Profit() })
+
+	return list, nil
+}
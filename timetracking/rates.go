@@ -0,0 +1,112 @@
+package timetracking
+
+import (
+	"context"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
+
+// FillRates sets the rates harvest left out of entries. A missing cost rate
+// is the cost rate the user had on the day, a missing rate of a billable
+// entry the rate of its task on the project or else the billable rate the
+// user had on the day. Cost rates require administrator permissions.
+func (t *Timetracking) FillRates(ctx context.Context, entries harvest.TimeEntries) error {
+	costs := make(map[int]harvest.Rates)
+	billable := make(map[int]harvest.Rates)
+	tasks := make(map[int]map[int]float64)
+
+	for _, e := range entries {
+		if e.ID == 0 || e.SpentDate == nil {
+			continue
+		}
+
+		if e.CostRate == 0 {
+			rates, ok := costs[e.User.ID]
+			if !ok {
+				var err error
+				rates, err = t.harvest.CostRates(ctx, e.User.ID, &harvest.RatesParams{}).All()
+				if err != nil {
+					return err
+				}
+				costs[e.User.ID] = rates
+			}
+			e.CostRate = rates.At(e.SpentDate.Time)
+		}
+
+		if !e.Billable || e.BillableRate != 0 {
+			continue
+		}
+
+		project, ok := tasks[e.Project.ID]
+		if !ok {
+			as, err := t.harvest.TaskAssignments(ctx, e.Project.ID, &harvest.TaskAssignmentsParams{}).All()
+			if err != nil {
+				return err
+			}
+			project = make(map[int]float64, len(as))
+			for _, a := range as {
+				project[a.Task.ID] = a.HourlyRate
+			}
+			tasks[e.Project.ID] = project
+		}
+		if e.BillableRate = project[e.Task.ID]; e.BillableRate != 0 {
+			continue
+		}
+
+		rates, ok := billable[e.User.ID]
+		if !ok {
+			var err error
+			rates, err = t.harvest.BillableRates(ctx, e.User.ID, &harvest.RatesParams{}).All()
+			if err != nil {
+				return err
+			}
+			billable[e.User.ID] = rates
+		}
+		e.BillableRate = rates.At(e.SpentDate.Time)
+	}
+
+	return nil
+}
+
+// ConvertRates converts the billable rates of entries from the currency of
+// their client, and their cost rates from the cost currency, to the base
+// currency of the config, if any. Rates are converted at the current exchange
+// rate, also those of earlier entries. It returns the currency of the revenue
+// of entries, empty when they are in several currencies that are not
+// converted.
+func (t *Timetracking) ConvertRates(ctx context.Context, entries harvest.TimeEntries) (string, error) {
+	conv := t.conf.Converter()
+	if conv == nil {
+		var code string
+		for _, e := range entries {
+			if !e.Billable || e.Client.Currency == "" {
+				continue
+			}
+			if code != "" && code != e.Client.Currency {
+				t.l.Warn("Revenue sums amounts in several currencies, set a \"currency\" \"base\" in the config to convert them")
+				return "", nil
+			}
+			code = e.Client.Currency
+		}
+		return code, nil
+	}
+
+	cost := t.conf.Currency.Cost
+	for _, e := range entries {
+		if e.Billable {
+			rate, err := conv.Convert(ctx, e.BillableRate, e.Client.Currency)
+			if err != nil {
+				return "", err
+			}
+			e.BillableRate = rate
+		}
+		rate, err := conv.Convert(ctx, e.CostRate, cost)
+		if err != nil {
+			return "", err
+		}
+		e.CostRate = rate
+		e.Client.Currency = conv.Base
+	}
+
+	return conv.Base, nil
+}
@@ -0,0 +1,131 @@
+package timetracking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
+
+// RecurringGroup is the external_reference group_id of entries created
+// by sync-recurring.
+const RecurringGroup = "timetracking-recurring"
+
+// Recurring is a time entry that is logged on every day it recurs on:
+// every day, every working day (weekday, the default) or every listed
+// weekday (monday, thursday). Only "day" includes days off and excluded days.
+type Recurring struct {
+	Name  string `json:"name"`
+	Every string `json:"every,omitempty"`
+	Template
+
+	all      bool
+	weekdays map[time.Weekday]struct{}
+}
+
+func (r *Recurring) parse() error {
+	if r.Name == "" {
+		return errors.New("Recurring entries require a name")
+	}
+	if err := r.Template.validate("Recurring entry", r.Name); err != nil {
+		return err
+	}
+
+	r.all, r.weekdays = false, nil
+	every := strings.ToLower(strings.TrimSpace(r.Every))
+	switch every {
+	case "day":
+		r.all = true
+	case "", "weekday", "working day":
+	default:
+		r.weekdays = make(map[time.Weekday]struct{})
+		for _, v := range strings.Split(every, ",") {
+			wd, ok := ParseWeekday(strings.TrimSpace(v))
+			if !ok {
+				return fmt.Errorf(
+					"Invalid every '%s' of recurring entry '%s' expected day, weekday or a list of weekdays",
+					r.Every,
+					r.Name,
+				)
+			}
+			r.weekdays[wd] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// Reference returns the external_reference id of the entry on d.
+func (r *Recurring) Reference(d time.Time) string {
+	return fmt.Sprintf("%s:%s", r.Name, d.Format(DateFormat))
+}
+
+// RecursOn reports whether r should be logged on d.
+func (c *Config) RecursOn(r *Recurring, d time.Time) bool {
+	if r.all {
+		return true
+	}
+	if c.Excluded(d) || c.Off(d) {
+		return false
+	}
+	if r.weekdays == nil {
+		return true
+	}
+
+	_, ok := r.weekdays[d.Weekday()]
+	return ok
+}
+
+// RecurringEntry is a recurring entry on a single day.
+type RecurringEntry struct {
+	*Recurring
+	Date time.Time `json:"date"`
+}
+
+// GetMissingRecurring returns the recurring entries between from and to
+// (inclusive) that have not been logged yet, recognized by their
+// external_reference.
+func (t *Timetracking) GetMissingRecurring(ctx context.Context, from, to time.Time) ([]*RecurringEntry, error) {
+	entries, err := t.GetTimeEntriesBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	logged := make(map[string]struct{})
+	for _, e := range entries {
+		if e.ExternalReference.GroupID == RecurringGroup {
+			logged[e.ExternalReference.ID] = struct{}{}
+		}
+	}
+
+	var missing []*RecurringEntry
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		for _, r := range t.conf.Recurring {
+			if !t.conf.RecursOn(r, d) {
+				continue
+			}
+			if _, ok := logged[r.Reference(d)]; ok {
+				continue
+			}
+			missing = append(missing, &RecurringEntry{r, d})
+		}
+	}
+
+	return missing, nil
+}
+
+// LogRecurring logs a recurring entry with its external_reference.
+func (t *Timetracking) LogRecurring(ctx context.Context, r *RecurringEntry) (*harvest.TimeEntry, error) {
+	return t.LogTime(
+		ctx,
+		r.ProjectID,
+		r.TaskID,
+		r.Date,
+		time.Duration(r.Hours*float64(time.Hour)),
+		r.Notes,
+		&harvest.ExternalReference{ID: r.Reference(r.Date), GroupID: RecurringGroup},
+	)
+}
@@ -0,0 +1,174 @@
+package timetracking
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// RolloverForfeit drops the hours of a retainer that are not used in
+	// their month.
+	RolloverForfeit = "forfeit"
+	// RolloverCarry adds the unused hours of a month to the next one.
+	RolloverCarry = "carry"
+)
+
+// Retainer is a fixed amount of hours a project is paid every month. Unused
+// hours are forfeited or, with rollover carry, added to the next month, at
+// most max_carry of them when it is set. Months are counted from start
+// (2006-01), which defaults to january of this year.
+type Retainer struct {
+	Hours    float64 `json:"hours"`
+	Rollover string  `json:"rollover,omitempty"`
+	MaxCarry float64 `json:"max_carry,omitempty"`
+	Start    string  `json:"start,omitempty"`
+}
+
+func (r *Retainer) validate(project string) error {
+	if r.Hours <= 0 {
+		return fmt.Errorf("Retainer '%s' requires hours", project)
+	}
+	if r.MaxCarry < 0 {
+		return fmt.Errorf("Retainer '%s' has an invalid max_carry", project)
+	}
+
+	r.Rollover = strings.ToLower(strings.TrimSpace(r.Rollover))
+	switch r.Rollover {
+	case "":
+		r.Rollover = RolloverForfeit
+	case RolloverForfeit, RolloverCarry:
+	default:
+		return fmt.Errorf(
+			"Invalid rollover '%s' of retainer '%s' expected %s or %s",
+			r.Rollover,
+			project,
+			RolloverForfeit,
+			RolloverCarry,
+		)
+	}
+
+	if r.Start != "" {
+		if _, err := time.Parse("2006-01", r.Start); err != nil {
+			return fmt.Errorf("Invalid start '%s' of retainer '%s' expected YYYY-MM", r.Start, project)
+		}
+	}
+
+	return nil
+}
+
+// start returns the first month of r in loc, january of the year of now
+// when no start is set.
+func (r *Retainer) start(now time.Time, loc *time.Location) time.Time {
+	if r.Start != "" {
+		if start, err := time.ParseInLocation("2006-01", r.Start, loc); err == nil {
+			return start
+		}
+	}
+
+	return time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, loc)
+}
+
+// RetainerMonth is a month of a retainer. The allowance is the hours of the
+// retainer plus the hours carried over from the month before.
+type RetainerMonth struct {
+	Month     time.Time `json:"month"`
+	Allowance Duration  `json:"allowance"`
+	Carried   Duration  `json:"carried"`
+	Delivered Duration  `json:"delivered"`
+}
+
+// Remaining returns the allowance that is not delivered.
+func (m *RetainerMonth) Remaining() Duration {
+	if m.Delivered > m.Allowance {
+		return 0
+	}
+	return m.Allowance - m.Delivered
+}
+
+// Over returns how much more than the allowance is delivered.
+func (m *RetainerMonth) Over() Duration {
+	if m.Delivered < m.Allowance {
+		return 0
+	}
+	return m.Delivered - m.Allowance
+}
+
+// RetainerReport is the delivered hours of a project against its retainer,
+// the most recent month first.
+type RetainerReport struct {
+	ProjectID int              `json:"project_id"`
+	Project   string           `json:"project"`
+	Client    string           `json:"client"`
+	Hours     Duration         `json:"hours"`
+	Rollover  string           `json:"rollover"`
+	Months    []*RetainerMonth `json:"months"`
+}
+
+// GetRetainer compares the hours everyone logged on project in every month
+// from the start of r up to and including the month of to with the retainer.
+// Only the last months are returned, all of them when months is 0.
+func (t *Timetracking) GetRetainer(
+	ctx context.Context,
+	project string,
+	r *Retainer,
+	to time.Time,
+	months int,
+) (*RetainerReport, error) {
+	p, err := t.FindProject(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+
+	start := r.start(t.Now(), t.loc)
+	entries, err := t.GetProjectEntries(ctx, p.ID, &start)
+	if err != nil {
+		return nil, err
+	}
+
+	delivered := make(map[string]Duration)
+	for _, e := range entries {
+		if e.SpentDate == nil {
+			continue
+		}
+		delivered[e.SpentDate.Format("2006-01")] += Duration(e.Hours.Duration)
+	}
+
+	report := &RetainerReport{
+		ProjectID: p.ID,
+		Project:   p.Name,
+		Client:    p.Client.Name,
+		Hours:     Duration(r.Hours * float64(time.Hour)),
+		Rollover:  r.Rollover,
+	}
+
+	maxCarry := Duration(r.MaxCarry * float64(time.Hour))
+	var carried Duration
+	for m := start; !m.After(to); m = m.AddDate(0, 1, 0) {
+		month := &RetainerMonth{
+			Month:     m,
+			Allowance: report.Hours + carried,
+			Carried:   carried,
+			Delivered: delivered[m.Format("2006-01")],
+		}
+		report.Months = append(report.Months, month)
+
+		carried = 0
+		if r.Rollover == RolloverCarry {
+			carried = month.Remaining()
+			if maxCarry != 0 && carried > maxCarry {
+				carried = maxCarry
+			}
+		}
+	}
+
+	if months > 0 && len(report.Months) > months {
+		report.Months = report.Months[len(report.Months)-months:]
+	}
+	for i, j := 0, len(report.Months)-1; i < j; i, j = i+1, j-1 {
+		report.Months[i], report.Months[j] = report.Months[j], report.Months[i]
+	}
+
+	return report, nil
+}
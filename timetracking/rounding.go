@@ -0,0 +1,171 @@
+package timetracking
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
+
+// The directions hours can be rounded in.
+const (
+	RoundNearest = "nearest"
+	RoundUp      = "up"
+	RoundDown    = "down"
+)
+
+// What hours are rounded per.
+const (
+	RoundPerEntry   = "entry"
+	RoundPerDay     = "day"
+	RoundPerProject = "project"
+)
+
+// Rounding rounds the hours of time entries to a multiple of Interval,
+// either every entry on its own or the total of every day or project.
+// It only rounds once Validate ran, use NewRounding outside of a config.
+type Rounding struct {
+	Interval string `json:"interval"`
+	Mode     string `json:"mode,omitempty"`
+	Per      string `json:"per,omitempty"`
+
+	interval time.Duration
+}
+
+// NewRounding returns a validated Rounding to a multiple of interval.
+func NewRounding(interval time.Duration, mode, per string) (*Rounding, error) {
+	r := &Rounding{Interval: interval.String(), Mode: mode, Per: per}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *Rounding) Validate() error {
+	d, err := time.ParseDuration(r.Interval)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("Invalid rounding interval '%s' expected e.g. 15m", r.Interval)
+	}
+	r.interval = d
+
+	switch r.Mode {
+	case "", RoundNearest, RoundUp, RoundDown:
+	default:
+		return fmt.Errorf(
+			"Invalid rounding mode '%s' expected %s, %s or %s",
+			r.Mode,
+			RoundNearest,
+			RoundUp,
+			RoundDown,
+		)
+	}
+
+	switch r.Per {
+	case "", RoundPerEntry, RoundPerDay, RoundPerProject:
+	default:
+		return fmt.Errorf(
+			"Invalid rounding per '%s' expected %s, %s or %s",
+			r.Per,
+			RoundPerEntry,
+			RoundPerDay,
+			RoundPerProject,
+		)
+	}
+
+	return nil
+}
+
+// Round rounds d to a multiple of the interval, d is returned as is when
+// there is none.
+func (r *Rounding) Round(d time.Duration) time.Duration {
+	if r.interval <= 0 {
+		return d
+	}
+
+	switch r.Mode {
+	case RoundUp:
+		if rem := d % r.interval; rem > 0 {
+			return d - rem + r.interval
+		}
+		return d
+	case RoundDown:
+		return d - d%r.interval
+	}
+
+	return d.Round(r.interval)
+}
+
+// Apply returns copies of entries with rounded hours, entries are left
+// untouched when r is nil. When rounding the total of a day or project
+// the difference is added to or taken from its largest entries so that
+// every grouping of the result adds up to the rounded totals.
+func (r *Rounding) Apply(entries harvest.TimeEntries) harvest.TimeEntries {
+	if r == nil {
+		return entries
+	}
+
+	rounded := make(harvest.TimeEntries, len(entries))
+	for i, e := range entries {
+		c := *e
+		rounded[i] = &c
+	}
+
+	if r.Per == "" || r.Per == RoundPerEntry {
+		for _, e := range rounded {
+			e.Hours.Duration = r.Round(e.Hours.Duration)
+		}
+		return rounded
+	}
+
+	key := func(e *harvest.TimeEntry) string {
+		if r.Per == RoundPerDay {
+			if e.SpentDate == nil {
+				return ""
+			}
+			return e.SpentDate.Format(DateFormat)
+		}
+		return strconv.Itoa(e.Project.ID)
+	}
+
+	sets := make(map[string]harvest.TimeEntries)
+	for _, e := range rounded {
+		k := key(e)
+		sets[k] = append(sets[k], e)
+	}
+
+	for _, set := range sets {
+		var total time.Duration
+		for _, e := range set {
+			total += e.Hours.Duration
+		}
+
+		diff := r.Round(total) - total
+		if diff == 0 {
+			continue
+		}
+
+		sort.SliceStable(set, func(i, j int) bool {
+			return set[i].Hours.Duration > set[j].Hours.Duration
+		})
+		if diff > 0 {
+			set[0].Hours.Duration += diff
+			continue
+		}
+
+		for _, e := range set {
+			take := -diff
+			if take > e.Hours.Duration {
+				take = e.Hours.Duration
+			}
+			e.Hours.Duration -= take
+			if diff += take; diff == 0 {
+				break
+			}
+		}
+	}
+
+	return rounded
+}
@@ -0,0 +1,146 @@
+package timetracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
+
+func TestRoundingRound(t *testing.T) {
+	tests := []struct {
+		mode string
+		in   time.Duration
+		want time.Duration
+	}{
+		{RoundNearest, 7 * time.Minute, 0},
+		{RoundNearest, 8 * time.Minute, 15 * time.Minute},
+		{RoundNearest, 52 * time.Minute, 45 * time.Minute},
+		{RoundNearest, 30 * time.Minute, 30 * time.Minute},
+		{"", 53 * time.Minute, time.Hour},
+		{RoundUp, time.Minute, 15 * time.Minute},
+		{RoundUp, 15 * time.Minute, 15 * time.Minute},
+		{RoundUp, 0, 0},
+		{RoundDown, 29 * time.Minute, 15 * time.Minute},
+		{RoundDown, 14 * time.Minute, 0},
+		{RoundDown, 45 * time.Minute, 45 * time.Minute},
+	}
+
+	for _, test := range tests {
+		t.Run(test.mode+" "+test.in.String(), func(t *testing.T) {
+			r, err := NewRounding(15*time.Minute, test.mode, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r.Round(test.in); got != test.want {
+				t.Errorf("Round(%s) = %s, want %s", test.in, got, test.want)
+			}
+		})
+	}
+}
+
+func TestNewRoundingInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		mode     string
+		per      string
+	}{
+		{"no interval", 0, "", ""},
+		{"negative interval", -time.Minute, "", ""},
+		{"mode", 15 * time.Minute, "sideways", ""},
+		{"per", 15 * time.Minute, "", "week"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := NewRounding(test.interval, test.mode, test.per); err == nil {
+				t.Errorf("NewRounding(%s, %q, %q) did not fail", test.interval, test.mode, test.per)
+			}
+		})
+	}
+}
+
+func TestRoundingApply(t *testing.T) {
+	entry := func(id, project int, day string, d time.Duration) *harvest.TimeEntry {
+		date, err := time.Parse(DateFormat, day)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &harvest.TimeEntry{
+			ID:        id,
+			Project:   harvest.ProjectRef{ID: project},
+			SpentDate: &harvest.Date{date},
+			Hours:     harvest.DurationHours{d},
+		}
+	}
+
+	entries := harvest.TimeEntries{
+		entry(1, 10, "2024-03-04", 20*time.Minute),
+		entry(2, 10, "2024-03-04", 25*time.Minute),
+		entry(3, 20, "2024-03-04", 5*time.Minute),
+		entry(4, 20, "2024-03-05", 50*time.Minute),
+	}
+
+	tests := []struct {
+		name string
+		mode string
+		per  string
+		want map[int]time.Duration
+	}{
+		{
+			name: "entry",
+			per:  RoundPerEntry,
+			want: map[int]time.Duration{1: 15 * time.Minute, 2: 30 * time.Minute, 3: 0, 4: 45 * time.Minute},
+		},
+		{
+			// 50m on both days rounds to 45m, taken from the largest entry.
+			name: "day",
+			per:  RoundPerDay,
+			want: map[int]time.Duration{1: 20 * time.Minute, 2: 20 * time.Minute, 3: 5 * time.Minute, 4: 45 * time.Minute},
+		},
+		{
+			// 45m of project 10 is a multiple, 55m of project 20 rounds up
+			// to an hour on its largest entry.
+			name: "project up",
+			mode: RoundUp,
+			per:  RoundPerProject,
+			want: map[int]time.Duration{1: 20 * time.Minute, 2: 25 * time.Minute, 3: 5 * time.Minute, 4: 55 * time.Minute},
+		},
+		{
+			// 55m of project 20 rounds down to 45m.
+			name: "project down",
+			mode: RoundDown,
+			per:  RoundPerProject,
+			want: map[int]time.Duration{1: 20 * time.Minute, 2: 25 * time.Minute, 3: 5 * time.Minute, 4: 40 * time.Minute},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := NewRounding(15*time.Minute, test.mode, test.per)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rounded := r.Apply(entries)
+			if len(rounded) != len(entries) {
+				t.Fatalf("Apply returned %d entries, want %d", len(rounded), len(entries))
+			}
+			for _, e := range rounded {
+				if e.Hours.Duration != test.want[e.ID] {
+					t.Errorf("entry %d = %s, want %s", e.ID, e.Hours.Duration, test.want[e.ID])
+				}
+			}
+		})
+	}
+
+	if entries[0].Hours.Duration != 20*time.Minute {
+		t.Errorf("Apply modified its input: entry 1 = %s", entries[0].Hours.Duration)
+	}
+
+	var r *Rounding
+	if got := r.Apply(entries); len(got) != len(entries) || got[0] != entries[0] {
+		t.Error("Apply of a nil Rounding should return the entries as is")
+	}
+}
@@ -0,0 +1,91 @@
+package timetracking
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
+
+// TaskShare is the part of the tracked hours spent on a task, on any
+// project, in a period and the one before it.
+type TaskShare struct {
+	Name          string   `json:"name"`
+	Projects      []string `json:"projects"`
+	Hours         Duration `json:"hours"`
+	Share         float64  `json:"share"`
+	Previous      Duration `json:"previous_hours"`
+	PreviousShare float64  `json:"previous_share"`
+}
+
+// Trend returns the change in hours since the previous period.
+func (s *TaskShare) Trend() Duration {
+	return s.Hours - s.Previous
+}
+
+// ShareTrend returns the change in share, in percentage points.
+func (s *TaskShare) ShareTrend() float64 {
+	return s.Share - s.PreviousShare
+}
+
+// TaskShares sums the hours per task of entries and previous, shares are
+// percentages of the total hours of their period. Tasks that were only
+// tracked in the previous period are included with no hours.
+func TaskShares(entries, previous harvest.TimeEntries) []*TaskShare {
+	byTask := make(map[string]*TaskShare)
+	projects := make(map[string]map[string]struct{})
+	get := func(e *harvest.TimeEntry) *TaskShare {
+		key := strconv.Itoa(e.Task.ID)
+		if e.Task.ID == 0 {
+			key = strings.ToLower(e.Task.Name)
+		}
+		s, ok := byTask[key]
+		if !ok {
+			s = &TaskShare{Name: e.Task.Name}
+			byTask[key] = s
+			projects[key] = make(map[string]struct{})
+		}
+		projects[key][e.Project.Name] = struct{}{}
+		return s
+	}
+
+	var total, prevTotal Duration
+	for _, e := range entries {
+		h := Duration(e.Hours.Duration)
+		get(e).Hours += h
+		total += h
+	}
+	for _, e := range previous {
+		h := Duration(e.Hours.Duration)
+		get(e).Previous += h
+		prevTotal += h
+	}
+
+	list := make([]*TaskShare, 0, len(byTask))
+	for key, s := range byTask {
+		if total != 0 {
+			s.Share = 100 * float64(s.Hours) / float64(total)
+		}
+		if prevTotal != 0 {
+			s.PreviousShare = 100 * float64(s.Previous) / float64(prevTotal)
+		}
+		for p := range projects[key] {
+			s.Projects = append(s.Projects, p)
+		}
+		sort.Strings(s.Projects)
+		list = append(list, s)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Hours != list[j].Hours {
+			return list[i].Hours > list[j].Hours
+		}
+		if list[i].Previous != list[j].Previous {
+			return list[i].Previous > list[j].Previous
+		}
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
@@ -0,0 +1,161 @@
+package timetracking
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// RollingWeeks is the amount of weeks the rolling average is taken over.
+const RollingWeeks = 4
+
+// WeekStats are the hours tracked in a week and the average of the
+// RollingWeeks weeks up to and including it.
+type WeekStats struct {
+	Start   time.Time `json:"start"`
+	Hours   Duration  `json:"hours"`
+	Rolling Duration  `json:"rolling_average"`
+}
+
+// WeekdayStats is the average tracked on the working days of a weekday.
+type WeekdayStats struct {
+	Weekday time.Weekday `json:"-"`
+	Name    string       `json:"weekday"`
+	Days    int          `json:"days"`
+	Average Duration     `json:"average"`
+}
+
+// Stats summarize the hours tracked on the working days of a period.
+type Stats struct {
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"`
+	Weeks    []*WeekStats    `json:"weeks"`
+	Weekdays []*WeekdayStats `json:"weekdays"`
+	Days     int             `json:"working_days"`
+	Average  Duration        `json:"daily_average"`
+	StdDev   Duration        `json:"daily_stddev"`
+	OnTarget int             `json:"on_target_days"`
+
+	// Longest is the longest streak of working days on which at least
+	// their target was tracked, it ended on LongestEnd. Current is the
+	// streak that lasts until To.
+	Longest    int        `json:"longest_streak"`
+	LongestEnd *time.Time `json:"longest_streak_end,omitempty"`
+	Current    int        `json:"current_streak"`
+}
+
+// Busiest returns the weekday with the highest average.
+func (s *Stats) Busiest() *WeekdayStats {
+	var busiest *WeekdayStats
+	for _, wd := range s.Weekdays {
+		if busiest == nil || wd.Average > busiest.Average {
+			busiest = wd
+		}
+	}
+
+	return busiest
+}
+
+// GetStats computes the stats of the weeks between from and to, which
+// should start on the first day of a week. Hours tracked on days off count
+// for the working day before them, days without a target (days off,
+// excluded days, holidays and whole absences) neither count as working days
+// nor break a streak.
+func (t *Timetracking) GetStats(ctx context.Context, capacity Duration, from, to time.Time) (*Stats, error) {
+	// The rolling average of the first weeks includes the weeks before.
+	fetchFrom := from.AddDate(0, 0, -7*(RollingWeeks-1))
+	_, entries, err := t.GetRange(ctx, fetchFrom, to, true)
+	if err != nil {
+		return nil, err
+	}
+
+	grouped, err := t.Group(entries, GroupByDay, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tracked := make(map[string]Duration, len(grouped))
+	for _, g := range grouped {
+		tracked[g.Key.Name] += Duration(g.Hours)
+	}
+
+	s := &Stats{From: from, To: to}
+	var weeks []Duration
+	var daily []float64
+	weekdays := make(map[time.Weekday]*WeekdayStats)
+	streak := 0
+	for week := fetchFrom; !week.After(to); week = week.AddDate(0, 0, 7) {
+		var hours Duration
+		for d := week; d.Before(week.AddDate(0, 0, 7)) && !d.After(to); d = d.AddDate(0, 0, 1) {
+			h := tracked[d.Format(DateFormat)]
+			hours += h
+			if d.Before(from) {
+				continue
+			}
+
+			target := t.conf.DayTarget(capacity, d)
+			if target <= 0 {
+				continue
+			}
+
+			s.Days++
+			daily = append(daily, float64(h))
+			wd, ok := weekdays[d.Weekday()]
+			if !ok {
+				wd = &WeekdayStats{Weekday: d.Weekday(), Name: d.Weekday().String()}
+				weekdays[d.Weekday()] = wd
+			}
+			wd.Days++
+			wd.Average += h
+
+			if h < target {
+				streak = 0
+				continue
+			}
+			s.OnTarget++
+			if streak++; streak > s.Longest {
+				end := d
+				s.Longest, s.LongestEnd = streak, &end
+			}
+		}
+
+		weeks = append(weeks, hours)
+		if week.Before(from) {
+			continue
+		}
+
+		var rolling Duration
+		n := min(RollingWeeks, len(weeks))
+		for _, h := range weeks[len(weeks)-n:] {
+			rolling += h
+		}
+		s.Weeks = append(s.Weeks, &WeekStats{week, hours, rolling / Duration(n)})
+	}
+	s.Current = streak
+
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		w, ok := weekdays[(wd+t.weekStart)%7]
+		if !ok {
+			continue
+		}
+		w.Average /= Duration(w.Days)
+		s.Weekdays = append(s.Weekdays, w)
+	}
+
+	if len(daily) != 0 {
+		var sum float64
+		for _, h := range daily {
+			sum += h
+		}
+		mean := sum / float64(len(daily))
+
+		var variance float64
+		for _, h := range daily {
+			variance += (h - mean) * (h - mean)
+		}
+		s.Average = Duration(mean)
+		s.StdDev = Duration(math.Sqrt(variance / float64(len(daily))))
+	}
+
+	return s, nil
+}
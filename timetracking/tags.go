@@ -0,0 +1,59 @@
+package timetracking
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
+
+// NoTag is the group of entries without a tag.
+const NoTag = "no tag"
+
+// tagRegex matches a #tag at the start of the notes or after whitespace, so
+// urls with a fragment are not tagged. Tags can contain a colon or slash to
+// build dimensions like #cc:marketing.
+var tagRegex = regexp.MustCompile(`(?:^|\s)#([\p{L}\p{N}_][\p{L}\p{N}_:/-]*)`)
+
+// Tags returns the #tags in the notes of e in the order they appear,
+// without the # and lowercased.
+func Tags(e *harvest.TimeEntry) []string {
+	matches := tagRegex.FindAllStringSubmatch(e.Notes, -1)
+	tags := make([]string, 0, len(matches))
+	for _, m := range matches {
+		tags = append(tags, strings.ToLower(m[1]))
+	}
+
+	return tags
+}
+
+// tagGrouper groups entries by their first tag that starts with prefix:, or
+// their first tag when prefix is empty. Totals should add up, so an entry
+// with several of those tags only counts for the first.
+func tagGrouper(prefix string) harvest.Grouper {
+	prefix = strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(prefix, "#"), ":"))
+	if prefix != "" {
+		prefix += ":"
+	}
+
+	return func(e *harvest.TimeEntry) (harvest.GroupKey, bool) {
+		for _, tag := range Tags(e) {
+			if strings.HasPrefix(tag, prefix) {
+				return harvest.Key("#"+tag, tag), e.ID != 0
+			}
+		}
+		return harvest.Key(NoTag), e.ID != 0
+	}
+}
+
+// HasTag reports whether the notes of e contain #tag, case insensitive.
+func HasTag(e *harvest.TimeEntry, tag string) bool {
+	tag = strings.TrimPrefix(tag, "#")
+	for _, t := range Tags(e) {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+
+	return false
+}
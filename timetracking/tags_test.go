@@ -0,0 +1,80 @@
+package timetracking
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
+
+func TestTags(t *testing.T) {
+	tests := []struct {
+		notes string
+		want  []string
+	}{
+		{"#Infra fix the build", []string{"infra"}},
+		{"Meeting #cc:Marketing #b/c-d", []string{"cc:marketing", "b/c-d"}},
+		{"see http://example.com/#frag", []string{}},
+		{"a#b", []string{}},
+		{"# not a tag", []string{}},
+		{"fix\t#bug, then #review.", []string{"bug", "review"}},
+		{"", []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.notes, func(t *testing.T) {
+			got := Tags(&harvest.TimeEntry{Notes: test.notes})
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Tags(%q) = %v, want %v", test.notes, got, test.want)
+			}
+		})
+	}
+}
+
+func TestHasTag(t *testing.T) {
+	e := &harvest.TimeEntry{Notes: "Deploy #Infra #cc:ops"}
+	tests := []struct {
+		tag  string
+		want bool
+	}{
+		{"infra", true},
+		{"#INFRA", true},
+		{"cc:ops", true},
+		{"cc", false},
+		{"inf", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tag, func(t *testing.T) {
+			if got := HasTag(e, test.tag); got != test.want {
+				t.Errorf("HasTag(%q, %q) = %t, want %t", e.Notes, test.tag, got, test.want)
+			}
+		})
+	}
+}
+
+func TestTagGrouper(t *testing.T) {
+	tests := []struct {
+		prefix string
+		notes  string
+		want   harvest.GroupKey
+	}{
+		{"", "#infra #cc:ops", harvest.Key("#infra", "infra")},
+		{"", "no tags", harvest.Key(NoTag)},
+		{"cc", "#infra #cc:ops #cc:sales", harvest.Key("#cc:ops", "cc:ops")},
+		{"#CC:", "#infra #cc:ops", harvest.Key("#cc:ops", "cc:ops")},
+		{"cc", "#infra #ccx", harvest.Key(NoTag)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.prefix+" "+test.notes, func(t *testing.T) {
+			got, include := tagGrouper(test.prefix)(&harvest.TimeEntry{ID: 1, Notes: test.notes})
+			if !include {
+				t.Errorf("tagGrouper(%q) excluded %q", test.prefix, test.notes)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("tagGrouper(%q)(%q) = %#v, want %#v", test.prefix, test.notes, got, test.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,11 @@
-package main
+package timetracking
 
 import (
 	"fmt"
 	"sort"
 	"strings"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
 )
 
 type Task struct {
@@ -22,6 +24,28 @@ func (t *Task) String() string {
 
 type Tasks []*Task
 
+// NewTasks lists every task of the given project assignments.
+func NewTasks(res []*harvest.UserAssignment) Tasks {
+	d := make(Tasks, 0, len(res))
+	for _, a := range res {
+		for _, t := range a.TaskAssignments {
+			d = append(
+				d,
+				&Task{
+					ClientID:    a.Client.ID,
+					ProjectID:   a.Project.ID,
+					TaskID:      t.Task.ID,
+					ClientName:  a.Client.Name,
+					ProjectName: a.Project.Name,
+					TaskName:    t.Task.Name,
+				},
+			)
+		}
+	}
+
+	return d
+}
+
 type Score struct {
 	Task  *Task
 	Score int
@@ -0,0 +1,57 @@
+package timetracking
+
+import (
+	"context"
+	"time"
+)
+
+// taskNamesTTL is how long project and task names are cached before they
+// are fetched from harvest again.
+const taskNamesTTL = 24 * time.Hour
+
+type cachedTaskNames struct {
+	Fetched time.Time           `json:"fetched"`
+	Names   map[string][]string `json:"names"`
+}
+
+// TaskNames returns the task names per project you are assigned to,
+// they are cached for a day so e.g. shell completion stays fast.
+func (t *Timetracking) TaskNames(ctx context.Context) (map[string][]string, error) {
+	key := "tasknames|" + t.conf.AccountID
+	var cached cachedTaskNames
+	if t.cache != nil {
+		ok, err := t.cache.Get(key, &cached)
+		if err == nil && ok && time.Since(cached.Fetched) < taskNamesTTL {
+			return cached.Names, nil
+		}
+	}
+
+	if err := t.SetUID(ctx, 0); err != nil {
+		return nil, err
+	}
+
+	assignments, err := t.GetUserProjectAssignments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string][]string, len(assignments))
+	for _, a := range assignments {
+		if a.Project == nil {
+			continue
+		}
+		tasks := make([]string, 0, len(a.TaskAssignments))
+		for _, ta := range a.TaskAssignments {
+			tasks = append(tasks, ta.Task.Name)
+		}
+		names[a.Project.Name] = tasks
+	}
+
+	if t.cache != nil {
+		if err := t.cache.Set(key, &cachedTaskNames{time.Now(), names}); err != nil {
+			t.l.Warn("Failed to write cache", "err", err)
+		}
+	}
+
+	return names, nil
+}
@@ -0,0 +1,104 @@
+package timetracking
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	StyleNone     = ""
+	StyleHeader   = "header"
+	StyleWeekend  = "weekend"
+	StyleUnder    = "under"
+	StyleOnTarget = "on_target"
+)
+
+var ansiColors = map[string]string{
+	"bold":    "1",
+	"dim":     "2",
+	"italic":  "3",
+	"red":     "31",
+	"green":   "32",
+	"yellow":  "33",
+	"blue":    "34",
+	"magenta": "35",
+	"cyan":    "36",
+	"white":   "37",
+}
+
+// Theme maps the table styles to space separated color names
+// (e.g. "bold red") or raw SGR parameters (e.g. "38;5;208").
+type Theme struct {
+	Header   string `json:"header,omitempty"`
+	Weekend  string `json:"weekend,omitempty"`
+	Under    string `json:"under,omitempty"`
+	OnTarget string `json:"on_target,omitempty"`
+}
+
+var DefaultTheme = Theme{
+	Header:   "bold",
+	Weekend:  "dim",
+	Under:    "red",
+	OnTarget: "green",
+}
+
+// Merge returns the default theme with the styles that are set in t.
+func (t *Theme) Merge() (Theme, error) {
+	m := DefaultTheme
+	if t == nil {
+		return m, nil
+	}
+
+	styles := map[*string]string{
+		&m.Header:   t.Header,
+		&m.Weekend:  t.Weekend,
+		&m.Under:    t.Under,
+		&m.OnTarget: t.OnTarget,
+	}
+	for dst, v := range styles {
+		if v == "" {
+			continue
+		}
+		if _, err := SGR(v); err != nil {
+			return m, err
+		}
+		*dst = v
+	}
+
+	return m, nil
+}
+
+// Style returns the colors of the named style.
+func (t Theme) Style(name string) string {
+	switch name {
+	case StyleHeader:
+		return t.Header
+	case StyleWeekend:
+		return t.Weekend
+	case StyleUnder:
+		return t.Under
+	case StyleOnTarget:
+		return t.OnTarget
+	}
+	return ""
+}
+
+// SGR converts color names to an ansi escape sequence.
+func SGR(colors string) (string, error) {
+	var codes []string
+	for _, c := range strings.Fields(colors) {
+		if code, ok := ansiColors[strings.ToLower(c)]; ok {
+			codes = append(codes, code)
+			continue
+		}
+		if strings.Trim(c, "0123456789;") != "" {
+			return "", fmt.Errorf("Invalid color '%s'", c)
+		}
+		codes = append(codes, c)
+	}
+
+	if len(codes) == 0 {
+		return "", nil
+	}
+	return "\033[" + strings.Join(codes, ";") + "m", nil
+}
@@ -0,0 +1,1350 @@
+package timetracking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/cache"
+	"github.com/frizinak/harvest-timetracking/currency"
+	"github.com/frizinak/harvest-timetracking/forecast"
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
+
+const DateFormat = "2006-01-02"
+
+type Timetracking struct {
+	l            *slog.Logger
+	conf         *Config
+	harvest      *harvest.Harvest
+	forecast     *forecast.Forecast
+	user         *harvest.User
+	self         bool
+	forecastUser *forecast.User
+	cache        *cache.Cache
+	issueRegex   *regexp.Regexp
+
+	// weekStart and timeFormat come from the config, or the harvest
+	// company once LoadCompany ran.
+	weekStart  time.Weekday
+	timeFormat string
+	// loc is the timezone days start and end in.
+	loc *time.Location
+	// separators are those of the harvest company once LoadCompany ran.
+	separators currency.Separators
+}
+
+// New creates a client for the harvest and forecast accounts in c, options
+// (e.g. harvest.WithUserAgent) apply to both and take precedence over c.
+// Requests are logged to l at debug level, progress at info.
+func New(l *slog.Logger, c *Config, options ...harvest.Option) (*Timetracking, error) {
+	aid, err := strconv.Atoi(c.AccountID)
+	if err != nil {
+		return nil, errors.New("account_id should be a numeric value")
+	}
+
+	fid := 0
+	if c.ForecastAccountID != "" {
+		fid, err = strconv.Atoi(c.ForecastAccountID)
+		if err != nil {
+			return nil, errors.New("forecast_account_id should be a numeric value or empty")
+		}
+	}
+
+	opts := []harvest.Option{harvest.WithUserAgent("timetracking")}
+	if c.MaxAttempts != 0 {
+		opts = append(opts, harvest.WithHTTPClient(harvest.NewRetryClient(c.MaxAttempts)))
+	}
+	opts = append(opts, options...)
+
+	opts = append(opts, harvest.WithLogger(l))
+
+	hopts, fopts := opts, opts
+	if c.HarvestURL != "" {
+		hopts = append([]harvest.Option{harvest.WithBaseURL(c.HarvestURL)}, opts...)
+	}
+	if c.ForecastURL != "" {
+		fopts = append([]harvest.Option{harvest.WithBaseURL(c.ForecastURL)}, opts...)
+	}
+
+	h := harvest.New(aid, c.Token, hopts...)
+	f := forecast.New(fid, c.Token, fopts...)
+
+	weekStart := time.Monday
+	if c.weekStart != nil {
+		weekStart = *c.weekStart
+	}
+
+	loc := time.Local
+	if c.location != nil {
+		loc = c.location
+	}
+
+	return &Timetracking{
+		l:          l,
+		conf:       c,
+		harvest:    h,
+		forecast:   f,
+		weekStart:  weekStart,
+		timeFormat: c.TimeFormat,
+		loc:        loc,
+	}, nil
+}
+
+// Location returns the timezone days start and end in, the timezone of the
+// config or the local one.
+func (t *Timetracking) Location() *time.Location {
+	return t.loc
+}
+
+// SetLocation changes the timezone days start and end in.
+func (t *Timetracking) SetLocation(loc *time.Location) {
+	t.loc = loc
+}
+
+// Now returns the current time in the timezone of t.
+func (t *Timetracking) Now() time.Time {
+	return time.Now().In(t.loc)
+}
+
+// Today returns the start of the current day in the timezone of t.
+func (t *Timetracking) Today() time.Time {
+	return Day(t.Now())
+}
+
+// Day returns the start of the calendar day of d in the timezone of t,
+// d keeps its date (e.g. the spent date of an entry) even when it is in
+// another timezone.
+func (t *Timetracking) Day(d time.Time) time.Time {
+	y, m, dd := d.Date()
+	return time.Date(y, m, dd, 0, 0, 0, 0, t.loc)
+}
+
+// Date returns the start of the given day in the timezone of t.
+func (t *Timetracking) Date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, t.loc)
+}
+
+// ParseDate parses a YYYY-MM-DD date in the timezone of t.
+func (t *Timetracking) ParseDate(s string) (time.Time, error) {
+	return time.ParseInLocation(DateFormat, s, t.loc)
+}
+
+// WeekStart returns the first day of a week.
+func (t *Timetracking) WeekStart() time.Weekday {
+	return t.weekStart
+}
+
+// StartOfWeek returns the first day of the week d is in.
+func (t *Timetracking) StartOfWeek(d time.Time) time.Time {
+	return StartOfWeek(t.Day(d), t.weekStart)
+}
+
+// Period returns the first and last day of a named period relative to now.
+func (t *Timetracking) Period(name string, now time.Time) (from, to time.Time, err error) {
+	return Period(name, now.In(t.loc), t.weekStart)
+}
+
+// TimeFormat returns the harvest time format durations are printed in,
+// see Duration.Format.
+func (t *Timetracking) TimeFormat() string {
+	return t.timeFormat
+}
+
+// Separators returns the separators amounts of money are written with.
+func (t *Timetracking) Separators() currency.Separators {
+	return t.separators
+}
+
+func (t *Timetracking) SetUID(ctx context.Context, uid int) (err error) {
+	t.user = nil
+	t.self = uid == 0
+	if uid == 0 {
+		t.user, err = t.harvest.GetMe(ctx)
+		return
+	}
+
+	t.user, err = t.harvest.GetUser(ctx, uid)
+	return
+}
+
+func (t *Timetracking) SetForecastUID(ctx context.Context, uid int) (err error) {
+	t.forecastUser = nil
+	var me *forecast.Me
+	if uid == 0 {
+		me, err = t.forecast.GetMe(ctx)
+		if err != nil {
+			return
+		}
+		uid = me.ID
+	}
+
+	t.forecastUser, err = t.forecast.GetUser(ctx, uid)
+	return
+}
+
+func (t *Timetracking) SetTokenSource(ts harvest.TokenSource) {
+	t.harvest.SetTokenSource(ts)
+	t.forecast.SetTokenSource(ts)
+}
+
+// SetCache enables caching of time entries, nil disables it.
+func (t *Timetracking) SetCache(c *cache.Cache) {
+	t.cache = c
+}
+
+// SetIssueRegex overrides the issue_regex of the config, the first match
+// in the notes of an entry is its issue key.
+func (t *Timetracking) SetIssueRegex(re *regexp.Regexp) {
+	t.issueRegex = re
+}
+
+func (t *Timetracking) IssueRegex() *regexp.Regexp {
+	if t.issueRegex == nil {
+		return t.conf.Issue()
+	}
+	return t.issueRegex
+}
+
+func (t *Timetracking) User() *harvest.User {
+	return t.user
+}
+
+// Capacity returns the weekly capacity of the user, unless it is
+// overridden in the config.
+func (t *Timetracking) Capacity() Duration {
+	return t.conf.Capacity(Duration(t.user.Capacity()))
+}
+
+func (t *Timetracking) ForecastUser() *forecast.User {
+	return t.forecastUser
+}
+
+func (t *Timetracking) GetRecentDaysGrouped(
+	ctx context.Context,
+	amount int,
+	from time.Time,
+	actualDays bool,
+	groupBy string,
+	filter harvest.Filter,
+) (int, harvest.Grouped, error) {
+	days, entries, err := t.GetRecentDays(ctx, amount, from, actualDays)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	group, err := t.Group(entries, groupBy, filter)
+	return days, group, err
+}
+
+func (t *Timetracking) GetRangeGrouped(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+	actualDays bool,
+	groupBy string,
+	filter harvest.Filter,
+) (int, harvest.Grouped, error) {
+	days, entries, err := t.GetRange(ctx, from, to, actualDays)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	group, err := t.Group(entries, groupBy, filter)
+	return days, group, err
+}
+
+// Group groups the entries that pass filter by one of Groups.
+func (t *Timetracking) Group(
+	entries harvest.TimeEntries,
+	groupBy string,
+	filter harvest.Filter,
+) (harvest.Grouped, error) {
+	grouper, err := t.Grouper(groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter != nil {
+		entries = entries.Filter(
+			func(e *harvest.TimeEntry) bool { return e.ID == 0 || filter(e) },
+		)
+	}
+
+	return entries.Group(grouper), nil
+}
+
+// Grouper returns a harvest.Grouper for one of the groupBy* constants, or
+// tag:prefix for the tags that start with prefix:. Date based groupers move
+// hours tracked on excluded days and days off to the previous working day.
+func (t *Timetracking) Grouper(groupBy string) (harvest.Grouper, error) {
+	if prefix, ok := strings.CutPrefix(groupBy, GroupByTag+":"); ok && prefix != "" {
+		return tagGrouper(prefix), nil
+	}
+
+	groupFormat := "2006-01-02"
+	switch groupBy {
+	case GroupByDay:
+	case GroupByWeek:
+	case GroupByMonth:
+		groupFormat = "2006-01"
+	case GroupByYear:
+		groupFormat = "2006"
+	case GroupByProject:
+		return func(e *harvest.TimeEntry) (harvest.GroupKey, bool) {
+			return harvest.Key(e.Project.Name, strconv.Itoa(e.Project.ID)), e.ID != 0
+		}, nil
+	case GroupByClient:
+		return func(e *harvest.TimeEntry) (harvest.GroupKey, bool) {
+			return harvest.Key(e.Client.Name, strconv.Itoa(e.Client.ID)), e.ID != 0
+		}, nil
+	case GroupByTask:
+		return func(e *harvest.TimeEntry) (harvest.GroupKey, bool) {
+			return harvest.Key(e.Task.Name, strconv.Itoa(e.Task.ID)), e.ID != 0
+		}, nil
+	case GroupByIssue:
+		re := t.IssueRegex()
+		return func(e *harvest.TimeEntry) (harvest.GroupKey, bool) {
+			key := e.IssueKey(re)
+			if key == "" {
+				return harvest.Key(NoIssue), e.ID != 0
+			}
+			return harvest.Key(key, key), e.ID != 0
+		}, nil
+	case GroupByTag:
+		return tagGrouper(""), nil
+	default:
+		return nil, fmt.Errorf("Invalid group '%s'", groupBy)
+	}
+
+	return func(e *harvest.TimeEntry) (harvest.GroupKey, bool) {
+		if e.SpentDate == nil {
+			return harvest.GroupKey{}, false
+		}
+
+		d := e.SpentDate.Time
+		for t.conf.Excluded(d) || t.conf.Off(d) {
+			d = d.AddDate(0, 0, -1)
+		}
+		e.SpentDate = &harvest.Date{d}
+
+		if groupBy == GroupByWeek {
+			w := t.StartOfWeek(d).Format(DateFormat)
+			return harvest.Key("week of "+w, w), true
+		}
+
+		f := e.SpentDate.Format(groupFormat)
+		return harvest.Key(f, f), true
+	}, nil
+}
+
+type cachedEntries struct {
+	Days    int                 `json:"days"`
+	Entries harvest.TimeEntries `json:"entries"`
+}
+
+func (t *Timetracking) GetRecentDays(
+	ctx context.Context,
+	amount int,
+	from time.Time,
+	actualDays bool,
+) (int, harvest.TimeEntries, error) {
+	return t.cached(
+		fmt.Sprintf("recent-days|%s|%d|%t", from.Format(DateFormat), amount, actualDays),
+		from,
+		func() (int, harvest.TimeEntries, error) {
+			return t.getRecentDays(ctx, amount, from, actualDays)
+		},
+	)
+}
+
+// GetRange fetches all entries spent between from and to (inclusive)
+// and returns them together with the amount of days, either the working days
+// in the range (actualDays) or the days that have entries.
+func (t *Timetracking) GetRange(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+	actualDays bool,
+) (int, harvest.TimeEntries, error) {
+	return t.cached(
+		fmt.Sprintf("range|%s|%s|%t", from.Format(DateFormat), to.Format(DateFormat), actualDays),
+		to,
+		func() (int, harvest.TimeEntries, error) {
+			return t.getRange(ctx, from, to, actualDays)
+		},
+	)
+}
+
+// cached returns the cached result of fetch, periods that end today
+// or later are never cached.
+func (t *Timetracking) cached(
+	key string,
+	to time.Time,
+	fetch func() (int, harvest.TimeEntries, error),
+) (int, harvest.TimeEntries, error) {
+	y, m, d := t.Now().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, to.Location())
+	if t.cache == nil || !to.Before(today) {
+		return fetch()
+	}
+
+	absences := make([]string, 0, len(t.conf.Absences))
+	for date, a := range t.conf.Absences {
+		if a.whole() {
+			absences = append(absences, date)
+		}
+	}
+	sort.Strings(absences)
+
+	timeOff := make([]string, 0, len(t.conf.timeOff))
+	for date, a := range t.conf.timeOff {
+		timeOff = append(timeOff, fmt.Sprintf("%s=%d", date, a.off))
+	}
+	sort.Strings(timeOff)
+
+	key = fmt.Sprintf(
+		"%s|%s|%d|%s|%s|%s|%s|%s",
+		key,
+		t.conf.AccountID,
+		t.User().ID,
+		strings.Join(t.conf.WeekdaysOff, ","),
+		strings.Join(t.conf.ExcludedDates, ","),
+		t.conf.Holidays,
+		strings.Join(absences, ","),
+		strings.Join(timeOff, ","),
+	)
+
+	var cached cachedEntries
+	if ok, err := t.cache.Get(key, &cached); err == nil && ok {
+		t.l.Debug("Cache hit", "key", key)
+		return cached.Days, cached.Entries, nil
+	}
+
+	days, entries, err := fetch()
+	if err != nil {
+		return days, entries, err
+	}
+
+	if err := t.cache.Set(key, &cachedEntries{days, entries}); err != nil {
+		t.l.Warn("Failed to write cache", "err", err)
+	}
+
+	return days, entries, nil
+}
+
+func (t *Timetracking) getRange(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+	actualDays bool,
+) (int, harvest.TimeEntries, error) {
+	t.l.Info("Fetching time entries", "from", from.Format(DateFormat), "to", to.Format(DateFormat))
+
+	entries := make(harvest.TimeEntries, 0)
+	counter := make(map[string]struct{})
+	if actualDays {
+		for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+			if t.conf.Excluded(d) || t.conf.Off(d) {
+				continue
+			}
+
+			counter[d.Format(DateFormat)] = struct{}{}
+			entries = append(
+				entries,
+				&harvest.TimeEntry{
+					Hours:     harvest.DurationHours{0},
+					SpentDate: &harvest.Date{d},
+				},
+			)
+		}
+	}
+
+	fetched, err := t.GetTimeEntriesBetween(ctx, from, to)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	for _, e := range fetched {
+		if e.SpentDate == nil {
+			continue
+		}
+
+		if !actualDays {
+			d := e.SpentDate.Time
+			for t.conf.Excluded(d) || t.conf.Off(d) {
+				d = d.AddDate(0, 0, -1)
+			}
+			counter[d.Format(DateFormat)] = struct{}{}
+		}
+		entries = append(entries, e)
+	}
+
+	return len(counter), entries, nil
+}
+
+func (t *Timetracking) getRecentDays(
+	ctx context.Context,
+	amount int,
+	from time.Time,
+	actualDays bool,
+) (int, harvest.TimeEntries, error) {
+	t.l.Info("Fetching time entries", "days", amount, "to", from.Format(DateFormat))
+
+	entries := make(harvest.TimeEntries, 0, amount)
+	counter := make(map[string]struct{})
+
+	// The amount of working days before from is the smallest window that
+	// could hold amount days with entries, fetch its pages concurrently.
+	start := t.conf.FirstWorkingDay(from, amount)
+	if actualDays {
+		for d := from; !d.Before(start); d = d.AddDate(0, 0, -1) {
+			if t.conf.Excluded(d) || t.conf.Off(d) {
+				continue
+			}
+
+			counter[d.Format(DateFormat)] = struct{}{}
+			entries = append(
+				entries,
+				&harvest.TimeEntry{
+					Hours:     harvest.DurationHours{0},
+					SpentDate: &harvest.Date{d},
+				},
+			)
+		}
+	}
+
+	add := func(it *harvest.Pager[*harvest.TimeEntry]) error {
+		for it.Next() {
+			e := it.Value()
+			if e.SpentDate == nil {
+				continue
+			}
+
+			d := e.SpentDate.Time
+			for t.conf.Excluded(d) || t.conf.Off(d) {
+				d = d.AddDate(0, 0, -1)
+			}
+
+			df := d.Format(DateFormat)
+			if _, ok := counter[df]; !ok && len(counter) == amount {
+				break
+			}
+			counter[df] = struct{}{}
+			entries = append(entries, e)
+		}
+
+		return it.Err()
+	}
+
+	fetched, err := t.GetTimeEntriesBetween(ctx, start, from)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := add(harvest.SlicePager(fetched)); err != nil {
+		return 0, nil, err
+	}
+
+	if len(counter) < amount {
+		// Not every working day in the window has entries,
+		// page further back from the day before it.
+		before := start.AddDate(0, 0, -1)
+		params := &harvest.TimeEntriesParams{UserID: &t.User().ID, To: &before}
+		if err := add(t.harvest.TimeEntries(ctx, params)); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	return len(counter), entries, nil
+}
+
+// ForEachTimeEntryBetween calls fn with every time entry spent between from
+// and to (inclusive) as its page arrives, without holding on to them.
+func (t *Timetracking) ForEachTimeEntryBetween(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+	fn func(*harvest.TimeEntry) error,
+) error {
+	t.l.Info("Streaming time entries", "from", from.Format(DateFormat), "to", to.Format(DateFormat))
+	return t.harvest.ForEachTimeEntry(
+		ctx,
+		&harvest.TimeEntriesParams{UserID: &t.User().ID, From: &from, To: &to},
+		fn,
+	)
+}
+
+// GetProjectEntries fetches the time entries of all users on a project
+// spent from the given day on.
+func (t *Timetracking) GetProjectEntries(
+	ctx context.Context,
+	projectID int,
+	from *time.Time,
+) (harvest.TimeEntries, error) {
+	return t.harvest.AllTimeEntries(
+		ctx,
+		&harvest.TimeEntriesParams{ProjectID: &projectID, From: from},
+		harvest.DefaultPageWorkers,
+	)
+}
+
+func (t *Timetracking) GetAssignmentsByName(ctx context.Context, projectName string) ([]*forecast.Assignment, error) {
+	if t.forecastUser == nil || t.forecastUser.ID == 0 {
+		return nil, errors.New("No forecast user set")
+	}
+
+	p, err := t.FindForecastProject(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	as, err := t.forecast.GetAssignments(
+		ctx,
+		&forecast.AssignmentsParams{
+			ProjectID: &p.ID,
+			PersonID:  &t.forecastUser.ID,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return as.Assignments, nil
+}
+
+// GetTimeOff returns the planned time off per day between from and to,
+// assignments without an allocation take fullDay of that day or, when
+// fullDay is nil, 0.
+func (t *Timetracking) GetTimeOff(
+	ctx context.Context,
+	projectName string,
+	from time.Time,
+	to time.Time,
+	fullDay func(d time.Time) time.Duration,
+) (map[string]time.Duration, error) {
+	as, err := t.GetAssignmentsByName(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	off := make(map[string]time.Duration)
+	first, last := from.Format(DateFormat), to.Format(DateFormat)
+	for _, a := range as {
+		if a.StartDate == nil || a.EndDate == nil {
+			continue
+		}
+
+		for d := a.StartDate.Time; !d.After(a.EndDate.Time); d = d.AddDate(0, 0, 1) {
+			f := d.Format(DateFormat)
+			if f < first || f > last {
+				continue
+			}
+			perDay := a.Allocation.Duration
+			if perDay == 0 && fullDay != nil {
+				perDay = fullDay(t.Day(d))
+			}
+			off[f] += perDay
+		}
+	}
+
+	return off, nil
+}
+
+// WithTimeOff returns a copy of t whose config counts the forecast time off
+// of the user between from and to as absences, see Config.TimeOffProject.
+// t itself is not changed. The time off is only a convenience, when it can
+// not be fetched the error is logged and t is returned.
+func (t *Timetracking) WithTimeOff(ctx context.Context, from, to time.Time) *Timetracking {
+	project := t.conf.TimeOffProject()
+	if project == "" {
+		return t
+	}
+
+	if t.forecastUser == nil {
+		if err := t.SetForecastUID(ctx, 0); err != nil {
+			t.l.Warn("Failed to fetch forecast time off", "err", err)
+			return t
+		}
+	}
+
+	// Only the time off of the forecast user applies to the harvest user.
+	if t.user != nil && t.forecastUser.HarvestID != 0 && t.forecastUser.HarvestID != t.user.ID {
+		return t
+	}
+
+	off, err := t.GetTimeOff(ctx, project, from, to, nil)
+	if err != nil {
+		t.l.Warn("Failed to fetch forecast time off", "err", err)
+		return t
+	}
+
+	c := *t
+	c.conf = t.conf.WithTimeOff(off)
+	return &c
+}
+
+// Config returns the config of t, with the time off of WithTimeOff.
+func (t *Timetracking) Config() *Config {
+	return t.conf
+}
+
+func (t *Timetracking) FindForecastProject(ctx context.Context, projectName string) (*forecast.Project, error) {
+	ps, err := t.forecast.GetProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var project *forecast.Project
+	for _, p := range ps.Projects {
+		if p.Name == projectName {
+			project = p
+		}
+	}
+
+	if project == nil {
+		return nil, fmt.Errorf("Could not find project id for a project named '%s'", projectName)
+	}
+
+	return project, nil
+}
+
+// Plan assigns the forecast user to a project from the first to the last
+// Day, perDay of 0 allocates entire days.
+func (t *Timetracking) Plan(
+	ctx context.Context,
+	projectName string,
+	from time.Time,
+	to time.Time,
+	perDay time.Duration,
+	notes string,
+) (*forecast.Assignment, error) {
+	if t.forecastUser == nil || t.forecastUser.ID == 0 {
+		return nil, errors.New("No forecast user set")
+	}
+
+	p, err := t.FindForecastProject(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	body := &forecast.AssignmentBody{
+		ProjectID: &p.ID,
+		PersonID:  &t.forecastUser.ID,
+		StartDate: &harvest.Date{from},
+		EndDate:   &harvest.Date{to},
+	}
+	if perDay != 0 {
+		body.Allocation = &harvest.DurationSeconds{perDay}
+	}
+	if notes != "" {
+		body.Notes = &notes
+	}
+
+	return t.forecast.CreateAssignment(ctx, body)
+}
+
+// GetMilestones returns the milestones between from and to of the projects the
+// forecast user is assigned to in that range, sorted by date, and the
+// projects by their forecast id.
+func (t *Timetracking) GetMilestones(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+) ([]*forecast.Milestone, map[int]*forecast.Project, error) {
+	if t.forecastUser == nil || t.forecastUser.ID == 0 {
+		return nil, nil, errors.New("No forecast user set")
+	}
+
+	ps, err := t.forecast.GetProjects(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	projects := make(map[int]*forecast.Project, len(ps.Projects))
+	for _, p := range ps.Projects {
+		projects[p.ID] = p
+	}
+
+	as, err := t.forecast.GetAssignments(
+		ctx,
+		&forecast.AssignmentsParams{
+			PersonID:  &t.forecastUser.ID,
+			StartDate: &from,
+			EndDate:   &to,
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mine := make(map[int]struct{}, len(as.Assignments))
+	for _, a := range as.Assignments {
+		mine[a.ProjectID] = struct{}{}
+	}
+
+	ms, err := t.forecast.GetMilestones(
+		ctx,
+		&forecast.MilestonesParams{StartDate: &from, EndDate: &to},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	list := make([]*forecast.Milestone, 0, len(ms.Milestones))
+	for _, m := range ms.Milestones {
+		if _, ok := mine[m.ProjectID]; !ok || m.Date == nil {
+			continue
+		}
+		if d := m.Date.Format(DateFormat); d < from.Format(DateFormat) || d > to.Format(DateFormat) {
+			continue
+		}
+		list = append(list, m)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Date.Before(list[j].Date.Time)
+	})
+
+	return list, projects, nil
+}
+
+func (t *Timetracking) DeleteAssignment(ctx context.Context, id int) error {
+	return t.forecast.DeleteAssignment(ctx, id)
+}
+
+// GetUserProjectAssignments returns the projects and tasks the user can log
+// time on. Those of the authenticated user come from
+// /users/me/project_assignments, which any user may request.
+func (t *Timetracking) GetUserProjectAssignments(ctx context.Context) ([]*harvest.UserAssignment, error) {
+	if t.self {
+		return t.harvest.MyAssignments(ctx, &harvest.UserAssignmentParams{}).All()
+	}
+
+	return t.harvest.UserAssignments(
+		ctx,
+		t.User().ID,
+		&harvest.UserAssignmentParams{},
+	).All()
+}
+
+func (t *Timetracking) StartTracker(ctx context.Context, projectID, taskID int) (*harvest.TimeEntry, error) {
+	return t.harvest.CreateTimeEntry(
+		ctx,
+		&harvest.CreateTimeEntryBody{
+			UserID:    &t.User().ID,
+			ProjectID: projectID,
+			TaskID:    taskID,
+			SpentDate: harvest.Date{t.Now()},
+		},
+	)
+}
+
+func (t *Timetracking) FindTask(ctx context.Context, projectName, taskName string) (*Task, error) {
+	res, err := t.GetUserProjectAssignments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return FindTaskIn(res, projectName, taskName)
+}
+
+// FindTaskByID returns the task of an assigned project by their ids.
+func (t *Timetracking) FindTaskByID(ctx context.Context, projectID, taskID int) (*Task, error) {
+	res, err := t.GetUserProjectAssignments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range res {
+		if a.Project == nil || a.Project.ID != projectID {
+			continue
+		}
+
+		for _, ta := range a.TaskAssignments {
+			if ta.Task.ID != taskID {
+				continue
+			}
+
+			task := &Task{
+				ProjectID:   a.Project.ID,
+				ProjectName: a.Project.Name,
+				TaskID:      ta.Task.ID,
+				TaskName:    ta.Task.Name,
+			}
+			if a.Client != nil {
+				task.ClientID = a.Client.ID
+				task.ClientName = a.Client.Name
+			}
+
+			return task, nil
+		}
+
+		return nil, fmt.Errorf("You are not assigned to task %d in project '%s'", taskID, a.Project.Name)
+	}
+
+	return nil, fmt.Errorf("You are not assigned to project %d", projectID)
+}
+
+// FindTaskIn finds a task by project and task name in the given assignments.
+func FindTaskIn(res []*harvest.UserAssignment, projectName, taskName string) (*Task, error) {
+	var project *harvest.UserAssignment
+	for _, a := range res {
+		if a.Project != nil && strings.EqualFold(a.Project.Name, projectName) {
+			project = a
+			break
+		}
+	}
+
+	if project == nil {
+		return nil, fmt.Errorf("Could not find a project named '%s'", projectName)
+	}
+
+	for _, ta := range project.TaskAssignments {
+		if !strings.EqualFold(ta.Task.Name, taskName) {
+			continue
+		}
+
+		task := &Task{
+			ProjectID:   project.Project.ID,
+			ProjectName: project.Project.Name,
+			TaskID:      ta.Task.ID,
+			TaskName:    ta.Task.Name,
+		}
+		if project.Client != nil {
+			task.ClientID = project.Client.ID
+			task.ClientName = project.Client.Name
+		}
+
+		return task, nil
+	}
+
+	return nil, fmt.Errorf(
+		"Could not find a task named '%s' in project '%s'",
+		taskName,
+		project.Project.Name,
+	)
+}
+
+// LogTime creates a time entry of hours on date, ref links it to an item
+// in another service and can be nil.
+func (t *Timetracking) LogTime(
+	ctx context.Context,
+	projectID,
+	taskID int,
+	date time.Time,
+	hours time.Duration,
+	notes string,
+	ref *harvest.ExternalReference,
+) (*harvest.TimeEntry, error) {
+	h := hours.Hours()
+	body := &harvest.CreateTimeEntryBody{
+		UserID:            &t.User().ID,
+		ProjectID:         projectID,
+		TaskID:            taskID,
+		SpentDate:         harvest.Date{date},
+		Hours:             &h,
+		ExternalReference: ref,
+	}
+	if notes != "" {
+		body.Notes = &notes
+	}
+
+	return t.harvest.CreateTimeEntry(ctx, body)
+}
+
+// FindByReference returns the entry on date that links to the same external
+// id (and group) as ref, if any.
+func (t *Timetracking) FindByReference(
+	ctx context.Context,
+	date time.Time,
+	ref *harvest.ExternalReference,
+) (*harvest.TimeEntry, error) {
+	day := Day(date)
+	entries, err := t.GetTimeEntriesBetween(ctx, day, day)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		if e.ExternalReference.ID == ref.ID && e.ExternalReference.GroupID == ref.GroupID {
+			return e, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (t *Timetracking) GetRunning(ctx context.Context) (*harvest.TimeEntry, error) {
+	running := true
+	res, err := t.harvest.GetTimeEntries(
+		ctx,
+		&harvest.TimeEntriesParams{UserID: &t.User().ID, Running: &running},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.TimeEntries) == 0 {
+		return nil, nil
+	}
+
+	return res.TimeEntries[0], nil
+}
+
+func (t *Timetracking) RestartTracker(ctx context.Context, entryID int) (*harvest.TimeEntry, error) {
+	return t.harvest.RestartTimeEntry(ctx, entryID)
+}
+
+func (t *Timetracking) StopTracker(ctx context.Context) (*harvest.TimeEntry, error) {
+	running, err := t.GetRunning(ctx)
+	if err != nil || running == nil {
+		return nil, err
+	}
+
+	return t.harvest.StopTimeEntry(ctx, running.ID)
+}
+
+func (t *Timetracking) UpdateNotes(ctx context.Context, entryID int, notes string) (*harvest.TimeEntry, error) {
+	return t.harvest.UpdateTimeEntry(
+		ctx,
+		entryID,
+		&harvest.UpdateTimeEntryBody{Notes: &notes},
+	)
+}
+
+// GetForecastAssignments returns the assignments of the forecast user between
+// from and to, keyed by the name of their project.
+func (t *Timetracking) GetForecastAssignments(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+) (map[string][]*forecast.Assignment, error) {
+	if t.forecastUser == nil || t.forecastUser.ID == 0 {
+		return nil, errors.New("No forecast user set")
+	}
+
+	ps, err := t.forecast.GetProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[int]string, len(ps.Projects))
+	for _, p := range ps.Projects {
+		names[p.ID] = p.Name
+	}
+
+	as, err := t.forecast.GetAssignments(
+		ctx,
+		&forecast.AssignmentsParams{
+			PersonID:  &t.forecastUser.ID,
+			StartDate: &from,
+			EndDate:   &to,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string][]*forecast.Assignment)
+	for _, a := range as.Assignments {
+		name := names[a.ProjectID]
+		m[name] = append(m[name], a)
+	}
+
+	return m, nil
+}
+
+func (t *Timetracking) GetEntry(ctx context.Context, entryID int) (*harvest.TimeEntry, error) {
+	return t.harvest.GetTimeEntry(ctx, entryID)
+}
+
+func (t *Timetracking) UpdateEntry(
+	ctx context.Context,
+	entryID int,
+	body *harvest.UpdateTimeEntryBody,
+) (*harvest.TimeEntry, error) {
+	return t.harvest.UpdateTimeEntry(ctx, entryID, body)
+}
+
+func (t *Timetracking) DeleteEntry(ctx context.Context, entryID int) error {
+	return t.harvest.DeleteTimeEntry(ctx, entryID)
+}
+
+func (t *Timetracking) GetProjects(ctx context.Context, active *bool) ([]*harvest.Project, error) {
+	return t.harvest.Projects(ctx, &harvest.ProjectsParams{Active: active}).All()
+}
+
+// FindProject finds a project by its id or (case insensitive) name.
+func (t *Timetracking) FindProject(ctx context.Context, nameOrID string) (*harvest.Project, error) {
+	if id, err := strconv.Atoi(nameOrID); err == nil {
+		return t.harvest.GetProject(ctx, id)
+	}
+
+	it := t.harvest.Projects(ctx, &harvest.ProjectsParams{})
+	for it.Next() {
+		if p := it.Value(); strings.EqualFold(p.Name, nameOrID) {
+			return p, nil
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, fmt.Errorf("Could not find a project named '%s'", nameOrID)
+}
+
+func (t *Timetracking) GetProjectTasks(ctx context.Context, projectID int) ([]*harvest.TaskAssignment, error) {
+	return t.harvest.TaskAssignments(ctx, projectID, &harvest.TaskAssignmentsParams{}).All()
+}
+
+// LoadCompany applies the company's week start day and time format
+// unless they are overridden in the config, and its separators of amounts.
+func (t *Timetracking) LoadCompany(ctx context.Context) error {
+	if t.conf.WeekStart != "" && t.conf.TimeFormat != "" {
+		return nil
+	}
+
+	company, err := t.harvest.GetCompany(ctx)
+	if err != nil {
+		return err
+	}
+
+	if t.conf.WeekStart == "" {
+		if wd, ok := ParseWeekday(company.WeekStart); ok {
+			t.weekStart = wd
+		}
+	}
+	if t.conf.TimeFormat == "" {
+		t.timeFormat = company.TimeFormat
+	}
+	t.separators = currency.Separators{Decimal: company.Decimal, Thousands: company.Thousands}
+
+	return nil
+}
+
+// FindClient finds a client by its id or (case insensitive) name.
+func (t *Timetracking) FindClient(ctx context.Context, nameOrID string) (*harvest.Client, error) {
+	if id, err := strconv.Atoi(nameOrID); err == nil {
+		return t.harvest.GetClient(ctx, id)
+	}
+
+	it := t.harvest.Clients(ctx, &harvest.ClientsParams{})
+	for it.Next() {
+		if c := it.Value(); strings.EqualFold(c.Name, nameOrID) {
+			return c, nil
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, fmt.Errorf("Could not find a client named '%s'", nameOrID)
+}
+
+// GetUninvoiced returns the billable time entries of all users that have not
+// been invoiced yet, of a single client unless clientID is 0, optionally
+// limited to those spent between from and to.
+func (t *Timetracking) GetUninvoiced(ctx context.Context, clientID int, from, to *time.Time) (harvest.TimeEntries, error) {
+	billed := false
+	p := &harvest.TimeEntriesParams{Billed: &billed, From: from, To: to}
+	if clientID != 0 {
+		p.ClientID = &clientID
+	}
+
+	entries, err := t.harvest.AllTimeEntries(ctx, p, harvest.DefaultPageWorkers)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries.Filter(harvest.Uninvoiced), nil
+}
+
+func (t *Timetracking) GetInvoices(ctx context.Context, p *harvest.InvoicesParams) ([]*harvest.Invoice, error) {
+	return t.harvest.Invoices(ctx, p).All()
+}
+
+// DraftInvoice creates a draft invoice for the client, importing the
+// uninvoiced billable time of the given projects between from and to.
+func (t *Timetracking) DraftInvoice(
+	ctx context.Context,
+	clientID int,
+	projectIDs []int,
+	from,
+	to time.Time,
+	summary string,
+	subject string,
+) (*harvest.Invoice, error) {
+	body := &harvest.CreateInvoiceBody{
+		ClientID:  clientID,
+		IssueDate: &harvest.Date{t.Today()},
+		LineItemsImport: &harvest.InvoiceLineItemsImport{
+			ProjectIDs: projectIDs,
+			Time: &harvest.InvoiceTimeImport{
+				SummaryType: summary,
+				From:        &harvest.Date{from},
+				To:          &harvest.Date{to},
+			},
+		},
+	}
+	if subject != "" {
+		body.Subject = &subject
+	}
+
+	return t.harvest.CreateInvoice(ctx, body)
+}
+
+func (t *Timetracking) GetExpensesBetween(ctx context.Context, from, to time.Time) ([]*harvest.Expense, error) {
+	return t.harvest.Expenses(
+		ctx,
+		&harvest.ExpensesParams{UserID: &t.User().ID, From: &from, To: &to},
+	).All()
+}
+
+// FindExpenseCategory finds an active expense category by its id or
+// (case insensitive) name.
+func (t *Timetracking) FindExpenseCategory(ctx context.Context, nameOrID string) (*harvest.ExpenseCategory, error) {
+	id, _ := strconv.Atoi(nameOrID)
+	active := true
+	it := t.harvest.ExpenseCategories(ctx, &harvest.ExpenseCategoriesParams{Active: &active})
+	for it.Next() {
+		if c := it.Value(); c.ID == id || strings.EqualFold(c.Name, nameOrID) {
+			return c, nil
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, fmt.Errorf("Could not find an expense category named '%s'", nameOrID)
+}
+
+func (t *Timetracking) CreateExpense(ctx context.Context, body *harvest.CreateExpenseBody) (*harvest.Expense, error) {
+	body.UserID = &t.User().ID
+	return t.harvest.CreateExpense(ctx, body)
+}
+
+// FindRole finds a role by its id or (case insensitive) name.
+func (t *Timetracking) FindRole(ctx context.Context, nameOrID string) (*harvest.Role, error) {
+	id, _ := strconv.Atoi(nameOrID)
+	it := t.harvest.Roles(ctx, &harvest.RolesParams{})
+	for it.Next() {
+		if r := it.Value(); r.ID == id || strings.EqualFold(r.Name, nameOrID) {
+			return r, nil
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, fmt.Errorf("Could not find a role named '%s'", nameOrID)
+}
+
+// Reportable returns the ids of the users the authenticated user can report
+// on: themselves and their teammates. It returns nil for administrators,
+// who can report on everyone.
+func (t *Timetracking) Reportable(ctx context.Context) (map[int]bool, error) {
+	me, err := t.harvest.GetMe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if me.Administrator() {
+		return nil, nil
+	}
+
+	teammates, err := t.harvest.Teammates(ctx, me.ID, &harvest.TeammatesParams{}).All()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[int]bool, len(teammates)+1)
+	ids[me.ID] = true
+	for _, u := range teammates {
+		ids[u.ID] = true
+	}
+
+	return ids, nil
+}
+
+// TeamWorkers is the default amount of concurrent requests of commands that
+// fetch several users, projects or retainers.
+const TeamWorkers = 4
+
+// GetTeam returns the users with the given ids, or all active users if
+// ids is empty, fetching at most workers users at once. Managers only get
+// the users they can report on, asking for someone else is an error.
+func (t *Timetracking) GetTeam(ctx context.Context, ids []int, workers int) ([]*harvest.User, error) {
+	reportable, err := t.Reportable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		active := true
+		users, err := t.harvest.ListUsers(ctx, &harvest.UsersParams{Active: &active})
+		if err != nil || reportable == nil {
+			return users, err
+		}
+
+		team := make([]*harvest.User, 0, len(users))
+		for _, u := range users {
+			if reportable[u.ID] {
+				team = append(team, u)
+			}
+		}
+		return team, nil
+	}
+
+	for _, id := range ids {
+		if reportable != nil && !reportable[id] {
+			return nil, fmt.Errorf("User %d is not one of your teammates", id)
+		}
+	}
+
+	users := make([]*harvest.User, len(ids))
+	err = harvest.Parallel(ctx, len(ids), workers, func(ctx context.Context, i int) error {
+		u, err := t.harvest.GetUser(ctx, ids[i])
+		users[i] = u
+		return err
+	})
+
+	return users, err
+}
+
+// GetTeamEntries fetches the time entries of each user between from and to
+// using a pool of workers, the result is in the same order as users. The
+// pages of a user are fetched one by one so there are never more than
+// workers requests at once.
+func (t *Timetracking) GetTeamEntries(
+	ctx context.Context,
+	users []*harvest.User,
+	from,
+	to time.Time,
+	workers int,
+) ([]harvest.TimeEntries, error) {
+	entries := make([]harvest.TimeEntries, len(users))
+	err := harvest.Parallel(ctx, len(users), workers, func(ctx context.Context, i int) error {
+		list, err := t.harvest.AllTimeEntries(
+			ctx,
+			&harvest.TimeEntriesParams{UserID: &users[i].ID, From: &from, To: &to},
+			1,
+		)
+		entries[i] = list
+		return err
+	})
+
+	return entries, err
+}
+
+func (t *Timetracking) GetCompany(ctx context.Context) (*harvest.Company, error) {
+	return t.harvest.GetCompany(ctx)
+}
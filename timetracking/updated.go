@@ -0,0 +1,128 @@
+package timetracking
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/frizinak/harvest-timetracking/harvest"
+)
+
+// entriesFullRefresh is how long cached entries are only updated with the
+// entries changed since the last fetch. updated_since does not return
+// deleted entries, after it all entries are fetched again.
+const entriesFullRefresh = time.Hour
+
+// updatedSinceSkew is subtracted from the time of the last fetch so updates
+// are not missed when our clock is ahead of harvest's.
+const updatedSinceSkew = time.Minute
+
+type cachedBetween struct {
+	Full    time.Time           `json:"full"`
+	Fetched time.Time           `json:"fetched"`
+	Entries harvest.TimeEntries `json:"entries"`
+}
+
+// GetTimeEntriesBetween fetches all time entries spent between from and to (inclusive).
+// With a cache, repeated calls within entriesFullRefresh only fetch the entries
+// updated since the previous call and the running timer and merge them with the
+// cached entries.
+func (t *Timetracking) GetTimeEntriesBetween(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+) (harvest.TimeEntries, error) {
+	params := &harvest.TimeEntriesParams{UserID: &t.User().ID, From: &from, To: &to}
+	if t.cache == nil {
+		return t.harvest.AllTimeEntries(ctx, params, harvest.DefaultPageWorkers)
+	}
+
+	key := fmt.Sprintf(
+		"between|%s|%d|%s|%s",
+		t.conf.AccountID,
+		t.User().ID,
+		from.Format(DateFormat),
+		to.Format(DateFormat),
+	)
+
+	now := time.Now()
+	var cached cachedBetween
+	ok, err := t.cache.Get(key, &cached)
+	if err != nil || !ok || now.Sub(cached.Full) >= entriesFullRefresh {
+		entries, err := t.harvest.AllTimeEntries(ctx, params, harvest.DefaultPageWorkers)
+		if err != nil {
+			return nil, err
+		}
+		cached = cachedBetween{Full: now, Entries: entries}
+	} else if err := t.mergeUpdated(ctx, &cached, from, to); err != nil {
+		return nil, err
+	}
+
+	cached.Fetched = now
+	if err := t.cache.Set(key, &cached); err != nil {
+		t.l.Warn("Failed to write cache", "err", err)
+	}
+
+	return cached.Entries, nil
+}
+
+// mergeUpdated replaces the cached entries that changed since they were
+// fetched, adds new ones and drops the ones moved out of the range.
+// Running timers are always refreshed, their hours change without an update.
+func (t *Timetracking) mergeUpdated(ctx context.Context, cached *cachedBetween, from, to time.Time) error {
+	since := cached.Fetched.Add(-updatedSinceSkew)
+	t.l.Info("Fetching updated time entries", "since", since.Format(time.RFC3339))
+
+	updated, err := t.harvest.AllTimeEntries(
+		ctx,
+		&harvest.TimeEntriesParams{UserID: &t.User().ID, UpdatedSince: &since},
+		harvest.DefaultPageWorkers,
+	)
+	if err != nil {
+		return err
+	}
+
+	running := true
+	timers, err := t.harvest.AllTimeEntries(
+		ctx,
+		&harvest.TimeEntriesParams{UserID: &t.User().ID, Running: &running},
+		harvest.DefaultPageWorkers,
+	)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[int]*harvest.TimeEntry, len(cached.Entries))
+	for _, e := range cached.Entries {
+		if !e.Running {
+			byID[e.ID] = e
+		}
+	}
+
+	first, last := from.Format(DateFormat), to.Format(DateFormat)
+	for _, e := range append(updated, timers...) {
+		delete(byID, e.ID)
+		if e.SpentDate == nil {
+			continue
+		}
+		if d := e.SpentDate.Format(DateFormat); d >= first && d <= last {
+			byID[e.ID] = e
+		}
+	}
+
+	entries := make(harvest.TimeEntries, 0, len(byID))
+	for _, e := range byID {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.SpentDate == nil || b.SpentDate == nil || a.SpentDate.Equal(b.SpentDate.Time) {
+			return a.ID > b.ID
+		}
+		return a.SpentDate.After(b.SpentDate.Time)
+	})
+
+	cached.Entries = entries
+	return nil
+}